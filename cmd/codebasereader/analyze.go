@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/engine"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// runAnalyze analyzes a directory and prints a report of its metrics and findings
+func runAnalyze(args []string) error {
+	config := engine.DefaultConfig()
+
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	flags.IntVar(&config.HistoryWindowDays, "window", config.HistoryWindowDays, "days of git history to analyze; 0 for all history")
+	entryPoints := flags.String("entry-points", "", "comma-separated name patterns of functions used from outside the project, such as handlers registered by name")
+	flags.StringVar(&config.ProjectConfig, "config", config.ProjectConfig, "project config file with architecture rules and quality gates, relative to the analyzed path")
+	flags.StringVar(&config.Baseline, "baseline", config.Baseline, "baseline file of accepted violations, relative to the analyzed path")
+	updateBaseline := flags.Bool("update-baseline", false, "record the current violations as the baseline instead of failing on them")
+	tightenBaseline := flags.Bool("tighten-baseline", false, "drop fixed and lower improved violations in the baseline when the run passes")
+	flags.BoolVar(&config.RecordTrends, "record", config.RecordTrends, "record a snapshot of the run in the trend store")
+	flags.StringVar(&config.Trends, "trends", config.Trends, "trend store file for -record, relative to the analyzed path")
+	revisionRange := flags.String("diff", "", "compare the files changed in a git revision range, such as main..feature, instead of analyzing the whole tree")
+	top := flags.Int("top", 20, "number of findings to show per section; 0 for all")
+	asJSON := flags.Bool("json", false, "print the full analysis as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: codebasereader analyze [flags] [path]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	for _, pattern := range strings.Split(*entryPoints, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			config.DeadCodeEntryPoints = append(config.DeadCodeEntryPoints, pattern)
+		}
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	analysisEngine := newApplication(config).GetEngine()
+	if *revisionRange != "" {
+		diff, err := analysisEngine.AnalyzeRevisions(root, *revisionRange)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(diff)
+		}
+		printRevisionDiff(diff, *top)
+		return nil
+	}
+
+	analysis, err := analysisEngine.AnalyzeDirectoryWithEnhancedMetrics(root)
+	if err != nil {
+		return err
+	}
+
+	if *updateBaseline {
+		baseline := metrics.NewBaseline(analysis, root)
+		if err := analysisEngine.SaveBaseline(root, baseline); err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %d violations in %s\n", len(baseline.Entries), analysisEngine.BaselinePath(root))
+		return nil
+	}
+	if *tightenBaseline && analysis.Baseline != nil && analysis.Baseline.Passed() {
+		if err := analysisEngine.SaveBaseline(root, analysis.Baseline.Tightened()); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Tightened %s\n", analysisEngine.BaselinePath(root))
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(analysis); err != nil {
+			return err
+		}
+		return checkFailures(analysis)
+	}
+
+	printOverview(root, analysis)
+	printDependencyCycles(root, analysis.DependencyGraph.Cycles, *top)
+	printModuleDependencies(root, analysis.DependencyGraph.Modules, *top)
+	printDeadCode(root, analysis.DeadCode, *top)
+	printArchitectureViolations(root, analysis.ArchitectureViolations, *top)
+	printQualityGates(root, analysis.QualityGates, *top)
+	if analysis.Baseline != nil {
+		printBaseline(analysis.Baseline, *top)
+	}
+
+	return checkFailures(analysis)
+}
+
+// checkFailures returns an error when the analysis found problems that should
+// fail a CI run, so the command exits with a non-zero status. With a baseline,
+// only violations that are new or worse than their baseline count.
+func checkFailures(analysis *metrics.EnhancedProjectAnalysis) error {
+	if comparison := analysis.Baseline; comparison != nil {
+		if !comparison.Passed() {
+			return fmt.Errorf("%d new and %d worsened violations compared with the baseline", len(comparison.New), len(comparison.Worsened))
+		}
+		return nil
+	}
+	if n := len(analysis.ArchitectureViolations); n > 0 {
+		return fmt.Errorf("%d imports break architecture rules", n)
+	}
+	if !metrics.GatesPassed(analysis.QualityGates) {
+		failed := 0
+		for _, gate := range analysis.QualityGates {
+			if !gate.Passed {
+				failed++
+			}
+		}
+		return fmt.Errorf("%d quality gates failed", failed)
+	}
+	return nil
+}
+
+// printOverview prints project size, languages and the quality score
+func printOverview(root string, analysis *metrics.EnhancedProjectAnalysis) {
+	fmt.Printf("Analysis of %s (%d files, %d lines)\n\n", root, analysis.TotalFiles, analysis.TotalLines)
+
+	languages := make([]string, 0, len(analysis.Languages))
+	for language := range analysis.Languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	fmt.Printf("%-12s  %6s  %8s  %9s  %7s\n", "LANGUAGE", "FILES", "LINES", "FUNCTIONS", "CLASSES")
+	for _, language := range languages {
+		stats := analysis.Languages[language]
+		fmt.Printf("%-12s  %6d  %8d  %9d  %7d\n", language, stats.FileCount, stats.LineCount, stats.FunctionCount, stats.ClassCount)
+	}
+
+	score := analysis.QualityScore
+	project := analysis.ProjectMetrics
+	fmt.Printf("\nQuality: %s (%.1f)\n", score.Grade, score.Overall)
+	fmt.Printf("  Maintainability index  %6.1f\n", project.MaintainabilityIndex)
+	fmt.Printf("  Average complexity     %6.1f (max %d)\n", project.AverageComplexity, project.MaxComplexity)
+	fmt.Printf("  Technical debt         %6.1f\n", project.TechnicalDebt)
+	fmt.Printf("  Duplication            %5.1f%%\n", project.CodeDuplication)
+	fmt.Printf("  Documentation          %5.1f%%\n", project.DocumentationRatio)
+}
+
+// printDependencyCycles lists each group of packages that depend on each other
+// with its shortest cycles and the imports between its packages
+func printDependencyCycles(root string, cycles []metrics.DependencyCycle, top int) {
+	fmt.Printf("\nDependency cycles (%d):\n", len(cycles))
+	if len(cycles) == 0 {
+		fmt.Println("  No circular dependencies between packages found.")
+		return
+	}
+
+	for i, cycle := range cycles {
+		fmt.Printf("  Cycle %d: %d packages\n", i+1, len(cycle.Packages))
+		for _, path := range cycle.Cycles {
+			names := make([]string, len(path))
+			for j, pkg := range path {
+				names[j] = relativePath(root, pkg)
+			}
+			fmt.Printf("    %s\n", strings.Join(names, " -> "))
+		}
+		for j, site := range cycle.Imports {
+			if top > 0 && j >= top {
+				fmt.Printf("      ... and %d more imports\n", len(cycle.Imports)-top)
+				break
+			}
+			location := fmt.Sprintf("%s:%d", relativePath(root, site.FilePath), site.Line)
+			fmt.Printf("      %-50s  %s\n", location, site.Import)
+		}
+	}
+}
+
+// printModuleDependencies lists, per manifest directory, the declared
+// dependencies that are never imported and the imports that are not declared
+func printModuleDependencies(root string, modules []metrics.ModuleDependencies, top int) {
+	fmt.Printf("\nDeclared dependencies (%d modules):\n", len(modules))
+	if len(modules) == 0 {
+		fmt.Println("  No go.mod, package.json or Python manifests found.")
+		return
+	}
+
+	printNames := func(label string, names []string) {
+		for i, name := range names {
+			if top > 0 && i >= top {
+				fmt.Printf("    ... and %d more\n", len(names)-top)
+				break
+			}
+			fmt.Printf("    %-10s  %s\n", label, name)
+		}
+	}
+	for _, module := range modules {
+		fmt.Printf("  %s (%s): %d declared, %d unused, %d undeclared\n", relativePath(root, module.Path),
+			module.Ecosystem, module.Declared, len(module.Unused), len(module.Undeclared))
+		printNames("unused", module.Unused)
+		printNames("undeclared", module.Undeclared)
+	}
+}
+
+// printDeadCode lists functions, methods and types that are never referenced
+func printDeadCode(root string, dead []metrics.DeadCode, top int) {
+	fmt.Printf("\nDead code (%d):\n", len(dead))
+	if len(dead) == 0 {
+		fmt.Println("  No unreferenced functions or types found.")
+		return
+	}
+
+	fmt.Printf("  %-8s  %-50s  %s\n", "KIND", "LOCATION", "NAME")
+	for i, d := range dead {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(dead)-top)
+			break
+		}
+		location := fmt.Sprintf("%s:%d", relativePath(root, d.FilePath), d.LineStart)
+		fmt.Printf("  %-8s  %-50s  %s\n", d.Kind, location, d.Name)
+	}
+}
+
+// printArchitectureViolations lists the imports that break the project's
+// architecture rules, grouped by rule
+func printArchitectureViolations(root string, violations []metrics.ArchitectureViolation, top int) {
+	fmt.Printf("\nArchitecture violations (%d):\n", len(violations))
+	if len(violations) == 0 {
+		fmt.Println("  No imports break the architecture rules.")
+		return
+	}
+
+	byRule := make(map[string][]metrics.ArchitectureViolation)
+	var rules []string
+	for _, v := range violations {
+		if byRule[v.Rule] == nil {
+			rules = append(rules, v.Rule)
+		}
+		byRule[v.Rule] = append(byRule[v.Rule], v)
+	}
+
+	for _, rule := range rules {
+		fmt.Printf("  %s\n", rule)
+		if reason := byRule[rule][0].Reason; reason != "" {
+			fmt.Printf("    %s\n", reason)
+		}
+		for i, v := range byRule[rule] {
+			if top > 0 && i >= top {
+				fmt.Printf("    ... and %d more\n", len(byRule[rule])-top)
+				break
+			}
+			location := fmt.Sprintf("%s:%d", relativePath(root, v.FilePath), v.Line)
+			fmt.Printf("    %-50s  %s\n", location, v.Import)
+		}
+	}
+}
+
+// printQualityGates prints whether each quality gate passed, with the functions
+// and files over its threshold
+func printQualityGates(root string, gates []metrics.GateResult, top int) {
+	fmt.Printf("\nQuality gates (%d):\n", len(gates))
+	if len(gates) == 0 {
+		fmt.Println("  No quality gates configured.")
+		return
+	}
+
+	for _, gate := range gates {
+		status := "PASS"
+		if !gate.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("  %s  %-24s %-14s actual %s\n", status, gate.Gate, gate.Threshold, gate.Actual)
+		for i, f := range gate.Failures {
+			if top > 0 && i >= top {
+				fmt.Printf("        ... and %d more\n", len(gate.Failures)-top)
+				break
+			}
+			location := relativePath(root, f.FilePath)
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, f.Line)
+			}
+			fmt.Printf("        %-50s  %-30s %.1f\n", location, f.Symbol, f.Value)
+		}
+	}
+}
+
+// printBaseline compares the violations with the baseline, listing the ones
+// that fail the run first
+func printBaseline(comparison *metrics.BaselineComparison, top int) {
+	fmt.Printf("\nBaseline: %d accepted, %d new, %d worsened, %d improved, %d fixed\n",
+		comparison.Accepted, len(comparison.New), len(comparison.Worsened), len(comparison.Improved), len(comparison.Fixed))
+
+	printEntries := func(title string, count int, entry func(i int) string) {
+		if count == 0 {
+			return
+		}
+		fmt.Printf("  %s:\n", title)
+		for i := 0; i < count; i++ {
+			if top > 0 && i >= top {
+				fmt.Printf("    ... and %d more\n", count-top)
+				break
+			}
+			fmt.Printf("    %s\n", entry(i))
+		}
+	}
+	printEntries("New", len(comparison.New), func(i int) string {
+		return baselineEntryLabel(comparison.New[i])
+	})
+	printEntries("Worsened", len(comparison.Worsened), func(i int) string {
+		change := comparison.Worsened[i]
+		return fmt.Sprintf("%s  %.1f -> %.1f", baselineEntryLabel(change.BaselineEntry), change.Baseline, change.Value)
+	})
+	printEntries("Improved", len(comparison.Improved), func(i int) string {
+		change := comparison.Improved[i]
+		return fmt.Sprintf("%s  %.1f -> %.1f", baselineEntryLabel(change.BaselineEntry), change.Baseline, change.Value)
+	})
+}
+
+// baselineEntryLabel describes a baseline entry by its kind, file and symbol
+func baselineEntryLabel(entry metrics.BaselineEntry) string {
+	label := entry.Kind
+	if entry.FilePath != "" {
+		label += "  " + entry.FilePath
+	}
+	if entry.Symbol != "" {
+		label += "  " + entry.Symbol
+	}
+	return label
+}
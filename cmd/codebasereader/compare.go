@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// runCompare compares two analyses saved with "analyze -json"
+func runCompare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	top := flags.Int("top", 20, "number of entries to show per section; 0 for all")
+	asJSON := flags.Bool("json", false, "print the full comparison as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: codebasereader compare [flags] <before.json> <after.json>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("expected two analysis files")
+	}
+
+	before, err := compare.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := compare.Load(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	comparison := compare.Compare(before, after)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(comparison)
+	}
+
+	fmt.Printf("Comparing %s (%d files, %s)\n     with %s (%d files, %s)\n", flags.Arg(0), comparison.Before.TotalFiles,
+		comparison.Before.GeneratedAt.Format("2006-01-02 15:04"), flags.Arg(1), comparison.After.TotalFiles,
+		comparison.After.GeneratedAt.Format("2006-01-02 15:04"))
+
+	printScoreChange(comparison.QualityScore)
+	printProjectMetricsChange(comparison.ProjectMetrics)
+	printLanguageChanges(comparison.Languages)
+	printDirectoryChanges(comparison.Directories, *top)
+	printSymbolChanges(comparison, *top)
+	return nil
+}
+
+// printScoreChange prints the quality score and grade of both analyses
+func printScoreChange(score compare.QualityScoreChange) {
+	marker := ""
+	if score.Regressed {
+		marker = "  REGRESSED"
+	}
+	fmt.Printf("\nQuality score: %.1f (%s) -> %.1f (%s)  %s%s\n", score.Before.Overall, score.Before.Grade,
+		score.After.Overall, score.After.Grade, signedFloat(score.After.Overall-score.Before.Overall), marker)
+}
+
+// printProjectMetricsChange prints the project metrics of both analyses
+func printProjectMetricsChange(change compare.ProjectMetricsChange) {
+	before, after := change.Before, change.After
+	fmt.Println("\nProject metrics:")
+	printIntChange("Complexity", before.TotalComplexity, after.TotalComplexity)
+	printFloatChange("Avg complexity", before.AverageComplexity, after.AverageComplexity)
+	printIntChange("Max complexity", before.MaxComplexity, after.MaxComplexity)
+	printFloatChange("Maintainability", before.MaintainabilityIndex, after.MaintainabilityIndex)
+	printFloatChange("Technical debt", before.TechnicalDebt, after.TechnicalDebt)
+	printFloatChange("Documentation %", before.DocumentationRatio, after.DocumentationRatio)
+	printFloatChange("Test coverage %", before.TestCoverage, after.TestCoverage)
+	printFloatChange("Duplication %", before.CodeDuplication, after.CodeDuplication)
+}
+
+// printLanguageChanges prints the languages that were added, removed or changed
+func printLanguageChanges(languages []compare.LanguageChange) {
+	fmt.Println("\nLanguages:")
+	for _, lang := range languages {
+		fmt.Printf("  %-9s %-14s files %s  lines %s  functions %s  complexity %s\n", lang.Status, lang.Language,
+			signedInt(lang.After.FileCount-lang.Before.FileCount), signedInt(lang.After.LineCount-lang.Before.LineCount),
+			signedInt(lang.After.FunctionCount-lang.Before.FunctionCount), signedInt(lang.After.Complexity-lang.Before.Complexity))
+	}
+}
+
+// printDirectoryChanges prints the directories that were added, removed or
+// changed, marking the ones that regressed
+func printDirectoryChanges(directories []compare.DirectoryChange, top int) {
+	var changed []compare.DirectoryChange
+	for _, dir := range directories {
+		if dir.Status != compare.Unchanged {
+			changed = append(changed, dir)
+		}
+	}
+
+	fmt.Printf("\nDirectories (%d changed):\n", len(changed))
+	for i, dir := range changed {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(changed)-top)
+			break
+		}
+		marker := " "
+		if dir.Regressed {
+			marker = "!"
+		}
+		fmt.Printf(" %s %-9s %-40s files %s  lines %s  complexity %s  maintainability %s\n", marker, dir.Status, dir.Path,
+			signedInt(dir.After.Files-dir.Before.Files), signedInt(dir.After.Lines-dir.Before.Lines),
+			signedInt(dir.After.Complexity-dir.Before.Complexity),
+			signedFloat(dir.After.MaintainabilityIndex-dir.Before.MaintainabilityIndex))
+	}
+}
+
+// printSymbolChanges counts the changed files and functions, then lists moved
+// files, renamed functions and the functions that regressed
+func printSymbolChanges(comparison *compare.Comparison, top int) {
+	changes := comparison.Changes
+
+	fileCounts := make(map[string]int)
+	var moved []metrics.FileDelta
+	for _, file := range changes.Files {
+		fileCounts[file.Status]++
+		if file.Status == git.StatusRenamed {
+			moved = append(moved, file)
+		}
+	}
+	fmt.Printf("\nFiles: %d modified, %d added, %d removed, %d moved\n", fileCounts[git.StatusModified],
+		fileCounts[git.StatusAdded], fileCounts[git.StatusDeleted], fileCounts[git.StatusRenamed])
+	for i, file := range moved {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(moved)-top)
+			break
+		}
+		fmt.Printf("  %s -> %s\n", file.OldPath, file.Path)
+	}
+
+	functionCounts := make(map[string]int)
+	var renamed []metrics.FunctionDelta
+	for _, fn := range changes.Functions {
+		functionCounts[fn.Status]++
+		if fn.Status == metrics.DiffRenamed {
+			renamed = append(renamed, fn)
+		}
+	}
+	fmt.Printf("\nFunctions: %d changed, %d added, %d removed, %d renamed\n", functionCounts[metrics.DiffChanged],
+		functionCounts[metrics.DiffAdded], functionCounts[metrics.DiffRemoved], functionCounts[metrics.DiffRenamed])
+	for i, fn := range renamed {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(renamed)-top)
+			break
+		}
+		fmt.Printf("  %s: %s -> %s\n", fn.FilePath, fn.OldName, fn.Name)
+	}
+
+	fmt.Printf("\nRegressed functions (%d):\n", len(comparison.Regressions))
+	if len(comparison.Regressions) == 0 {
+		fmt.Println("  No function got more complex or accrued debt.")
+	}
+	for i, fn := range comparison.Regressions {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(comparison.Regressions)-top)
+			break
+		}
+		fmt.Printf("  %-50s %s\n", fmt.Sprintf("%s:%d", fn.FilePath, fn.After.Line), fn.Name)
+		fmt.Printf("  %-50s %s\n", "", functionChange(fn))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/engine"
+)
+
+// runCoupling reports file pairs that change together in the git history
+func runCoupling(args []string) error {
+	config := engine.DefaultConfig()
+
+	flags := flag.NewFlagSet("coupling", flag.ContinueOnError)
+	flags.IntVar(&config.HistoryWindowDays, "window", config.HistoryWindowDays, "days of history to analyze; 0 for all history")
+	flags.IntVar(&config.CouplingMinSharedCommits, "min-shared", config.CouplingMinSharedCommits, "minimum commits a pair must share")
+	flags.Float64Var(&config.CouplingMinStrength, "min-strength", config.CouplingMinStrength, "minimum coupling strength in percent")
+	top := flags.Int("top", 20, "number of pairs to show; 0 for all")
+	asJSON := flags.Bool("json", false, "print the full result as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: codebasereader coupling [flags] [path]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	coupling, err := newApplication(config).GetEngine().AnalyzeTemporalCoupling(root)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(coupling)
+	}
+
+	window := "all history"
+	if config.HistoryWindowDays > 0 {
+		window = fmt.Sprintf("last %d days", config.HistoryWindowDays)
+	}
+	fmt.Printf("Temporal coupling in %s (%d commits, %s)\n\n", root, coupling.CommitsAnalyzed, window)
+
+	if len(coupling.Pairs) == 0 {
+		fmt.Println("No file pairs above the coupling thresholds.")
+		return nil
+	}
+
+	fmt.Printf("%8s  %6s  %-6s  %s\n", "STRENGTH", "SHARED", "IMPORT", "FILES")
+	for i, pair := range coupling.Pairs {
+		if *top > 0 && i >= *top {
+			fmt.Printf("... and %d more pairs\n", len(coupling.Pairs)-*top)
+			break
+		}
+		imports := "no"
+		if pair.HasDependency {
+			imports = "yes"
+		}
+		fmt.Printf("%7.0f%%  %6d  %-6s  %s <-> %s\n", pair.Strength, pair.SharedCommits, imports,
+			relativePath(root, pair.FileA), relativePath(root, pair.FileB))
+	}
+
+	if len(coupling.Clusters) > 0 {
+		fmt.Println("\nClusters:")
+		for i, cluster := range coupling.Clusters {
+			files := make([]string, len(cluster))
+			for j, file := range cluster {
+				files[j] = relativePath(root, file)
+			}
+			fmt.Printf("%3d. %s\n", i+1, strings.Join(files, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// printRevisionDiff prints what a change did: the net change of the changed
+// files, and the functions and classes it added, removed or changed
+func printRevisionDiff(diff *metrics.RevisionDiff, top int) {
+	fmt.Printf("Changes in %s (%.8s..%.8s): %d files\n", diff.Range, diff.Base, diff.Head, len(diff.Files))
+	if len(diff.Files) == 0 {
+		fmt.Println("  No supported files changed.")
+		return
+	}
+
+	before, after := diff.Before, diff.After
+	fmt.Println("\nNet change:")
+	printIntChange("Lines", before.Lines, after.Lines)
+	printIntChange("Code lines", before.CodeLines, after.CodeLines)
+	printIntChange("Functions", before.Functions, after.Functions)
+	printIntChange("Classes", before.Classes, after.Classes)
+	printIntChange("Complexity", before.Complexity, after.Complexity)
+	printFloatChange("Avg complexity", before.AverageComplexity, after.AverageComplexity)
+	printFloatChange("Technical debt", before.TechnicalDebt, after.TechnicalDebt)
+	printFloatChange("Maintainability", before.MaintainabilityIndex, after.MaintainabilityIndex)
+
+	fmt.Printf("\nFiles (%d):\n", len(diff.Files))
+	for i, file := range diff.Files {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(diff.Files)-top)
+			break
+		}
+		var from, to metrics.FileSnapshot
+		if file.Before != nil {
+			from = *file.Before
+		}
+		if file.After != nil {
+			to = *file.After
+		}
+		name := file.Path
+		if file.Status == git.StatusRenamed {
+			name = file.OldPath + " -> " + file.Path
+		}
+		if file.Skipped != "" {
+			fmt.Printf("  %-9s %-50s not analyzed: %s\n", file.Status, name, file.Skipped)
+			continue
+		}
+		fmt.Printf("  %-9s %-50s lines %s  complexity %s  debt %s\n", file.Status, name,
+			signedInt(to.Lines-from.Lines), signedInt(to.Complexity-from.Complexity), signedFloat(to.TechnicalDebt-from.TechnicalDebt))
+	}
+
+	fmt.Printf("\nFunctions (%d):\n", len(diff.Functions))
+	for i, fn := range diff.Functions {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(diff.Functions)-top)
+			break
+		}
+		name := fn.Name
+		if fn.OldName != "" {
+			name = fn.OldName + " -> " + fn.Name
+		}
+		fmt.Printf("  %-8s %-50s %s\n", fn.Status, fmt.Sprintf("%s:%d", fn.FilePath, functionLine(fn)), name)
+		fmt.Printf("           %s\n", functionChange(fn))
+	}
+
+	if len(diff.Classes) > 0 {
+		fmt.Printf("\nClasses (%d):\n", len(diff.Classes))
+		for i, class := range diff.Classes {
+			if top > 0 && i >= top {
+				fmt.Printf("  ... and %d more\n", len(diff.Classes)-top)
+				break
+			}
+			var from, to metrics.ClassSnapshot
+			if class.Before != nil {
+				from = *class.Before
+			}
+			if class.After != nil {
+				to = *class.After
+			}
+			fmt.Printf("  %-8s %-50s methods %s  lines %s  complexity %s\n", class.Status, class.FilePath+"  "+class.Name,
+				signedInt(to.Methods-from.Methods), signedInt(to.LinesOfCode-from.LinesOfCode), signedInt(to.Complexity-from.Complexity))
+		}
+	}
+}
+
+// functionLine returns the line of a function at the head revision, or at the
+// base revision when it was removed
+func functionLine(fn metrics.FunctionDelta) int {
+	if fn.After != nil {
+		return fn.After.Line
+	}
+	return fn.Before.Line
+}
+
+// functionChange describes a function's metrics, with the change for functions
+// present at both revisions
+func functionChange(fn metrics.FunctionDelta) string {
+	if fn.Before == nil || fn.After == nil {
+		s := fn.After
+		if s == nil {
+			s = fn.Before
+		}
+		return fmt.Sprintf("complexity %d, %d lines, %d params, debt %.1f", s.Complexity, s.LinesOfCode, s.Parameters, s.TechnicalDebt)
+	}
+
+	var parts []string
+	from, to := fn.Before, fn.After
+	if from.Complexity != to.Complexity {
+		parts = append(parts, fmt.Sprintf("complexity %d -> %d (%s)", from.Complexity, to.Complexity, signedInt(to.Complexity-from.Complexity)))
+	}
+	if from.LinesOfCode != to.LinesOfCode {
+		parts = append(parts, fmt.Sprintf("lines %d -> %d (%s)", from.LinesOfCode, to.LinesOfCode, signedInt(to.LinesOfCode-from.LinesOfCode)))
+	}
+	if from.Parameters != to.Parameters {
+		parts = append(parts, fmt.Sprintf("params %d -> %d", from.Parameters, to.Parameters))
+	}
+	if from.TechnicalDebt != to.TechnicalDebt {
+		parts = append(parts, fmt.Sprintf("debt %.1f -> %.1f", from.TechnicalDebt, to.TechnicalDebt))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printIntChange prints a total at both revisions and its change
+func printIntChange(label string, before, after int) {
+	fmt.Printf("  %-16s %8s  (%d -> %d)\n", label, signedInt(after-before), before, after)
+}
+
+// printFloatChange prints an average or debt at both revisions and its change
+func printFloatChange(label string, before, after float64) {
+	fmt.Printf("  %-16s %8s  (%.1f -> %.1f)\n", label, signedFloat(after-before), before, after)
+}
+
+// signedInt formats a change with its sign
+func signedInt(n int) string {
+	return fmt.Sprintf("%+d", n)
+}
+
+// signedFloat formats a change with its sign and one decimal
+func signedFloat(f float64) string {
+	return fmt.Sprintf("%+.1f", f)
+}
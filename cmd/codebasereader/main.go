@@ -3,22 +3,95 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
 	app "github.com/tito-sala/codebasereaderv2/internal/core"
 	"github.com/tito-sala/codebasereaderv2/internal/engine"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
 )
 
 func main() {
+	if len(os.Args) < 2 {
+		printInfo()
+		return
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "analyze":
+		err = runAnalyze(os.Args[2:])
+	case "coupling":
+		err = runCoupling(os.Args[2:])
+	case "compare":
+		err = runCompare(os.Args[2:])
+	case "trends":
+		err = runTrends(os.Args[2:])
+	case "report":
+		err = runReport(os.Args[2:])
+	case "help", "-h", "--help":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printUsage lists the available commands
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: codebasereader [command] [flags] [path]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  analyze    Report project metrics and unreferenced code")
+	fmt.Fprintln(os.Stderr, "  coupling   Report files that change together in git history")
+	fmt.Fprintln(os.Stderr, "  compare    Compare two analyses saved with analyze -json")
+	fmt.Fprintln(os.Stderr, "  trends     Chart the metrics recorded with analyze -record over time")
+	fmt.Fprintln(os.Stderr, "  report     Export an analysis as an HTML report, Markdown summary or SARIF log")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run without a command to show the configuration and supported languages.")
+}
+
+// newApplication creates an application with all language parsers registered
+func newApplication(config *engine.Config) *app.Application {
+	application := app.NewApplication(config)
+	registry := application.GetEngine().GetParserRegistry()
+
+	application.RegisterParser(parser.NewGoParser())
+	application.RegisterParser(parser.NewPythonParser())
+	application.RegisterParser(parser.NewSQLParser())
+	application.RegisterParser(parser.NewJavaScriptParser())
+	application.RegisterParser(parser.NewComponentParser(registry))
+
+	return application
+}
+
+// relativePath returns path relative to root for display, or path itself when
+// it is not under root
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// printInfo displays the configuration and registered languages
+func printInfo() {
 	fmt.Println("CodebaseReader v2 – Initializing...")
 
 	// Create application with default configuration
 	config := engine.DefaultConfig()
-	app := app.NewApplication(config)
+	app := newApplication(config)
 
 	// Validate setup
 	if err := app.ValidateSetup(); err != nil {
 		log.Printf("Setup validation failed: %v", err)
-		log.Println("Note: No parsers registered yet. This is expected at this stage.")
 	}
 
 	// Display configuration
@@ -28,7 +101,7 @@ func main() {
 	fmt.Printf("  AI Provider: %s\n", config.AIProvider)
 	fmt.Printf("  Exclude Patterns: %v\n", config.ExcludePatterns)
 
-	// Display supported languages (will be empty until parsers are registered)
+	// Display supported languages
 	languages := app.GetSupportedLanguages()
 	fmt.Printf("Supported Languages: %d registered\n", len(languages))
 	for lang, exts := range languages {
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
+	"github.com/tito-sala/codebasereaderv2/internal/engine"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/report"
+)
+
+// runReport exports an analysis as a document to share outside the terminal
+func runReport(args []string) error {
+	config := engine.DefaultConfig()
+
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	format := flags.String("format", "html", "report format: html, markdown or sarif")
+	output := flags.String("o", "", "file to write the report to; standard output when empty")
+	from := flags.String("from", "", "analysis saved with analyze -json to report instead of analyzing the path")
+	base := flags.String("base", "", "with -format markdown, analysis saved with analyze -json to report the changes since, such as one of the target branch")
+	maxLength := flags.Int("max-length", report.DefaultMarkdownLength, "with -format markdown, maximum length in bytes; 0 for no limit")
+	top := flags.Int("top", 10, "with -format markdown, number of functions to show per section; 0 for all")
+	complexityWarning := flags.Int("complexity-warning", report.DefaultComplexityWarning, "with -format sarif, cyclomatic complexity over which functions are warnings")
+	complexityError := flags.Int("complexity-error", report.DefaultComplexityError, "with -format sarif, cyclomatic complexity over which functions are errors")
+	flags.StringVar(&config.ProjectConfig, "config", config.ProjectConfig, "project config file with architecture rules and quality gates, relative to the analyzed path")
+	flags.IntVar(&config.HistoryWindowDays, "window", config.HistoryWindowDays, "days of git history to analyze; 0 for all history")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: codebasereader report [flags] [path]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var write func(io.Writer, *metrics.EnhancedProjectAnalysis) error
+	switch *format {
+	case "html":
+		write = report.WriteHTML
+	case "markdown":
+		var baseAnalysis *metrics.EnhancedProjectAnalysis
+		if *base != "" {
+			loaded, err := compare.Load(*base)
+			if err != nil {
+				return err
+			}
+			baseAnalysis = loaded
+		}
+		options := report.MarkdownOptions{MaxLength: *maxLength, Top: *top}
+		write = func(w io.Writer, analysis *metrics.EnhancedProjectAnalysis) error {
+			return report.WriteMarkdown(w, baseAnalysis, analysis, options)
+		}
+	case "sarif":
+		options := report.SARIFOptions{ComplexityWarning: *complexityWarning, ComplexityError: *complexityError}
+		write = func(w io.Writer, analysis *metrics.EnhancedProjectAnalysis) error {
+			return report.WriteSARIF(w, analysis, options)
+		}
+	default:
+		return fmt.Errorf("unknown report format %q", *format)
+	}
+
+	analysis, err := loadOrAnalyze(config, *from, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return write(os.Stdout, analysis)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := write(file, analysis); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s report to %s\n", *format, *output)
+	return nil
+}
+
+// loadOrAnalyze reads a saved analysis when from is set, and otherwise analyzes
+// path, the current directory when empty
+func loadOrAnalyze(config *engine.Config, from, path string) (*metrics.EnhancedProjectAnalysis, error) {
+	if from != "" {
+		return compare.Load(from)
+	}
+
+	if path == "" {
+		path = "."
+	}
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return newApplication(config).GetEngine().AnalyzeDirectoryWithEnhancedMetrics(root)
+}
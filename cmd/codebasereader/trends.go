@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/engine"
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/trends"
+)
+
+// runTrends shows how the metrics recorded by "analyze -record" evolved
+func runTrends(args []string) error {
+	config := engine.DefaultConfig()
+
+	flags := flag.NewFlagSet("trends", flag.ContinueOnError)
+	flags.StringVar(&config.Trends, "store", config.Trends, "trend store file, relative to the analyzed path")
+	dir := flags.String("dir", "", "show the trends of one directory, relative to the analyzed path")
+	last := flags.Int("last", 30, "number of most recent snapshots to chart; 0 for all")
+	top := flags.Int("top", 10, "number of directories to show, most complex first; 0 for all")
+	asJSON := flags.Bool("json", false, "print the snapshots as JSON")
+	backfill := flags.Bool("backfill", false, "record snapshots of past commits before showing the trends")
+	every := flags.Int("every", 10, "with -backfill, analyze every Nth commit on the first-parent history")
+	weekly := flags.Bool("weekly", false, "with -backfill, analyze the newest commit of each week instead")
+	window := flags.Int("window", 365, "with -backfill, days of history to analyze; 0 for all history")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: codebasereader trends [flags] [path]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	analysisEngine := newApplication(config).GetEngine()
+	if *backfill {
+		if err := runBackfill(analysisEngine, root, *every, *weekly, *window); err != nil {
+			return err
+		}
+	}
+
+	snapshots, err := analysisEngine.LoadTrends(root)
+	if err != nil {
+		return err
+	}
+	if *last > 0 && len(snapshots) > *last {
+		snapshots = snapshots[len(snapshots)-*last:]
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(snapshots)
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots in %s. Record runs with \"codebasereader analyze -record\".\n", analysisEngine.TrendStore(root).Path())
+		return nil
+	}
+	first, latest := snapshots[0], snapshots[len(snapshots)-1]
+	fmt.Printf("Trends of %s: %d snapshots from %s to %s\n", root, len(snapshots),
+		first.Timestamp.Format("2006-01-02 15:04"), latest.Timestamp.Format("2006-01-02 15:04"))
+	fmt.Printf("Grade: %s -> %s\n\n", first.QualityScore.Grade, latest.QualityScore.Grade)
+
+	if *dir != "" {
+		rel := filepath.ToSlash(filepath.Clean(*dir))
+		fmt.Printf("Directory %s:\n", rel)
+		for _, metric := range trends.DirectoryMetrics {
+			printTrend(metric, trends.DirectorySeries(snapshots, rel, metric))
+		}
+		return nil
+	}
+
+	fmt.Println("Project:")
+	for _, metric := range trends.ProjectMetrics {
+		printTrend(metric, trends.Series(snapshots, metric))
+	}
+	printDirectoryTrends(snapshots, *top)
+	return nil
+}
+
+// runBackfill records snapshots of past commits, reporting progress on stderr
+// so it does not mix with the trends or their JSON
+func runBackfill(analysisEngine *engine.Engine, root string, every int, weekly bool, window int) error {
+	options := engine.BackfillOptions{Every: every, Weekly: weekly}
+	if window > 0 {
+		options.Since = time.Now().AddDate(0, 0, -window)
+	}
+
+	summary, err := analysisEngine.BackfillTrends(root, options, func(done, total int, commit git.Commit) {
+		fmt.Fprintf(os.Stderr, "[%d/%d] %.12s %s\n", done+1, total, commit.Hash, commit.Time.Format("2006-01-02"))
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Backfilled %d of %d selected commits; %d were already recorded\n\n", summary.Recorded, summary.Selected, summary.Skipped)
+	return nil
+}
+
+// printTrend prints the first and latest value of a metric with its sparkline
+func printTrend(metric string, points []trends.Point) {
+	if len(points) == 0 {
+		fmt.Printf("  %-20s no data\n", metric)
+		return
+	}
+	values := trends.Values(points)
+	first, latest := values[0], values[len(values)-1]
+	fmt.Printf("  %-20s %10.1f -> %10.1f  %s  %s\n", metric, first, latest, signedFloat(latest-first), trends.Sparkline(values, 0))
+}
+
+// printDirectoryTrends prints the complexity and debt sparklines of the most
+// complex directories
+func printDirectoryTrends(snapshots []trends.Snapshot, top int) {
+	dirs := trends.Directories(snapshots)
+	fmt.Printf("\nDirectories (%d):\n", len(dirs))
+	width := max(len(snapshots), 3)
+	fmt.Printf("  %-40s  %-*s  %s\n", "DIRECTORY", width+7, "COMPLEXITY", "DEBT")
+	for i, dir := range dirs {
+		if top > 0 && i >= top {
+			fmt.Printf("  ... and %d more\n", len(dirs)-top)
+			break
+		}
+		complexity := trends.Values(trends.DirectorySeries(snapshots, dir, trends.Complexity))
+		debt := trends.Values(trends.DirectorySeries(snapshots, dir, trends.TechnicalDebt))
+		fmt.Printf("  %-40s  %6.0f %-*s  %6.1f %s\n", dir, complexity[len(complexity)-1], width, trends.Sparkline(complexity, 0),
+			debt[len(debt)-1], trends.Sparkline(debt, 0))
+	}
+}
@@ -0,0 +1,349 @@
+package callgraph
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Node is a function or method in the call graph
+type Node struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`    // Type.Method or function name
+	Package  string   `json:"package"` // package directory (Go) or module path (Python), relative to the root
+	FilePath string   `json:"file_path"`
+	Line     int      `json:"line"`
+	Language string   `json:"language"`
+	IsPublic bool     `json:"is_public"`
+	Callers  []string `json:"callers,omitempty"`
+	Callees  []string `json:"callees,omitempty"`
+}
+
+// FanIn returns the number of distinct functions that call this one
+func (n *Node) FanIn() int {
+	return len(n.Callers)
+}
+
+// FanOut returns the number of distinct functions this one calls
+func (n *Node) FanOut() int {
+	return len(n.Callees)
+}
+
+// Graph is the project-wide call graph. Calls to code outside the project, and
+// calls whose target can't be told apart, are counted but not linked.
+type Graph struct {
+	Nodes      map[string]*Node `json:"nodes"`
+	Calls      int              `json:"calls"`      // call sites resolved to a project function
+	Unresolved int              `json:"unresolved"` // call sites that could not be resolved
+}
+
+// Build resolves the call sites recorded by the parsers into a call graph.
+// Go calls are resolved through the types the parser inferred; Python calls are
+// resolved by module and class name on a best-effort basis. A method call on a
+// value of unknown type is linked when exactly one method in the project has
+// that name.
+func Build(results []*parser.AnalysisResult, rootPath string) *Graph {
+	b := &builder{
+		graph:   &Graph{Nodes: make(map[string]*Node)},
+		root:    rootPath,
+		scopes:  make(map[string]map[string]string),
+		methods: make(map[string][]string),
+		classes: make(map[string][]string),
+	}
+
+	for _, result := range results {
+		for _, fn := range result.Functions {
+			b.add(result, fn.Receiver, fn)
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				b.add(result, class.Name, method)
+			}
+		}
+	}
+
+	edges := make(map[[2]string]bool)
+	for _, result := range results {
+		scope := b.scopeOf(result)
+		resolveAll := func(owner string, fn parser.FunctionInfo) {
+			caller := b.id(scope, owner, fn.Name)
+			for _, call := range fn.Calls {
+				callee := b.resolve(result, scope, call)
+				if callee == "" {
+					b.graph.Unresolved++
+					continue
+				}
+				b.graph.Calls++
+				edges[[2]string{caller, callee}] = true
+			}
+		}
+		for _, fn := range result.Functions {
+			resolveAll(fn.Receiver, fn)
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				resolveAll(class.Name, method)
+			}
+		}
+	}
+
+	for edge := range edges {
+		caller, callee := b.graph.Nodes[edge[0]], b.graph.Nodes[edge[1]]
+		if caller == nil || callee == nil {
+			continue
+		}
+		caller.Callees = append(caller.Callees, callee.ID)
+		callee.Callers = append(callee.Callers, caller.ID)
+	}
+	for _, node := range b.graph.Nodes {
+		sort.Strings(node.Callers)
+		sort.Strings(node.Callees)
+	}
+
+	return b.graph
+}
+
+// Sorted returns all nodes ordered by ID
+func (g *Graph) Sorted() []*Node {
+	nodes := make([]*Node, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+// MostCalled returns up to limit nodes with the highest fan-in
+func (g *Graph) MostCalled(limit int) []*Node {
+	return g.top(limit, (*Node).FanIn)
+}
+
+// LargestFanOut returns up to limit nodes with the highest fan-out
+func (g *Graph) LargestFanOut(limit int) []*Node {
+	return g.top(limit, (*Node).FanOut)
+}
+
+// top returns up to limit nodes with a positive metric, highest first
+func (g *Graph) top(limit int, metric func(*Node) int) []*Node {
+	var nodes []*Node
+	for _, node := range g.Sorted() {
+		if metric(node) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return metric(nodes[i]) > metric(nodes[j])
+	})
+	if len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	return nodes
+}
+
+// builder indexes the project's functions for call resolution
+type builder struct {
+	graph   *Graph
+	root    string
+	scopes  map[string]map[string]string // package or module -> Type.Name or Name -> node ID
+	methods map[string][]string          // language and method name -> node IDs, for calls on values of unknown type
+	classes map[string][]string          // language and type name -> scopes declaring a method on it
+}
+
+// add creates the node for a function or a method of owner
+func (b *builder) add(result *parser.AnalysisResult, owner string, fn parser.FunctionInfo) {
+	scope := b.scopeOf(result)
+	name := strings.TrimPrefix(fn.Name, "async ")
+	key := name
+	if owner != "" {
+		key = owner + "." + name
+	}
+
+	id := b.id(scope, owner, fn.Name)
+	b.graph.Nodes[id] = &Node{
+		ID:       id,
+		Name:     key,
+		Package:  scope,
+		FilePath: result.FilePath,
+		Line:     fn.LineStart,
+		Language: result.Language,
+		IsPublic: fn.IsPublic,
+	}
+
+	if b.scopes[scope] == nil {
+		b.scopes[scope] = make(map[string]string)
+	}
+	b.scopes[scope][key] = id
+	if owner != "" {
+		method, class := result.Language+"\x00"+name, result.Language+"\x00"+owner
+		b.methods[method] = append(b.methods[method], id)
+		if !slices.Contains(b.classes[class], scope) {
+			b.classes[class] = append(b.classes[class], scope)
+		}
+	}
+}
+
+// id returns the node ID of a function: its scope, owner type and name
+func (b *builder) id(scope, owner, name string) string {
+	name = strings.TrimPrefix(name, "async ")
+	if owner != "" {
+		name = owner + "." + name
+	}
+	return scope + "." + name
+}
+
+// scopeOf returns the name lookup scope of a file: its package directory in Go,
+// where all files of a directory share a namespace, and its module elsewhere
+func (b *builder) scopeOf(result *parser.AnalysisResult) string {
+	rel := result.FilePath
+	if r, err := filepath.Rel(b.root, result.FilePath); err == nil {
+		rel = r
+	}
+	rel = filepath.ToSlash(rel)
+	if result.Language == "Go" {
+		return path.Dir(rel)
+	}
+	return strings.TrimSuffix(rel, path.Ext(rel))
+}
+
+// resolve returns the node ID a call site refers to, or an empty string
+func (b *builder) resolve(result *parser.AnalysisResult, scope string, call parser.CallSite) string {
+	switch result.Language {
+	case "Go":
+		return b.resolveGo(scope, call)
+	case "Python":
+		return b.resolvePython(scope, call)
+	}
+	return ""
+}
+
+// resolveGo resolves a call using the package and receiver type the parser inferred
+func (b *builder) resolveGo(scope string, call parser.CallSite) string {
+	const language = "Go"
+	target := scope
+	if call.Package != "" {
+		target = b.matchScope(call.Package, "/", false)
+		if target == "" {
+			return ""
+		}
+	}
+
+	switch {
+	case call.Type != "":
+		if id := b.scopes[target][call.Type+"."+call.Name]; id != "" {
+			return id
+		}
+		// Promoted from an embedded type or declared elsewhere
+		return b.uniqueMethod(language, call.Name)
+	case call.Method:
+		return b.uniqueMethod(language, call.Name)
+	default:
+		return b.scopes[target][call.Name]
+	}
+}
+
+// resolvePython resolves a call by module, class and function name
+func (b *builder) resolvePython(scope string, call parser.CallSite) string {
+	const language = "Python"
+	switch {
+	case call.Package != "":
+		module := b.matchModule(scope, call.Package)
+		if module != "" {
+			if id := b.lookup(module, call.Name); id != "" {
+				return id
+			}
+		}
+		// pkg.module.Class.method()
+		if dot := strings.LastIndex(call.Package, "."); dot > 0 {
+			if module := b.matchModule(scope, call.Package[:dot]); module != "" {
+				return b.scopes[module][call.Package[dot+1:]+"."+call.Name]
+			}
+		}
+		return ""
+	case call.Type != "":
+		if id := b.scopes[scope][call.Type+"."+call.Name]; id != "" {
+			return id
+		}
+		// A class declared once in the project
+		if scopes := b.classes["Python\x00"+call.Type]; len(scopes) == 1 {
+			if id := b.scopes[scopes[0]][call.Type+"."+call.Name]; id != "" {
+				return id
+			}
+		}
+		return b.uniqueMethod(language, call.Name)
+	case call.Method:
+		return b.uniqueMethod(language, call.Name)
+	default:
+		return b.lookup(scope, call.Name)
+	}
+}
+
+// lookup finds a function in a module, or the constructor of a class named name
+func (b *builder) lookup(scope, name string) string {
+	if id := b.scopes[scope][name]; id != "" {
+		return id
+	}
+	return b.scopes[scope][name+".__init__"]
+}
+
+// uniqueMethod returns the only method of the language in the project with the given name
+func (b *builder) uniqueMethod(language, name string) string {
+	if ids := b.methods[language+"\x00"+name]; len(ids) == 1 {
+		return ids[0]
+	}
+	return ""
+}
+
+// matchModule resolves a Python module name, relative ones (.models, ..util)
+// from the calling module's package
+func (b *builder) matchModule(scope, module string) string {
+	if strings.HasPrefix(module, ".") {
+		joined := RelativeModulePath(path.Dir(scope), module)
+		if b.scopes[joined] != nil {
+			return joined
+		}
+		if b.scopes[joined+"/__init__"] != nil {
+			return joined + "/__init__"
+		}
+		return ""
+	}
+	if match := b.matchScope(module, ".", true); match != "" {
+		return match
+	}
+	return b.matchScope(module+".__init__", ".", true)
+}
+
+// RelativeModulePath returns the slash-separated path a Python relative module
+// name (.models, ..util.io) refers to from the directory of the importing
+// module: the first dot is that directory and each further dot its parent.
+func RelativeModulePath(dir, module string) string {
+	trimmed := strings.TrimLeft(module, ".")
+	for i := 1; i < len(module)-len(trimmed); i++ {
+		dir = path.Dir(dir)
+	}
+	return path.Join(dir, strings.ReplaceAll(trimmed, ".", "/"))
+}
+
+// matchScope finds the scope a package or module name refers to. A scope
+// matches when its path equals the name or is a suffix of it, so Go import paths
+// resolve; with either set the name may also be a suffix of the scope, so Python
+// modules under a source root resolve. The longest match wins.
+func (b *builder) matchScope(name, separator string, either bool) string {
+	name = strings.ReplaceAll(name, separator, "/")
+	best := ""
+	for scope := range b.scopes {
+		matches := scope == name || strings.HasSuffix(name, "/"+scope) || (either && strings.HasSuffix(scope, "/"+name))
+		if scope == "." || !matches {
+			continue
+		}
+		if len(scope) > len(best) || (len(scope) == len(best) && scope < best) {
+			best = scope
+		}
+	}
+	return best
+}
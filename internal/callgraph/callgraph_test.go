@@ -0,0 +1,149 @@
+package callgraph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestBuildGo(t *testing.T) {
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "/p/cmd/app/main.go",
+			Language: "Go",
+			Functions: []parser.FunctionInfo{
+				{Name: "main", LineStart: 5, Calls: []parser.CallSite{
+					{Name: "NewStore", Package: "example.com/p/internal/store", Line: 6},
+					{Name: "Save", Type: "Store", Package: "example.com/p/internal/store", Method: true, Line: 7},
+					{Name: "run", Line: 8},
+					{Name: "Println", Package: "fmt", Line: 9},
+				}},
+				{Name: "run", LineStart: 12, Calls: []parser.CallSite{
+					{Name: "Flush", Method: true, Line: 13},
+					{Name: "Save", Method: true, Line: 14},
+				}},
+			},
+		},
+		{
+			FilePath: "/p/internal/store/store.go",
+			Language: "Go",
+			Functions: []parser.FunctionInfo{
+				{Name: "NewStore", IsPublic: true},
+				{Name: "Save", Receiver: "Store", IsPublic: true, Calls: []parser.CallSite{
+					{Name: "Flush", Type: "Store", Method: true},
+					{Name: "Flush", Type: "Store", Method: true},
+				}},
+				{Name: "Flush", Receiver: "Store", IsPublic: true},
+			},
+		},
+		{
+			// A second Save makes calls on values of unknown type ambiguous
+			FilePath:  "/p/internal/cache/cache.go",
+			Language:  "Go",
+			Functions: []parser.FunctionInfo{{Name: "Save", Receiver: "Cache"}},
+		},
+	}
+
+	graph := Build(results, "/p")
+
+	if len(graph.Nodes) != 6 {
+		t.Fatalf("Expected 6 nodes, got %d", len(graph.Nodes))
+	}
+	main := graph.Nodes["cmd/app.main"]
+	if main == nil {
+		t.Fatal("Expected a node for main")
+	}
+	expected := []string{"cmd/app.run", "internal/store.NewStore", "internal/store.Store.Save"}
+	if !reflect.DeepEqual(main.Callees, expected) {
+		t.Errorf("Expected main to call %v, got %v", expected, main.Callees)
+	}
+
+	// Flush is the only method with that name; Save is ambiguous
+	run := graph.Nodes["cmd/app.run"]
+	if !reflect.DeepEqual(run.Callees, []string{"internal/store.Store.Flush"}) {
+		t.Errorf("Expected run to call only Store.Flush, got %v", run.Callees)
+	}
+
+	flush := graph.Nodes["internal/store.Store.Flush"]
+	if flush.FanIn() != 2 || flush.Name != "Store.Flush" || flush.Package != "internal/store" {
+		t.Errorf("Expected Store.Flush called by 2 functions, got %+v", flush)
+	}
+
+	if graph.Calls != 6 || graph.Unresolved != 2 {
+		t.Errorf("Expected 6 resolved and 2 unresolved calls, got %d and %d", graph.Calls, graph.Unresolved)
+	}
+
+	mostCalled := graph.MostCalled(1)
+	if len(mostCalled) != 1 || mostCalled[0].ID != "internal/store.Store.Flush" {
+		t.Errorf("Expected Store.Flush to be the most called, got %v", mostCalled)
+	}
+	if fanOut := graph.LargestFanOut(10); len(fanOut) != 3 || fanOut[0].ID != "cmd/app.main" {
+		t.Errorf("Expected main to have the largest fan-out, got %v", fanOut)
+	}
+}
+
+func TestBuildPython(t *testing.T) {
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "/p/src/app/models.py",
+			Language: "Python",
+			Classes: []parser.ClassInfo{{
+				Name: "User",
+				Methods: []parser.FunctionInfo{
+					{Name: "__init__"},
+					{Name: "save", Calls: []parser.CallSite{{Name: "validate", Type: "User", Method: true}}},
+					{Name: "validate"},
+				},
+			}},
+		},
+		{
+			FilePath: "/p/src/app/views.py",
+			Language: "Python",
+			Functions: []parser.FunctionInfo{
+				{Name: "create", Calls: []parser.CallSite{
+					{Name: "User", Package: ".models"},
+					{Name: "save", Method: true},
+					{Name: "render"},
+					{Name: "dumps", Package: "json"},
+				}},
+				{Name: "async render"},
+			},
+		},
+		{
+			FilePath: "/p/tests/test_views.py",
+			Language: "Python",
+			Functions: []parser.FunctionInfo{
+				{Name: "test_create", Calls: []parser.CallSite{
+					{Name: "create", Package: "app.views"},
+					{Name: "validate", Package: "app.models.User"},
+				}},
+			},
+		},
+	}
+
+	graph := Build(results, "/p")
+
+	create := graph.Nodes["src/app/views.create"]
+	if create == nil {
+		t.Fatal("Expected a node for create")
+	}
+	expected := []string{"src/app/models.User.__init__", "src/app/models.User.save", "src/app/views.render"}
+	if !reflect.DeepEqual(create.Callees, expected) {
+		t.Errorf("Expected create to call %v, got %v", expected, create.Callees)
+	}
+
+	test := graph.Nodes["tests/test_views.test_create"]
+	expected = []string{"src/app/models.User.validate", "src/app/views.create"}
+	if !reflect.DeepEqual(test.Callees, expected) {
+		t.Errorf("Expected the test to call %v, got %v", expected, test.Callees)
+	}
+
+	validate := graph.Nodes["src/app/models.User.validate"]
+	if !reflect.DeepEqual(validate.Callers, []string{"src/app/models.User.save", "tests/test_views.test_create"}) {
+		t.Errorf("Expected validate to be called by save and the test, got %v", validate.Callers)
+	}
+	if graph.Unresolved != 1 {
+		t.Errorf("Expected only json.dumps to be unresolved, got %d", graph.Unresolved)
+	}
+}
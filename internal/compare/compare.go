@@ -0,0 +1,226 @@
+// Package compare diffs two saved analyses of a project, such as the JSON
+// exports of two releases
+package compare
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Statuses of languages and directories between two analyses
+const (
+	Added     = "added"
+	Removed   = "removed"
+	Changed   = "changed"
+	Unchanged = "unchanged"
+)
+
+// Comparison is a structured diff of two analyses of a project
+type Comparison struct {
+	Before Snapshot `json:"before"`
+	After  Snapshot `json:"after"`
+
+	Languages   []LanguageChange  `json:"languages"`
+	Directories []DirectoryChange `json:"directories"`
+	// Files matched by path or, when moved, by similarity, and the functions and
+	// classes added, removed, renamed or changed in them
+	Changes metrics.RevisionDiff `json:"changes"`
+	// Functions that got more complex or accrued debt, worst first
+	Regressions []metrics.FunctionDelta `json:"regressions"`
+
+	ProjectMetrics ProjectMetricsChange `json:"project_metrics"`
+	QualityScore   QualityScoreChange   `json:"quality_score"`
+}
+
+// Snapshot identifies one of the compared analyses
+type Snapshot struct {
+	RootPath    string    `json:"root_path"`
+	GeneratedAt time.Time `json:"generated_at"`
+	TotalFiles  int       `json:"total_files"`
+	TotalLines  int       `json:"total_lines"`
+}
+
+// LanguageChange compares the statistics of a language
+type LanguageChange struct {
+	Language string                `json:"language"`
+	Status   string                `json:"status"`
+	Before   metrics.LanguageStats `json:"before"`
+	After    metrics.LanguageStats `json:"after"`
+}
+
+// DirectoryChange compares the statistics of a directory, by path relative to
+// the root of each analysis
+type DirectoryChange struct {
+	Path      string         `json:"path"`
+	Status    string         `json:"status"`
+	Before    DirectoryTotal `json:"before"`
+	After     DirectoryTotal `json:"after"`
+	Regressed bool           `json:"regressed"` // more complex or less maintainable
+}
+
+// DirectoryTotal holds the compared statistics of a directory
+type DirectoryTotal struct {
+	Files                int     `json:"files"`
+	Lines                int     `json:"lines"`
+	Complexity           int     `json:"complexity"`
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+}
+
+// ProjectMetricsChange holds the project metrics of both analyses
+type ProjectMetricsChange struct {
+	Before metrics.ProjectMetrics `json:"before"`
+	After  metrics.ProjectMetrics `json:"after"`
+}
+
+// QualityScoreChange holds the quality score of both analyses
+type QualityScoreChange struct {
+	Before    metrics.QualityScore `json:"before"`
+	After     metrics.QualityScore `json:"after"`
+	Regressed bool                 `json:"regressed"` // lower overall score
+}
+
+// Compare diffs two analyses of a project
+func Compare(before, after *metrics.EnhancedProjectAnalysis) *Comparison {
+	comparison := &Comparison{
+		Before:         snapshotOf(before),
+		After:          snapshotOf(after),
+		Languages:      compareLanguages(before.Languages, after.Languages),
+		Directories:    compareDirectories(before, after),
+		ProjectMetrics: ProjectMetricsChange{Before: before.ProjectMetrics, After: after.ProjectMetrics},
+		QualityScore: QualityScoreChange{
+			Before:    before.QualityScore,
+			After:     after.QualityScore,
+			Regressed: after.QualityScore.Overall < before.QualityScore.Overall,
+		},
+	}
+
+	comparison.Changes = metrics.CompareRevisions(matchFiles(before, after))
+	comparison.Regressions = []metrics.FunctionDelta{}
+	for _, fn := range comparison.Changes.Functions {
+		if Regressed(fn) {
+			comparison.Regressions = append(comparison.Regressions, fn)
+		}
+	}
+	sort.SliceStable(comparison.Regressions, func(i, j int) bool {
+		return comparison.Regressions[i].ComplexityDelta() > comparison.Regressions[j].ComplexityDelta()
+	})
+
+	return comparison
+}
+
+// Regressed reports whether a function present in both analyses got more
+// complex or accrued technical debt
+func Regressed(fn metrics.FunctionDelta) bool {
+	if fn.Before == nil || fn.After == nil {
+		return false
+	}
+	return fn.After.Complexity > fn.Before.Complexity || fn.After.TechnicalDebt > fn.Before.TechnicalDebt
+}
+
+// snapshotOf identifies an analysis
+func snapshotOf(analysis *metrics.EnhancedProjectAnalysis) Snapshot {
+	return Snapshot{
+		RootPath:    analysis.RootPath,
+		GeneratedAt: analysis.GeneratedAt,
+		TotalFiles:  analysis.TotalFiles,
+		TotalLines:  analysis.TotalLines,
+	}
+}
+
+// compareLanguages pairs the language statistics of both analyses
+func compareLanguages(before, after map[string]metrics.LanguageStats) []LanguageChange {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := []LanguageChange{}
+	for _, name := range sortedKeys(names) {
+		old, hadOld := before[name]
+		current, hasCurrent := after[name]
+		change := LanguageChange{Language: name, Before: old, After: current, Status: statusOf(hadOld, hasCurrent, old == current)}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// compareDirectories pairs directories by their path relative to each root
+func compareDirectories(before, after *metrics.EnhancedProjectAnalysis) []DirectoryChange {
+	totals := func(analysis *metrics.EnhancedProjectAnalysis) map[string]DirectoryTotal {
+		dirs := make(map[string]DirectoryTotal)
+		for dir, stats := range analysis.DirectoryStats {
+			dirs[relativePath(analysis.RootPath, dir)] = DirectoryTotal{
+				Files:                stats.FileCount,
+				Lines:                stats.LineCount,
+				Complexity:           stats.Complexity,
+				MaintainabilityIndex: stats.MaintainabilityIndex,
+			}
+		}
+		return dirs
+	}
+	old, current := totals(before), totals(after)
+
+	paths := make(map[string]bool)
+	for path := range old {
+		paths[path] = true
+	}
+	for path := range current {
+		paths[path] = true
+	}
+
+	changes := []DirectoryChange{}
+	for _, path := range sortedKeys(paths) {
+		from, hadOld := old[path]
+		to, hasCurrent := current[path]
+		change := DirectoryChange{Path: path, Before: from, After: to, Status: statusOf(hadOld, hasCurrent, from == to)}
+		change.Regressed = change.Status == Changed &&
+			(to.Complexity > from.Complexity || to.MaintainabilityIndex < from.MaintainabilityIndex)
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// statusOf classifies an entry by the analyses it occurs in
+func statusOf(hadOld, hasCurrent, same bool) string {
+	switch {
+	case !hadOld:
+		return Added
+	case !hasCurrent:
+		return Removed
+	case same:
+		return Unchanged
+	default:
+		return Changed
+	}
+}
+
+// fileResults returns the parsed files of an analysis
+func fileResults(analysis *metrics.EnhancedProjectAnalysis) []*parser.AnalysisResult {
+	results, _ := analysis.FileResults.([]*parser.AnalysisResult)
+	return results
+}
+
+// relativePath returns a slash-separated path relative to root
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
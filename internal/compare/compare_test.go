@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCompare(t *testing.T) {
+	fn := func(name string, complexity, loc, params int) parser.FunctionInfo {
+		return parser.FunctionInfo{Name: name, LineStart: 1, CyclomaticComplexity: complexity, LinesOfCode: loc, ParameterCount: params}
+	}
+	file := func(path string, lines int, functions ...parser.FunctionInfo) *parser.AnalysisResult {
+		return &parser.AnalysisResult{FilePath: path, Language: "Go", LineCount: lines, Functions: functions}
+	}
+
+	before := &metrics.EnhancedProjectAnalysis{
+		RootPath: "/old",
+		Languages: map[string]metrics.LanguageStats{
+			"Go":     {FileCount: 3, LineCount: 300},
+			"Python": {FileCount: 1, LineCount: 50},
+		},
+		DirectoryStats: map[string]metrics.DirectoryStats{
+			"/old/pkg":  {FileCount: 2, Complexity: 10, MaintainabilityIndex: 70},
+			"/old/util": {FileCount: 1, Complexity: 4, MaintainabilityIndex: 80},
+		},
+		QualityScore: metrics.QualityScore{Overall: 80, Grade: "B"},
+		FileResults: []*parser.AnalysisResult{
+			file("/old/pkg/parse.go", 120, fn("Parse", 6, 30, 2), fn("loadConfig", 4, 20, 1)),
+			file("/old/pkg/same.go", 80, fn("Same", 2, 10, 0)),
+			file("/old/util/strings.go", 100, fn("Trim", 2, 8, 1), fn("Split", 3, 12, 2), fn("Join", 2, 6, 2)),
+		},
+	}
+	after := &metrics.EnhancedProjectAnalysis{
+		RootPath: "/new",
+		Languages: map[string]metrics.LanguageStats{
+			"Go": {FileCount: 3, LineCount: 330},
+		},
+		DirectoryStats: map[string]metrics.DirectoryStats{
+			"/new/pkg":      {FileCount: 2, Complexity: 14, MaintainabilityIndex: 65},
+			"/new/internal": {FileCount: 1, Complexity: 7, MaintainabilityIndex: 80},
+		},
+		QualityScore: metrics.QualityScore{Overall: 75, Grade: "C"},
+		FileResults: []*parser.AnalysisResult{
+			file("/new/pkg/parse.go", 150, fn("Parse", 9, 45, 2), fn("loadSettings", 4, 20, 1)),
+			file("/new/pkg/same.go", 80, fn("Same", 2, 10, 0)),
+			file("/new/internal/text/strings.go", 104, fn("Trim", 2, 8, 1), fn("Split", 3, 12, 2), fn("JoinAll", 2, 6, 3)),
+		},
+	}
+
+	comparison := Compare(before, after)
+
+	if len(comparison.Languages) != 2 || comparison.Languages[0].Status != Changed || comparison.Languages[1].Status != Removed {
+		t.Errorf("Expected Go changed and Python removed, got %+v", comparison.Languages)
+	}
+
+	statuses := make(map[string]DirectoryChange)
+	for _, dir := range comparison.Directories {
+		statuses[dir.Path] = dir
+	}
+	if statuses["internal"].Status != Added || statuses["util"].Status != Removed || !statuses["pkg"].Regressed {
+		t.Errorf("Unexpected directory changes: %+v", comparison.Directories)
+	}
+	if !comparison.QualityScore.Regressed {
+		t.Error("Expected the lower quality score to be a regression")
+	}
+
+	// same.go is unchanged and left out; strings.go moved
+	files := comparison.Changes.Files
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 changed files, got %+v", files)
+	}
+	if files[0].Status != git.StatusRenamed || files[0].OldPath != "util/strings.go" || files[0].Path != "internal/text/strings.go" {
+		t.Errorf("Expected strings.go to be matched as moved, got %+v", files[0])
+	}
+	if files[1].Status != git.StatusModified || files[1].Path != "pkg/parse.go" {
+		t.Errorf("Expected parse.go to be modified, got %+v", files[1])
+	}
+
+	var renamed, added []string
+	for _, fn := range comparison.Changes.Functions {
+		switch fn.Status {
+		case metrics.DiffRenamed:
+			renamed = append(renamed, fn.OldName+"->"+fn.Name)
+		case metrics.DiffAdded:
+			added = append(added, fn.Name)
+		}
+	}
+	if len(renamed) != 1 || renamed[0] != "loadConfig->loadSettings" {
+		t.Errorf("Expected loadConfig to be renamed to loadSettings, got %v", renamed)
+	}
+	// JoinAll takes another parameter, so it is not taken for a rename of Join
+	if len(added) != 1 || added[0] != "JoinAll" {
+		t.Errorf("Expected JoinAll to be added, got %v", added)
+	}
+
+	if len(comparison.Regressions) != 1 || comparison.Regressions[0].Name != "Parse" {
+		t.Errorf("Expected Parse to regress, got %+v", comparison.Regressions)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	analysis := &metrics.EnhancedProjectAnalysis{
+		RootPath:    "/p",
+		TotalFiles:  1,
+		FileResults: []*parser.AnalysisResult{{FilePath: "/p/a.go", Functions: []parser.FunctionInfo{{Name: "main"}}}},
+	}
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "analysis.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	results := fileResults(loaded)
+	if loaded.RootPath != "/p" || len(results) != 1 || results[0].Functions[0].Name != "main" {
+		t.Errorf("Expected the saved analysis back, got %+v with %+v", loaded, results)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected malformed JSON to fail")
+	}
+}
@@ -0,0 +1,35 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Load reads an analysis saved as JSON, such as the output of
+// "codebasereader analyze -json", with its file results decoded
+func Load(path string) (*metrics.EnhancedProjectAnalysis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis: %w", err)
+	}
+
+	// The file results are untyped in the analysis, so decode them separately
+	var document struct {
+		metrics.EnhancedProjectAnalysis
+		FileResults []*parser.AnalysisResult `json:"file_results"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis %s: %w", path, err)
+	}
+
+	if document.FileResults == nil {
+		document.FileResults = []*parser.AnalysisResult{}
+	}
+	analysis := document.EnhancedProjectAnalysis
+	analysis.FileResults = document.FileResults
+	return &analysis, nil
+}
@@ -0,0 +1,152 @@
+package compare
+
+import (
+	"path"
+	"sort"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// minFileSimilarity is how similar a removed and an added file must be to
+// count as one moved file
+const minFileSimilarity = 0.6
+
+// matchFiles pairs the files of two analyses, first by path relative to each
+// root and then, for files only in one of them, by similarity, so that moved
+// files are compared instead of listed as removed and added. Files whose
+// metrics are the same in both are left out.
+func matchFiles(before, after *metrics.EnhancedProjectAnalysis) []metrics.RevisionFile {
+	old := make(map[string]*parser.AnalysisResult)
+	for _, result := range fileResults(before) {
+		old[relativePath(before.RootPath, result.FilePath)] = result
+	}
+	current := make(map[string]*parser.AnalysisResult)
+	for _, result := range fileResults(after) {
+		current[relativePath(after.RootPath, result.FilePath)] = result
+	}
+
+	var files []metrics.RevisionFile
+	var removed, added []string
+	for _, rel := range sortedKeys(keySet(old, current)) {
+		from, hadOld := old[rel]
+		to, hasCurrent := current[rel]
+		switch {
+		case !hadOld:
+			added = append(added, rel)
+		case !hasCurrent:
+			removed = append(removed, rel)
+		case !sameFile(from, to):
+			files = append(files, metrics.RevisionFile{Path: rel, OldPath: rel, Status: git.StatusModified, Before: from, After: to})
+		}
+	}
+
+	// Most similar pairs first, each file matched at most once
+	type candidate struct {
+		from, to   string
+		similarity float64
+	}
+	var candidates []candidate
+	for _, from := range removed {
+		for _, to := range added {
+			if similarity := fileSimilarity(from, to, old[from], current[to]); similarity >= minFileSimilarity {
+				candidates = append(candidates, candidate{from, to, similarity})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	moved := make(map[string]bool)
+	for _, c := range candidates {
+		if moved[c.from] || moved[c.to] {
+			continue
+		}
+		moved[c.from], moved[c.to] = true, true
+		files = append(files, metrics.RevisionFile{Path: c.to, OldPath: c.from, Status: git.StatusRenamed, Before: old[c.from], After: current[c.to]})
+	}
+
+	for _, rel := range removed {
+		if !moved[rel] {
+			files = append(files, metrics.RevisionFile{Path: rel, OldPath: rel, Status: git.StatusDeleted, Before: old[rel]})
+		}
+	}
+	for _, rel := range added {
+		if !moved[rel] {
+			files = append(files, metrics.RevisionFile{Path: rel, Status: git.StatusAdded, After: current[rel]})
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// fileSimilarity scores how likely two files are the same file moved, from the
+// functions and classes they share, their size and their name. Files in
+// different languages never match.
+func fileSimilarity(fromPath, toPath string, from, to *parser.AnalysisResult) float64 {
+	if from.Language != to.Language {
+		return 0
+	}
+
+	size := 1.0
+	if from.LineCount != to.LineCount {
+		size = float64(min(from.LineCount, to.LineCount)) / float64(max(from.LineCount, to.LineCount))
+	}
+	name := 0.0
+	if path.Base(fromPath) == path.Base(toPath) {
+		name = 1
+	}
+
+	fromSymbols, toSymbols := symbolNames(from), symbolNames(to)
+	if len(fromSymbols) == 0 && len(toSymbols) == 0 {
+		return 0.5*size + 0.5*name
+	}
+	shared := 0
+	for symbol := range fromSymbols {
+		if toSymbols[symbol] {
+			shared++
+		}
+	}
+	symbols := float64(shared) / float64(len(fromSymbols)+len(toSymbols)-shared)
+
+	return 0.6*symbols + 0.2*size + 0.2*name
+}
+
+// symbolNames returns the names of the functions, methods and classes of a file
+func symbolNames(result *parser.AnalysisResult) map[string]bool {
+	names := make(map[string]bool)
+	for _, fn := range result.Functions {
+		names[fn.Receiver+"."+fn.Name] = true
+	}
+	for _, class := range result.Classes {
+		names[class.Name] = true
+		for _, method := range class.Methods {
+			names[class.Name+"."+method.Name] = true
+		}
+	}
+	return names
+}
+
+// sameFile reports whether a file has the same size, complexity and debt in
+// both analyses, so it is not worth comparing function by function
+func sameFile(from, to *parser.AnalysisResult) bool {
+	return from.LineCount == to.LineCount && from.CodeLines == to.CodeLines &&
+		from.CyclomaticComplexity == to.CyclomaticComplexity && from.TechnicalDebt == to.TechnicalDebt &&
+		len(from.Functions) == len(to.Functions) && len(from.Classes) == len(to.Classes)
+}
+
+// keySet returns the keys of both maps
+func keySet(a, b map[string]*parser.AnalysisResult) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
@@ -0,0 +1,51 @@
+package coverage
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// knownReportNames are the default output names of common coverage tools
+var knownReportNames = map[string]bool{
+	"coverage.out":           true, // go test -coverprofile
+	"cover.out":              true,
+	"c.out":                  true,
+	"coverage.txt":           true,
+	"coverage.xml":           true, // pytest-cov, coverage.py
+	"cobertura.xml":          true,
+	"cobertura-coverage.xml": true, // istanbul/nyc
+	"lcov.info":              true, // istanbul/nyc, c8, lcov
+	"jacoco.xml":             true, // JaCoCo Maven plugin
+	"jacocotestreport.xml":   true, // JaCoCo Gradle plugin
+}
+
+// Discover finds coverage artifacts under root by their conventional file names.
+// Directories whose base name matches one of excludeDirs are skipped.
+func Discover(root string, excludeDirs []string) []string {
+	exclude := make(map[string]bool)
+	for _, dir := range excludeDirs {
+		exclude[dir] = true
+	}
+
+	var reports []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && exclude[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := strings.ToLower(d.Name())
+		if knownReportNames[name] || strings.HasSuffix(name, ".coverprofile") {
+			reports = append(reports, path)
+		}
+		return nil
+	})
+
+	return reports
+}
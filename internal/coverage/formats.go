@@ -0,0 +1,206 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// parseGoProfile reads a Go coverprofile. Each block line has the form
+// "file.go:startLine.startCol,endLine.endCol numStatements count".
+func (r *Report) parseGoProfile(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return fmt.Errorf("line %d: missing file separator", lineNum)
+		}
+		file := line[:colon]
+
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return fmt.Errorf("line %d: expected block, statements and count", lineNum)
+		}
+		start, end, ok := parseGoBlock(fields[0])
+		if !ok {
+			return fmt.Errorf("line %d: invalid block %q", lineNum, fields[0])
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("line %d: invalid count %q", lineNum, fields[2])
+		}
+
+		for l := start; l <= end; l++ {
+			r.AddLine(file, l, count)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseGoBlock extracts the start and end lines from "start.col,end.col"
+func parseGoBlock(block string) (start, end int, ok bool) {
+	from, to, found := strings.Cut(block, ",")
+	if !found {
+		return 0, 0, false
+	}
+	startLine, _, _ := strings.Cut(from, ".")
+	endLine, _, _ := strings.Cut(to, ".")
+
+	start, err := strconv.Atoi(startLine)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err = strconv.Atoi(endLine)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseLCOV reads an LCOV tracefile (lcov.info). Only SF and DA records are used.
+func (r *Report) parseLCOV(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	current := ""
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = strings.TrimPrefix(line, "SF:")
+		case line == "end_of_record":
+			current = ""
+		case strings.HasPrefix(line, "DA:"):
+			if current == "" {
+				return fmt.Errorf("line %d: DA record outside of a source file", lineNum)
+			}
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return fmt.Errorf("line %d: invalid DA record", lineNum)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid line number %q", lineNum, fields[0])
+			}
+			hits, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid hit count %q", lineNum, fields[1])
+			}
+			r.AddLine(current, number, hits)
+		}
+	}
+	return scanner.Err()
+}
+
+// coberturaReport is the subset of the Cobertura XML schema used for line coverage
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// jacocoReport is the subset of the JaCoCo XML schema used for line coverage
+type jacocoReport struct {
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string `xml:"name,attr"`
+			Lines []struct {
+				Number        int `xml:"nr,attr"`
+				CoveredInstrs int `xml:"ci,attr"`
+			} `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// parseXML reads a Cobertura (<coverage>) or JaCoCo (<report>) XML report
+func (r *Report) parseXML(data []byte) error {
+	root, err := xmlRootElement(data)
+	if err != nil {
+		return err
+	}
+
+	switch root {
+	case "coverage":
+		var report coberturaReport
+		if err := xml.Unmarshal(data, &report); err != nil {
+			return err
+		}
+		r.addCobertura(report)
+	case "report":
+		var report jacocoReport
+		if err := xml.Unmarshal(data, &report); err != nil {
+			return err
+		}
+		r.addJaCoCo(report)
+	default:
+		return fmt.Errorf("unsupported XML coverage format <%s>", root)
+	}
+	return nil
+}
+
+// addCobertura merges Cobertura line hits. Filenames are relative to one of the
+// listed sources; the first source is used since matching also tries suffixes.
+func (r *Report) addCobertura(report coberturaReport) {
+	source := ""
+	if len(report.Sources) > 0 {
+		source = strings.TrimSpace(report.Sources[0])
+	}
+
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			file := class.Filename
+			if source != "" && !path.IsAbs(file) {
+				file = path.Join(source, file)
+			}
+			for _, line := range class.Lines {
+				r.AddLine(file, line.Number, line.Hits)
+			}
+		}
+	}
+}
+
+// addJaCoCo merges JaCoCo line data. Source paths are the package name joined
+// with the file name; a line is covered when any of its instructions ran.
+func (r *Report) addJaCoCo(report jacocoReport) {
+	for _, pkg := range report.Packages {
+		for _, sourceFile := range pkg.SourceFiles {
+			file := path.Join(pkg.Name, sourceFile.Name)
+			for _, line := range sourceFile.Lines {
+				r.AddLine(file, line.Number, line.CoveredInstrs)
+			}
+		}
+	}
+}
+
+// xmlRootElement returns the name of the document's root element
+func xmlRootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
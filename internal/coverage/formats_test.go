@@ -0,0 +1,130 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeReport(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile_GoProfile(t *testing.T) {
+	path := writeReport(t, "coverage.out", `mode: set
+example.com/app/pkg/util.go:3.20,5.2 2 1
+example.com/app/pkg/util.go:7.20,9.2 1 0
+example.com/app/pkg/util.go:9.2,10.3 1 1
+`)
+
+	report := NewReport()
+	if err := report.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	lines := report.files["example.com/app/pkg/util.go"]
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 instrumented lines, got %d", len(lines))
+	}
+	if lines[4] != 1 || lines[8] != 0 {
+		t.Errorf("Expected line 4 covered and line 8 uncovered, got %d and %d", lines[4], lines[8])
+	}
+	// Line 9 ends an uncovered block and starts a covered one
+	if lines[9] != 1 {
+		t.Errorf("Expected shared line 9 to keep the highest count, got %d", lines[9])
+	}
+}
+
+func TestLoadFile_LCOV(t *testing.T) {
+	path := writeReport(t, "lcov.info", `TN:
+SF:src/app.js
+DA:1,1
+DA:2,0
+DA:3,5
+end_of_record
+SF:src/other.js
+DA:10,0
+end_of_record
+`)
+
+	report := NewReport()
+	if err := report.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	if report.FileCount() != 2 {
+		t.Fatalf("Expected 2 files, got %d", report.FileCount())
+	}
+	if report.files["src/app.js"][3] != 5 {
+		t.Errorf("Expected 5 hits on app.js line 3, got %d", report.files["src/app.js"][3])
+	}
+}
+
+func TestLoadFile_Cobertura(t *testing.T) {
+	path := writeReport(t, "coverage.xml", `<?xml version="1.0" ?>
+<coverage version="7.4" line-rate="0.5">
+	<sources><source>/work/project</source></sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="models.py" filename="app/models.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="4" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`)
+
+	report := NewReport()
+	if err := report.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	lines := report.files["/work/project/app/models.py"]
+	if len(lines) != 2 || lines[1] != 1 || lines[4] != 0 {
+		t.Errorf("Unexpected Cobertura lines: %v (files %v)", lines, report.files)
+	}
+}
+
+func TestLoadFile_JaCoCo(t *testing.T) {
+	path := writeReport(t, "jacoco.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="demo">
+	<package name="com/example">
+		<sourcefile name="Service.java">
+			<line nr="5" mi="0" ci="3" mb="0" cb="0"/>
+			<line nr="6" mi="2" ci="0" mb="0" cb="0"/>
+		</sourcefile>
+	</package>
+</report>`)
+
+	report := NewReport()
+	if err := report.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	lines := report.files["com/example/Service.java"]
+	if lines[5] != 3 || lines[6] != 0 {
+		t.Errorf("Unexpected JaCoCo lines: %v", lines)
+	}
+}
+
+func TestLoadFile_Unrecognized(t *testing.T) {
+	path := writeReport(t, "coverage.txt", "all tests passed\n")
+
+	report := NewReport()
+	if err := report.LoadFile(path); err == nil {
+		t.Error("Expected an error for an unrecognized format")
+	}
+	if len(report.Sources) != 0 {
+		t.Errorf("Expected no loaded sources, got %v", report.Sources)
+	}
+}
@@ -0,0 +1,183 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Report holds line hit counts from one or more coverage artifacts, keyed by the
+// file path as written in the artifact
+type Report struct {
+	files   map[string]map[int]int
+	Sources []string
+}
+
+// NewReport creates an empty coverage report
+func NewReport() *Report {
+	return &Report{
+		files:   make(map[string]map[int]int),
+		Sources: []string{},
+	}
+}
+
+// AddLine records hits for an instrumented line. Lines reported more than once,
+// by overlapping blocks or several artifacts, keep the highest hit count.
+func (r *Report) AddLine(filePath string, line, hits int) {
+	filePath = filepath.ToSlash(filepath.Clean(filePath))
+	lines := r.files[filePath]
+	if lines == nil {
+		lines = make(map[int]int)
+		r.files[filePath] = lines
+	}
+	if current, exists := lines[line]; !exists || hits > current {
+		lines[line] = hits
+	}
+}
+
+// FileCount returns the number of files with coverage data
+func (r *Report) FileCount() int {
+	return len(r.files)
+}
+
+// LoadFile reads a coverage artifact, detecting its format from the content,
+// and merges it into the report
+func (r *Report) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read coverage report %s: %w", path, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		err = r.parseGoProfile(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		err = r.parseXML(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		err = r.parseLCOV(trimmed)
+	default:
+		err = fmt.Errorf("unrecognized coverage format")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse coverage report %s: %w", path, err)
+	}
+
+	r.Sources = append(r.Sources, path)
+	return nil
+}
+
+// Apply maps the report onto analyzed files and fills per-file and per-function
+// coverage. Report paths are matched against result paths directly, relative to
+// rootPath, or by their longest suffix that names an analyzed file (Go import
+// paths, package-relative JaCoCo paths). It returns the number of matched files.
+func (r *Report) Apply(results []*parser.AnalysisResult, rootPath string) int {
+	byPath := make(map[string]*parser.AnalysisResult)
+	for _, result := range results {
+		path := filepath.ToSlash(filepath.Clean(result.FilePath))
+		byPath[path] = result
+		if rel, err := filepath.Rel(rootPath, result.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+			byPath[filepath.ToSlash(rel)] = result
+		}
+	}
+
+	// Merge line data per analyzed file; several report paths can map to one file
+	matched := make(map[*parser.AnalysisResult]map[int]int)
+	reportPaths := make([]string, 0, len(r.files))
+	for path := range r.files {
+		reportPaths = append(reportPaths, path)
+	}
+	sort.Strings(reportPaths)
+
+	for _, path := range reportPaths {
+		result := matchResult(byPath, path)
+		if result == nil {
+			continue
+		}
+		lines := matched[result]
+		if lines == nil {
+			lines = make(map[int]int)
+			matched[result] = lines
+		}
+		for line, hits := range r.files[path] {
+			if current, exists := lines[line]; !exists || hits > current {
+				lines[line] = hits
+			}
+		}
+	}
+
+	for result, lines := range matched {
+		applyLines(result, lines)
+	}
+
+	return len(matched)
+}
+
+// matchResult finds the analyzed file a report path refers to
+func matchResult(byPath map[string]*parser.AnalysisResult, path string) *parser.AnalysisResult {
+	if result, exists := byPath[path]; exists {
+		return result
+	}
+
+	// Strip leading path components until the remainder names an analyzed file
+	for idx := strings.Index(path, "/"); idx >= 0; idx = strings.Index(path, "/") {
+		path = path[idx+1:]
+		if result, exists := byPath[path]; exists {
+			return result
+		}
+	}
+	return nil
+}
+
+// applyLines sets the coverage fields of a result and its functions from line hits
+func applyLines(result *parser.AnalysisResult, lines map[int]int) {
+	result.CoverableLines, result.CoveredLines = countLines(lines, 1, result.LineCount)
+	result.TestCoverage = percentage(result.CoveredLines, result.CoverableLines)
+
+	for i := range result.Functions {
+		applyFunction(&result.Functions[i], lines)
+	}
+	for i := range result.Classes {
+		for j := range result.Classes[i].Methods {
+			applyFunction(&result.Classes[i].Methods[j], lines)
+		}
+	}
+}
+
+// applyFunction sets a function's coverage from the lines it spans
+func applyFunction(fn *parser.FunctionInfo, lines map[int]int) {
+	end := fn.LineEnd
+	if end < fn.LineStart {
+		end = fn.LineStart
+	}
+	fn.CoverableLines, fn.CoveredLines = countLines(lines, fn.LineStart, end)
+	fn.TestCoverage = percentage(fn.CoveredLines, fn.CoverableLines)
+}
+
+// countLines counts instrumented and covered lines within [start, end].
+// An end of 0 or less means no upper bound.
+func countLines(lines map[int]int, start, end int) (coverable, covered int) {
+	for line, hits := range lines {
+		if line < start || (end > 0 && line > end) {
+			continue
+		}
+		coverable++
+		if hits > 0 {
+			covered++
+		}
+	}
+	return coverable, covered
+}
+
+// percentage returns part/total as a percentage, or 0 when total is 0
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
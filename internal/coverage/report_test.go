@@ -0,0 +1,84 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestApply(t *testing.T) {
+	report := NewReport()
+	// Go import path; only the suffix names an analyzed file
+	for line := 1; line <= 10; line++ {
+		hits := 0
+		if line <= 4 {
+			hits = 1
+		}
+		report.AddLine("example.com/app/pkg/util.go", line, hits)
+	}
+	report.AddLine("/elsewhere/unknown.go", 1, 1)
+
+	util := &parser.AnalysisResult{
+		FilePath:  "/repo/pkg/util.go",
+		LineCount: 12,
+		Functions: []parser.FunctionInfo{
+			{Name: "Covered", LineStart: 1, LineEnd: 4},
+			{Name: "Uncovered", LineStart: 6, LineEnd: 10},
+			{Name: "Untracked", LineStart: 12, LineEnd: 12},
+		},
+	}
+	other := &parser.AnalysisResult{FilePath: "/repo/main.go", LineCount: 5}
+
+	matched := report.Apply([]*parser.AnalysisResult{util, other}, "/repo")
+	if matched != 1 {
+		t.Fatalf("Expected 1 matched file, got %d", matched)
+	}
+
+	if util.CoverableLines != 10 || util.CoveredLines != 4 || util.TestCoverage != 40 {
+		t.Errorf("Expected 4/10 lines (40%%), got %d/%d (%.1f%%)", util.CoveredLines, util.CoverableLines, util.TestCoverage)
+	}
+	if util.Functions[0].TestCoverage != 100 {
+		t.Errorf("Expected Covered at 100%%, got %.1f", util.Functions[0].TestCoverage)
+	}
+	if util.Functions[1].TestCoverage != 0 || util.Functions[1].CoverableLines != 5 {
+		t.Errorf("Expected Uncovered at 0%% of 5 lines, got %.1f of %d", util.Functions[1].TestCoverage, util.Functions[1].CoverableLines)
+	}
+	if util.Functions[2].CoverableLines != 0 {
+		t.Errorf("Expected no coverage data for Untracked, got %d lines", util.Functions[2].CoverableLines)
+	}
+	if other.CoverableLines != 0 {
+		t.Errorf("Expected no coverage data for main.go, got %d lines", other.CoverableLines)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"coverage.out",
+		"web/coverage/lcov.info",
+		"services/api/unit.coverprofile",
+		"node_modules/pkg/coverage/lcov.info",
+		"README.md",
+	}
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("mode: set\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports := Discover(root, []string{"node_modules"})
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %v", reports)
+	}
+	for _, report := range reports {
+		if filepath.Base(filepath.Dir(filepath.Dir(report))) == "node_modules" {
+			t.Errorf("Expected excluded directory to be skipped, got %s", report)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/manifest"
+	"github.com/tito-sala/codebasereaderv2/internal/trends"
+)
+
+// BackfillOptions selects the past commits analyzed to backfill the trend store
+type BackfillOptions struct {
+	Every  int       // analyze every Nth commit on the first-parent history, counting back from the newest
+	Weekly bool      // analyze the newest commit of each week instead
+	Since  time.Time // zero for all history
+}
+
+// BackfillResult counts the commits of a backfill
+type BackfillResult struct {
+	Selected int // commits selected by the options
+	Skipped  int // selected commits already in the trend store
+	Recorded int // snapshots added to the trend store
+}
+
+// BackfillTrends analyzes past commits of the git repository at rootPath and
+// records a snapshot of each in its trend store, dated at the commit. Files
+// are read from git into scratch directories, so the working tree is not
+// touched. Up to MaxWorkers commits are analyzed at once, each run of
+// consecutive commits in its own scratch directory. Commits already recorded
+// are skipped, so an interrupted backfill resumes where it stopped. Progress,
+// when set, is called before each commit is analyzed.
+func (e *Engine) BackfillTrends(rootPath string, options BackfillOptions, progress func(done, total int, commit git.Commit)) (BackfillResult, error) {
+	var summary BackfillResult
+
+	repo, err := git.Open(rootPath)
+	if err != nil {
+		return summary, err
+	}
+	commits, err := repo.Mainline("HEAD", options.Since)
+	if err != nil {
+		return summary, err
+	}
+	selected := selectBackfillCommits(commits, options)
+	summary.Selected = len(selected)
+
+	store := e.TrendStore(rootPath)
+	snapshots, err := store.Load()
+	if err != nil {
+		return summary, err
+	}
+	recorded := make(map[string]bool, len(snapshots))
+	for _, snapshot := range snapshots {
+		recorded[snapshot.Commit] = true
+	}
+	var pending []git.Commit
+	for _, commit := range selected {
+		if recorded[commit.Hash] {
+			summary.Skipped++
+			continue
+		}
+		pending = append(pending, commit)
+	}
+	if len(pending) == 0 {
+		return summary, nil
+	}
+
+	// Commits are split into consecutive runs analyzed side by side, each in its
+	// own scratch directory, and the workers are shared between the runs
+	lanes := min(max(e.config.MaxWorkers, 1), len(pending))
+	workers := max(e.config.MaxWorkers/lanes, 1)
+	type laneResult struct {
+		commit   git.Commit
+		snapshot trends.Snapshot
+		err      error
+	}
+	results := make(chan laneResult)
+	stop := make(chan struct{})
+	var progressMutex sync.Mutex
+	started := 0
+	var wg sync.WaitGroup
+	for lane := 0; lane < lanes; lane++ {
+		run := pending[lane*len(pending)/lanes : (lane+1)*len(pending)/lanes]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := e.backfillRun(repo, run, workers, func(commit git.Commit) bool {
+				progressMutex.Lock()
+				defer progressMutex.Unlock()
+				select {
+				case <-stop:
+					return false
+				default:
+				}
+				if progress != nil {
+					progress(started, len(pending), commit)
+				}
+				started++
+				return true
+			}, func(commit git.Commit, snapshot trends.Snapshot) {
+				results <- laneResult{commit: commit, snapshot: snapshot}
+			})
+			if err != nil {
+				results <- laneResult{err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var firstErr error
+	for result := range results {
+		if firstErr != nil {
+			continue
+		}
+		if result.err == nil {
+			result.err = store.Append(result.snapshot)
+		}
+		if result.err != nil {
+			firstErr = result.err
+			progressMutex.Lock()
+			close(stop)
+			progressMutex.Unlock()
+			continue
+		}
+		summary.Recorded++
+	}
+	return summary, firstErr
+}
+
+// backfillRun analyzes consecutive commits in a scratch directory of its own,
+// calling start before each commit and stopping when it returns false, and
+// record with the snapshot of each analyzed commit
+func (e *Engine) backfillRun(repo *git.Repository, commits []git.Commit, workers int, start func(git.Commit) bool, record func(git.Commit, trends.Snapshot)) error {
+	scratch, err := os.MkdirTemp("", "codebasereader-backfill-")
+	if err != nil {
+		return fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	// The scratch directory is not a repository and each run is recorded by
+	// the caller, so history mining and recording are off; the cache and the
+	// fixed scratch path let each commit only parse the files that changed
+	// since the previous one. Baselines are not compared, as they are never
+	// read from the commit.
+	config := *e.config
+	config.MaxWorkers = workers
+	config.GitHistory = false
+	config.RecordTrends = false
+	config.IncrementalCache = true
+	config.Baseline = filepath.Join(scratch, DefaultBaselineFile)
+	analysisEngine := NewEngine(&config)
+	analysisEngine.parserRegistry = e.parserRegistry
+	checkout := &scratchCheckout{
+		repo:          repo,
+		dir:           scratch,
+		walker:        NewFileWalker(e.parserRegistry, &config),
+		projectConfig: filepath.ToSlash(filepath.Clean(projectFile("", config.ProjectConfig, DefaultProjectConfigFile))),
+		files:         make(map[string]string),
+	}
+
+	for _, commit := range commits {
+		if !start(commit) {
+			return nil
+		}
+		if err := checkout.update(commit.Hash); err != nil {
+			return fmt.Errorf("failed to read commit %s: %w", commit.Hash, err)
+		}
+		analysis, err := analysisEngine.AnalyzeDirectoryWithEnhancedMetrics(scratch)
+		if err != nil {
+			return fmt.Errorf("failed to analyze commit %s: %w", commit.Hash, err)
+		}
+
+		snapshot := trends.NewSnapshot(analysis, scratch, commit.Hash)
+		snapshot.Timestamp = commit.Time
+		record(commit, snapshot)
+	}
+	return nil
+}
+
+// selectBackfillCommits picks the commits to analyze from a first-parent
+// history, newest first, and returns them oldest first. The newest commit is
+// always picked.
+func selectBackfillCommits(commits []git.Commit, options BackfillOptions) []git.Commit {
+	var selected []git.Commit
+	weeks := make(map[string]bool)
+	for i, commit := range commits {
+		if options.Weekly {
+			year, week := commit.Time.ISOWeek()
+			key := fmt.Sprintf("%d-%02d", year, week)
+			if weeks[key] {
+				continue
+			}
+			weeks[key] = true
+		} else if options.Every > 1 && i%options.Every != 0 {
+			continue
+		}
+		selected = append(selected, commit)
+	}
+
+	for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+		selected[i], selected[j] = selected[j], selected[i]
+	}
+	return selected
+}
+
+// scratchCheckout mirrors the analyzable files of a commit in a scratch
+// directory, writing only the files that changed since the previous commit
+type scratchCheckout struct {
+	repo          *git.Repository
+	dir           string
+	walker        *FileWalker
+	projectConfig string            // project config file relative to the root, read from each commit
+	files         map[string]string // blob hash by slash-separated relative path
+}
+
+// update makes the scratch directory hold the files of a commit that can be
+// analyzed: those with a parser, dependency manifests and the project config
+func (c *scratchCheckout) update(rev string) error {
+	tree, err := c.repo.Tree(rev)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]string, len(tree))
+	var changed []string
+	for _, file := range tree {
+		if !c.wanted(file.Path) {
+			continue
+		}
+		files[file.Path] = file.Blob
+		if c.files[file.Path] != file.Blob {
+			changed = append(changed, file.Blob)
+		}
+	}
+
+	for rel := range c.files {
+		if _, exists := files[rel]; !exists {
+			if err := os.Remove(filepath.Join(c.dir, filepath.FromSlash(rel))); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	blobs, err := c.repo.ReadBlobs(changed)
+	if err != nil {
+		return err
+	}
+	for rel, blob := range files {
+		if c.files[rel] == blob {
+			continue
+		}
+		target := filepath.Join(c.dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, blobs[blob], 0644); err != nil {
+			return err
+		}
+	}
+
+	c.files = files
+	return nil
+}
+
+// wanted reports whether a file of a commit is needed to analyze it
+func (c *scratchCheckout) wanted(rel string) bool {
+	target := filepath.Join(c.dir, filepath.FromSlash(rel))
+	if c.walker.isExcluded(target, c.dir) {
+		return false
+	}
+	return c.walker.getParserForFile(target) != nil || manifest.IsManifest(path.Base(rel)) || rel == c.projectConfig
+}
@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+)
+
+func TestSelectBackfillCommits(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	// Newest first, as in the log; March 2 and 3 2026 fall in one ISO week, March 9 and 10 in the next
+	commits := []git.Commit{{Hash: "e", Time: day(10)}, {Hash: "d", Time: day(9)}, {Hash: "c", Time: day(3)}, {Hash: "b", Time: day(2)}, {Hash: "a", Time: day(1)}}
+
+	hashes := func(selected []git.Commit) string {
+		var s string
+		for _, commit := range selected {
+			s += commit.Hash
+		}
+		return s
+	}
+	if got := hashes(selectBackfillCommits(commits, BackfillOptions{Every: 2})); got != "ace" {
+		t.Errorf("Every 2nd commit: expected ace, got %s", got)
+	}
+	if got := hashes(selectBackfillCommits(commits, BackfillOptions{})); got != "abcde" {
+		t.Errorf("All commits: expected abcde, got %s", got)
+	}
+	if got := hashes(selectBackfillCommits(commits, BackfillOptions{Weekly: true})); got != "ace" {
+		t.Errorf("Weekly: expected ace, got %s", got)
+	}
+}
+
+func TestBackfillTrends(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	writeFile("a.go", "package a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "one")
+	writeFile("b.go", "package a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "two")
+	writeFile("c.go", "package a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "three")
+	// Uncommitted files are not part of any commit
+	writeFile("wip.go", "package a\n")
+
+	engine := NewEngine(DefaultConfig())
+	engine.GetParserRegistry().RegisterParser(&MockParser{name: "Go", extensions: []string{"go"}})
+
+	var progressed int
+	summary, err := engine.BackfillTrends(root, BackfillOptions{Every: 2}, func(done, total int, commit git.Commit) { progressed++ })
+	if err != nil {
+		t.Fatalf("BackfillTrends failed: %v", err)
+	}
+	if summary.Selected != 2 || summary.Recorded != 2 || progressed != 2 {
+		t.Fatalf("Expected the first and last commits recorded, got %+v after %d commits", summary, progressed)
+	}
+
+	snapshots, err := engine.LoadTrends(root)
+	if err != nil {
+		t.Fatalf("LoadTrends failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].TotalFiles != 1 || snapshots[1].TotalFiles != 3 {
+		t.Errorf("Expected snapshots of 1 and 3 files, got %+v", snapshots)
+	}
+
+	// A second run resumes and only analyzes the commits not yet recorded
+	summary, err = engine.BackfillTrends(root, BackfillOptions{}, nil)
+	if err != nil {
+		t.Fatalf("BackfillTrends failed: %v", err)
+	}
+	if summary.Selected != 3 || summary.Skipped != 2 || summary.Recorded != 1 {
+		t.Errorf("Expected only the middle commit recorded, got %+v", summary)
+	}
+	if _, err := os.Stat(filepath.Join(root, "wip.go")); err != nil {
+		t.Errorf("Expected the working tree untouched: %v", err)
+	}
+}
+
+func TestBackfillTrends_Parallel(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com", "GIT_COMMITTER_DATE="+date,
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	gitCmd("", "init", "-q", "-b", "main")
+	const commits = 7
+	for i := 1; i <= commits; i++ {
+		name := filepath.Join(root, fmt.Sprintf("f%d.go", i))
+		if err := os.WriteFile(name, []byte("package a\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		date := fmt.Sprintf("2026-03-%02dT12:00:00Z", i)
+		gitCmd(date, "add", ".")
+		gitCmd(date, "commit", "-q", "-m", fmt.Sprintf("commit %d", i))
+	}
+
+	config := DefaultConfig()
+	config.MaxWorkers = 3
+	engine := NewEngine(config)
+	engine.GetParserRegistry().RegisterParser(&MockParser{name: "Go", extensions: []string{"go"}})
+
+	summary, err := engine.BackfillTrends(root, BackfillOptions{}, nil)
+	if err != nil {
+		t.Fatalf("BackfillTrends failed: %v", err)
+	}
+	if summary.Recorded != commits {
+		t.Fatalf("Expected %d commits recorded, got %+v", commits, summary)
+	}
+
+	snapshots, err := engine.LoadTrends(root)
+	if err != nil {
+		t.Fatalf("LoadTrends failed: %v", err)
+	}
+	if len(snapshots) != commits {
+		t.Fatalf("Expected %d snapshots, got %d", commits, len(snapshots))
+	}
+	// Each run analyzes its commits in a scratch directory of its own
+	for i, snapshot := range snapshots {
+		if snapshot.TotalFiles != i+1 {
+			t.Errorf("Snapshot %d: expected %d files, got %d", i, i+1, snapshot.TotalFiles)
+		}
+	}
+}
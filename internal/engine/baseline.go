@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// DefaultBaselineFile is the name of the baseline file looked up in the analyzed root
+const DefaultBaselineFile = ".codebasereader-baseline.json"
+
+// BaselinePath returns the path of the baseline file for rootPath
+func (e *Engine) BaselinePath(rootPath string) string {
+	return projectFile(rootPath, e.config.Baseline, DefaultBaselineFile)
+}
+
+// LoadBaseline reads the baseline of accepted violations for rootPath. It
+// returns nil when the project has no baseline.
+func (e *Engine) LoadBaseline(rootPath string) (*metrics.Baseline, error) {
+	baselinePath := e.BaselinePath(rootPath)
+	data, err := os.ReadFile(baselinePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	baseline := &metrics.Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", baselinePath, err)
+	}
+	return baseline, nil
+}
+
+// SaveBaseline writes the baseline for rootPath
+func (e *Engine) SaveBaseline(rootPath string, baseline metrics.Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(e.BaselinePath(rootPath), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
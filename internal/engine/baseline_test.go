@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+func TestSaveAndLoadBaseline(t *testing.T) {
+	root := t.TempDir()
+	engine := NewEngine(DefaultConfig())
+
+	baseline, err := engine.LoadBaseline(root)
+	if err != nil || baseline != nil {
+		t.Fatalf("Expected no baseline in an empty project, got %+v, %v", baseline, err)
+	}
+
+	saved := metrics.Baseline{Version: 1, Entries: []metrics.BaselineEntry{
+		{Kind: metrics.GateFunctionComplexity, FilePath: "a.go", Symbol: "parse", Value: 20},
+	}}
+	if err := engine.SaveBaseline(root, saved); err != nil {
+		t.Fatalf("SaveBaseline failed: %v", err)
+	}
+	baseline, err = engine.LoadBaseline(root)
+	if err != nil {
+		t.Fatalf("LoadBaseline failed: %v", err)
+	}
+	if baseline == nil || len(baseline.Entries) != 1 || baseline.Entries[0] != saved.Entries[0] {
+		t.Errorf("Expected the saved baseline back, got %+v", baseline)
+	}
+}
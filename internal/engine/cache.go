@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// parseCache keeps the parse results of the files of the previous run by path
+// and content, so a run over mostly unchanged files only parses the changed
+// ones. Entries not used by a run are dropped when it ends. A nil cache caches
+// nothing.
+type parseCache struct {
+	mutex    sync.Mutex
+	previous map[parseCacheKey]*parser.AnalysisResult
+	current  map[parseCacheKey]*parser.AnalysisResult
+}
+
+// parseCacheKey identifies a file's content at a path
+type parseCacheKey struct {
+	path string
+	hash [sha256.Size]byte
+}
+
+// newParseCache creates an empty parse cache
+func newParseCache() *parseCache {
+	return &parseCache{
+		previous: make(map[parseCacheKey]*parser.AnalysisResult),
+		current:  make(map[parseCacheKey]*parser.AnalysisResult),
+	}
+}
+
+// get returns a copy of the cached result for a file's content, or nil
+func (c *parseCache) get(path string, content []byte) *parser.AnalysisResult {
+	if c == nil {
+		return nil
+	}
+	key := parseCacheKey{path: path, hash: sha256.Sum256(content)}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result, exists := c.current[key]
+	if !exists {
+		if result, exists = c.previous[key]; !exists {
+			return nil
+		}
+		c.current[key] = result
+	}
+	return cloneResult(result)
+}
+
+// put caches a copy of the result of parsing a file's content
+func (c *parseCache) put(path string, content []byte, result *parser.AnalysisResult) {
+	if c == nil {
+		return
+	}
+	key := parseCacheKey{path: path, hash: sha256.Sum256(content)}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current[key] = cloneResult(result)
+}
+
+// rotate ends a run, keeping only the entries it used
+func (c *parseCache) rotate() {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.previous, c.current = c.current, make(map[parseCacheKey]*parser.AnalysisResult)
+}
+
+// cloneResult copies a parse result deeply enough that the later steps of an
+// analysis, which set coverage, history, duplication and dependency versions,
+// do not change the cached copy
+func cloneResult(result *parser.AnalysisResult) *parser.AnalysisResult {
+	clone := *result
+	clone.History = nil
+	clone.Functions = append([]parser.FunctionInfo(nil), result.Functions...)
+	clone.Dependencies = append([]parser.Dependency(nil), result.Dependencies...)
+	clone.Classes = append([]parser.ClassInfo(nil), result.Classes...)
+	for i := range clone.Classes {
+		clone.Classes[i].Methods = append([]parser.FunctionInfo(nil), clone.Classes[i].Methods...)
+	}
+	return &clone
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestEngine_IncrementalCache(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go", "package a\n")
+	write("b.go", "package b\n")
+
+	var parsed atomic.Int32
+	mock := &MockParser{name: "Go", extensions: []string{"go"}}
+	mock.parseFunc = func(filePath string, content []byte) (*parser.AnalysisResult, error) {
+		parsed.Add(1)
+		return &parser.AnalysisResult{
+			FilePath:  filePath,
+			Language:  "Go",
+			LineCount: len(strings.Split(string(content), "\n")),
+			Functions: []parser.FunctionInfo{{Name: "f", LineStart: 1, LineEnd: 1}},
+		}, nil
+	}
+
+	config := DefaultConfig()
+	config.IncrementalCache = true
+	engine := NewEngine(config)
+	engine.GetParserRegistry().RegisterParser(mock)
+
+	first, err := engine.AnalyzeDirectory(root)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory failed: %v", err)
+	}
+	// Later steps of an analysis change results; the cached copies must not change
+	first.FileResults[0].Functions[0].Name = "changed"
+
+	write("b.go", "package b\n\nfunc b() {}\n")
+	second, err := engine.AnalyzeDirectory(root)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory failed: %v", err)
+	}
+
+	if parsed.Load() != 3 {
+		t.Errorf("Expected the unchanged file to be parsed once, got %d parses", parsed.Load())
+	}
+	if second.TotalFiles != 2 {
+		t.Errorf("Expected 2 files, got %d", second.TotalFiles)
+	}
+	for _, result := range second.FileResults {
+		if result.Functions[0].Name != "f" {
+			t.Errorf("Expected the cached result unchanged, got %+v", result.Functions)
+		}
+	}
+}
@@ -3,9 +3,15 @@ package engine
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/tito-sala/codebasereaderv2/internal/callgraph"
+	"github.com/tito-sala/codebasereaderv2/internal/coverage"
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/manifest"
 	"github.com/tito-sala/codebasereaderv2/internal/metrics"
 	"github.com/tito-sala/codebasereaderv2/internal/parser"
 )
@@ -17,6 +23,8 @@ type Engine struct {
 	workerPool        *WorkerPool
 	metricsCalculator *metrics.Calculator
 	metricsAggregator *metrics.Aggregator
+	// Parse results of files already analyzed, when IncrementalCache is set
+	parseCache *parseCache
 }
 
 // NewEngine creates a new analysis engine with the given configuration
@@ -25,13 +33,17 @@ func NewEngine(config *Config) *Engine {
 		config = DefaultConfig()
 	}
 
-	return &Engine{
+	engine := &Engine{
 		parserRegistry:    parser.NewParserRegistry(),
 		config:            config,
 		workerPool:        NewWorkerPool(config.MaxWorkers),
 		metricsCalculator: metrics.NewCalculator(),
 		metricsAggregator: metrics.NewAggregator(),
 	}
+	if config.IncrementalCache {
+		engine.parseCache = newParseCache()
+	}
+	return engine
 }
 
 // GetParserRegistry returns the parser registry for registering new parsers
@@ -53,6 +65,11 @@ func (e *Engine) UpdateConfig(config *Config) {
 			e.workerPool.Stop()
 			e.workerPool = NewWorkerPool(config.MaxWorkers)
 		}
+		if !config.IncrementalCache {
+			e.parseCache = nil
+		} else if e.parseCache == nil {
+			e.parseCache = newParseCache()
+		}
 	}
 }
 
@@ -99,35 +116,48 @@ func (e *Engine) AnalyzeDirectoryWithProgress(rootPath string, progressCallback
 		}, nil
 	}
 
-	// Submit jobs to worker pool
-	for _, walkResult := range walkResults {
-		content, err := e.readFileContent(walkResult.FilePath)
-		if err != nil {
-			fmt.Printf("Warning: failed to read file %s: %v\n", walkResult.FilePath, err)
-			continue
-		}
+	// Submit jobs from a separate goroutine so results are drained while the
+	// queue fills; the number of jobs is known once every file has been read
+	submitted := make(chan int, 1)
+	go func() {
+		count := 0
+		for _, walkResult := range walkResults {
+			content, err := e.readFileContent(walkResult.FilePath)
+			if err != nil {
+				fmt.Printf("Warning: failed to read file %s: %v\n", walkResult.FilePath, err)
+				continue
+			}
 
-		job := AnalysisJob{
-			FilePath:          walkResult.FilePath,
-			Content:           content,
-			Parser:            walkResult.Parser,
-			MetricsCalculator: e.metricsCalculator,
-		}
+			job := AnalysisJob{
+				FilePath:          walkResult.FilePath,
+				Content:           content,
+				Parser:            walkResult.Parser,
+				MetricsCalculator: e.metricsCalculator,
+				Cache:             e.parseCache,
+			}
 
-		if err := workerPool.SubmitJob(job); err != nil {
-			return nil, fmt.Errorf("failed to submit job for %s: %w", walkResult.FilePath, err)
+			if err := workerPool.SubmitJob(job); err != nil {
+				fmt.Printf("Warning: failed to submit job for %s: %v\n", walkResult.FilePath, err)
+				break
+			}
+			count++
 		}
-	}
+		submitted <- count
+	}()
 
 	// Collect results
 	var results []*parser.AnalysisResult
 	var errors []error
 	processedCount := 0
+	expectedCount := -1
 
 	resultChan := workerPool.GetResultChannel()
 
-	for processedCount < totalFiles {
+	for expectedCount < 0 || processedCount < expectedCount {
 		select {
+		case count := <-submitted:
+			expectedCount = count
+
 		case jobResult := <-resultChan:
 			processedCount++
 
@@ -149,6 +179,7 @@ func (e *Engine) AnalyzeDirectoryWithProgress(rootPath string, progressCallback
 			}
 		}
 	}
+	e.parseCache.rotate()
 
 	// Aggregate results into project analysis
 	analysis := e.aggregateResults(rootPath, results)
@@ -261,20 +292,22 @@ func (wp *WorkerPool) Stop() {
 	wp.running = false
 }
 
-// SubmitJob adds a job to the worker pool queue
+// SubmitJob adds a job to the worker pool queue, waiting while the queue is
+// full. Results must be read concurrently or a full result queue stalls the workers.
 func (wp *WorkerPool) SubmitJob(job AnalysisJob) error {
 	wp.mutex.RLock()
-	defer wp.mutex.RUnlock()
+	running := wp.running
+	wp.mutex.RUnlock()
 
-	if !wp.running {
+	if !running {
 		return fmt.Errorf("worker pool is not running")
 	}
 
 	select {
 	case wp.jobQueue <- job:
 		return nil
-	default:
-		return fmt.Errorf("job queue is full")
+	case <-wp.stopChan:
+		return fmt.Errorf("worker pool stopped")
 	}
 }
 
@@ -298,10 +331,18 @@ func (w *worker) start(wg *sync.WaitGroup) {
 	for {
 		select {
 		case job := <-w.jobQueue:
-			result, err := job.Parser.Parse(job.FilePath, job.Content)
-			if err == nil && result != nil && job.MetricsCalculator != nil {
-				// Calculate enhanced metrics for the file
-				job.MetricsCalculator.CalculateFileMetrics(result, job.Content)
+			// Unchanged files are taken from the cache instead of parsed again
+			result := job.Cache.get(job.FilePath, job.Content)
+			var err error
+			if result == nil {
+				result, err = job.Parser.Parse(job.FilePath, job.Content)
+				if err == nil && result != nil && job.MetricsCalculator != nil {
+					// Calculate enhanced metrics for the file
+					job.MetricsCalculator.CalculateFileMetrics(result, job.Content)
+				}
+				if err == nil && result != nil {
+					job.Cache.put(job.FilePath, job.Content, result)
+				}
 			}
 			w.resultQueue <- AnalysisJobResult{
 				Result: result,
@@ -344,6 +385,7 @@ func (e *Engine) aggregateResults(rootPath string, results []*parser.AnalysisRes
 	// Aggregate basic statistics
 	for _, result := range results {
 		analysis.TotalLines += result.LineCount
+		languageLines := result.LanguageLines()
 
 		// Update language statistics
 		langStats, exists := analysis.Languages[result.Language]
@@ -352,12 +394,24 @@ func (e *Engine) aggregateResults(rootPath string, results []*parser.AnalysisRes
 		}
 
 		langStats.FileCount++
-		langStats.LineCount += result.LineCount
+		langStats.LineCount += languageLines[result.Language]
 		langStats.FunctionCount += len(result.Functions)
 		langStats.ClassCount += len(result.Classes)
 		langStats.Complexity += result.Complexity
+		langStats.CognitiveComplexity += result.CognitiveComplexity
 
 		analysis.Languages[result.Language] = langStats
+
+		// Files with embedded languages also contribute lines to each of them
+		for lang, lines := range languageLines {
+			if lang == result.Language {
+				continue
+			}
+			embeddedStats := analysis.Languages[lang]
+			embeddedStats.FileCount++
+			embeddedStats.LineCount += lines
+			analysis.Languages[lang] = embeddedStats
+		}
 	}
 
 	return analysis
@@ -370,14 +424,43 @@ func (e *Engine) AnalyzeDirectoryWithEnhancedMetrics(rootPath string) (*metrics.
 
 // AnalyzeDirectoryWithEnhancedMetricsAndProgress analyzes with enhanced metrics and progress reporting
 func (e *Engine) AnalyzeDirectoryWithEnhancedMetricsAndProgress(rootPath string, progressCallback func(current, total int, filePath string)) (*metrics.EnhancedProjectAnalysis, error) {
+	// Read the project's own settings first so a malformed file fails fast
+	project, err := e.LoadProjectConfig(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := e.LoadBaseline(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// First get basic analysis
 	basicAnalysis, err := e.AnalyzeDirectoryWithProgress(rootPath, progressCallback)
 	if err != nil {
 		return nil, err
 	}
 
+	// Detect duplicated code before aggregation so per-file percentages roll up
+	duplication := e.detectDuplication(basicAnalysis.FileResults)
+
+	// Import test coverage reports onto the analyzed files
+	coverageReports := e.importCoverage(rootPath, basicAnalysis.FileResults)
+
+	// Attach version control history to the analyzed files
+	commits := e.mineHistory(rootPath, basicAnalysis.FileResults)
+
+	// Compare declared dependencies with imports, recording declared versions
+	modules := e.checkManifests(rootPath, basicAnalysis.FileResults)
+
 	// Use metrics aggregator to calculate comprehensive project metrics
 	enhancedAnalysis := e.metricsAggregator.AggregateProjectMetrics(basicAnalysis.FileResults, rootPath)
+	enhancedAnalysis.Duplication = duplication
+	enhancedAnalysis.CoverageReports = coverageReports
+	enhancedAnalysis.DependencyGraph.SetModules(modules)
+	enhancedAnalysis.TemporalCoupling = metrics.AnalyzeTemporalCoupling(commits, basicAnalysis.FileResults, rootPath, e.couplingOptions())
+	enhancedAnalysis.CallGraph = callgraph.Build(basicAnalysis.FileResults, rootPath)
+	enhancedAnalysis.DeadCode = metrics.FindDeadCode(basicAnalysis.FileResults, e.deadCodeOptions())
+	enhancedAnalysis.ArchitectureViolations = metrics.CheckArchitecture(project.ArchitectureRules, basicAnalysis.FileResults, rootPath)
 
 	// Copy basic fields
 	enhancedAnalysis.TotalLines = basicAnalysis.TotalLines
@@ -388,18 +471,239 @@ func (e *Engine) AnalyzeDirectoryWithEnhancedMetricsAndProgress(rootPath string,
 	enhancedLanguages := make(map[string]metrics.LanguageStats)
 	for lang, basicStats := range basicAnalysis.Languages {
 		enhancedLanguages[lang] = metrics.LanguageStats{
-			FileCount:     basicStats.FileCount,
-			LineCount:     basicStats.LineCount,
-			FunctionCount: basicStats.FunctionCount,
-			ClassCount:    basicStats.ClassCount,
-			Complexity:    basicStats.Complexity,
+			FileCount:           basicStats.FileCount,
+			LineCount:           basicStats.LineCount,
+			FunctionCount:       basicStats.FunctionCount,
+			ClassCount:          basicStats.ClassCount,
+			Complexity:          basicStats.Complexity,
+			CognitiveComplexity: basicStats.CognitiveComplexity,
 		}
 	}
 	enhancedAnalysis.Languages = enhancedLanguages
 
+	// Language coverage is weighted by coverable lines
+	coverageLines := make(map[string][2]int)
+	for _, result := range basicAnalysis.FileResults {
+		lines := coverageLines[result.Language]
+		lines[0] += result.CoveredLines
+		lines[1] += result.CoverableLines
+		coverageLines[result.Language] = lines
+	}
+	for lang, lines := range coverageLines {
+		if stats, exists := enhancedLanguages[lang]; exists && lines[1] > 0 {
+			stats.TestCoverage = float64(lines[0]) / float64(lines[1]) * 100
+			enhancedLanguages[lang] = stats
+		}
+	}
+
+	// Gates are checked last, against the complete analysis
+	if project.QualityGates != nil {
+		enhancedAnalysis.QualityGates = metrics.EvaluateQualityGates(*project.QualityGates, enhancedAnalysis)
+	}
+	if baseline != nil {
+		enhancedAnalysis.Baseline = baseline.Compare(enhancedAnalysis, rootPath)
+	}
+
+	if e.config.RecordTrends {
+		if err := e.RecordTrend(rootPath, enhancedAnalysis); err != nil {
+			return nil, err
+		}
+	}
+
 	return enhancedAnalysis, nil
 }
 
+// detectDuplication runs clone detection over the analyzed files and records
+// the duplicated percentage on each result
+func (e *Engine) detectDuplication(results []*parser.AnalysisResult) metrics.DuplicationAnalysis {
+	detector := metrics.NewDuplicationDetector(e.config.DuplicationMinTokens)
+	for _, result := range results {
+		content, err := e.readFileContent(result.FilePath)
+		if err != nil {
+			continue
+		}
+		detector.AddFile(result.FilePath, result.Language, content)
+	}
+	return detector.Detect(results)
+}
+
+// importCoverage loads the configured or discovered coverage reports and applies
+// them to the analyzed files. It returns the reports that were loaded; unreadable
+// or unrecognized artifacts are skipped.
+func (e *Engine) importCoverage(rootPath string, results []*parser.AnalysisResult) []string {
+	var paths []string
+	for _, path := range e.config.CoverageReports {
+		// Relative report paths are resolved against the analyzed root
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rootPath, path)
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		paths = coverage.Discover(rootPath, e.config.ExcludePatterns)
+	}
+
+	report := coverage.NewReport()
+	for _, path := range paths {
+		_ = report.LoadFile(path)
+	}
+	if report.FileCount() == 0 {
+		return nil
+	}
+
+	report.Apply(results, rootPath)
+	return report.Sources
+}
+
+// checkManifests loads the dependency manifests under rootPath and compares
+// them with the imports of the analyzed files. Unreadable or malformed
+// manifests are skipped.
+func (e *Engine) checkManifests(rootPath string, results []*parser.AnalysisResult) []metrics.ModuleDependencies {
+	var manifests []*manifest.Manifest
+	for _, path := range manifest.Discover(rootPath, e.config.ExcludePatterns) {
+		if m, err := manifest.Load(path); err == nil {
+			manifests = append(manifests, m)
+		}
+	}
+	return metrics.CheckModuleDependencies(manifests, results)
+}
+
+// mineHistory attaches git history to each analyzed file and returns the mined
+// commits. It does nothing when history mining is disabled or the root is not
+// inside a git working tree.
+func (e *Engine) mineHistory(rootPath string, results []*parser.AnalysisResult) []git.Commit {
+	if !e.config.GitHistory {
+		return nil
+	}
+
+	repo, err := git.Open(rootPath)
+	if err != nil {
+		return nil
+	}
+
+	commits, err := repo.Log()
+	if err != nil {
+		return nil
+	}
+
+	since := e.historySince()
+	histories := git.BuildFileHistory(commits, since)
+	for _, result := range results {
+		rel, err := filepath.Rel(rootPath, result.FilePath)
+		if err != nil {
+			continue
+		}
+		if history, exists := histories[filepath.ToSlash(rel)]; exists {
+			result.History = history
+		}
+	}
+
+	e.countFunctionChanges(repo, rootPath, results, since)
+	return commits
+}
+
+// historySince returns the start of the history window, or zero for all history
+func (e *Engine) historySince() time.Time {
+	if e.config.HistoryWindowDays <= 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -e.config.HistoryWindowDays)
+}
+
+// couplingOptions returns the temporal coupling thresholds from the configuration
+func (e *Engine) couplingOptions() metrics.CouplingOptions {
+	options := metrics.DefaultCouplingOptions()
+	options.Since = e.historySince()
+	options.MinSharedCommits = e.config.CouplingMinSharedCommits
+	options.MinStrength = e.config.CouplingMinStrength
+	return options
+}
+
+// deadCodeOptions returns the dead code entry points from the configuration
+func (e *Engine) deadCodeOptions() metrics.DeadCodeOptions {
+	return metrics.DeadCodeOptions{EntryPoints: e.config.DeadCodeEntryPoints}
+}
+
+// AnalyzeTemporalCoupling analyzes the files under rootPath and reports the pairs
+// that change together in its git history. Unlike the enhanced analysis, it fails
+// when rootPath is not inside a git working tree.
+func (e *Engine) AnalyzeTemporalCoupling(rootPath string) (*metrics.TemporalCoupling, error) {
+	analysis, err := e.AnalyzeDirectory(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.Open(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.Log()
+	if err != nil {
+		return nil, err
+	}
+
+	coupling := metrics.AnalyzeTemporalCoupling(commits, analysis.FileResults, rootPath, e.couplingOptions())
+	return &coupling, nil
+}
+
+// MaxTracedFiles limits how many of the most changed files get function-level
+// change counts, since following line ranges reads a file's whole patch history.
+// Functions of other files keep a ChangeCount of 0.
+const MaxTracedFiles = 30
+
+// countFunctionChanges records, for the functions and methods of the most
+// frequently changed files, how many commits in the history window changed
+// lines of the function, following it back as code around it moved
+func (e *Engine) countFunctionChanges(repo *git.Repository, rootPath string, results []*parser.AnalysisResult, since time.Time) {
+	var changed []*parser.AnalysisResult
+	for _, result := range results {
+		if result.History != nil && result.History.CommitCount > 0 {
+			changed = append(changed, result)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if changed[i].History.CommitCount != changed[j].History.CommitCount {
+			return changed[i].History.CommitCount > changed[j].History.CommitCount
+		}
+		return changed[i].FilePath < changed[j].FilePath
+	})
+	if len(changed) > MaxTracedFiles {
+		changed = changed[:MaxTracedFiles]
+	}
+
+	for _, result := range changed {
+		rel, err := filepath.Rel(rootPath, result.FilePath)
+		if err != nil {
+			continue
+		}
+
+		var functions []*parser.FunctionInfo
+		for i := range result.Functions {
+			functions = append(functions, &result.Functions[i])
+		}
+		for i := range result.Classes {
+			for j := range result.Classes[i].Methods {
+				functions = append(functions, &result.Classes[i].Methods[j])
+			}
+		}
+		if len(functions) == 0 {
+			continue
+		}
+		ranges := make([]git.LineRange, len(functions))
+		for i, fn := range functions {
+			ranges[i] = git.LineRange{Start: fn.LineStart, End: fn.LineEnd}
+		}
+
+		counts, err := repo.RangeChanges(filepath.ToSlash(rel), ranges, since)
+		if err != nil {
+			continue
+		}
+		for i, fn := range functions {
+			fn.ChangeCount = counts[i]
+		}
+	}
+}
+
 // GetSupportedExtensions returns all supported file extensions
 func (e *Engine) GetSupportedExtensions() []string {
 	return e.parserRegistry.GetSupportedExtensions()
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
 	"github.com/tito-sala/codebasereaderv2/internal/parser"
 )
 
@@ -91,6 +92,65 @@ func TestWorkerPool(t *testing.T) {
 	}
 }
 
+func TestWorkerPool_SubmitJobWaitsForRoom(t *testing.T) {
+	pool := NewWorkerPool(1)
+	pool.Start()
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	blocking := &MockParser{name: "Go", extensions: []string{"go"}, parseFunc: func(filePath string, content []byte) (*parser.AnalysisResult, error) {
+		<-release
+		return &parser.AnalysisResult{FilePath: filePath}, nil
+	}}
+	job := AnalysisJob{FilePath: "a.go", Parser: blocking, MetricsCalculator: metrics.NewCalculator()}
+
+	// One job is being parsed and the rest fill the queue
+	capacity := cap(pool.jobQueue)
+	for i := 0; i <= capacity; i++ {
+		if err := pool.SubmitJob(job); err != nil {
+			t.Fatalf("SubmitJob %d failed: %v", i, err)
+		}
+	}
+
+	submitted := make(chan error, 1)
+	go func() { submitted <- pool.SubmitJob(job) }()
+	select {
+	case err := <-submitted:
+		t.Fatalf("Expected SubmitJob to wait for room in the full queue, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	for i := 0; i < capacity+2; i++ {
+		<-pool.GetResultChannel()
+	}
+	if err := <-submitted; err != nil {
+		t.Errorf("Expected the waiting job to be submitted, got %v", err)
+	}
+}
+
+func TestEngine_AnalyzeMoreFilesThanQueueCapacity(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 250; i++ {
+		if err := os.WriteFile(filepath.Join(root, fmt.Sprintf("file%d.go", i)), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := DefaultConfig()
+	config.MaxWorkers = 1 // queues hold 100 jobs and results
+	engine := NewEngine(config)
+	engine.GetParserRegistry().RegisterParser(&MockParser{name: "Go", extensions: []string{"go"}})
+
+	analysis, err := engine.AnalyzeDirectory(root)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory failed: %v", err)
+	}
+	if analysis.TotalFiles != 250 {
+		t.Errorf("Expected 250 files, got %d", analysis.TotalFiles)
+	}
+}
+
 func TestProjectAnalysis(t *testing.T) {
 	analysis := &ProjectAnalysis{
 		RootPath:   "/test/project",
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// DefaultProjectConfigFile is the name of the project configuration file looked
+// up in the analyzed root
+const DefaultProjectConfigFile = ".codebasereader.json"
+
+// ProjectConfig holds the settings a project keeps in its repository, such as
+// the layering rules its imports must follow and its quality gates
+type ProjectConfig struct {
+	ArchitectureRules []metrics.ArchitectureRule `json:"architecture_rules"`
+	QualityGates      *metrics.QualityGates      `json:"quality_gates"`
+}
+
+// LoadProjectConfig reads the project configuration for rootPath from the
+// configured file, resolved against the root when relative. A missing file
+// yields an empty configuration; a malformed one is an error.
+func (e *Engine) LoadProjectConfig(rootPath string) (*ProjectConfig, error) {
+	configPath := projectFile(rootPath, e.config.ProjectConfig, DefaultProjectConfigFile)
+
+	project := &ProjectConfig{}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return project, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+	if err := json.Unmarshal(data, project); err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", configPath, err)
+	}
+
+	for _, rule := range project.ArchitectureRules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid project config %s: %w", configPath, err)
+		}
+	}
+	if project.QualityGates != nil {
+		if err := project.QualityGates.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid project config %s: %w", configPath, err)
+		}
+	}
+
+	return project, nil
+}
+
+// projectFile resolves a file the project keeps in its repository against the
+// analyzed root, falling back to its default name when none is configured
+func projectFile(rootPath, configured, fallback string) string {
+	if configured == "" {
+		configured = fallback
+	}
+	if !filepath.IsAbs(configured) {
+		configured = filepath.Join(rootPath, configured)
+	}
+	return configured
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	engine := NewEngine(DefaultConfig())
+
+	project, err := engine.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("Expected a missing project config to be fine, got %v", err)
+	}
+	if len(project.ArchitectureRules) != 0 {
+		t.Errorf("Expected no rules, got %+v", project.ArchitectureRules)
+	}
+
+	config := `{
+  "architecture_rules": [
+    {"name": "engine-without-ui", "from": "internal/engine/...", "forbid": ["internal/tui/..."]}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(root, DefaultProjectConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err = engine.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig failed: %v", err)
+	}
+	if len(project.ArchitectureRules) != 1 || project.ArchitectureRules[0].Name != "engine-without-ui" {
+		t.Errorf("Expected the engine-without-ui rule, got %+v", project.ArchitectureRules)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, DefaultProjectConfigFile), []byte(`{"architecture_rules": [{"from": "a"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.LoadProjectConfig(root); err == nil || !strings.Contains(err.Error(), "allow or forbid") {
+		t.Errorf("Expected a rule without allow or forbid to be rejected, got %v", err)
+	}
+}
+
+func TestLoadProjectConfig_QualityGates(t *testing.T) {
+	root := t.TempDir()
+	engine := NewEngine(DefaultConfig())
+
+	config := `{"quality_gates": {"max_function_complexity": 15, "min_grade": "B", "max_circular_dependencies": 0}}`
+	if err := os.WriteFile(filepath.Join(root, DefaultProjectConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := engine.LoadProjectConfig(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig failed: %v", err)
+	}
+	gates := project.QualityGates
+	if gates == nil || gates.MaxFunctionComplexity != 15 || gates.MinGrade != "B" || gates.MaxCircularDependencies == nil || *gates.MaxCircularDependencies != 0 {
+		t.Errorf("Expected the configured quality gates, got %+v", gates)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, DefaultProjectConfigFile), []byte(`{"quality_gates": {"min_grade": "E"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.LoadProjectConfig(root); err == nil || !strings.Contains(err.Error(), "min_grade") {
+		t.Errorf("Expected an invalid grade to be rejected, got %v", err)
+	}
+}
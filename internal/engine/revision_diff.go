@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// AnalyzeRevisions compares the supported files changed between the two ends of
+// a revision range, such as "main..feature", in the git repository at rootPath.
+// Files are read from git at each revision, so the working tree is not touched.
+func (e *Engine) AnalyzeRevisions(rootPath, revisionRange string) (*metrics.RevisionDiff, error) {
+	repo, err := git.Open(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	base, head, err := repo.ResolveRange(revisionRange)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := repo.ChangedFiles(base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	walker := NewFileWalker(e.parserRegistry, e.config)
+	var files []metrics.RevisionFile
+	for _, change := range changed {
+		filePath := filepath.Join(rootPath, filepath.FromSlash(change.Path))
+		if walker.getParserForFile(filePath) == nil || walker.isExcluded(filePath, rootPath) {
+			continue
+		}
+
+		file := metrics.RevisionFile{Path: change.Path, OldPath: change.OldPath, Status: change.Status}
+		tooLarge := false
+		if change.Status != git.StatusAdded {
+			if file.Before, err = e.analyzeRevisionFile(repo, base, rootPath, change.OldPath); err != nil {
+				return nil, err
+			}
+			tooLarge = file.Before == nil
+		}
+		if change.Status != git.StatusDeleted {
+			if file.After, err = e.analyzeRevisionFile(repo, head, rootPath, change.Path); err != nil {
+				return nil, err
+			}
+			tooLarge = tooLarge || file.After == nil
+		}
+		if tooLarge {
+			// Comparing one side only would count the whole file as added or
+			// removed, so the file is listed without metrics
+			file.Before, file.After = nil, nil
+			file.Skipped = fmt.Sprintf("exceeds size limit (%d bytes)", e.config.MaxFileSize)
+		}
+		files = append(files, file)
+	}
+
+	diff := metrics.CompareRevisions(files)
+	diff.Base, diff.Head, diff.Range = base, head, revisionRange
+	return &diff, nil
+}
+
+// analyzeRevisionFile parses a file as it was at a commit. The result carries
+// the file's path in the working tree, so it reads like a current analysis.
+// Files over MaxFileSize are skipped as the walker skips them: the result is
+// nil without an error.
+func (e *Engine) analyzeRevisionFile(repo *git.Repository, rev, rootPath, relPath string) (*parser.AnalysisResult, error) {
+	content, err := repo.FileAt(rev, relPath)
+	if err != nil {
+		return nil, err
+	}
+	if e.config.MaxFileSize > 0 && int64(len(content)) > e.config.MaxFileSize {
+		return nil, nil
+	}
+
+	filePath := filepath.Join(rootPath, filepath.FromSlash(relPath))
+	fileParser, err := e.parserRegistry.GetParser(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("no parser available for file %s: %w", relPath, err)
+	}
+	result, err := fileParser.Parse(filePath, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %.8s: %w", relPath, rev, err)
+	}
+	e.metricsCalculator.CalculateFileMetrics(result, content)
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeRevisions_SkipsOversizedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	writeFile("small.go", "package a\n")
+	writeFile("generated.go", "package a\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "one")
+	writeFile("small.go", "package a\n\n// grown\n")
+	// Grows past the limit, so the head side cannot be analyzed
+	writeFile("generated.go", "package a\n"+strings.Repeat("// generated\n", 20))
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "two")
+
+	config := DefaultConfig()
+	config.MaxFileSize = 100
+	engine := NewEngine(config)
+	engine.GetParserRegistry().RegisterParser(&MockParser{name: "Go", extensions: []string{"go"}})
+
+	diff, err := engine.AnalyzeRevisions(root, "HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("AnalyzeRevisions failed: %v", err)
+	}
+	if len(diff.Files) != 2 {
+		t.Fatalf("Expected both changed files, got %+v", diff.Files)
+	}
+	for _, file := range diff.Files {
+		switch file.Path {
+		case "generated.go":
+			if file.Skipped == "" || file.Before != nil || file.After != nil {
+				t.Errorf("Expected the oversized file to be skipped without metrics, got %+v", file)
+			}
+		case "small.go":
+			if file.Skipped != "" || file.Before == nil || file.After == nil {
+				t.Errorf("Expected the small file to be analyzed at both revisions, got %+v", file)
+			}
+		}
+	}
+	if diff.Before.Files != 1 || diff.After.Files != 1 {
+		t.Errorf("Expected the totals to count only the analyzed file, got %d and %d", diff.Before.Files, diff.After.Files)
+	}
+}
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/trends"
+)
+
+// DefaultTrendsFile is the name of the trend store looked up in the analyzed root
+const DefaultTrendsFile = ".codebasereader-trends.jsonl"
+
+// TrendStore returns the store of per-run snapshots for rootPath
+func (e *Engine) TrendStore(rootPath string) *trends.Store {
+	return trends.NewStore(projectFile(rootPath, e.config.Trends, DefaultTrendsFile))
+}
+
+// LoadTrends reads the recorded snapshots for rootPath, oldest first
+func (e *Engine) LoadTrends(rootPath string) ([]trends.Snapshot, error) {
+	return e.TrendStore(rootPath).Load()
+}
+
+// RecordTrend appends a snapshot of an analysis of rootPath to its trend
+// store, tagged with the checked-out commit when the root is in a git working tree
+func (e *Engine) RecordTrend(rootPath string, analysis *metrics.EnhancedProjectAnalysis) error {
+	commit := ""
+	if repo, err := git.Open(rootPath); err == nil {
+		commit, _ = repo.Head()
+	}
+	return e.TrendStore(rootPath).Append(trends.NewSnapshot(analysis, rootPath, commit))
+}
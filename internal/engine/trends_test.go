@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+func TestRecordAndLoadTrends(t *testing.T) {
+	root := t.TempDir()
+	engine := NewEngine(DefaultConfig())
+
+	snapshots, err := engine.LoadTrends(root)
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("Expected no trends in an empty project, got %d snapshots, %v", len(snapshots), err)
+	}
+
+	for _, complexity := range []int{10, 12} {
+		analysis := &metrics.EnhancedProjectAnalysis{
+			RootPath:       root,
+			GeneratedAt:    time.Now(),
+			ProjectMetrics: metrics.ProjectMetrics{TotalComplexity: complexity},
+		}
+		if err := engine.RecordTrend(root, analysis); err != nil {
+			t.Fatalf("RecordTrend failed: %v", err)
+		}
+	}
+
+	snapshots, err = engine.LoadTrends(root)
+	if err != nil {
+		t.Fatalf("LoadTrends failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[1].ProjectMetrics.TotalComplexity != 12 {
+		t.Errorf("Expected both runs recorded in order, got %+v", snapshots)
+	}
+	if snapshots[0].Commit != "" {
+		t.Errorf("Expected no commit outside a git working tree, got %q", snapshots[0].Commit)
+	}
+}
@@ -10,11 +10,12 @@ import (
 
 // LanguageStats contains statistics for a specific programming language
 type LanguageStats struct {
-	FileCount     int `json:"file_count"`
-	LineCount     int `json:"line_count"`
-	FunctionCount int `json:"function_count"`
-	ClassCount    int `json:"class_count"`
-	Complexity    int `json:"complexity"`
+	FileCount           int `json:"file_count"`
+	LineCount           int `json:"line_count"`
+	FunctionCount       int `json:"function_count"`
+	ClassCount          int `json:"class_count"`
+	Complexity          int `json:"complexity"`
+	CognitiveComplexity int `json:"cognitive_complexity"`
 }
 
 // ProjectAnalysis contains the complete analysis results for a project
@@ -35,6 +36,7 @@ type AnalysisJob struct {
 	Content           []byte
 	Parser            parser.Parser
 	MetricsCalculator *metrics.Calculator
+	Cache             *parseCache // nil when results are not cached
 }
 
 // AnalysisJobResult contains the result of processing an analysis job
@@ -53,6 +55,38 @@ type Config struct {
 	IncludePatterns []string `json:"include_patterns"`
 	MaxFileSize     int64    `json:"max_file_size"` // in bytes
 	Timeout         int      `json:"timeout"`       // in seconds
+
+	// Minimum clone length in tokens for duplication detection
+	DuplicationMinTokens int `json:"duplication_min_tokens"`
+
+	// Coverage artifacts to import; when empty, known report names are discovered under the root
+	CoverageReports []string `json:"coverage_reports"`
+
+	// Git history mining; skipped when the root is not inside a git working tree
+	GitHistory        bool `json:"git_history"`
+	HistoryWindowDays int  `json:"history_window_days"` // 0 includes all history
+
+	// Temporal coupling thresholds for files that change in the same commits
+	CouplingMinSharedCommits int     `json:"coupling_min_shared_commits"`
+	CouplingMinStrength      float64 `json:"coupling_min_strength"` // percent
+
+	// Name patterns of functions called from outside the project, never reported as dead code
+	DeadCodeEntryPoints []string `json:"dead_code_entry_points"`
+
+	// Project configuration file with architecture rules, relative to the analyzed root
+	ProjectConfig string `json:"project_config"`
+
+	// Baseline file of accepted violations, relative to the analyzed root
+	Baseline string `json:"baseline"`
+
+	// Trend store of per-run snapshots, relative to the analyzed root; runs are
+	// only recorded in it when RecordTrends is set
+	Trends       string `json:"trends"`
+	RecordTrends bool   `json:"record_trends"`
+
+	// Reuse the parse results of files whose content did not change since the
+	// previous run of the same engine
+	IncrementalCache bool `json:"incremental_cache"`
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -65,5 +99,18 @@ func DefaultConfig() *Config {
 		IncludePatterns: []string{},
 		MaxFileSize:     1024 * 1024, // 1MB
 		Timeout:         30,          // 30 seconds
+
+		DuplicationMinTokens: metrics.DefaultMinCloneTokens,
+		GitHistory:           true,
+		HistoryWindowDays:    365,
+
+		CouplingMinSharedCommits: metrics.DefaultCouplingOptions().MinSharedCommits,
+		CouplingMinStrength:      metrics.DefaultCouplingOptions().MinStrength,
+
+		DeadCodeEntryPoints: metrics.DefaultDeadCodeOptions().EntryPoints,
+
+		ProjectConfig: DefaultProjectConfigFile,
+		Baseline:      DefaultBaselineFile,
+		Trends:        DefaultTrendsFile,
 	}
 }
@@ -229,6 +229,17 @@ func (fw *FileWalker) shouldExcludeFile(filePath, rootPath string) bool {
 	return false
 }
 
+// isExcluded checks whether a file, or any directory above it within the root,
+// is excluded from analysis, for files that are not found by walking
+func (fw *FileWalker) isExcluded(filePath, rootPath string) bool {
+	for dir := filepath.Dir(filePath); dir != rootPath && strings.HasPrefix(dir, rootPath); dir = filepath.Dir(dir) {
+		if fw.shouldExcludeDirectory(dir, rootPath) {
+			return true
+		}
+	}
+	return fw.shouldExcludeFile(filePath, rootPath)
+}
+
 // getParserForFile returns the appropriate parser for a file, or nil if unsupported
 func (fw *FileWalker) getParserForFile(filePath string) parser.Parser {
 	parser, err := fw.parserRegistry.GetParser(filePath)
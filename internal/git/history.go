@@ -0,0 +1,219 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Field and record separators used in the git log format
+const (
+	recordSeparator = "\x1e"
+	fieldSeparator  = "\x1f"
+)
+
+// logFormat prints one header line per commit: hash, author name, email and timestamp
+const logFormat = "--format=" + recordSeparator + "%H" + fieldSeparator + "%an" + fieldSeparator + "%aE" + fieldSeparator + "%at"
+
+// FileChange is one file's line changes in a commit
+type FileChange struct {
+	Path    string
+	Added   int
+	Removed int
+}
+
+// Commit is a non-merge commit and the files it changed
+type Commit struct {
+	Hash        string
+	AuthorName  string
+	AuthorEmail string
+	Time        time.Time
+	Changes     []FileChange
+}
+
+// Log returns the non-merge commits that touched files under the repository
+// root, newest first. Paths are slash-separated and relative to the root.
+func (r *Repository) Log() ([]Commit, error) {
+	output, err := r.run("log", "--no-merges", "--no-renames", "--relative", "--numstat", logFormat, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output)
+}
+
+// FileHistory mines the log of the repository and returns the history of every
+// file under the repository root, keyed by slash-separated path relative to it.
+// Commit counts and line churn only include commits made at or after since;
+// a zero since includes all history.
+func (r *Repository) FileHistory(since time.Time) (map[string]*parser.GitHistory, error) {
+	commits, err := r.Log()
+	if err != nil {
+		return nil, err
+	}
+	return BuildFileHistory(commits, since), nil
+}
+
+// parseLog reads "git log --numstat" output in logFormat
+func parseLog(output []byte) ([]Commit, error) {
+	var commits []Commit
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, recordSeparator) {
+			fields := strings.Split(strings.TrimPrefix(line, recordSeparator), fieldSeparator)
+			if len(fields) < 4 {
+				continue
+			}
+			seconds, _ := strconv.ParseInt(fields[3], 10, 64)
+			commits = append(commits, Commit{
+				Hash:        fields[0],
+				AuthorName:  fields[1],
+				AuthorEmail: strings.ToLower(fields[2]),
+				Time:        time.Unix(seconds, 0),
+			})
+			continue
+		}
+
+		// numstat: "added<TAB>removed<TAB>path"; binary files report "-"
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 || len(commits) == 0 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		removed, _ := strconv.Atoi(parts[1])
+
+		commit := &commits[len(commits)-1]
+		commit.Changes = append(commit.Changes, FileChange{Path: parts[2], Added: added, Removed: removed})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+	return commits, nil
+}
+
+// fileStats accumulates history for one path while commits are read
+type fileStats struct {
+	history *parser.GitHistory
+	authors map[string]*parser.AuthorContribution
+}
+
+// BuildFileHistory summarizes commits per file. Commit counts and line churn only
+// include commits made at or after since; a zero since includes all commits.
+func BuildFileHistory(commits []Commit, since time.Time) map[string]*parser.GitHistory {
+	files := make(map[string]*fileStats)
+
+	for _, commit := range commits {
+		inWindow := since.IsZero() || !commit.Time.Before(since)
+
+		for _, change := range commit.Changes {
+			stats := files[change.Path]
+			if stats == nil {
+				stats = &fileStats{
+					history: &parser.GitHistory{},
+					authors: make(map[string]*parser.AuthorContribution),
+				}
+				files[change.Path] = stats
+			}
+			history := stats.history
+
+			if history.LastModified.IsZero() || commit.Time.After(history.LastModified) {
+				history.LastModified = commit.Time
+			}
+			if history.FirstModified.IsZero() || commit.Time.Before(history.FirstModified) {
+				history.FirstModified = commit.Time
+			}
+
+			if inWindow {
+				history.CommitCount++
+				history.LinesAdded += change.Added
+				history.LinesRemoved += change.Removed
+			}
+
+			key := commit.AuthorEmail
+			if key == "" {
+				key = commit.AuthorName
+			}
+			author := stats.authors[key]
+			if author == nil {
+				author = &parser.AuthorContribution{Name: commit.AuthorName, Email: commit.AuthorEmail}
+				stats.authors[key] = author
+			}
+			author.Commits++
+			author.Lines += change.Added + change.Removed
+		}
+	}
+
+	histories := make(map[string]*parser.GitHistory, len(files))
+	for path, stats := range files {
+		history := stats.history
+		for _, author := range stats.authors {
+			history.Authors = append(history.Authors, *author)
+		}
+		SortAuthors(history.Authors)
+		history.AuthorCount = len(history.Authors)
+		history.BusFactor = BusFactor(history.Authors)
+		histories[path] = history
+	}
+	return histories
+}
+
+// SortAuthors orders contributions by lines changed, then commits, largest first
+func SortAuthors(authors []parser.AuthorContribution) {
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Lines != authors[j].Lines {
+			return authors[i].Lines > authors[j].Lines
+		}
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Email < authors[j].Email
+	})
+}
+
+// BusFactor returns the fewest authors who together made more than half of the
+// changes. Lines changed are used when known, commits otherwise (binary files).
+func BusFactor(authors []parser.AuthorContribution) int {
+	if len(authors) == 0 {
+		return 0
+	}
+
+	totalLines, totalCommits := 0, 0
+	for _, author := range authors {
+		totalLines += author.Lines
+		totalCommits += author.Commits
+	}
+
+	weights := make([]int, len(authors))
+	total := totalLines
+	for i, author := range authors {
+		weights[i] = author.Lines
+		if totalLines == 0 {
+			weights[i] = author.Commits
+		}
+	}
+	if totalLines == 0 {
+		total = totalCommits
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(weights)))
+
+	owned := 0
+	for i, weight := range weights {
+		owned += weight
+		if owned*2 > total {
+			return i + 1
+		}
+	}
+	return len(authors)
+}
@@ -0,0 +1,142 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestParseLogAndBuildFileHistory(t *testing.T) {
+	day := int64(24 * 60 * 60)
+	now := time.Now().Unix()
+	header := func(name, email string, at int64) string {
+		return recordSeparator + "abc" + fieldSeparator + name + fieldSeparator + email + fieldSeparator + strconv.FormatInt(at, 10) + "\n"
+	}
+
+	output := header("Ada", "Ada@example.com", now-day) +
+		"\n10\t2\tpkg/a.go\n5\t0\tpkg/b.go\n" +
+		header("Linus", "linus@example.com", now-10*day) +
+		"\n3\t3\tpkg/a.go\n-\t-\tassets/logo.png\n" +
+		header("Ada", "ada@example.com", now-400*day) +
+		"\n100\t0\tpkg/a.go\n"
+
+	commits, err := parseLog([]byte(output))
+	if err != nil {
+		t.Fatalf("parseLog failed: %v", err)
+	}
+	if len(commits) != 3 || len(commits[1].Changes) != 2 {
+		t.Fatalf("Expected 3 commits with 2 changes in the second, got %+v", commits)
+	}
+
+	histories := BuildFileHistory(commits, time.Unix(now-30*day, 0))
+
+	a := histories["pkg/a.go"]
+	if a == nil {
+		t.Fatal("Expected history for pkg/a.go")
+	}
+	// The oldest commit is outside the window
+	if a.CommitCount != 2 || a.LinesAdded != 13 || a.LinesRemoved != 5 {
+		t.Errorf("Expected 2 commits +13/-5, got %d +%d/-%d", a.CommitCount, a.LinesAdded, a.LinesRemoved)
+	}
+	// Authorship covers all history; emails are case-insensitive
+	if a.AuthorCount != 2 || a.Authors[0].Email != "ada@example.com" || a.Authors[0].Commits != 2 {
+		t.Errorf("Unexpected authors: %+v", a.Authors)
+	}
+	if a.BusFactor != 1 {
+		t.Errorf("Expected bus factor 1, got %d", a.BusFactor)
+	}
+	if a.FirstModified.Unix() != now-400*day || a.LastModified.Unix() != now-day {
+		t.Errorf("Unexpected first/last modified: %v / %v", a.FirstModified, a.LastModified)
+	}
+
+	logo := histories["assets/logo.png"]
+	if logo == nil || logo.CommitCount != 1 || logo.LinesAdded != 0 || logo.BusFactor != 1 {
+		t.Errorf("Unexpected binary file history: %+v", logo)
+	}
+}
+
+func TestBusFactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		authors  []parser.AuthorContribution
+		expected int
+	}{
+		{"no authors", nil, 0},
+		{"single owner", []parser.AuthorContribution{{Lines: 90}, {Lines: 10}}, 1},
+		{"even split", []parser.AuthorContribution{{Lines: 50}, {Lines: 50}}, 2},
+		{"spread", []parser.AuthorContribution{{Lines: 30}, {Lines: 30}, {Lines: 20}, {Lines: 20}}, 2},
+		{"commits for binary files", []parser.AuthorContribution{{Commits: 1}, {Commits: 1}, {Commits: 1}}, 2},
+	}
+
+	for _, tt := range tests {
+		if got := BusFactor(tt.authors); got != tt.expected {
+			t.Errorf("%s: expected bus factor %d, got %d", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestRepositoryFileHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("init", "-q")
+	writeFile("src/main.go", "package main\n\nfunc main() {}\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "initial")
+	writeFile("src/main.go", "package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+	gitCmd("commit", "-q", "-am", "print")
+
+	if _, err := Open(filepath.Join(root, "missing")); err == nil {
+		t.Error("Expected an error opening a missing directory")
+	}
+
+	// Paths are relative to the directory the repository is opened at
+	repo, err := Open(filepath.Join(root, "src"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	histories, err := repo.FileHistory(time.Time{})
+	if err != nil {
+		t.Fatalf("FileHistory failed: %v", err)
+	}
+
+	history := histories["main.go"]
+	if history == nil {
+		t.Fatalf("Expected history for main.go, got %v", histories)
+	}
+	if history.CommitCount != 2 || history.LinesAdded != 6 || history.LinesRemoved != 1 {
+		t.Errorf("Expected 2 commits +6/-1, got %d +%d/-%d", history.CommitCount, history.LinesAdded, history.LinesRemoved)
+	}
+	if history.AuthorCount != 1 || history.Authors[0].Name != "Ada" {
+		t.Errorf("Unexpected authors: %+v", history.Authors)
+	}
+}
@@ -0,0 +1,202 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LineRange is a span of lines of a file, 1-based and inclusive
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// hunk is one change of a diff: oldCount lines from oldStart were replaced by
+// newCount lines from newStart. An empty side starts after the line it names,
+// as in unified diff headers.
+type hunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+// rangeCommit is a commit and the hunks it changed a file with
+type rangeCommit struct {
+	authored time.Time
+	hunks    []hunk
+}
+
+// hunkHeader matches unified diff hunk headers such as "@@ -12,3 +12,5 @@"
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// RangeChanges counts, for each range of lines of a file in the working tree,
+// the non-merge commits at or after since that changed any line in it. Like
+// git log -L, each range is followed back through history as lines above it
+// are added and removed, and across renames, until the commit that added it.
+// Path is relative to the repository root. A zero since counts all commits.
+func (r *Repository) RangeChanges(path string, ranges []LineRange, since time.Time) ([]int, error) {
+	uncommitted, err := r.run("diff", "--no-ext-diff", "--unified=0", "HEAD", "--", path)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"log", "--follow", "--no-merges", "--no-ext-diff", "-p", "--unified=0",
+		"--format=" + recordSeparator + "%at"}
+	if !since.IsZero() {
+		// Commit dates are at or after author dates, so no commit in the window is missed
+		args = append(args, "--since="+strconv.FormatInt(since.Unix(), 10))
+	}
+	output, err := r.run(append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	hunks, err := parseHunks(uncommitted)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := parseRangeLog(output)
+	if err != nil {
+		return nil, err
+	}
+	return countRangeChanges(ranges, hunks, commits, since), nil
+}
+
+// countRangeChanges follows ranges of the working tree back through the
+// uncommitted changes and then through commits, newest first, counting the
+// commits in the window that changed each range
+func countRangeChanges(ranges []LineRange, uncommitted []hunk, commits []rangeCommit, since time.Time) []int {
+	counts := make([]int, len(ranges))
+	for i, lines := range ranges {
+		lines, exists := traceRange(lines, uncommitted)
+		for _, commit := range commits {
+			if !exists {
+				break
+			}
+			if rangeChanged(lines, commit.hunks) && (since.IsZero() || !commit.authored.Before(since)) {
+				counts[i]++
+			}
+			lines, exists = traceRange(lines, commit.hunks)
+		}
+	}
+	return counts
+}
+
+// rangeChanged reports whether a diff added, changed or removed lines within a
+// range of its new version
+func rangeChanged(lines LineRange, hunks []hunk) bool {
+	for _, h := range hunks {
+		if h.newCount > 0 && h.newStart <= lines.End && h.newStart+h.newCount-1 >= lines.Start {
+			return true
+		}
+		// Lines removed between two lines of the range
+		if h.newCount == 0 && h.newStart >= lines.Start && h.newStart < lines.End {
+			return true
+		}
+	}
+	return false
+}
+
+// traceRange maps a range of the new version of a diff to the old version. It
+// reports false when none of its lines existed before.
+func traceRange(lines LineRange, hunks []hunk) (LineRange, bool) {
+	if lines.Start < 1 || lines.End < lines.Start {
+		return lines, false
+	}
+	old := LineRange{Start: traceLine(lines.Start, hunks, false), End: traceLine(lines.End, hunks, true)}
+	return old, old.Start <= old.End
+}
+
+// traceLine maps a line of the new version of a diff to the old version. A
+// changed line maps to the first or, for the end of a range, last line the
+// hunk replaced, so ranges shrink to the lines that existed before.
+func traceLine(line int, hunks []hunk, end bool) int {
+	shift := 0
+	for _, h := range hunks {
+		if h.newCount > 0 && line >= h.newStart && line < h.newStart+h.newCount {
+			switch {
+			case h.oldCount == 0 && end:
+				return h.oldStart
+			case h.oldCount == 0:
+				return h.oldStart + 1
+			case end:
+				return h.oldStart + h.oldCount - 1
+			default:
+				return h.oldStart
+			}
+		}
+		if h.newStart+max(h.newCount, 1)-1 >= line {
+			break
+		}
+		shift += h.oldCount - h.newCount
+	}
+	return line + shift
+}
+
+// parseRangeLog reads "git log -p --unified=0" output with one header line of
+// the author timestamp per commit
+func parseRangeLog(output []byte) ([]rangeCommit, error) {
+	var commits []rangeCommit
+	var diff bytes.Buffer
+	flush := func() error {
+		defer diff.Reset()
+		if len(commits) == 0 {
+			return nil
+		}
+		hunks, err := parseHunks(diff.Bytes())
+		commits[len(commits)-1].hunks = hunks
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, recordSeparator) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			seconds, _ := strconv.ParseInt(strings.TrimPrefix(line, recordSeparator), 10, 64)
+			commits = append(commits, rangeCommit{authored: time.Unix(seconds, 0)})
+			continue
+		}
+		diff.WriteString(line)
+		diff.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// parseHunks reads the hunk headers of a unified diff of one file
+func parseHunks(diff []byte) ([]hunk, error) {
+	var hunks []hunk
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := hunkHeader.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		count := func(s string) int {
+			if s == "" {
+				return 1
+			}
+			n, _ := strconv.Atoi(s)
+			return n
+		}
+		oldStart, _ := strconv.Atoi(match[1])
+		newStart, _ := strconv.Atoi(match[3])
+		hunks = append(hunks, hunk{oldStart, count(match[2]), newStart, count(match[4])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git diff: %w", err)
+	}
+	return hunks, nil
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceRange(t *testing.T) {
+	// Two lines inserted after line 2, line 10 replaced by three, line 20 removed
+	hunks := []hunk{{2, 0, 3, 2}, {10, 1, 12, 3}, {20, 1, 23, 0}}
+
+	cases := []struct {
+		lines    LineRange
+		expected LineRange
+		changed  bool
+		exists   bool
+	}{
+		{LineRange{1, 2}, LineRange{1, 2}, false, true},
+		{LineRange{6, 8}, LineRange{4, 6}, false, true},
+		{LineRange{3, 4}, LineRange{3, 2}, true, false}, // added by the diff
+		{LineRange{12, 14}, LineRange{10, 10}, true, true},
+		{LineRange{16, 30}, LineRange{12, 27}, true, true}, // line 20 removed inside
+		{LineRange{26, 28}, LineRange{23, 25}, false, true},
+	}
+	for _, c := range cases {
+		old, exists := traceRange(c.lines, hunks)
+		if changed := rangeChanged(c.lines, hunks); changed != c.changed {
+			t.Errorf("Range %v: expected changed %v, got %v", c.lines, c.changed, changed)
+		}
+		if exists != c.exists || (exists && old != c.expected) {
+			t.Errorf("Range %v: expected %v (%v), got %v (%v)", c.lines, c.expected, c.exists, old, exists)
+		}
+	}
+}
+
+func TestCountRangeChanges_Window(t *testing.T) {
+	output := recordSeparator + "1710000000\n\ndiff --git a/a.go b/a.go\n@@ -3 +3 @@\n-\treturn 1\n+\treturn 2\n" +
+		recordSeparator + "1700000000\n\ndiff --git a/a.go b/a.go\nnew file mode 100644\n@@ -0,0 +1,4 @@\n+package a\n+func f() int {\n+\treturn 1\n+}\n"
+	commits, err := parseRangeLog([]byte(output))
+	if err != nil {
+		t.Fatalf("parseRangeLog failed: %v", err)
+	}
+	if len(commits) != 2 || len(commits[1].hunks) != 1 {
+		t.Fatalf("Expected two commits with one hunk each, got %+v", commits)
+	}
+
+	ranges := []LineRange{{2, 4}, {1, 1}}
+	if got := countRangeChanges(ranges, nil, commits, time.Time{}); got[0] != 2 || got[1] != 1 {
+		t.Errorf("Expected 2 and 1 commits over all history, got %v", got)
+	}
+	if got := countRangeChanges(ranges, nil, commits, time.Unix(1705000000, 0)); got[0] != 1 || got[1] != 0 {
+		t.Errorf("Expected 1 and 0 commits in the window, got %v", got)
+	}
+}
+
+func TestParseRangeLog_LongLine(t *testing.T) {
+	// A minified file can put more on one line than the scanner holds
+	output := recordSeparator + "1710000000\n\ndiff --git a/a.js b/a.js\n@@ -1 +1 @@\n+" + strings.Repeat("x", 2*1024*1024) + "\n"
+	if _, err := parseRangeLog([]byte(output)); err == nil {
+		t.Error("Expected an error for a line longer than the scanner buffer")
+	}
+	if _, err := parseLog([]byte(output)); err == nil {
+		t.Error("Expected parseLog to fail on the same line")
+	}
+}
+
+func TestRepositoryRangeChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	commit := func(name, message string, lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if message != "" {
+			gitCmd("add", "-A")
+			gitCmd("commit", "-q", "-m", message)
+		}
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	commit("a.go", "add f and g", "package a", "func f() {", "\tone()", "}", "func g() {", "\ttwo()", "}")
+	commit("a.go", "change g", "package a", "func f() {", "\tone()", "}", "func g() {", "\tthree()", "}")
+	commit("a.go", "add imports", "package a", "", "import \"fmt\"", "", "func f() {", "\tone()", "}", "func g() {", "\tthree()", "}")
+	commit("a.go", "change f", "package a", "", "import \"fmt\"", "", "func f() {", "\tfmt.Println()", "}", "func g() {", "\tthree()", "}")
+	gitCmd("mv", "a.go", "b.go")
+	gitCmd("commit", "-q", "-m", "rename")
+	commit("b.go", "change g again", "package a", "", "import \"fmt\"", "", "func f() {", "\tfmt.Println()", "}", "func g() {", "\tfour()", "}")
+	// Uncommitted lines above both functions move them in the working tree only
+	commit("b.go", "", "package a", "", "import \"fmt\"", "", "var x = 1", "", "func f() {", "\tfmt.Println()", "}", "func g() {", "\tfour()", "}")
+
+	repo, err := Open(root)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	counts, err := repo.RangeChanges("b.go", []LineRange{{7, 9}, {10, 12}, {5, 5}}, time.Time{})
+	if err != nil {
+		t.Fatalf("RangeChanges failed: %v", err)
+	}
+	// f: added and changed; g: added and changed twice, across the rename; x: not committed
+	if counts[0] != 2 || counts[1] != 3 || counts[2] != 0 {
+		t.Errorf("Expected 2, 3 and 0 commits, got %v", counts)
+	}
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Repository runs git commands against a local working tree
+type Repository struct {
+	root string
+}
+
+// Open returns a repository for the working tree containing root. It fails when
+// git is not installed or root is not inside a git working tree.
+func Open(root string) (*Repository, error) {
+	repo := &Repository{root: root}
+	output, err := repo.run("rev-parse", "--is-inside-work-tree")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(output)) != "true" {
+		return nil, fmt.Errorf("%s is not inside a git working tree", root)
+	}
+	return repo, nil
+}
+
+// Root returns the directory the repository was opened at
+func (r *Repository) Root() string {
+	return r.root
+}
+
+// run executes a git command in the repository root and returns its standard output
+func (r *Repository) run(args ...string) ([]byte, error) {
+	return r.runWithInput(nil, args...)
+}
+
+// runWithInput executes a git command in the repository root with input as its
+// standard input and returns its standard output
+func (r *Repository) runWithInput(input []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.root}, args...)...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], message)
+	}
+	return stdout.Bytes(), nil
+}
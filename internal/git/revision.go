@@ -0,0 +1,122 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Change statuses of files between two revisions
+const (
+	StatusAdded    = "added"
+	StatusDeleted  = "deleted"
+	StatusModified = "modified"
+	StatusRenamed  = "renamed"
+)
+
+// ChangedFile is a file that differs between two revisions. Paths are
+// slash-separated and relative to the repository root.
+type ChangedFile struct {
+	Path    string
+	OldPath string // path at the base revision; differs from Path for renames
+	Status  string
+}
+
+// ResolveRange resolves a revision range to the commits at both ends. It accepts
+// "base..head", "base...head", which compares head with the merge base of the
+// two, and a single revision, compared with HEAD. An empty end means HEAD.
+func (r *Repository) ResolveRange(spec string) (base, head string, err error) {
+	baseRev, headRev, found := strings.Cut(spec, "..")
+	mergeBase := false
+	if found && strings.HasPrefix(headRev, ".") {
+		headRev = headRev[1:]
+		mergeBase = true
+	}
+	if baseRev == "" {
+		return "", "", fmt.Errorf("invalid revision range %q, expected base..head", spec)
+	}
+	if headRev == "" {
+		headRev = "HEAD"
+	}
+
+	if base, err = r.resolveCommit(baseRev); err != nil {
+		return "", "", err
+	}
+	if head, err = r.resolveCommit(headRev); err != nil {
+		return "", "", err
+	}
+	if mergeBase {
+		output, err := r.run("merge-base", base, head)
+		if err != nil {
+			return "", "", err
+		}
+		base = strings.TrimSpace(string(output))
+	}
+	return base, head, nil
+}
+
+// Head returns the full hash of the checked-out commit
+func (r *Repository) Head() (string, error) {
+	return r.resolveCommit("HEAD")
+}
+
+// resolveCommit returns the full hash of the commit a revision names
+func (r *Repository) resolveCommit(rev string) (string, error) {
+	output, err := r.run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ChangedFiles returns the files under the repository root that differ between
+// two commits, with renames detected
+func (r *Repository) ChangedFiles(base, head string) ([]ChangedFile, error) {
+	output, err := r.run("diff", "--name-status", "-z", "-M", "--relative", base, head, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(output), nil
+}
+
+// parseNameStatus reads "git diff --name-status -z" output, where each entry is a
+// status followed by one path, or two for renames and copies
+func parseNameStatus(output []byte) []ChangedFile {
+	fields := bytes.Split(bytes.TrimSuffix(output, []byte{0}), []byte{0})
+	var files []ChangedFile
+	for i := 0; i < len(fields); i++ {
+		status := string(fields[i])
+		if status == "" || i+1 >= len(fields) {
+			continue
+		}
+		i++
+		file := ChangedFile{Path: string(fields[i]), OldPath: string(fields[i])}
+
+		switch status[0] {
+		case 'A':
+			file.Status, file.OldPath = StatusAdded, ""
+		case 'D':
+			file.Status = StatusDeleted
+		case 'R', 'C':
+			// Copies leave their source in place, so only the copy is new
+			if i+1 < len(fields) {
+				i++
+				file.Path = string(fields[i])
+			}
+			file.Status = StatusRenamed
+			if status[0] == 'C' {
+				file.Status, file.OldPath = StatusAdded, ""
+			}
+		default:
+			file.Status = StatusModified
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// FileAt returns the content of a file at a commit, without touching the working
+// tree. Path is relative to the repository root.
+func (r *Repository) FileAt(rev, path string) ([]byte, error) {
+	return r.run("show", rev+":./"+path)
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	output := "M\x00pkg/a.go\x00A\x00pkg/new.go\x00D\x00old.py\x00R087\x00pkg/b.go\x00pkg/c.go\x00C100\x00x.go\x00y.go\x00"
+	files := parseNameStatus([]byte(output))
+	expected := []ChangedFile{
+		{Path: "pkg/a.go", OldPath: "pkg/a.go", Status: StatusModified},
+		{Path: "pkg/new.go", Status: StatusAdded},
+		{Path: "old.py", OldPath: "old.py", Status: StatusDeleted},
+		{Path: "pkg/c.go", OldPath: "pkg/b.go", Status: StatusRenamed},
+		{Path: "y.go", Status: StatusAdded},
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %d files, got %+v", len(expected), files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("File %d: expected %+v, got %+v", i, expected[i], files[i])
+		}
+	}
+}
+
+func TestRepositoryRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com",
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitCmd("init", "-q", "-b", "main")
+	writeFile("src/main.go", "package main\n\nfunc main() {}\n")
+	writeFile("src/util.go", "package main\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "initial")
+	gitCmd("checkout", "-q", "-b", "feature")
+	writeFile("src/main.go", "package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+	writeFile("src/extra.go", "package main\n\nfunc extra() {}\n")
+	gitCmd("rm", "-q", "src/util.go")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "change")
+
+	repo, err := Open(filepath.Join(root, "src"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, _, err := repo.ResolveRange("missing..feature"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+	base, head, err := repo.ResolveRange("main...feature")
+	if err != nil {
+		t.Fatalf("ResolveRange failed: %v", err)
+	}
+	if len(base) != 40 || len(head) != 40 || base == head {
+		t.Fatalf("Expected two different commit hashes, got %q and %q", base, head)
+	}
+	if current, err := repo.Head(); err != nil || current != head {
+		t.Errorf("Expected HEAD at the feature commit %q, got %q, %v", head, current, err)
+	}
+
+	files, err := repo.ChangedFiles(base, head)
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, file := range files {
+		statuses[file.Path] = file.Status
+	}
+	if len(files) != 3 || statuses["main.go"] != StatusModified || statuses["extra.go"] != StatusAdded || statuses["util.go"] != StatusDeleted {
+		t.Errorf("Unexpected changed files: %+v", files)
+	}
+
+	// Contents come from the commit, not the working tree
+	writeFile("src/main.go", "package main\n")
+	content, err := repo.FileAt(base, "main.go")
+	if err != nil {
+		t.Fatalf("FileAt failed: %v", err)
+	}
+	if !strings.Contains(string(content), "func main() {}") {
+		t.Errorf("Expected main.go at the base revision, got %q", content)
+	}
+
+	commits, err := repo.Mainline("HEAD", time.Time{})
+	if err != nil {
+		t.Fatalf("Mainline failed: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Hash != head || commits[1].Hash != base {
+		t.Errorf("Expected the feature and initial commits, got %+v", commits)
+	}
+
+	tree, err := repo.Tree(base)
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Path != "main.go" || tree[1].Path != "util.go" {
+		t.Fatalf("Expected main.go and util.go at the base revision, got %+v", tree)
+	}
+	blobs, err := repo.ReadBlobs([]string{tree[0].Blob, tree[1].Blob})
+	if err != nil {
+		t.Fatalf("ReadBlobs failed: %v", err)
+	}
+	if string(blobs[tree[0].Blob]) != "package main\n\nfunc main() {}\n" || string(blobs[tree[1].Blob]) != "package main\n" {
+		t.Errorf("Unexpected blob contents: %q", blobs)
+	}
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TreeFile is a file in the tree of a commit
+type TreeFile struct {
+	Path string // slash-separated, relative to the repository root
+	Blob string // hash of the file's content
+}
+
+// Mainline returns the commits on the first-parent history of rev that touched
+// files under the repository root, newest first, without their file changes.
+// A zero since includes all history.
+func (r *Repository) Mainline(rev string, since time.Time) ([]Commit, error) {
+	args := []string{"log", "--first-parent", logFormat}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	output, err := r.run(append(args, rev, "--", ".")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output)
+}
+
+// Tree lists the files under the repository root at a commit, without touching
+// the working tree. Submodules are left out.
+func (r *Repository) Tree(rev string) ([]TreeFile, error) {
+	output, err := r.run("ls-tree", "-r", "-z", rev, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	return parseTree(output), nil
+}
+
+// parseTree reads "git ls-tree -r -z" output, where each entry is
+// "<mode> <type> <hash>\t<path>"
+func parseTree(output []byte) []TreeFile {
+	var files []TreeFile
+	for _, entry := range bytes.Split(bytes.TrimSuffix(output, []byte{0}), []byte{0}) {
+		info, path, found := strings.Cut(string(entry), "\t")
+		fields := strings.Fields(info)
+		if !found || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		files = append(files, TreeFile{Path: path, Blob: fields[2]})
+	}
+	return files
+}
+
+// ReadBlobs returns the contents of blobs by hash, read in one git process
+func (r *Repository) ReadBlobs(hashes []string) (map[string][]byte, error) {
+	if len(hashes) == 0 {
+		return map[string][]byte{}, nil
+	}
+	output, err := r.runWithInput([]byte(strings.Join(hashes, "\n")+"\n"), "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+	return parseBlobs(output)
+}
+
+// parseBlobs reads "git cat-file --batch" output, where each object is a
+// "<hash> <type> <size>" line followed by its content and a newline
+func parseBlobs(output []byte) (map[string][]byte, error) {
+	blobs := make(map[string][]byte)
+	for len(output) > 0 {
+		header, rest, found := bytes.Cut(output, []byte{'\n'})
+		if !found {
+			break
+		}
+		fields := strings.Fields(string(header))
+		if len(fields) == 2 && fields[1] == "missing" {
+			return nil, fmt.Errorf("git object %s is missing", fields[0])
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git cat-file output %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size > len(rest) {
+			return nil, fmt.Errorf("unexpected git cat-file output %q", header)
+		}
+		blobs[fields[0]] = rest[:size]
+		output = bytes.TrimPrefix(rest[size:], []byte{'\n'})
+	}
+	return blobs, nil
+}
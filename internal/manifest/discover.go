@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// Discover finds dependency manifests under root by their conventional file
+// names. Directories whose base name matches one of excludeDirs are skipped.
+func Discover(root string, excludeDirs []string) []string {
+	exclude := make(map[string]bool)
+	for _, dir := range excludeDirs {
+		exclude[dir] = true
+	}
+
+	var manifests []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && exclude[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if IsManifest(d.Name()) {
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+
+	return manifests
+}
@@ -0,0 +1,357 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// requirementPattern matches a PEP 508 requirement: a distribution name,
+// optional extras and a version specifier
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+// tomlVersionPattern finds the version key of an inline table such as
+// { version = "^2.0", extras = ["socks"] }
+var tomlVersionPattern = regexp.MustCompile(`\bversion\s*=\s*["']([^"']*)["']`)
+
+// parseGoMod reads the module path and require directives of a go.mod file.
+// Requirements marked "// indirect" are only needed by other dependencies.
+func (m *Manifest) parseGoMod(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	block := ""
+	for scanner.Scan() {
+		lineNum++
+		code, comment, _ := strings.Cut(scanner.Text(), "//")
+		code = strings.TrimSpace(code)
+		indirect := strings.HasPrefix(strings.TrimSpace(comment), "indirect")
+		if code == "" {
+			continue
+		}
+
+		if block != "" {
+			if code == ")" {
+				block = ""
+			} else if block == "require" {
+				if err := m.addGoRequirement(strings.Fields(code), indirect, lineNum); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		fields := strings.Fields(code)
+		switch {
+		case len(fields) >= 2 && fields[len(fields)-1] == "(":
+			block = fields[0]
+		case fields[0] == "module" && len(fields) == 2:
+			m.Module = strings.Trim(fields[1], `"`)
+		case fields[0] == "require":
+			if err := m.addGoRequirement(fields[1:], indirect, lineNum); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// addGoRequirement records a "path version" requirement
+func (m *Manifest) addGoRequirement(fields []string, indirect bool, lineNum int) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("line %d: expected module path and version", lineNum)
+	}
+	m.Requirements = append(m.Requirements, Requirement{
+		Name:     strings.Trim(fields[0], `"`),
+		Version:  fields[1],
+		Indirect: indirect,
+		Line:     lineNum,
+	})
+	return nil
+}
+
+// parseRequirements reads a pip requirements file. Options, includes of other
+// files, editable installs and plain URLs or paths are skipped.
+func (m *Manifest) parseRequirements(data []byte, dev bool) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if hash := strings.Index(line, "#"); hash == 0 || (hash > 0 && line[hash-1] == ' ') {
+			line = strings.TrimSpace(line[:hash])
+		}
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// A URL is only usable when named, as in "name @ https://..."
+		if strings.Contains(line, "://") && !strings.Contains(line, "@") {
+			continue
+		}
+
+		if name, version, ok := parseRequirement(line); ok {
+			m.Requirements = append(m.Requirements, Requirement{Name: name, Version: version, Dev: dev, Line: lineNum})
+		}
+	}
+	return scanner.Err()
+}
+
+// parseRequirement splits a PEP 508 requirement into its name and version
+// specifier, dropping extras and environment markers
+func parseRequirement(spec string) (name, version string, ok bool) {
+	spec, _, _ = strings.Cut(spec, ";")
+	match := requirementPattern.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return "", "", false
+	}
+	version = strings.TrimSpace(match[3])
+	if strings.HasPrefix(version, "@") {
+		// Direct reference to a URL or path
+		version = ""
+	}
+	return match[1], strings.Trim(version, "() "), true
+}
+
+// parsePyProject reads PEP 621 project dependencies, PEP 735 dependency groups
+// and Poetry dependency tables from a pyproject.toml file. Optional extras and
+// groups are treated as dev dependencies.
+func (m *Manifest) parsePyProject(data []byte) error {
+	return scanTOML(data, func(table, key, value string, line int) {
+		switch {
+		case table == "project" && key == "name":
+			m.Module = tomlString(value)
+		case table == "project" && key == "dependencies":
+			m.addPythonRequirements(value, line, false)
+		case table == "project.optional-dependencies", table == "dependency-groups":
+			m.addPythonRequirements(value, line, true)
+		case table == "tool.poetry" && key == "name" && m.Module == "":
+			m.Module = tomlString(value)
+		case table == "tool.poetry.dependencies":
+			m.addTableRequirement(key, value, line, false)
+		case table == "tool.poetry.dev-dependencies",
+			strings.HasPrefix(table, "tool.poetry.group.") && strings.HasSuffix(table, ".dependencies"):
+			m.addTableRequirement(key, value, line, true)
+		}
+	})
+}
+
+// parsePipfile reads the packages and dev-packages tables of a Pipfile
+func (m *Manifest) parsePipfile(data []byte) error {
+	return scanTOML(data, func(table, key, value string, line int) {
+		switch table {
+		case "packages":
+			m.addTableRequirement(key, value, line, false)
+		case "dev-packages":
+			m.addTableRequirement(key, value, line, true)
+		}
+	})
+}
+
+// addPythonRequirements records each requirement string of a TOML array
+func (m *Manifest) addPythonRequirements(value string, line int, dev bool) {
+	for _, item := range tomlArrayStrings(value) {
+		if name, version, ok := parseRequirement(item.value); ok {
+			m.Requirements = append(m.Requirements, Requirement{Name: name, Version: version, Dev: dev, Line: line + item.offset})
+		}
+	}
+}
+
+// addTableRequirement records a "name = version" or "name = { version = ... }"
+// entry. The Python interpreter constraint Poetry keeps among them is skipped.
+func (m *Manifest) addTableRequirement(name, value string, line int, dev bool) {
+	if strings.EqualFold(name, "python") {
+		return
+	}
+	version := tomlString(value)
+	if match := tomlVersionPattern.FindStringSubmatch(value); strings.HasPrefix(value, "{") && match != nil {
+		version = match[1]
+	}
+	m.Requirements = append(m.Requirements, Requirement{Name: name, Version: version, Dev: dev, Line: line})
+}
+
+// parsePackageJSON reads the package name and dependency maps of a package.json
+// file. devDependencies are dev dependencies; peer and optional ones are not.
+func (m *Manifest) parsePackageJSON(data []byte) error {
+	var pkg struct {
+		Name                 string            `json:"name"`
+		Dependencies         map[string]string `json:"dependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+	m.Module = pkg.Name
+
+	lines := strings.Split(string(data), "\n")
+	add := func(section string, deps map[string]string, dev bool) {
+		names := make([]string, 0, len(deps))
+		for name := range deps {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m.Requirements = append(m.Requirements, Requirement{
+				Name:    name,
+				Version: deps[name],
+				Dev:     dev,
+				Line:    jsonKeyLine(lines, section, name),
+			})
+		}
+	}
+	add("dependencies", pkg.Dependencies, false)
+	add("peerDependencies", pkg.PeerDependencies, false)
+	add("optionalDependencies", pkg.OptionalDependencies, false)
+	add("devDependencies", pkg.DevDependencies, true)
+
+	sort.SliceStable(m.Requirements, func(i, j int) bool {
+		return m.Requirements[i].Line < m.Requirements[j].Line
+	})
+	return nil
+}
+
+// jsonKeyLine returns the line of a key that follows the given section key, or
+// 0 if it can't be found
+func jsonKeyLine(lines []string, section, key string) int {
+	inSection := false
+	for i, line := range lines {
+		if !inSection {
+			inSection = strings.Contains(line, `"`+section+`"`)
+			if !inSection {
+				continue
+			}
+			line = line[strings.Index(line, `"`+section+`"`)+len(section)+2:]
+		}
+		if strings.Contains(line, `"`+key+`"`) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// scanTOML calls fn with the table, key and raw value of each key/value pair in
+// a TOML document. It understands the subset manifests use: table headers,
+// bare and quoted keys, and values such as arrays that continue over several
+// lines. line is where the pair starts.
+func scanTOML(data []byte, fn func(table, key, value string, line int)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	table := ""
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			table = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("line %d: expected key = value", lineNum)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+
+		start := lineNum
+		for nesting(value) > 0 && scanner.Scan() {
+			lineNum++
+			value += "\n" + strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		}
+		fn(table, key, value, start)
+	}
+	return scanner.Err()
+}
+
+// stripTOMLComment removes a "#" comment that is not inside a string
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// nesting returns how many arrays and inline tables are left open in value
+func nesting(value string) int {
+	depth := 0
+	var quote rune
+	for _, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// tomlString returns the content of a quoted TOML string, or the value as is
+func tomlString(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// arrayItem is a string element of a TOML array and the number of lines it
+// appears after the array's start
+type arrayItem struct {
+	value  string
+	offset int
+}
+
+// tomlArrayStrings returns the strings directly inside a TOML array. Strings in
+// nested inline tables, such as PEP 735 group includes, are skipped.
+func tomlArrayStrings(value string) []arrayItem {
+	var items []arrayItem
+	var current strings.Builder
+	var quote rune
+	depth, offset := 0, 0
+	for _, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				if depth == 1 {
+					items = append(items, arrayItem{value: current.String(), offset: offset})
+				}
+				current.Reset()
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		case r == '\n':
+			offset++
+		}
+	}
+	return items
+}
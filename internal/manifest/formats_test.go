@@ -0,0 +1,195 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeManifest(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	return path
+}
+
+func loadManifest(t *testing.T, name, content string) *Manifest {
+	t.Helper()
+	m, err := Load(writeManifest(t, name, content))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return m
+}
+
+func TestLoad_GoMod(t *testing.T) {
+	m := loadManifest(t, "go.mod", `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	golang.org/x/text v0.14.0
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+)
+
+replace golang.org/x/text => ../text
+`)
+
+	if m.Ecosystem != EcosystemGo || m.Module != "example.com/app" {
+		t.Errorf("Expected go module example.com/app, got %s %q", m.Ecosystem, m.Module)
+	}
+	expected := []Requirement{
+		{Name: "github.com/spf13/cobra", Version: "v1.8.0", Line: 5},
+		{Name: "golang.org/x/text", Version: "v0.14.0", Line: 8},
+		{Name: "github.com/inconshreveable/mousetrap", Version: "v1.1.0", Indirect: true, Line: 9},
+	}
+	if !reflect.DeepEqual(m.Requirements, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Requirements)
+	}
+}
+
+func TestLoad_GoModInvalid(t *testing.T) {
+	if _, err := Load(writeManifest(t, "go.mod", "module app\nrequire (\n\tbroken\n)\n")); err == nil {
+		t.Error("Expected an error for a requirement without a version")
+	}
+}
+
+func TestLoad_Requirements(t *testing.T) {
+	m := loadManifest(t, "requirements-dev.txt", `# tooling
+-r requirements.txt
+--index-url https://pypi.org/simple
+requests[socks]>=2.31,<3  # http
+PyYAML==6.0.1; python_version >= "3.8"
+-e git+https://github.com/org/lib.git#egg=lib
+https://example.com/pkg.tar.gz
+mylib @ https://example.com/mylib.whl
+pytest
+`)
+
+	if m.Ecosystem != EcosystemPython {
+		t.Errorf("Expected python ecosystem, got %s", m.Ecosystem)
+	}
+	expected := []Requirement{
+		{Name: "requests", Version: ">=2.31,<3", Dev: true, Line: 4},
+		{Name: "PyYAML", Version: "==6.0.1", Dev: true, Line: 5},
+		{Name: "mylib", Dev: true, Line: 8},
+		{Name: "pytest", Dev: true, Line: 9},
+	}
+	if !reflect.DeepEqual(m.Requirements, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Requirements)
+	}
+}
+
+func TestLoad_PyProject(t *testing.T) {
+	m := loadManifest(t, "pyproject.toml", `[project]
+name = "myapp"
+dependencies = [
+    "httpx>=0.27",  # client
+    "pydantic[email]~=2.0",
+]
+
+[project.optional-dependencies]
+docs = ["sphinx"]
+
+[dependency-groups]
+test = ["pytest", {include-group = "docs"}]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+click = { version = "^8.1", optional = true }
+
+[tool.poetry.group.dev.dependencies]
+ruff = "*"
+`)
+
+	if m.Module != "myapp" {
+		t.Errorf("Expected module myapp, got %q", m.Module)
+	}
+	expected := []Requirement{
+		{Name: "httpx", Version: ">=0.27", Line: 4},
+		{Name: "pydantic", Version: "~=2.0", Line: 5},
+		{Name: "sphinx", Dev: true, Line: 9},
+		{Name: "pytest", Dev: true, Line: 12},
+		{Name: "click", Version: "^8.1", Line: 16},
+		{Name: "ruff", Version: "*", Dev: true, Line: 19},
+	}
+	if !reflect.DeepEqual(m.Requirements, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Requirements)
+	}
+}
+
+func TestLoad_Pipfile(t *testing.T) {
+	m := loadManifest(t, "Pipfile", `[[source]]
+url = "https://pypi.org/simple"
+
+[packages]
+flask = "*"
+"Flask-Login" = {version = ">=0.6"}
+
+[dev-packages]
+black = "==24.1.0"
+`)
+
+	expected := []Requirement{
+		{Name: "flask", Version: "*", Line: 5},
+		{Name: "Flask-Login", Version: ">=0.6", Line: 6},
+		{Name: "black", Version: "==24.1.0", Dev: true, Line: 9},
+	}
+	if !reflect.DeepEqual(m.Requirements, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Requirements)
+	}
+}
+
+func TestLoad_PackageJSON(t *testing.T) {
+	m := loadManifest(t, "package.json", `{
+  "name": "@org/web",
+  "dependencies": {
+    "react": "^18.2.0",
+    "@tanstack/query": "5.0.0"
+  },
+  "devDependencies": {
+    "vitest": "^1.0.0"
+  }
+}
+`)
+
+	if m.Ecosystem != EcosystemNPM || m.Module != "@org/web" {
+		t.Errorf("Expected npm package @org/web, got %s %q", m.Ecosystem, m.Module)
+	}
+	expected := []Requirement{
+		{Name: "react", Version: "^18.2.0", Line: 4},
+		{Name: "@tanstack/query", Version: "5.0.0", Line: 5},
+		{Name: "vitest", Version: "^1.0.0", Dev: true, Line: 8},
+	}
+	if !reflect.DeepEqual(m.Requirements, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Requirements)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"go.mod", "web/package.json", "web/node_modules/dep/package.json", "py/requirements.txt", "py/notes.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := Discover(root, []string{"node_modules"})
+	expected := []string{
+		filepath.Join(root, "go.mod"),
+		filepath.Join(root, "py/requirements.txt"),
+		filepath.Join(root, "web/package.json"),
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("Expected %v, got %v", expected, found)
+	}
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Ecosystems a manifest can declare dependencies for
+const (
+	EcosystemGo     = "go"
+	EcosystemPython = "python"
+	EcosystemNPM    = "npm"
+)
+
+// Manifest is a dependency declaration file such as go.mod or package.json
+type Manifest struct {
+	Path         string        `json:"path"`
+	Ecosystem    string        `json:"ecosystem"`
+	Module       string        `json:"module,omitempty"` // module path or package name, if declared
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Requirement is a dependency declared in a manifest
+type Requirement struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`  // version or version constraint as written
+	Dev      bool   `json:"dev,omitempty"`      // only needed for development, tests or optional extras
+	Indirect bool   `json:"indirect,omitempty"` // required by another dependency, not the module itself
+	Line     int    `json:"line,omitempty"`
+}
+
+// Dir returns the directory the manifest applies to
+func (m *Manifest) Dir() string {
+	return filepath.Dir(m.Path)
+}
+
+// IsManifest reports whether a file name is one of the recognized manifests
+func IsManifest(name string) bool {
+	return ecosystemOf(name) != ""
+}
+
+// ecosystemOf returns the ecosystem of a manifest file name, or an empty string
+func ecosystemOf(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "go.mod":
+		return EcosystemGo
+	case lower == "package.json":
+		return EcosystemNPM
+	case lower == "pyproject.toml", lower == "pipfile", isRequirementsFile(lower):
+		return EcosystemPython
+	}
+	return ""
+}
+
+// isRequirementsFile matches pip requirements files such as requirements.txt,
+// requirements-dev.txt and test-requirements.txt
+func isRequirementsFile(lower string) bool {
+	return strings.HasSuffix(lower, ".txt") && strings.Contains(lower, "requirements")
+}
+
+// Load reads a manifest, detecting its format from the file name
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	name := filepath.Base(path)
+	m := &Manifest{
+		Path:         path,
+		Ecosystem:    ecosystemOf(name),
+		Requirements: []Requirement{},
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "go.mod":
+		err = m.parseGoMod(data)
+	case lower == "package.json":
+		err = m.parsePackageJSON(data)
+	case lower == "pyproject.toml":
+		err = m.parsePyProject(data)
+	case lower == "pipfile":
+		err = m.parsePipfile(data)
+	case isRequirementsFile(lower):
+		// Requirements files named after dev or test tooling hold dev dependencies
+		dev := strings.Contains(lower, "dev") || strings.Contains(lower, "test")
+		err = m.parseRequirements(data, dev)
+	default:
+		return nil, fmt.Errorf("unrecognized manifest %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	return m, nil
+}
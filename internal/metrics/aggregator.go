@@ -3,8 +3,10 @@ package metrics
 import (
 	"math"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/tito-sala/codebasereaderv2/internal/git"
 	"github.com/tito-sala/codebasereaderv2/internal/parser"
 )
 
@@ -41,6 +43,16 @@ func (a *Aggregator) AggregateProjectMetrics(results []*parser.AnalysisResult, r
 
 	// Analyze dependency relationships
 	a.analyzeDependencyGraph(analysis)
+	a.analyzePackageGraph(analysis)
+
+	// Flag complex functions with little test coverage
+	a.calculateCoverageRisks(analysis)
+
+	// Rank complex code that changes often
+	a.calculateHotspots(analysis)
+
+	// Measure class cohesion and flag god and data classes
+	a.calculateClassCohesion(analysis)
 
 	// Calculate overall quality score
 	a.calculateOverallQualityScore(analysis)
@@ -51,8 +63,12 @@ func (a *Aggregator) AggregateProjectMetrics(results []*parser.AnalysisResult, r
 // calculateProjectMetrics calculates overall project metrics
 func (a *Aggregator) calculateProjectMetrics(analysis *EnhancedProjectAnalysis) {
 	var totalComplexity, maxComplexity int
+	var totalCognitive, maxCognitive int
+	var totalVolume, totalDifficulty, totalEffort, totalBugs float64
 	var totalMaintainability, totalTechnicalDebt float64
 	var totalCodeLines, totalCommentLines int
+	var duplicatedLines float64
+	var coveredLines, coverableLines int
 	var functionsWithDocs, totalFunctions int
 
 	// Safe type assertion with check
@@ -67,11 +83,23 @@ func (a *Aggregator) calculateProjectMetrics(analysis *EnhancedProjectAnalysis)
 		totalTechnicalDebt += result.TechnicalDebt
 		totalCodeLines += result.CodeLines
 		totalCommentLines += result.CommentLines
+		duplicatedLines += result.CodeDuplication / 100 * float64(result.CodeLines)
+		coveredLines += result.CoveredLines
+		coverableLines += result.CoverableLines
+
+		totalCognitive += result.CognitiveComplexity
+		totalVolume += result.Halstead.Volume
+		totalDifficulty += result.Halstead.Difficulty
+		totalEffort += result.Halstead.Effort
+		totalBugs += result.Halstead.EstimatedBugs
 
 		// Track maximum complexity
 		if result.CyclomaticComplexity > maxComplexity {
 			maxComplexity = result.CyclomaticComplexity
 		}
+		if result.CognitiveComplexity > maxCognitive {
+			maxCognitive = result.CognitiveComplexity
+		}
 
 		// Count documented functions
 		for _, fn := range result.Functions {
@@ -102,6 +130,7 @@ func (a *Aggregator) calculateProjectMetrics(analysis *EnhancedProjectAnalysis)
 
 	// Calculate averages and ratios
 	avgComplexity := float64(totalComplexity) / float64(fileCount)
+	avgCognitive := float64(totalCognitive) / float64(fileCount)
 	avgMaintainability := totalMaintainability / float64(fileCount)
 
 	var documentationRatio float64
@@ -114,23 +143,46 @@ func (a *Aggregator) calculateProjectMetrics(analysis *EnhancedProjectAnalysis)
 		codeToCommentRatio = float64(totalCodeLines) / float64(totalCommentLines)
 	}
 
+	// Duplication is weighted by code lines so small files do not dominate
+	var codeDuplication float64
+	if totalCodeLines > 0 {
+		codeDuplication = duplicatedLines / float64(totalCodeLines) * 100
+	}
+
+	// Coverage counts only files matched by a coverage report
+	var testCoverage float64
+	if coverableLines > 0 {
+		testCoverage = float64(coveredLines) / float64(coverableLines) * 100
+	}
+
 	analysis.ProjectMetrics = ProjectMetrics{
-		TotalComplexity:      totalComplexity,
-		AverageComplexity:    avgComplexity,
-		MaxComplexity:        maxComplexity,
-		MaintainabilityIndex: avgMaintainability,
-		TechnicalDebt:        totalTechnicalDebt,
-		DocumentationRatio:   documentationRatio,
-		CodeToCommentRatio:   codeToCommentRatio,
-		// CodeDuplication and TestCoverage would require more sophisticated analysis
-		CodeDuplication: 0.0, // Placeholder
-		TestCoverage:    0.0, // Placeholder
+		TotalComplexity:            totalComplexity,
+		AverageComplexity:          avgComplexity,
+		MaxComplexity:              maxComplexity,
+		TotalCognitiveComplexity:   totalCognitive,
+		AverageCognitiveComplexity: avgCognitive,
+		MaxCognitiveComplexity:     maxCognitive,
+		TotalHalsteadVolume:        totalVolume,
+		AverageHalsteadVolume:      totalVolume / float64(fileCount),
+		AverageHalsteadDifficulty:  totalDifficulty / float64(fileCount),
+		TotalHalsteadEffort:        totalEffort,
+		EstimatedBugs:              totalBugs,
+		MaintainabilityIndex:       avgMaintainability,
+		TechnicalDebt:              totalTechnicalDebt,
+		DocumentationRatio:         documentationRatio,
+		CodeToCommentRatio:         codeToCommentRatio,
+		CodeDuplication:            codeDuplication,
+		TestCoverage:               testCoverage,
 	}
 }
 
 // calculateDirectoryStats calculates statistics for each directory
 func (a *Aggregator) calculateDirectoryStats(analysis *EnhancedProjectAnalysis) {
 	dirStats := make(map[string]*DirectoryStats)
+	dirCodeLines := make(map[string]int)
+	dirCoverage := make(map[string]*[2]int)  // covered, coverable lines
+	langCoverage := make(map[string]*[2]int) // keyed by directory and language
+	dirAuthors := make(map[string]map[string]*parser.AuthorContribution)
 
 	// Safe type assertion with check
 	fileResults, ok := analysis.FileResults.([]*parser.AnalysisResult)
@@ -152,18 +204,29 @@ func (a *Aggregator) calculateDirectoryStats(analysis *EnhancedProjectAnalysis)
 		stats.FileCount++
 		stats.LineCount += result.LineCount
 		stats.Complexity += result.CyclomaticComplexity
+		stats.CognitiveComplexity += result.CognitiveComplexity
+		stats.CodeDuplication += result.CodeDuplication / 100 * float64(result.CodeLines)
+		dirCodeLines[dir] += result.CodeLines
+		addCoverageLines(dirCoverage, dir, result)
+		addCoverageLines(langCoverage, dir+"\x00"+result.Language, result)
+		if result.History != nil {
+			a.addHistoryStats(stats, dirAuthors, dir, result.History)
+		}
 
 		// Update language-specific stats for this directory
 		langStats := stats.Languages[result.Language]
 		langStats.FileCount++
-		langStats.LineCount += result.LineCount
+		langStats.LineCount += result.LanguageLines()[result.Language]
 		langStats.FunctionCount += len(result.Functions)
 		langStats.ClassCount += len(result.Classes)
 		langStats.Complexity += result.Complexity
 		langStats.CyclomaticComplexity += result.CyclomaticComplexity
-		langStats.CodeLines += result.CodeLines
-		langStats.CommentLines += result.CommentLines
-		langStats.BlankLines += result.BlankLines
+		langStats.CognitiveComplexity += result.CognitiveComplexity
+		if len(result.LanguageBlocks) == 0 {
+			langStats.CodeLines += result.CodeLines
+			langStats.CommentLines += result.CommentLines
+			langStats.BlankLines += result.BlankLines
+		}
 		langStats.MaintainabilityIndex += result.MaintainabilityIndex
 		langStats.TechnicalDebt += result.TechnicalDebt
 
@@ -173,6 +236,9 @@ func (a *Aggregator) calculateDirectoryStats(analysis *EnhancedProjectAnalysis)
 		}
 
 		stats.Languages[result.Language] = langStats
+
+		// Files with embedded languages also contribute lines to each of them
+		a.addEmbeddedLanguageStats(stats.Languages, result)
 	}
 
 	// Calculate averages and finalize stats
@@ -188,18 +254,133 @@ func (a *Aggregator) calculateDirectoryStats(analysis *EnhancedProjectAnalysis)
 					langStats.MaintainabilityIndex = langStats.MaintainabilityIndex / float64(langStats.FileCount)
 					totalMaintainability += langStats.MaintainabilityIndex
 				}
+				if lines := langCoverage[path+"\x00"+lang]; lines != nil && lines[1] > 0 {
+					langStats.TestCoverage = float64(lines[0]) / float64(lines[1]) * 100
+				}
 				stats.Languages[lang] = langStats
 			}
 
 			stats.MaintainabilityIndex = totalMaintainability / float64(len(stats.Languages))
 		}
 
+		if lines := dirCoverage[path]; lines != nil && lines[1] > 0 {
+			stats.TestCoverage = float64(lines[0]) / float64(lines[1]) * 100
+		}
+
+		if authors := dirAuthors[path]; len(authors) > 0 {
+			contributions := make([]parser.AuthorContribution, 0, len(authors))
+			for _, author := range authors {
+				contributions = append(contributions, *author)
+			}
+			stats.AuthorCount = len(contributions)
+			stats.BusFactor = git.BusFactor(contributions)
+		}
+
+		// CodeDuplication holds duplicated lines until here; convert to a percentage
+		if dirCodeLines[path] > 0 {
+			stats.CodeDuplication = stats.CodeDuplication / float64(dirCodeLines[path]) * 100
+		}
+
 		finalStats[path] = *stats
 	}
 
 	analysis.DirectoryStats = finalStats
 }
 
+// addHistoryStats adds a file's git history to its directory and merges its
+// authors into the directory's author contributions
+func (a *Aggregator) addHistoryStats(stats *DirectoryStats, dirAuthors map[string]map[string]*parser.AuthorContribution, dir string, history *parser.GitHistory) {
+	stats.CommitCount += history.CommitCount
+	stats.LinesAdded += history.LinesAdded
+	stats.LinesRemoved += history.LinesRemoved
+	if history.LastModified.After(stats.LastModified) {
+		stats.LastModified = history.LastModified
+	}
+
+	authors := dirAuthors[dir]
+	if authors == nil {
+		authors = make(map[string]*parser.AuthorContribution)
+		dirAuthors[dir] = authors
+	}
+	for _, contribution := range history.Authors {
+		key := contribution.Email
+		if key == "" {
+			key = contribution.Name
+		}
+		author := authors[key]
+		if author == nil {
+			author = &parser.AuthorContribution{Name: contribution.Name, Email: contribution.Email}
+			authors[key] = author
+		}
+		author.Commits += contribution.Commits
+		author.Lines += contribution.Lines
+	}
+}
+
+// addCoverageLines adds a file's covered and coverable lines to the totals for key
+func addCoverageLines(totals map[string]*[2]int, key string, result *parser.AnalysisResult) {
+	if result.CoverableLines == 0 {
+		return
+	}
+	lines := totals[key]
+	if lines == nil {
+		lines = &[2]int{}
+		totals[key] = lines
+	}
+	lines[0] += result.CoveredLines
+	lines[1] += result.CoverableLines
+}
+
+// calculateCoverageRisks lists functions whose CRAP score (change risk anti-patterns)
+// reaches the conventional threshold of 30, highest risk first
+func (a *Aggregator) calculateCoverageRisks(analysis *EnhancedProjectAnalysis) {
+	const crapThreshold = 30.0
+
+	fileResults, ok := analysis.FileResults.([]*parser.AnalysisResult)
+	if !ok {
+		return
+	}
+
+	risks := []CoverageRisk{}
+	addRisk := func(filePath, name string, fn parser.FunctionInfo) {
+		if fn.CoverableLines == 0 {
+			return
+		}
+		crap := a.calculator.CalculateCRAP(fn.Complexity, fn.TestCoverage)
+		if crap < crapThreshold {
+			return
+		}
+		risks = append(risks, CoverageRisk{
+			FilePath:   filePath,
+			Function:   name,
+			Line:       fn.LineStart,
+			Complexity: fn.Complexity,
+			Coverage:   fn.TestCoverage,
+			CRAP:       crap,
+		})
+	}
+
+	for _, result := range fileResults {
+		for _, fn := range result.Functions {
+			addRisk(result.FilePath, fn.Name, fn)
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				addRisk(result.FilePath, class.Name+"."+method.Name, method)
+			}
+		}
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].CRAP != risks[j].CRAP {
+			return risks[i].CRAP > risks[j].CRAP
+		}
+		return risks[i].FilePath+risks[i].Function < risks[j].FilePath+risks[j].Function
+	})
+
+	analysis.CoverageRisks = risks
+}
+
 // analyzeDependencyGraph analyzes project dependency relationships
 func (a *Aggregator) analyzeDependencyGraph(analysis *EnhancedProjectAnalysis) {
 	internalDeps := make(map[string][]string)
@@ -242,9 +423,6 @@ func (a *Aggregator) analyzeDependencyGraph(analysis *EnhancedProjectAnalysis) {
 		allDeps[result.FilePath] = fileDeps
 	}
 
-	// Detect circular dependencies (simplified detection)
-	circularDeps := a.detectCircularDependencies(internalDeps)
-
 	// Calculate dependency depth
 	maxDepth := a.calculateDependencyDepth(internalDeps)
 
@@ -252,88 +430,12 @@ func (a *Aggregator) analyzeDependencyGraph(analysis *EnhancedProjectAnalysis) {
 		InternalDependencies: internalDeps,
 		ExternalDependencies: externalDeps,
 		StandardDependencies: standardDeps,
-		CircularDependencies: circularDeps,
+		CircularDependencies: [][]string{}, // Package cycles are found by analyzePackageGraph
 		DependencyDepth:      maxDepth,
 		UnusedDependencies:   []string{}, // Would require more sophisticated analysis
 	}
 }
 
-// detectCircularDependencies detects circular dependency chains
-func (a *Aggregator) detectCircularDependencies(deps map[string][]string) [][]string {
-	// Simplified circular dependency detection with safety limits
-	var circular [][]string
-
-	// Safety limit to prevent infinite loops
-	const maxDepth = 100
-
-	visited := make(map[string]bool)
-	recursionStack := make(map[string]bool)
-
-	var dfs func(string, []string, int) bool
-	dfs = func(node string, path []string, depth int) bool {
-		// Safety check for maximum depth
-		if depth > maxDepth {
-			return false
-		}
-
-		if recursionStack[node] {
-			// Found a cycle, extract the cycle
-			cycleStart := -1
-			for i, p := range path {
-				if p == node {
-					cycleStart = i
-					break
-				}
-			}
-			if cycleStart >= 0 && cycleStart < len(path) {
-				cycle := make([]string, len(path)-cycleStart+1)
-				copy(cycle, path[cycleStart:])
-				cycle[len(cycle)-1] = node
-				circular = append(circular, cycle)
-			}
-			return true
-		}
-
-		if visited[node] {
-			return false
-		}
-
-		visited[node] = true
-		recursionStack[node] = true
-		newPath := make([]string, len(path)+1)
-		copy(newPath, path)
-		newPath[len(path)] = node
-
-		// Only process dependencies that exist in our graph
-		if nodeDeps, exists := deps[node]; exists {
-			for _, dep := range nodeDeps {
-				if dfs(dep, newPath, depth+1) {
-					break // Stop after finding first cycle to prevent excessive processing
-				}
-			}
-		}
-
-		recursionStack[node] = false
-		return false
-	}
-
-	// Process each node with safety limits
-	processedCount := 0
-	const maxNodes = 1000
-
-	for node := range deps {
-		if processedCount >= maxNodes {
-			break // Safety limit on number of nodes processed
-		}
-		if !visited[node] {
-			dfs(node, []string{}, 0)
-		}
-		processedCount++
-	}
-
-	return circular
-}
-
 // calculateDependencyDepth calculates the maximum dependency depth
 func (a *Aggregator) calculateDependencyDepth(deps map[string][]string) int {
 	maxDepth := 0
@@ -414,6 +516,26 @@ func (a *Aggregator) calculateOverallQualityScore(analysis *EnhancedProjectAnaly
 	}
 }
 
+// addEmbeddedLanguageStats attributes the lines of embedded language blocks
+// (e.g. <script> and <style> in a component) to their own languages
+func (a *Aggregator) addEmbeddedLanguageStats(languages map[string]LanguageStats, result *parser.AnalysisResult) {
+	counted := make(map[string]bool)
+	for _, block := range result.LanguageBlocks {
+		langStats := languages[block.Language]
+		if block.Language != result.Language {
+			if !counted[block.Language] {
+				langStats.FileCount++
+				counted[block.Language] = true
+			}
+			langStats.LineCount += block.LineCount
+		}
+		langStats.CodeLines += block.CodeLines
+		langStats.CommentLines += block.CommentLines
+		langStats.BlankLines += block.BlankLines
+		languages[block.Language] = langStats
+	}
+}
+
 // isTestFile determines if a file is a test file based on naming conventions
 func (a *Aggregator) isTestFile(filePath string) bool {
 	fileName := filepath.Base(filePath)
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -115,31 +116,167 @@ func TestAggregateProjectMetrics(t *testing.T) {
 	}
 }
 
-func TestDetectCircularDependencies(t *testing.T) {
+func TestAggregateCognitiveComplexity(t *testing.T) {
 	aggregator := NewAggregator()
 
-	// Test case with no circular dependencies
-	deps1 := map[string][]string{
-		"a.go": {"b.go"},
-		"b.go": {"c.go"},
-		"c.go": {},
+	results := []*parser.AnalysisResult{
+		{FilePath: "pkg/a.go", Language: "Go", CognitiveComplexity: 12, AnalyzedAt: time.Now()},
+		{FilePath: "pkg/b.go", Language: "Go", CognitiveComplexity: 4, AnalyzedAt: time.Now()},
+		{FilePath: "scripts/run.py", Language: "Python", CognitiveComplexity: 2, AnalyzedAt: time.Now()},
 	}
 
-	circular1 := aggregator.detectCircularDependencies(deps1)
-	if len(circular1) != 0 {
-		t.Errorf("Expected no circular dependencies, got %d", len(circular1))
+	analysis := aggregator.AggregateProjectMetrics(results, "/test/project")
+
+	if analysis.ProjectMetrics.TotalCognitiveComplexity != 18 {
+		t.Errorf("Expected total cognitive complexity 18, got %d", analysis.ProjectMetrics.TotalCognitiveComplexity)
+	}
+	if analysis.ProjectMetrics.MaxCognitiveComplexity != 12 {
+		t.Errorf("Expected max cognitive complexity 12, got %d", analysis.ProjectMetrics.MaxCognitiveComplexity)
+	}
+	if analysis.ProjectMetrics.AverageCognitiveComplexity != 6 {
+		t.Errorf("Expected average cognitive complexity 6, got %f", analysis.ProjectMetrics.AverageCognitiveComplexity)
 	}
 
-	// Test case with circular dependencies
-	deps2 := map[string][]string{
-		"a.go": {"b.go"},
-		"b.go": {"c.go"},
-		"c.go": {"a.go"},
+	pkg := analysis.DirectoryStats["pkg"]
+	if pkg.CognitiveComplexity != 16 {
+		t.Errorf("Expected pkg cognitive complexity 16, got %d", pkg.CognitiveComplexity)
+	}
+	if pkg.Languages["Go"].CognitiveComplexity != 16 {
+		t.Errorf("Expected Go cognitive complexity 16 in pkg, got %d", pkg.Languages["Go"].CognitiveComplexity)
+	}
+}
+
+func TestAggregateHalsteadMetrics(t *testing.T) {
+	aggregator := NewAggregator()
+
+	results := []*parser.AnalysisResult{
+		{FilePath: "a.go", Language: "Go", Halstead: parser.HalsteadMetrics{Volume: 300, Difficulty: 10, Effort: 3000, EstimatedBugs: 0.1}},
+		{FilePath: "b.go", Language: "Go", Halstead: parser.HalsteadMetrics{Volume: 900, Difficulty: 20, Effort: 18000, EstimatedBugs: 0.3}},
+	}
+
+	metrics := aggregator.AggregateProjectMetrics(results, "/test/project").ProjectMetrics
+
+	if metrics.TotalHalsteadVolume != 1200 || metrics.AverageHalsteadVolume != 600 {
+		t.Errorf("Expected total/average volume 1200/600, got %.1f/%.1f", metrics.TotalHalsteadVolume, metrics.AverageHalsteadVolume)
+	}
+	if metrics.AverageHalsteadDifficulty != 15 {
+		t.Errorf("Expected average difficulty 15, got %.1f", metrics.AverageHalsteadDifficulty)
+	}
+	if metrics.TotalHalsteadEffort != 21000 {
+		t.Errorf("Expected total effort 21000, got %.1f", metrics.TotalHalsteadEffort)
+	}
+	if metrics.EstimatedBugs < 0.399 || metrics.EstimatedBugs > 0.401 {
+		t.Errorf("Expected 0.4 estimated bugs, got %.3f", metrics.EstimatedBugs)
+	}
+}
+
+func TestAggregateCodeDuplication(t *testing.T) {
+	aggregator := NewAggregator()
+
+	results := []*parser.AnalysisResult{
+		{FilePath: "pkg/a.go", Language: "Go", CodeLines: 100, CodeDuplication: 30},
+		{FilePath: "pkg/b.go", Language: "Go", CodeLines: 50, CodeDuplication: 0},
+		{FilePath: "cmd/main.go", Language: "Go", CodeLines: 50, CodeDuplication: 50},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/test/project")
+
+	// (30 + 0 + 25) duplicated lines out of 200
+	if math.Abs(analysis.ProjectMetrics.CodeDuplication-27.5) > 0.001 {
+		t.Errorf("Expected 27.5%% duplication, got %.2f", analysis.ProjectMetrics.CodeDuplication)
+	}
+	if math.Abs(analysis.QualityScore.CodeDuplication-27.5) > 0.001 {
+		t.Errorf("Expected quality score to use 27.5%% duplication, got %.2f", analysis.QualityScore.CodeDuplication)
+	}
+	if math.Abs(analysis.DirectoryStats["pkg"].CodeDuplication-20) > 0.001 {
+		t.Errorf("Expected 20%% duplication in pkg, got %.2f", analysis.DirectoryStats["pkg"].CodeDuplication)
+	}
+}
+
+func TestAggregateTestCoverage(t *testing.T) {
+	aggregator := NewAggregator()
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "pkg/a.go", Language: "Go", CoveredLines: 30, CoverableLines: 40, TestCoverage: 75,
+			Functions: []parser.FunctionInfo{
+				{Name: "Risky", LineStart: 3, Complexity: 12, TestCoverage: 10, CoverableLines: 20},
+				{Name: "Tested", LineStart: 30, Complexity: 12, TestCoverage: 100, CoverableLines: 20},
+			},
+		},
+		{FilePath: "pkg/b.go", Language: "Go", CoveredLines: 0, CoverableLines: 10},
+		{
+			FilePath: "cmd/main.go", Language: "Go",
+			Functions: []parser.FunctionInfo{{Name: "main", Complexity: 20}},
+		},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/test/project")
+
+	// Files without coverage data do not count against the project
+	if analysis.ProjectMetrics.TestCoverage != 60 {
+		t.Errorf("Expected 60%% project coverage, got %.2f", analysis.ProjectMetrics.TestCoverage)
+	}
+	if analysis.QualityScore.TestCoverage != 60 {
+		t.Errorf("Expected quality score to use 60%% coverage, got %.2f", analysis.QualityScore.TestCoverage)
+	}
+	if analysis.DirectoryStats["pkg"].TestCoverage != 60 {
+		t.Errorf("Expected 60%% coverage in pkg, got %.2f", analysis.DirectoryStats["pkg"].TestCoverage)
+	}
+	if analysis.DirectoryStats["pkg"].Languages["Go"].TestCoverage != 60 {
+		t.Errorf("Expected 60%% Go coverage in pkg, got %.2f", analysis.DirectoryStats["pkg"].Languages["Go"].TestCoverage)
 	}
+	if analysis.DirectoryStats["cmd"].TestCoverage != 0 {
+		t.Errorf("Expected no coverage in cmd, got %.2f", analysis.DirectoryStats["cmd"].TestCoverage)
+	}
+
+	if len(analysis.CoverageRisks) != 1 {
+		t.Fatalf("Expected 1 coverage risk, got %+v", analysis.CoverageRisks)
+	}
+	if risk := analysis.CoverageRisks[0]; risk.Function != "Risky" || risk.Line != 3 || risk.CRAP < 30 {
+		t.Errorf("Unexpected coverage risk: %+v", risk)
+	}
+}
+
+func TestDirectoryStatsGitHistory(t *testing.T) {
+	aggregator := NewAggregator()
+
+	recent := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "pkg/a.go", Language: "Go",
+			History: &parser.GitHistory{
+				CommitCount: 4, LinesAdded: 100, LinesRemoved: 20, LastModified: recent.AddDate(0, -1, 0),
+				Authors: []parser.AuthorContribution{{Name: "Ada", Email: "ada@example.com", Commits: 4, Lines: 120}},
+			},
+		},
+		{
+			FilePath: "pkg/b.go", Language: "Go",
+			History: &parser.GitHistory{
+				CommitCount: 2, LinesAdded: 50, LinesRemoved: 10, LastModified: recent,
+				Authors: []parser.AuthorContribution{
+					{Name: "Linus", Email: "linus@example.com", Commits: 1, Lines: 50},
+					{Name: "Ada", Email: "ada@example.com", Commits: 1, Lines: 10},
+				},
+			},
+		},
+		{FilePath: "pkg/c.go", Language: "Go"},
+	}
+
+	stats := aggregator.AggregateProjectMetrics(results, "/test/project").DirectoryStats["pkg"]
 
-	circular2 := aggregator.detectCircularDependencies(deps2)
-	if len(circular2) == 0 {
-		t.Error("Expected circular dependencies to be detected")
+	if stats.CommitCount != 6 || stats.LinesAdded != 150 || stats.LinesRemoved != 30 {
+		t.Errorf("Expected 6 commits +150/-30, got %d +%d/-%d", stats.CommitCount, stats.LinesAdded, stats.LinesRemoved)
+	}
+	if stats.AuthorCount != 2 {
+		t.Errorf("Expected 2 distinct authors, got %d", stats.AuthorCount)
+	}
+	// Ada owns 130 of 180 changed lines
+	if stats.BusFactor != 1 {
+		t.Errorf("Expected bus factor 1, got %d", stats.BusFactor)
+	}
+	if !stats.LastModified.Equal(recent) {
+		t.Errorf("Expected last modified %v, got %v", recent, stats.LastModified)
 	}
 }
 
@@ -193,3 +330,44 @@ func TestIsTestFile(t *testing.T) {
 		}
 	}
 }
+
+func TestDirectoryStatsEmbeddedLanguages(t *testing.T) {
+	aggregator := NewAggregator()
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath:  "web/App.vue",
+			Language:  "Vue",
+			LineCount: 20,
+			Functions: []parser.FunctionInfo{{Name: "setup", Complexity: 2}},
+			LanguageBlocks: []parser.LanguageBlock{
+				{Kind: "template", Language: "HTML", LineCount: 8, CodeLines: 7, BlankLines: 1},
+				{Kind: "script", Language: "TypeScript", LineCount: 9, CodeLines: 8, CommentLines: 1},
+				{Kind: "style", Language: "CSS", LineCount: 3, CodeLines: 3},
+			},
+			AnalyzedAt: time.Now(),
+		},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/test/project")
+	languages := analysis.DirectoryStats["web"].Languages
+
+	vue := languages["Vue"]
+	if vue.FileCount != 1 || vue.LineCount != 0 || vue.FunctionCount != 1 {
+		t.Errorf("Expected Vue to own the file and its functions but no lines, got %+v", vue)
+	}
+
+	ts := languages["TypeScript"]
+	if ts.FileCount != 1 || ts.LineCount != 9 || ts.CodeLines != 8 || ts.CommentLines != 1 {
+		t.Errorf("Expected TypeScript to receive the script block lines, got %+v", ts)
+	}
+
+	html := languages["HTML"]
+	if html.LineCount != 8 || html.BlankLines != 1 {
+		t.Errorf("Expected HTML to receive the template lines, got %+v", html)
+	}
+
+	if languages["CSS"].LineCount != 3 {
+		t.Errorf("Expected CSS to receive 3 lines, got %d", languages["CSS"].LineCount)
+	}
+}
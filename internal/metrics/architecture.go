@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/callgraph"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// ArchitectureRule restricts what the packages matching From may import.
+// Patterns are slash-separated paths relative to the project root, or import
+// paths such as net/http for code outside the project. They may use path.Match
+// wildcards, and a trailing "/..." also matches every package below.
+type ArchitectureRule struct {
+	Name   string   `json:"name,omitempty"`
+	From   string   `json:"from"`
+	Allow  []string `json:"allow,omitempty"`  // when set, the only project packages From may import
+	Forbid []string `json:"forbid,omitempty"` // project packages and import paths From must not import
+	Reason string   `json:"reason,omitempty"`
+}
+
+// Validate reports rules that can never match or would never report anything
+func (r ArchitectureRule) Validate() error {
+	if r.From == "" {
+		return fmt.Errorf("rule %q: from is required", r.Label())
+	}
+	if len(r.Allow) == 0 && len(r.Forbid) == 0 {
+		return fmt.Errorf("rule %q: allow or forbid is required", r.Label())
+	}
+	for _, pattern := range append(append([]string{r.From}, r.Allow...), r.Forbid...) {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+			return fmt.Errorf("rule %q: invalid pattern %q", r.Label(), pattern)
+		}
+	}
+	return nil
+}
+
+// Label returns the rule's name, or a description of it when it has none
+func (r ArchitectureRule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if len(r.Forbid) > 0 {
+		return r.From + " must not import " + strings.Join(r.Forbid, ", ")
+	}
+	return r.From + " may only import " + strings.Join(r.Allow, ", ")
+}
+
+// CheckArchitecture evaluates the rules against every import of the analyzed
+// files. Imports of project packages are matched by the imported package's
+// path relative to rootPath; other imports are matched by their import path.
+// Standard library and third-party imports are never project packages, even
+// when a project directory shares their name, so Allow lists leave them alone.
+// An import breaking several rules is reported once per rule.
+func CheckArchitecture(rules []ArchitectureRule, results []*parser.AnalysisResult, rootPath string) []ArchitectureViolation {
+	violations := []ArchitectureViolation{}
+	if len(rules) == 0 {
+		return violations
+	}
+
+	resolver := newPackageResolver(rootPath, results)
+	for _, result := range results {
+		from := path.Dir(resolver.relative(result.FilePath))
+		for _, rule := range rules {
+			if !matchPackagePattern(rule.From, from) {
+				continue
+			}
+
+			for _, dep := range result.Dependencies {
+				target, internal := dep.Name, false
+				if dir := resolver.resolve(result.FilePath, result.Language, dep); dir != "" {
+					target, internal = resolver.relative(dir), true
+					if dir == filepath.Dir(result.FilePath) {
+						continue
+					}
+				} else if result.Language == "Python" && strings.HasPrefix(dep.Name, ".") {
+					// A relative module that was not analyzed still lies in the
+					// project, so forbidden packages match its path
+					target = callgraph.RelativeModulePath(from, dep.Name)
+				}
+
+				if !breaksRule(rule, target, dep.Name, internal) {
+					continue
+				}
+				violations = append(violations, ArchitectureViolation{
+					Rule:     rule.Label(),
+					Reason:   rule.Reason,
+					FilePath: result.FilePath,
+					Line:     dep.Line,
+					Import:   dep.Name,
+					From:     from,
+					Target:   target,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].FilePath != violations[j].FilePath {
+			return violations[i].FilePath < violations[j].FilePath
+		}
+		return violations[i].Line < violations[j].Line
+	})
+
+	return violations
+}
+
+// breaksRule reports whether importing target breaks the rule. Allow lists
+// only restrict project packages; forbidden patterns match project packages,
+// import paths and dotted module names written with slashes.
+func breaksRule(rule ArchitectureRule, target, imp string, internal bool) bool {
+	names := []string{target, imp}
+	if strings.Contains(imp, ".") && !strings.Contains(imp, "/") {
+		names = append(names, strings.ReplaceAll(imp, ".", "/"))
+	}
+	for _, pattern := range rule.Forbid {
+		for _, name := range names {
+			if matchPackagePattern(pattern, name) {
+				return true
+			}
+		}
+	}
+
+	if len(rule.Allow) == 0 || !internal {
+		return false
+	}
+	for _, pattern := range rule.Allow {
+		if matchPackagePattern(pattern, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPackagePattern matches a package path against a rule pattern. A pattern
+// ending in "/..." matches the package and everything below it, and "..." alone
+// matches any package.
+func matchPackagePattern(pattern, pkg string) bool {
+	if pattern == "..." {
+		return true
+	}
+	base, recursive := strings.CutSuffix(pattern, "/...")
+	if matched, _ := path.Match(base, pkg); matched {
+		return true
+	}
+	if !recursive {
+		return false
+	}
+
+	// Match the pattern against the leading path elements of the package
+	depth := strings.Count(base, "/") + 1
+	parts := strings.Split(pkg, "/")
+	if len(parts) <= depth {
+		return false
+	}
+	matched, _ := path.Match(base, strings.Join(parts[:depth], "/"))
+	return matched
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCheckArchitecture(t *testing.T) {
+	root := goModule(t, "github.com/x/p")
+	file := func(path string, deps ...parser.Dependency) *parser.AnalysisResult {
+		return &parser.AnalysisResult{FilePath: path, Language: "Go", Dependencies: deps}
+	}
+	dep := func(name string, line int) parser.Dependency {
+		return parser.Dependency{Name: name, Line: line}
+	}
+
+	results := []*parser.AnalysisResult{
+		file(root+"/internal/engine/engine.go",
+			dep("fmt", 3),
+			dep("github.com/x/p/internal/metrics", 4),
+			dep("github.com/x/p/internal/tui/views", 5)),
+		file(root+"/internal/tui/model.go",
+			dep("github.com/x/p/internal/engine", 3),
+			dep("github.com/x/p/internal/metrics", 4)),
+		file(root+"/internal/tui/views/view.go",
+			dep("github.com/x/p/internal/tui", 3)),
+		file(root + "/internal/metrics/types.go"),
+		file(root+"/domain/order/order.go",
+			dep("net/http", 7),
+			dep("strings", 8)),
+	}
+
+	rules := []ArchitectureRule{
+		{Name: "engine-without-ui", From: "internal/engine/...", Forbid: []string{"internal/tui/..."}, Reason: "the engine is UI independent"},
+		{From: "internal/tui/...", Allow: []string{"internal/engine", "internal/tui/..."}},
+		{From: "domain/*", Forbid: []string{"net/http"}},
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+	}
+
+	violations := CheckArchitecture(rules, results, root)
+	expected := []ArchitectureViolation{
+		{Rule: "domain/* must not import net/http", FilePath: root + "/domain/order/order.go", Line: 7, Import: "net/http", From: "domain/order", Target: "net/http"},
+		{Rule: "engine-without-ui", Reason: "the engine is UI independent", FilePath: root + "/internal/engine/engine.go", Line: 5,
+			Import: "github.com/x/p/internal/tui/views", From: "internal/engine", Target: "internal/tui/views"},
+		{Rule: "internal/tui/... may only import internal/engine, internal/tui/...", FilePath: root + "/internal/tui/model.go", Line: 4,
+			Import: "github.com/x/p/internal/metrics", From: "internal/tui", Target: "internal/metrics"},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %+v", len(expected), len(violations), violations)
+	}
+	for i := range expected {
+		if violations[i] != expected[i] {
+			t.Errorf("Violation %d: expected %+v, got %+v", i, expected[i], violations[i])
+		}
+	}
+}
+
+func TestCheckArchitecture_PythonRelativeImports(t *testing.T) {
+	file := func(path string, deps ...string) *parser.AnalysisResult {
+		result := &parser.AnalysisResult{FilePath: path, Language: "Python"}
+		for i, name := range deps {
+			result.Dependencies = append(result.Dependencies, parser.Dependency{Name: name, Line: i + 1})
+		}
+		return result
+	}
+
+	results := []*parser.AnalysisResult{
+		// from ..services.auth import login; from ..billing import charge
+		file("/p/app/models/user.py", "..services.auth.login", "..billing.charge"),
+		file("/p/app/services/auth.py"),
+	}
+	rules := []ArchitectureRule{{From: "app/models", Forbid: []string{"app/services/...", "app/billing/..."}}}
+
+	violations := CheckArchitecture(rules, results, "/p")
+	expected := []ArchitectureViolation{
+		{Rule: "app/models must not import app/services/..., app/billing/...", FilePath: "/p/app/models/user.py", Line: 1,
+			Import: "..services.auth.login", From: "app/models", Target: "app/services"},
+		// No file of app/billing was analyzed, so the module path is the target
+		{Rule: "app/models must not import app/services/..., app/billing/...", FilePath: "/p/app/models/user.py", Line: 2,
+			Import: "..billing.charge", From: "app/models", Target: "app/billing/charge"},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %+v", len(expected), len(violations), violations)
+	}
+	for i := range expected {
+		if violations[i] != expected[i] {
+			t.Errorf("Violation %d: expected %+v, got %+v", i, expected[i], violations[i])
+		}
+	}
+}
+
+func TestCheckArchitecture_OtherProjectsSharingNames(t *testing.T) {
+	root := goModule(t, "github.com/x/p")
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: root + "/internal/engine/engine.go",
+			Language: "Go",
+			Dependencies: []parser.Dependency{
+				{Name: "errors", Type: "standard", Line: 3},
+				{Name: "github.com/pkg/errors", Type: "external", Line: 4},
+				{Name: "github.com/x/p/internal/model", Type: "external", Line: 5},
+			},
+		},
+		{FilePath: root + "/internal/errors/errors.go", Language: "Go"},
+		{FilePath: root + "/internal/model/model.go", Language: "Go"},
+		{FilePath: root + "/errors/wrap.go", Language: "Go"},
+	}
+	rules := []ArchitectureRule{{From: "internal/engine", Allow: []string{"internal/model"}}}
+
+	if violations := CheckArchitecture(rules, results, root); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", violations)
+	}
+
+	// The project's own errors package is still restricted
+	results[0].Dependencies = append(results[0].Dependencies, parser.Dependency{Name: "github.com/x/p/internal/errors", Type: "external", Line: 6})
+	violations := CheckArchitecture(rules, results, root)
+	if len(violations) != 1 || violations[0].Target != "internal/errors" || violations[0].Line != 6 {
+		t.Errorf("Expected a violation for internal/errors, got %+v", violations)
+	}
+}
+
+func TestArchitectureRuleValidate(t *testing.T) {
+	invalid := []ArchitectureRule{
+		{Forbid: []string{"net/http"}},
+		{From: "domain/..."},
+		{From: "domain/[", Forbid: []string{"net/http"}},
+	}
+	for _, rule := range invalid {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", rule)
+		}
+	}
+}
+
+func TestMatchPackagePattern(t *testing.T) {
+	tests := []struct {
+		pattern, pkg string
+		expected     bool
+	}{
+		{"internal/tui/...", "internal/tui", true},
+		{"internal/tui/...", "internal/tui/views", true},
+		{"internal/tui/...", "internal/tuix", false},
+		{"internal/*/...", "internal/tui/views", true},
+		{"internal/*", "internal/tui/views", false},
+		{"...", "anything/at/all", true},
+		{"net/http", "net/http", true},
+	}
+	for _, test := range tests {
+		if got := matchPackagePattern(test.pattern, test.pkg); got != test.expected {
+			t.Errorf("matchPackagePattern(%q, %q) = %v, expected %v", test.pattern, test.pkg, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// BaselineKindArchitecture marks baseline entries for architecture violations;
+// other entries are named after the quality gate they fail
+const BaselineKindArchitecture = "architecture"
+
+// Baseline records the violations a project has accepted, so that existing
+// problems in legacy code do not fail every run. Entries are keyed by file and
+// symbol rather than line, so they survive unrelated edits.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is an accepted violation and the value it was accepted at
+type BaselineEntry struct {
+	Kind     string  `json:"kind"`                // quality gate name or "architecture"
+	FilePath string  `json:"file_path,omitempty"` // relative to the root; empty for project gates
+	Symbol   string  `json:"symbol,omitempty"`    // function name, or import for architecture violations
+	Value    float64 `json:"value"`
+}
+
+// BaselineChange is a violation whose value moved away from its baseline
+type BaselineChange struct {
+	BaselineEntry
+	Baseline float64 `json:"baseline"`
+}
+
+// BaselineComparison sorts the current violations by their baseline: new ones
+// and ones that got worse fail, the rest are tolerated
+type BaselineComparison struct {
+	New      []BaselineEntry  `json:"new"`
+	Worsened []BaselineChange `json:"worsened"`
+	Improved []BaselineChange `json:"improved"`
+	Fixed    []BaselineEntry  `json:"fixed"` // baseline entries that no longer occur
+	Accepted int              `json:"accepted"`
+
+	// Entries to keep when tightening, at their better value
+	kept []BaselineEntry
+}
+
+// baselineKey identifies a violation across runs
+type baselineKey struct {
+	kind, filePath, symbol string
+}
+
+func (e BaselineEntry) key() baselineKey {
+	return baselineKey{e.Kind, e.FilePath, e.Symbol}
+}
+
+// NewBaseline records the violations of an analysis: failing quality gates,
+// with each offending function or file, and architecture violations
+func NewBaseline(analysis *EnhancedProjectAnalysis, rootPath string) Baseline {
+	return Baseline{Version: 1, Entries: sortedEntries(baselineEntries(analysis, rootPath))}
+}
+
+// Compare checks the violations of an analysis against the baseline
+func (b Baseline) Compare(analysis *EnhancedProjectAnalysis, rootPath string) *BaselineComparison {
+	comparison := &BaselineComparison{
+		New:      []BaselineEntry{},
+		Worsened: []BaselineChange{},
+		Improved: []BaselineChange{},
+		Fixed:    []BaselineEntry{},
+	}
+	current := baselineEntries(analysis, rootPath)
+
+	for _, accepted := range b.Entries {
+		entry, found := current[accepted.key()]
+		switch {
+		case !found:
+			comparison.Fixed = append(comparison.Fixed, accepted)
+		case worse(entry, accepted.Value):
+			comparison.Worsened = append(comparison.Worsened, BaselineChange{BaselineEntry: entry, Baseline: accepted.Value})
+			comparison.kept = append(comparison.kept, accepted)
+		default:
+			if entry.Value != accepted.Value {
+				comparison.Improved = append(comparison.Improved, BaselineChange{BaselineEntry: entry, Baseline: accepted.Value})
+			}
+			comparison.Accepted++
+			comparison.kept = append(comparison.kept, entry)
+		}
+		delete(current, accepted.key())
+	}
+	comparison.New = sortedEntries(current)
+
+	return comparison
+}
+
+// Passed reports whether no violation is new or worse than its baseline
+func (c *BaselineComparison) Passed() bool {
+	return len(c.New) == 0 && len(c.Worsened) == 0
+}
+
+// Tightened returns the baseline ratcheted to the current state: fixed entries
+// are dropped and improved ones keep their better value. New violations are not
+// added, and worsened ones keep their old value.
+func (c *BaselineComparison) Tightened() Baseline {
+	entries := make(map[baselineKey]BaselineEntry, len(c.kept))
+	for _, entry := range c.kept {
+		entries[entry.key()] = entry
+	}
+	return Baseline{Version: 1, Entries: sortedEntries(entries)}
+}
+
+// baselineEntries collects the violations of an analysis by key. A symbol that
+// occurs more than once in a file keeps its worst value.
+func baselineEntries(analysis *EnhancedProjectAnalysis, rootPath string) map[baselineKey]BaselineEntry {
+	entries := make(map[baselineKey]BaselineEntry)
+	add := func(entry BaselineEntry) {
+		if existing, found := entries[entry.key()]; found && !worse(entry, existing.Value) {
+			return
+		}
+		entries[entry.key()] = entry
+	}
+	relative := func(filePath string) string {
+		if rel, err := filepath.Rel(rootPath, filePath); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return filepath.ToSlash(filePath)
+	}
+
+	for _, gate := range analysis.QualityGates {
+		if gate.Passed {
+			continue
+		}
+		if len(gate.Failures) == 0 {
+			add(BaselineEntry{Kind: gate.Gate, Value: gate.Value})
+			continue
+		}
+		for _, failure := range gate.Failures {
+			add(BaselineEntry{Kind: gate.Gate, FilePath: relative(failure.FilePath), Symbol: failure.Symbol, Value: failure.Value})
+		}
+	}
+	for _, violation := range analysis.ArchitectureViolations {
+		add(BaselineEntry{Kind: BaselineKindArchitecture, FilePath: relative(violation.FilePath), Symbol: violation.Import})
+	}
+
+	return entries
+}
+
+// worse reports whether an entry's value is worse than the given one. Gates on
+// minimums get worse as their value drops, all others as it grows.
+func worse(entry BaselineEntry, than float64) bool {
+	switch entry.Kind {
+	case GateMaintainabilityIndex, GateDocumentationRatio:
+		return entry.Value < than
+	default:
+		return entry.Value > than
+	}
+}
+
+// sortedEntries orders entries by kind, file and symbol so baseline files diff cleanly
+func sortedEntries(entries map[baselineKey]BaselineEntry) []BaselineEntry {
+	sorted := make([]BaselineEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Symbol < b.Symbol
+	})
+	return sorted
+}
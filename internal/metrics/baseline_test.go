@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"testing"
+)
+
+func TestBaselineCompare(t *testing.T) {
+	analysisWith := func(complexity map[string]float64, maintainability float64, imports ...string) *EnhancedProjectAnalysis {
+		gate := GateResult{Gate: GateFunctionComplexity}
+		for symbol, value := range complexity {
+			gate.Failures = append(gate.Failures, GateFailure{FilePath: "/p/a.go", Symbol: symbol, Line: 10, Value: value})
+		}
+		gate = gate.finish()
+		analysis := &EnhancedProjectAnalysis{
+			QualityGates: []GateResult{
+				gate,
+				{Gate: GateMaintainabilityIndex, Value: maintainability, Passed: maintainability >= 65},
+			},
+		}
+		for _, imp := range imports {
+			analysis.ArchitectureViolations = append(analysis.ArchitectureViolations,
+				ArchitectureViolation{FilePath: "/p/internal/engine/engine.go", Line: 5, Import: imp})
+		}
+		return analysis
+	}
+
+	baseline := NewBaseline(analysisWith(map[string]float64{"parse": 20, "Server.Handle": 18, "load": 16}, 60, "internal/tui"), "/p")
+	expected := []BaselineEntry{
+		{Kind: BaselineKindArchitecture, FilePath: "internal/engine/engine.go", Symbol: "internal/tui"},
+		{Kind: GateFunctionComplexity, FilePath: "a.go", Symbol: "Server.Handle", Value: 18},
+		{Kind: GateFunctionComplexity, FilePath: "a.go", Symbol: "load", Value: 16},
+		{Kind: GateFunctionComplexity, FilePath: "a.go", Symbol: "parse", Value: 20},
+		{Kind: GateMaintainabilityIndex, Value: 60},
+	}
+	if len(baseline.Entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %+v", len(expected), baseline.Entries)
+	}
+	for i := range expected {
+		if baseline.Entries[i] != expected[i] {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, expected[i], baseline.Entries[i])
+		}
+	}
+
+	// parse got worse, Handle improved, load was fixed, run is new and the
+	// maintainability index dropped
+	comparison := baseline.Compare(analysisWith(map[string]float64{"parse": 22, "Server.Handle": 17, "run": 30}, 58, "internal/tui"), "/p")
+	if comparison.Passed() {
+		t.Error("Expected the comparison to fail")
+	}
+	if len(comparison.New) != 1 || comparison.New[0].Symbol != "run" {
+		t.Errorf("Expected run to be new, got %+v", comparison.New)
+	}
+	if len(comparison.Worsened) != 2 || comparison.Worsened[0].Symbol != "parse" || comparison.Worsened[1].Kind != GateMaintainabilityIndex {
+		t.Errorf("Expected parse and the maintainability index to be worse, got %+v", comparison.Worsened)
+	}
+	if len(comparison.Improved) != 1 || comparison.Improved[0].Symbol != "Server.Handle" || comparison.Improved[0].Baseline != 18 {
+		t.Errorf("Expected Server.Handle to improve from 18, got %+v", comparison.Improved)
+	}
+	if len(comparison.Fixed) != 1 || comparison.Fixed[0].Symbol != "load" {
+		t.Errorf("Expected load to be fixed, got %+v", comparison.Fixed)
+	}
+	if comparison.Accepted != 2 {
+		t.Errorf("Expected 2 accepted violations, got %d", comparison.Accepted)
+	}
+
+	tightened := comparison.Tightened()
+	values := make(map[string]float64)
+	for _, entry := range tightened.Entries {
+		values[entry.Kind+" "+entry.Symbol] = entry.Value
+	}
+	if len(tightened.Entries) != 4 || values[GateFunctionComplexity+" Server.Handle"] != 17 || values[GateFunctionComplexity+" parse"] != 20 {
+		t.Errorf("Expected fixed entries dropped and improved ones lowered, got %+v", tightened.Entries)
+	}
+	if _, found := values[GateFunctionComplexity+" run"]; found {
+		t.Error("Expected new violations not to be added when tightening")
+	}
+
+	if !baseline.Compare(analysisWith(map[string]float64{"parse": 20}, 61, "internal/tui"), "/p").Passed() {
+		t.Error("Expected a run without new or worse violations to pass")
+	}
+}
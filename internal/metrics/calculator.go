@@ -17,8 +17,11 @@ type Calculator struct {
 func NewCalculator() *Calculator {
 	return &Calculator{
 		commentPatterns: map[string]*regexp.Regexp{
-			"go":     regexp.MustCompile(`^\s*//|/\*[\s\S]*?\*/`),
-			"python": regexp.MustCompile(`^\s*#|'''[\s\S]*?'''|"""[\s\S]*?"""`),
+			"go":         regexp.MustCompile(`^\s*//|/\*[\s\S]*?\*/`),
+			"python":     regexp.MustCompile(`^\s*#|'''[\s\S]*?'''|"""[\s\S]*?"""`),
+			"sql":        regexp.MustCompile(`^\s*--|/\*[\s\S]*?\*/`),
+			"javascript": regexp.MustCompile(`^\s*//|/\*[\s\S]*?\*/`),
+			"typescript": regexp.MustCompile(`^\s*//|/\*[\s\S]*?\*/`),
 		},
 	}
 }
@@ -33,6 +36,9 @@ func (c *Calculator) CalculateFileMetrics(result *parser.AnalysisResult, content
 	// Calculate complexity metrics
 	c.calculateComplexityMetrics(result)
 
+	// Calculate Halstead metrics
+	c.calculateHalsteadMetrics(result)
+
 	// Calculate maintainability index
 	c.calculateMaintainabilityIndex(result)
 
@@ -70,6 +76,16 @@ func (c *Calculator) calculateLineMetrics(result *parser.AnalysisResult, lines [
 		}
 	}
 
+	// Files with embedded languages classify lines per block using each block's comment syntax
+	if len(result.LanguageBlocks) > 0 {
+		codeLines, commentLines, blankLines = 0, 0, 0
+		for _, block := range result.LanguageBlocks {
+			codeLines += block.CodeLines
+			commentLines += block.CommentLines
+			blankLines += block.BlankLines
+		}
+	}
+
 	result.CodeLines = codeLines
 	result.CommentLines = commentLines
 	result.BlankLines = blankLines
@@ -83,6 +99,7 @@ func (c *Calculator) calculateLineMetrics(result *parser.AnalysisResult, lines [
 // calculateComplexityMetrics calculates various complexity metrics
 func (c *Calculator) calculateComplexityMetrics(result *parser.AnalysisResult) {
 	totalCyclomatic := 0
+	totalCognitive := 0
 
 	// Calculate cyclomatic complexity for functions
 	for i, fn := range result.Functions {
@@ -90,6 +107,7 @@ func (c *Calculator) calculateComplexityMetrics(result *parser.AnalysisResult) {
 		result.Functions[i].LinesOfCode = fn.LineEnd - fn.LineStart + 1
 		result.Functions[i].ParameterCount = len(fn.Parameters)
 		totalCyclomatic += fn.Complexity
+		totalCognitive += fn.CognitiveComplexity
 	}
 
 	// Calculate cyclomatic complexity for classes and their methods
@@ -101,6 +119,7 @@ func (c *Calculator) calculateComplexityMetrics(result *parser.AnalysisResult) {
 			result.Classes[i].Methods[j].ParameterCount = len(method.Parameters)
 			classComplexity += method.Complexity
 			totalCyclomatic += method.Complexity
+			totalCognitive += method.CognitiveComplexity
 		}
 		result.Classes[i].Complexity = classComplexity
 		result.Classes[i].LinesOfCode = class.LineEnd - class.LineStart + 1
@@ -109,6 +128,42 @@ func (c *Calculator) calculateComplexityMetrics(result *parser.AnalysisResult) {
 	}
 
 	result.CyclomaticComplexity = totalCyclomatic
+	result.CognitiveComplexity = totalCognitive
+}
+
+// calculateHalsteadMetrics derives Halstead volume, difficulty, effort and
+// estimated bugs from the operator and operand counts reported by the parser
+func (c *Calculator) calculateHalsteadMetrics(result *parser.AnalysisResult) {
+	for i := range result.Functions {
+		deriveHalsteadMetrics(&result.Functions[i].Halstead)
+	}
+
+	for i := range result.Classes {
+		for j := range result.Classes[i].Methods {
+			deriveHalsteadMetrics(&result.Classes[i].Methods[j].Halstead)
+		}
+	}
+
+	deriveHalsteadMetrics(&result.Halstead)
+}
+
+// deriveHalsteadMetrics fills in the derived Halstead measures from the base counts
+func deriveHalsteadMetrics(h *parser.HalsteadMetrics) {
+	h.Vocabulary = h.DistinctOperators + h.DistinctOperands
+	h.Length = h.TotalOperators + h.TotalOperands
+
+	h.Volume = 0
+	if h.Vocabulary > 1 {
+		h.Volume = float64(h.Length) * math.Log2(float64(h.Vocabulary))
+	}
+
+	h.Difficulty = 0
+	if h.DistinctOperands > 0 {
+		h.Difficulty = float64(h.DistinctOperators) / 2.0 * float64(h.TotalOperands) / float64(h.DistinctOperands)
+	}
+
+	h.Effort = h.Difficulty * h.Volume
+	h.EstimatedBugs = h.Volume / 3000.0
 }
 
 // calculateMaintainabilityIndex calculates the maintainability index
@@ -120,9 +175,11 @@ func (c *Calculator) calculateMaintainabilityIndex(result *parser.AnalysisResult
 		return
 	}
 
-	// Simplified calculation without full Halstead metrics
-	// Using approximations based on available data
-	halsteadVolume := float64(result.CodeLines) * 2.0 // Approximation
+	halsteadVolume := result.Halstead.Volume
+	if halsteadVolume <= 0 {
+		// Parsers that don't report operator/operand counts fall back to an approximation
+		halsteadVolume = float64(result.CodeLines) * 2.0
+	}
 	cyclomaticComplexity := float64(result.CyclomaticComplexity)
 	linesOfCode := float64(result.CodeLines)
 
@@ -151,15 +208,7 @@ func (c *Calculator) calculateTechnicalDebt(result *parser.AnalysisResult) {
 
 	// High complexity functions contribute to technical debt
 	for _, fn := range result.Functions {
-		if fn.CyclomaticComplexity > 10 {
-			debt += float64(fn.CyclomaticComplexity-10) * 0.5
-		}
-		if fn.ParameterCount > 5 {
-			debt += float64(fn.ParameterCount-5) * 0.3
-		}
-		if fn.LinesOfCode > 50 {
-			debt += float64(fn.LinesOfCode-50) * 0.1
-		}
+		debt += functionDebt(fn)
 	}
 
 	// Large classes contribute to technical debt
@@ -188,6 +237,22 @@ func (c *Calculator) calculateTechnicalDebt(result *parser.AnalysisResult) {
 	result.TechnicalDebt = debt
 }
 
+// functionDebt is the technical debt of a function that is too complex, takes
+// too many parameters or is too long
+func functionDebt(fn parser.FunctionInfo) float64 {
+	debt := 0.0
+	if fn.CyclomaticComplexity > 10 {
+		debt += float64(fn.CyclomaticComplexity-10) * 0.5
+	}
+	if fn.ParameterCount > 5 {
+		debt += float64(fn.ParameterCount-5) * 0.3
+	}
+	if fn.LinesOfCode > 50 {
+		debt += float64(fn.LinesOfCode-50) * 0.1
+	}
+	return debt
+}
+
 // calculateDependencyMetrics calculates dependency-related metrics
 func (c *Calculator) calculateDependencyMetrics(result *parser.AnalysisResult) {
 	result.ImportCount = len(result.Imports)
@@ -202,6 +267,7 @@ func (c *Calculator) calculateDependencyMetrics(result *parser.AnalysisResult) {
 			UsageCount:  1, // Simplified - would need more analysis for actual usage
 			IsDirectDep: true,
 			FilePath:    result.FilePath,
+			Line:        result.ImportLines[imp],
 		}
 		dependencies = append(dependencies, dep)
 	}
@@ -241,12 +307,33 @@ func (c *Calculator) classifyDependency(importPath, language string) string {
 			return "internal"
 		}
 
+		return "external"
+	case "sql":
+		// SQL dependencies are references to tables in the same schema
+		return "internal"
+	case "javascript", "typescript", "vue", "svelte", "html":
+		// Relative and aliased module paths point into the project
+		if strings.HasPrefix(importPath, ".") || strings.HasPrefix(importPath, "/") ||
+			strings.HasPrefix(importPath, "@/") || strings.HasPrefix(importPath, "~/") {
+			return "internal"
+		}
+		if strings.HasPrefix(importPath, "node:") {
+			return "standard"
+		}
 		return "external"
 	default:
 		return "unknown"
 	}
 }
 
+// CalculateCRAP computes the Change Risk Anti-Patterns score of a function from its
+// cyclomatic complexity and test coverage percentage: comp² × (1 − cov)³ + comp
+func (c *Calculator) CalculateCRAP(complexity int, coverage float64) float64 {
+	comp := float64(complexity)
+	uncovered := 1 - math.Min(math.Max(coverage, 0), 100)/100
+	return comp*comp*math.Pow(uncovered, 3) + comp
+}
+
 // CalculateQualityScore calculates an overall quality score
 func (c *Calculator) CalculateQualityScore(maintainability, complexity, documentation, testCoverage, duplication float64) (float64, string) {
 	// Weighted average of different quality factors
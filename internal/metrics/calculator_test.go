@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -143,6 +145,11 @@ func TestClassifyDependency(t *testing.T) {
 		{"requests", "python", "external"},
 		{"mymodule.submodule", "python", "internal"},
 		{"numpy", "python", "external"},
+		{"public.users", "sql", "internal"},
+		{"./components/Button.vue", "vue", "internal"},
+		{"@/stores/user", "typescript", "internal"},
+		{"vue", "vue", "external"},
+		{"node:path", "javascript", "standard"},
 	}
 
 	for _, test := range tests {
@@ -153,3 +160,100 @@ func TestClassifyDependency(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateCognitiveComplexity(t *testing.T) {
+	calculator := NewCalculator()
+
+	result := &parser.AnalysisResult{
+		FilePath: "service.py",
+		Language: "Python",
+		Functions: []parser.FunctionInfo{
+			{Name: "load", LineStart: 1, LineEnd: 10, Complexity: 4, CognitiveComplexity: 6},
+		},
+		Classes: []parser.ClassInfo{
+			{Name: "Service", Methods: []parser.FunctionInfo{
+				{Name: "run", LineStart: 12, LineEnd: 20, Complexity: 3, CognitiveComplexity: 5},
+			}},
+		},
+		AnalyzedAt: time.Now(),
+	}
+
+	calculator.CalculateFileMetrics(result, []byte("def load():\n    pass\n"))
+
+	if result.CognitiveComplexity != 11 {
+		t.Errorf("Expected file cognitive complexity 11, got %d", result.CognitiveComplexity)
+	}
+	if result.CyclomaticComplexity != 7 {
+		t.Errorf("Expected file cyclomatic complexity 7, got %d", result.CyclomaticComplexity)
+	}
+	if result.Functions[0].CognitiveComplexity != 6 {
+		t.Errorf("Expected function cognitive complexity to be preserved, got %d", result.Functions[0].CognitiveComplexity)
+	}
+}
+
+func TestCalculateHalsteadMetrics(t *testing.T) {
+	calculator := NewCalculator()
+
+	counts := parser.HalsteadMetrics{
+		DistinctOperators: 6,
+		DistinctOperands:  4,
+		TotalOperators:    6,
+		TotalOperands:     9,
+	}
+	result := &parser.AnalysisResult{
+		FilePath:   "add.go",
+		Language:   "Go",
+		Functions:  []parser.FunctionInfo{{Name: "add", LineStart: 1, LineEnd: 3, Complexity: 1, Halstead: counts}},
+		Halstead:   counts,
+		AnalyzedAt: time.Now(),
+	}
+
+	content := []byte("func add(a, b int) int {\n" + strings.Repeat("\ta = a + b\n", 40) + "\treturn a\n}\n")
+	calculator.CalculateFileMetrics(result, content)
+
+	h := result.Functions[0].Halstead
+	if h.Vocabulary != 10 || h.Length != 15 {
+		t.Errorf("Expected vocabulary 10 and length 15, got %d and %d", h.Vocabulary, h.Length)
+	}
+
+	expectedVolume := 15 * math.Log2(10)
+	if math.Abs(h.Volume-expectedVolume) > 0.001 {
+		t.Errorf("Expected volume %.3f, got %.3f", expectedVolume, h.Volume)
+	}
+	if math.Abs(h.Difficulty-6.75) > 0.001 {
+		t.Errorf("Expected difficulty 6.75, got %.3f", h.Difficulty)
+	}
+	if math.Abs(h.Effort-h.Difficulty*h.Volume) > 0.001 {
+		t.Errorf("Expected effort to be difficulty * volume, got %.3f", h.Effort)
+	}
+	if math.Abs(h.EstimatedBugs-expectedVolume/3000) > 0.0001 {
+		t.Errorf("Expected estimated bugs %.4f, got %.4f", expectedVolume/3000, h.EstimatedBugs)
+	}
+
+	// The maintainability index uses the real Halstead volume
+	expectedMI := 171.0 - 5.2*math.Log(expectedVolume) - 0.23*1 - 16.2*math.Log(float64(result.CodeLines))
+	if math.Abs(result.MaintainabilityIndex-expectedMI) > 0.001 {
+		t.Errorf("Expected maintainability index %.3f, got %.3f", expectedMI, result.MaintainabilityIndex)
+	}
+}
+
+func TestCalculateCRAP(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		complexity int
+		coverage   float64
+		expected   float64
+	}{
+		{10, 100, 10}, // fully covered: CRAP equals complexity
+		{10, 0, 110},  // uncovered: comp² + comp
+		{6, 50, 10.5}, // 36 * 0.125 + 6
+		{1, 0, 2},
+	}
+
+	for _, tt := range tests {
+		if got := calc.CalculateCRAP(tt.complexity, tt.coverage); math.Abs(got-tt.expected) > 0.001 {
+			t.Errorf("CalculateCRAP(%d, %.0f) = %.3f, expected %.3f", tt.complexity, tt.coverage, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Design smells reported for classes
+const (
+	SmellGodClass  = "god class"
+	SmellDataClass = "data class"
+)
+
+// Thresholds for design smells, following Lanza and Marinescu
+const (
+	godClassMinWMC       = 47      // very high complexity
+	godClassMinATFD      = 6       // uses more than a few fields of other classes
+	godClassMaxTCC       = 1.0 / 3 // methods rarely share fields
+	dataClassMaxWMC      = 31      // little behavior of its own
+	dataClassMinAccessor = 2.0 / 3 // most methods are getters and setters
+	accessorMaxLines     = 5
+)
+
+// calculateClassCohesion computes cohesion metrics for every concrete class with
+// methods and flags god classes and data classes. Go methods are declared apart
+// from their type, so they are matched to structs in the same package by receiver.
+func (a *Aggregator) calculateClassCohesion(analysis *EnhancedProjectAnalysis) {
+	fileResults, ok := analysis.FileResults.([]*parser.AnalysisResult)
+	if !ok {
+		return
+	}
+
+	receiverMethods := make(map[string][]parser.FunctionInfo)
+	for _, result := range fileResults {
+		for _, fn := range result.Functions {
+			if fn.Receiver != "" {
+				key := filepath.Dir(result.FilePath) + "\x00" + fn.Receiver
+				receiverMethods[key] = append(receiverMethods[key], fn)
+			}
+		}
+	}
+
+	cohesion := []ClassCohesion{}
+	for _, result := range fileResults {
+		for _, class := range result.Classes {
+			if class.IsAbstract {
+				continue
+			}
+			methods := class.Methods
+			if len(methods) == 0 {
+				methods = receiverMethods[filepath.Dir(result.FilePath)+"\x00"+class.Name]
+			}
+			if len(methods) == 0 {
+				continue
+			}
+			cohesion = append(cohesion, classCohesion(result.FilePath, class, methods))
+		}
+	}
+
+	sort.Slice(cohesion, func(i, j int) bool {
+		ci, cj := cohesion[i], cohesion[j]
+		if len(ci.Smells) != len(cj.Smells) {
+			return len(ci.Smells) > len(cj.Smells)
+		}
+		if ci.LCOM4 != cj.LCOM4 {
+			return ci.LCOM4 > cj.LCOM4
+		}
+		if ci.WMC != cj.WMC {
+			return ci.WMC > cj.WMC
+		}
+		if ci.FilePath != cj.FilePath {
+			return ci.FilePath < cj.FilePath
+		}
+		return ci.LineStart < cj.LineStart
+	})
+
+	analysis.ClassCohesion = cohesion
+}
+
+// classCohesion computes LCOM4, LCOM-HS, TCC, WMC and ATFD for a class and its methods
+func classCohesion(filePath string, class parser.ClassInfo, methods []parser.FunctionInfo) ClassCohesion {
+	c := ClassCohesion{
+		FilePath:    filePath,
+		Class:       class.Name,
+		LineStart:   class.LineStart,
+		LineEnd:     class.LineEnd,
+		MethodCount: len(methods),
+		FieldCount:  len(class.Fields),
+	}
+
+	fields := make(map[string]bool)
+	for _, field := range class.Fields {
+		fields[fieldName(field)] = true
+	}
+
+	// Constructors touch every field, which would hide a lack of cohesion
+	var members []parser.FunctionInfo
+	byName := make(map[string]int)
+	foreign := make(map[string]bool)
+	for _, method := range methods {
+		c.WMC += method.Complexity
+		for _, data := range method.ForeignData {
+			foreign[data] = true
+		}
+		if method.Name == "__init__" {
+			continue
+		}
+		byName[methodName(method.Name)] = len(members)
+		members = append(members, method)
+	}
+	c.ATFD = len(foreign)
+
+	// Fields used by each method, limited to declared fields
+	used := make([]map[string]bool, len(members))
+	users := make(map[string]int)
+	for i, method := range members {
+		used[i] = make(map[string]bool)
+		for _, field := range method.FieldsAccessed {
+			if fields[field] && !used[i][field] {
+				used[i][field] = true
+				users[field]++
+			}
+		}
+	}
+
+	c.LCOM4 = lcom4(members, used, byName)
+
+	m := len(members)
+	if m > 1 && len(fields) > 0 {
+		accesses := 0
+		for field := range fields {
+			accesses += users[field]
+		}
+		mean := float64(accesses) / float64(len(fields))
+		c.LCOMHS = max(0, (float64(m)-mean)/float64(m-1))
+	}
+
+	c.TCC = 1
+	if m > 1 {
+		connected := 0
+		for i := 0; i < m; i++ {
+			for j := i + 1; j < m; j++ {
+				for field := range used[i] {
+					if used[j][field] {
+						connected++
+						break
+					}
+				}
+			}
+		}
+		c.TCC = float64(connected) / float64(m*(m-1)/2)
+	}
+
+	if c.WMC >= godClassMinWMC && c.ATFD >= godClassMinATFD && c.TCC < godClassMaxTCC {
+		c.Smells = append(c.Smells, SmellGodClass)
+	}
+	if isDataClass(c, members, fields) {
+		c.Smells = append(c.Smells, SmellDataClass)
+	}
+
+	return c
+}
+
+// lcom4 counts the connected groups of methods, where two methods are connected
+// when they use a common field or one calls the other
+func lcom4(methods []parser.FunctionInfo, used []map[string]bool, byName map[string]int) int {
+	parent := make([]int, len(methods))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		parent[find(i)] = find(j)
+	}
+
+	owner := make(map[string]int)
+	for i, method := range methods {
+		for field := range used[i] {
+			if j, exists := owner[field]; exists {
+				union(i, j)
+			} else {
+				owner[field] = i
+			}
+		}
+		for _, callee := range method.MethodsCalled {
+			if j, exists := byName[callee]; exists {
+				union(i, j)
+			}
+		}
+	}
+
+	groups := 0
+	for i := range methods {
+		if find(i) == i {
+			groups++
+		}
+	}
+	return groups
+}
+
+// isDataClass reports whether a class holds data but has little behavior: it has
+// fields, low complexity and its methods are mostly getters and setters
+func isDataClass(c ClassCohesion, methods []parser.FunctionInfo, fields map[string]bool) bool {
+	if len(fields) < 2 || len(methods) == 0 || c.WMC >= dataClassMaxWMC {
+		return false
+	}
+
+	accessors := 0
+	for _, method := range methods {
+		if isAccessor(method, fields) {
+			accessors++
+		}
+	}
+	return float64(accessors)/float64(len(methods)) >= dataClassMinAccessor
+}
+
+// isAccessor reports whether a method is a short getter or setter: named get, set,
+// is or has, or after one of the class's fields
+func isAccessor(method parser.FunctionInfo, fields map[string]bool) bool {
+	if method.Complexity > 1 || method.LineEnd-method.LineStart+1 > accessorMaxLines {
+		return false
+	}
+
+	name := strings.ToLower(methodName(method.Name))
+	for _, prefix := range []string{"get", "set", "is", "has"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for field := range fields {
+		if strings.ToLower(field) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName extracts the name from a field declaration such as "name string" or
+// an embedded "*pkg.Type"
+func fieldName(field string) string {
+	name := field
+	if parts := strings.Fields(field); len(parts) > 1 {
+		name = parts[0]
+	}
+	name = strings.TrimLeft(name, "*")
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	return name
+}
+
+// methodName strips the async marker the Python parser adds to method names
+func methodName(name string) string {
+	return strings.TrimPrefix(name, "async ")
+}
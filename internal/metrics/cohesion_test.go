@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCalculateClassCohesion(t *testing.T) {
+	aggregator := NewAggregator()
+
+	// A large class whose methods each use their own field and reach into other objects
+	manager := parser.ClassInfo{Name: "Manager", LineStart: 1, LineEnd: 400}
+	for i := 0; i < 10; i++ {
+		field := fmt.Sprintf("field%d", i)
+		manager.Fields = append(manager.Fields, field)
+		manager.Methods = append(manager.Methods, parser.FunctionInfo{
+			Name: fmt.Sprintf("handle%d", i), LineStart: 10 + i*30, LineEnd: 35 + i*30, Complexity: 5,
+			FieldsAccessed: []string{field},
+			ForeignData:    []string{fmt.Sprintf("order.item%d", i%6)},
+		})
+	}
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "/p/cart/cart.go",
+			Classes: []parser.ClassInfo{
+				{Name: "Cart", LineStart: 3, LineEnd: 7, Fields: []string{"items []Item", "total int", "log *Logger"}},
+				{Name: "Store", IsAbstract: true, Methods: []parser.FunctionInfo{{Name: "Save"}}},
+				{Name: "Item", Fields: []string{"Name string"}},
+			},
+		},
+		{
+			// Go methods live apart from their struct
+			FilePath: "/p/cart/methods.go",
+			Functions: []parser.FunctionInfo{
+				{Name: "Add", Receiver: "Cart", Complexity: 2, FieldsAccessed: []string{"items"}, MethodsCalled: []string{"recalc"}},
+				{Name: "recalc", Receiver: "Cart", Complexity: 3, FieldsAccessed: []string{"items", "total"}},
+				{Name: "Log", Receiver: "Cart", Complexity: 1, FieldsAccessed: []string{"log"}},
+				{Name: "helper", Complexity: 4},
+			},
+		},
+		{
+			FilePath: "/p/app/models.py",
+			Classes: []parser.ClassInfo{
+				manager,
+				{
+					Name: "Point", LineStart: 410, LineEnd: 425, Fields: []string{"x", "y"},
+					Methods: []parser.FunctionInfo{
+						{Name: "__init__", LineStart: 411, LineEnd: 413, Complexity: 1, FieldsAccessed: []string{"x", "y"}},
+						{Name: "get_x", LineStart: 415, LineEnd: 416, Complexity: 1, FieldsAccessed: []string{"x"}},
+						{Name: "get_y", LineStart: 418, LineEnd: 419, Complexity: 1, FieldsAccessed: []string{"y"}},
+						{Name: "set_x", LineStart: 421, LineEnd: 422, Complexity: 1, FieldsAccessed: []string{"x"}},
+					},
+				},
+			},
+		},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/p")
+
+	classes := analysis.ClassCohesion
+	if len(classes) != 3 {
+		t.Fatalf("Expected 3 classes with methods, got %d: %+v", len(classes), classes)
+	}
+
+	god := classes[0]
+	if god.Class != "Manager" || !reflect.DeepEqual(god.Smells, []string{SmellGodClass}) {
+		t.Errorf("Expected Manager to be a god class first, got %+v", god)
+	}
+	if god.WMC != 50 || god.ATFD != 6 || god.LCOM4 != 10 || god.TCC != 0 {
+		t.Errorf("Expected WMC 50, ATFD 6, LCOM4 10, TCC 0, got %+v", god)
+	}
+
+	data := classes[1]
+	if data.Class != "Point" || !reflect.DeepEqual(data.Smells, []string{SmellDataClass}) {
+		t.Errorf("Expected Point to be a data class, got %+v", data)
+	}
+	// The constructor is left out: get_x and set_x share x, get_y stands alone
+	if data.LCOM4 != 2 || data.MethodCount != 4 {
+		t.Errorf("Expected LCOM4 2 over 4 methods, got %+v", data)
+	}
+
+	cart := classes[2]
+	if cart.Class != "Cart" || cart.FilePath != "/p/cart/cart.go" || cart.LineStart != 3 || cart.LineEnd != 7 {
+		t.Fatalf("Expected Cart with its declaration range, got %+v", cart)
+	}
+	if len(cart.Smells) != 0 {
+		t.Errorf("Expected no smells for Cart, got %v", cart.Smells)
+	}
+	if cart.MethodCount != 3 || cart.FieldCount != 3 || cart.WMC != 6 {
+		t.Errorf("Expected 3 methods, 3 fields and WMC 6, got %+v", cart)
+	}
+	// Add and recalc are connected by items and a call; Log only uses log
+	if cart.LCOM4 != 2 {
+		t.Errorf("Expected LCOM4 2, got %d", cart.LCOM4)
+	}
+	if math.Abs(cart.TCC-1.0/3) > 0.001 {
+		t.Errorf("Expected TCC 0.33, got %.3f", cart.TCC)
+	}
+	// Fields are used by 2, 1 and 1 methods: (3 - 4/3) / (3 - 1)
+	if math.Abs(cart.LCOMHS-5.0/6) > 0.001 {
+		t.Errorf("Expected LCOM-HS 0.833, got %.3f", cart.LCOMHS)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := map[string]string{
+		"items []Item":  "items",
+		"*sync.Mutex":   "Mutex",
+		"Logger":        "Logger",
+		"x":             "x",
+		"id SERIAL KEY": "id",
+	}
+	for field, expected := range tests {
+		if got := fieldName(field); got != expected {
+			t.Errorf("fieldName(%q) = %q, expected %q", field, got, expected)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Kinds of unreferenced declarations
+const (
+	DeadFunction = "function"
+	DeadMethod   = "method"
+	DeadType     = "type"
+)
+
+// DeadCodeOptions controls which declarations are assumed to be used from
+// outside the analyzed code
+type DeadCodeOptions struct {
+	// Name patterns (path.Match syntax) of entry points such as main, test
+	// functions and handlers registered with a framework
+	EntryPoints []string
+}
+
+// DefaultDeadCodeOptions returns the entry points of Go and Python programs and
+// their test frameworks
+func DefaultDeadCodeOptions() DeadCodeOptions {
+	return DeadCodeOptions{
+		EntryPoints: []string{
+			"main", "init", "Test*", "Benchmark*", "Example*", "Fuzz*",
+			"test_*", "setUp*", "tearDown*",
+		},
+	}
+}
+
+// pythonPlainDecorators don't register the decorated function anywhere, so they
+// don't make it an entry point
+var pythonPlainDecorators = map[string]bool{
+	"staticmethod": true, "classmethod": true, "property": true, "cached_property": true,
+	"functools.cached_property": true, "abstractmethod": true, "abc.abstractmethod": true,
+	"wraps": true, "functools.wraps": true, "cache": true, "functools.cache": true,
+	"lru_cache": true, "functools.lru_cache": true, "overload": true, "typing.overload": true,
+	"override": true, "typing.override": true,
+}
+
+// FindDeadCode reports declarations that nothing in the project refers to: Go
+// unexported functions, methods and types not used in their package, and Python
+// module-level functions, private methods and private classes not used in any
+// module. Entry points, names exported through __all__ and functions with
+// registering decorators are treated as used.
+func FindDeadCode(results []*parser.AnalysisResult, options DeadCodeOptions) []DeadCode {
+	// Go names are package scoped; Python names are matched across the project
+	goRefs := make(map[string]map[string]bool)
+	pythonRefs := make(map[string]bool)
+	for _, result := range results {
+		switch result.Language {
+		case "Go":
+			dir := filepath.Dir(result.FilePath)
+			if goRefs[dir] == nil {
+				goRefs[dir] = make(map[string]bool)
+			}
+			for _, name := range result.References {
+				goRefs[dir][name] = true
+			}
+		case "Python":
+			for _, name := range append(result.References, result.Exports...) {
+				pythonRefs[name] = true
+			}
+		}
+	}
+
+	isEntryPoint := func(name string) bool {
+		for _, pattern := range options.EntryPoints {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	dead := []DeadCode{}
+	report := func(result *parser.AnalysisResult, name, kind string, lineStart, lineEnd int) {
+		dead = append(dead, DeadCode{
+			FilePath:  result.FilePath,
+			Name:      name,
+			Kind:      kind,
+			Language:  result.Language,
+			LineStart: lineStart,
+			LineEnd:   lineEnd,
+		})
+	}
+
+	for _, result := range results {
+		switch result.Language {
+		case "Go":
+			refs := goRefs[filepath.Dir(result.FilePath)]
+			for _, fn := range result.Functions {
+				if fn.IsPublic || refs[fn.Name] || isEntryPoint(fn.Name) {
+					continue
+				}
+				if fn.Receiver != "" {
+					report(result, fn.Receiver+"."+fn.Name, DeadMethod, fn.LineStart, fn.LineEnd)
+				} else {
+					report(result, fn.Name, DeadFunction, fn.LineStart, fn.LineEnd)
+				}
+			}
+			for _, class := range result.Classes {
+				if !class.IsPublic && !refs[class.Name] {
+					report(result, class.Name, DeadType, class.LineStart, class.LineEnd)
+				}
+			}
+
+		case "Python":
+			isUsed := func(fn parser.FunctionInfo) bool {
+				name := methodName(fn.Name)
+				return pythonRefs[name] || isEntryPoint(name) || isDunder(name) || hasRegisteringDecorator(fn.Decorators)
+			}
+			for _, fn := range result.Functions {
+				if !isUsed(fn) {
+					report(result, methodName(fn.Name), DeadFunction, fn.LineStart, fn.LineEnd)
+				}
+			}
+			for _, class := range result.Classes {
+				if isPrivatePython(class.Name) && !pythonRefs[class.Name] {
+					report(result, class.Name, DeadType, class.LineStart, class.LineEnd)
+					continue
+				}
+				for _, method := range class.Methods {
+					if isPrivatePython(methodName(method.Name)) && !isUsed(method) {
+						report(result, class.Name+"."+methodName(method.Name), DeadMethod, method.LineStart, method.LineEnd)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(dead, func(i, j int) bool {
+		if dead[i].FilePath != dead[j].FilePath {
+			return dead[i].FilePath < dead[j].FilePath
+		}
+		return dead[i].LineStart < dead[j].LineStart
+	})
+
+	return dead
+}
+
+// hasRegisteringDecorator reports whether a decorator may register the function
+// with a framework, as routes, fixtures and signal handlers do
+func hasRegisteringDecorator(decorators []string) bool {
+	for _, decorator := range decorators {
+		if pythonPlainDecorators[decorator] {
+			continue
+		}
+		if strings.HasSuffix(decorator, ".setter") || strings.HasSuffix(decorator, ".getter") || strings.HasSuffix(decorator, ".deleter") {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isDunder reports whether a name is a Python special method such as __init__
+func isDunder(name string) bool {
+	return len(name) > 4 && strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")
+}
+
+// isPrivatePython reports whether a Python name is private by convention
+func isPrivatePython(name string) bool {
+	return strings.HasPrefix(name, "_") && !isDunder(name)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestFindDeadCode(t *testing.T) {
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "/p/store/store.go",
+			Language: "Go",
+			Functions: []parser.FunctionInfo{
+				{Name: "Open", IsPublic: true, LineStart: 3},
+				{Name: "connect", LineStart: 8},
+				{Name: "retry", LineStart: 12},
+				{Name: "flush", Receiver: "cache", LineStart: 20},
+				{Name: "evict", Receiver: "cache", LineStart: 25},
+				{Name: "init", LineStart: 30},
+			},
+			Classes: []parser.ClassInfo{
+				{Name: "cache", LineStart: 15},
+				{Name: "legacy", LineStart: 35},
+				{Name: "Store", IsPublic: true, LineStart: 40},
+			},
+			References: []string{"cache", "connect", "flush"},
+		},
+		{
+			// Names used in another package don't keep unexported ones alive
+			FilePath:   "/p/cmd/main.go",
+			Language:   "Go",
+			Functions:  []parser.FunctionInfo{{Name: "main"}},
+			References: []string{"retry", "legacy"},
+		},
+		{
+			FilePath: "/p/app/views.py",
+			Language: "Python",
+			Functions: []parser.FunctionInfo{
+				{Name: "index", LineStart: 5, Decorators: []string{"app.route"}},
+				{Name: "helper", LineStart: 10},
+				{Name: "unused", LineStart: 15},
+				{Name: "async exported", LineStart: 20},
+				{Name: "cached", LineStart: 25, Decorators: []string{"functools.lru_cache"}},
+			},
+			Classes: []parser.ClassInfo{
+				{Name: "View", LineStart: 30, Methods: []parser.FunctionInfo{
+					{Name: "__init__", LineStart: 31},
+					{Name: "render", LineStart: 33},
+					{Name: "_format", LineStart: 36},
+					{Name: "_escape", LineStart: 39},
+				}},
+				{Name: "_Cache", LineStart: 45},
+			},
+			References: []string{"_format"},
+			Exports:    []string{"exported"},
+		},
+		{
+			FilePath:   "/p/tests/test_views.py",
+			Language:   "Python",
+			Functions:  []parser.FunctionInfo{{Name: "test_index", LineStart: 1}},
+			References: []string{"helper"},
+		},
+	}
+
+	dead := FindDeadCode(results, DefaultDeadCodeOptions())
+
+	var got []string
+	for _, d := range dead {
+		got = append(got, d.Kind+" "+d.Name)
+	}
+	expected := []string{
+		"function unused",
+		"function cached",
+		"method View._escape",
+		"type _Cache",
+		"function retry",
+		"method cache.evict",
+		"type legacy",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected dead code %v, got %v", expected, got)
+	}
+
+	// Functions registered by name are configured as entry points
+	options := DefaultDeadCodeOptions()
+	options.EntryPoints = append(options.EntryPoints, "retry", "unused")
+	for _, d := range FindDeadCode(results, options) {
+		if d.Name == "retry" || d.Name == "unused" {
+			t.Errorf("Expected entry point %s not to be reported", d.Name)
+		}
+	}
+}
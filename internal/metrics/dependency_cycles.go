@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// findDependencyCycles finds the strongly connected components of the package
+// graph with Tarjan's algorithm. Every component of more than one package is a
+// dependency cycle; it is reported with the shortest cycle through each of its
+// packages and the imports between its packages, all of which lie on a cycle.
+func findDependencyCycles(imports map[string]map[string][]ImportSite) []DependencyCycle {
+	// Visit packages and their targets in order so results are deterministic
+	nodes := make(map[string]bool)
+	for from, targets := range imports {
+		nodes[from] = true
+		for to := range targets {
+			nodes[to] = true
+		}
+	}
+	order := sortedNames(nodes)
+	edges := make(map[string][]string, len(imports))
+	for from, targets := range imports {
+		for to := range targets {
+			edges[from] = append(edges[from], to)
+		}
+		sort.Strings(edges[from])
+	}
+
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range edges[node] {
+			if _, visited := index[next]; !visited {
+				connect(next)
+				lowlink[node] = min(lowlink[node], lowlink[next])
+			} else if onStack[next] {
+				lowlink[node] = min(lowlink[node], index[next])
+			}
+		}
+
+		if lowlink[node] != index[node] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+	for _, node := range order {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+
+	cycles := make([]DependencyCycle, 0, len(components))
+	for _, component := range components {
+		members := make(map[string]bool, len(component))
+		for _, pkg := range component {
+			members[pkg] = true
+		}
+
+		cycle := DependencyCycle{Packages: component, Imports: []ImportSite{}}
+		seen := make(map[string]bool)
+		for _, pkg := range component {
+			path := shortestCycle(pkg, edges, members)
+			key := strings.Join(rotateCycle(path), "\x00")
+			if !seen[key] {
+				seen[key] = true
+				cycle.Cycles = append(cycle.Cycles, path)
+			}
+		}
+
+		for _, from := range component {
+			for _, to := range edges[from] {
+				if members[to] {
+					cycle.Imports = append(cycle.Imports, imports[from][to]...)
+				}
+			}
+		}
+		sort.SliceStable(cycle.Imports, func(i, j int) bool {
+			a, b := cycle.Imports[i], cycle.Imports[j]
+			if a.FilePath != b.FilePath {
+				return a.FilePath < b.FilePath
+			}
+			return a.Line < b.Line
+		})
+
+		cycles = append(cycles, cycle)
+	}
+
+	sort.SliceStable(cycles, func(i, j int) bool {
+		if len(cycles[i].Packages) != len(cycles[j].Packages) {
+			return len(cycles[i].Packages) > len(cycles[j].Packages)
+		}
+		return cycles[i].Packages[0] < cycles[j].Packages[0]
+	})
+
+	return cycles
+}
+
+// shortestCycle returns the shortest path from start back to itself through
+// packages of its component, found breadth first. The path ends with start.
+func shortestCycle(start string, edges map[string][]string, members map[string]bool) []string {
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[node] {
+			if !members[next] {
+				continue
+			}
+			if next == start {
+				var path []string
+				for at := node; at != start; at = previous[at] {
+					path = append(path, at)
+				}
+				path = append(path, start)
+				slices.Reverse(path)
+				return append(path, start)
+			}
+			if _, visited := previous[next]; !visited {
+				previous[next] = node
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// rotateCycle returns a closed cycle without its repeated end, starting at its
+// smallest package, so the same cycle found from different packages compares equal
+func rotateCycle(path []string) []string {
+	if len(path) < 2 {
+		return path
+	}
+	open := path[:len(path)-1]
+	smallest := 0
+	for i, pkg := range open {
+		if pkg < open[smallest] {
+			smallest = i
+		}
+	}
+	return append(slices.Clone(open[smallest:]), open[:smallest]...)
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestFindDependencyCycles(t *testing.T) {
+	aggregator := NewAggregator()
+	root := goModule(t, "github.com/x/p")
+
+	file := func(path string, imports ...string) *parser.AnalysisResult {
+		result := &parser.AnalysisResult{FilePath: path, Language: "Go"}
+		for i, name := range imports {
+			result.Dependencies = append(result.Dependencies, parser.Dependency{Name: name, Type: "external", Line: i + 3})
+		}
+		return result
+	}
+
+	results := []*parser.AnalysisResult{
+		file(root+"/a/a.go", "github.com/x/p/b"),
+		file(root+"/b/b.go", "github.com/x/p/c"),
+		file(root+"/c/c.go", "github.com/x/p/a", "github.com/x/p/b"),
+		file(root+"/d/d.go", "github.com/x/p/a"),
+		file(root+"/e/e.go", "github.com/x/p/f"),
+		file(root+"/f/f.go", "github.com/x/p/e", "fmt"),
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, root)
+	cycles := analysis.DependencyGraph.Cycles
+	if len(cycles) != 2 {
+		t.Fatalf("Expected 2 strongly connected components, got %d: %+v", len(cycles), cycles)
+	}
+
+	largest := cycles[0]
+	if !reflect.DeepEqual(largest.Packages, []string{root + "/a", root + "/b", root + "/c"}) {
+		t.Errorf("Expected packages a, b and c in the largest component, got %v", largest.Packages)
+	}
+	expectedCycles := [][]string{
+		{root + "/a", root + "/b", root + "/c", root + "/a"},
+		{root + "/b", root + "/c", root + "/b"},
+	}
+	if !reflect.DeepEqual(largest.Cycles, expectedCycles) {
+		t.Errorf("Expected minimal cycles %v, got %v", expectedCycles, largest.Cycles)
+	}
+	expectedImports := []ImportSite{
+		{From: root + "/a", To: root + "/b", FilePath: root + "/a/a.go", Line: 3, Import: "github.com/x/p/b"},
+		{From: root + "/b", To: root + "/c", FilePath: root + "/b/b.go", Line: 3, Import: "github.com/x/p/c"},
+		{From: root + "/c", To: root + "/a", FilePath: root + "/c/c.go", Line: 3, Import: "github.com/x/p/a"},
+		{From: root + "/c", To: root + "/b", FilePath: root + "/c/c.go", Line: 4, Import: "github.com/x/p/b"},
+	}
+	if !reflect.DeepEqual(largest.Imports, expectedImports) {
+		t.Errorf("Expected imports %+v, got %+v", expectedImports, largest.Imports)
+	}
+
+	if !reflect.DeepEqual(cycles[1].Cycles, [][]string{{root + "/e", root + "/f", root + "/e"}}) {
+		t.Errorf("Expected the cycle between e and f, got %v", cycles[1].Cycles)
+	}
+	if len(analysis.DependencyGraph.CircularDependencies) != 3 {
+		t.Errorf("Expected 3 circular dependency paths, got %v", analysis.DependencyGraph.CircularDependencies)
+	}
+}
+
+func TestFindDependencyCycles_Acyclic(t *testing.T) {
+	imports := map[string]map[string][]ImportSite{
+		"/p/a": {"/p/b": {{From: "/p/a", To: "/p/b"}}},
+		"/p/b": {"/p/c": {{From: "/p/b", To: "/p/c"}}},
+	}
+	if cycles := findDependencyCycles(imports); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %+v", cycles)
+	}
+}
+
+func TestFindDependencyCycles_PythonRelativeImports(t *testing.T) {
+	aggregator := NewAggregator()
+
+	file := func(path string, imports ...string) *parser.AnalysisResult {
+		result := &parser.AnalysisResult{FilePath: path, Language: "Python"}
+		for i, name := range imports {
+			result.Dependencies = append(result.Dependencies, parser.Dependency{Name: name, Type: "internal", Line: i + 1})
+		}
+		return result
+	}
+
+	results := []*parser.AnalysisResult{
+		// from ..services.auth import login
+		file("/p/app/models/user.py", "..services.auth.login"),
+		// from ..models.user import User
+		file("/p/app/services/auth.py", "..models.user.User"),
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/p")
+	cycles := analysis.DependencyGraph.Cycles
+	if len(cycles) != 1 {
+		t.Fatalf("Expected 1 strongly connected component, got %d: %+v", len(cycles), cycles)
+	}
+	if !reflect.DeepEqual(cycles[0].Packages, []string{"/p/app/models", "/p/app/services"}) {
+		t.Errorf("Expected models and services in the cycle, got %v", cycles[0].Packages)
+	}
+	expectedImports := []ImportSite{
+		{From: "/p/app/models", To: "/p/app/services", FilePath: "/p/app/models/user.py", Line: 1, Import: "..services.auth.login"},
+		{From: "/p/app/services", To: "/p/app/models", FilePath: "/p/app/services/auth.py", Line: 1, Import: "..models.user.User"},
+	}
+	if !reflect.DeepEqual(cycles[0].Imports, expectedImports) {
+		t.Errorf("Expected imports %+v, got %+v", expectedImports, cycles[0].Imports)
+	}
+}
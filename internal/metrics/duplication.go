@@ -0,0 +1,431 @@
+package metrics
+
+import (
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// DefaultMinCloneTokens is the minimum number of tokens a duplicated block must span
+const DefaultMinCloneTokens = 50
+
+// maxCloneBucket caps how many locations sharing a window hash are compared,
+// so highly repetitive code (generated tables, literals) stays linear
+const maxCloneBucket = 64
+
+// Tokenizers for the comment styles of the supported languages. Comments and
+// whitespace are dropped; strings, numbers, words and punctuation become tokens.
+var (
+	cStyleTokenPattern = regexp.MustCompile(
+		"`[^`]*`" + `|"(?:\\.|[^"\\\n])*"|'(?:\\.|[^'\\\n])*'|/\*[\s\S]*?\*/|//[^\n]*|\d[\w.]*|[A-Za-z_$][\w$]*|[^\s\w]`)
+	hashStyleTokenPattern = regexp.MustCompile(
+		`"""[\s\S]*?"""|'''[\s\S]*?'''|"(?:\\.|[^"\\\n])*"|'(?:\\.|[^'\\\n])*'|#[^\n]*|\d[\w.]*|[A-Za-z_][\w]*|[^\s\w]`)
+	sqlStyleTokenPattern = regexp.MustCompile(
+		`"(?:[^"\n])*"|'(?:''|[^'])*'|/\*[\s\S]*?\*/|--[^\n]*|\d[\w.]*|[A-Za-z_][\w$]*|[^\s\w]`)
+)
+
+// cloneKeywords are kept verbatim during normalization so that structurally
+// different code does not collapse into the same token sequence
+var cloneKeywords = map[string]bool{
+	"if": true, "else": true, "elif": true, "for": true, "while": true, "do": true,
+	"switch": true, "case": true, "default": true, "break": true, "continue": true,
+	"return": true, "func": true, "function": true, "def": true, "class": true,
+	"struct": true, "interface": true, "type": true, "var": true, "let": true,
+	"const": true, "import": true, "from": true, "package": true, "try": true,
+	"catch": true, "except": true, "finally": true, "raise": true, "throw": true,
+	"new": true, "go": true, "defer": true, "select": true, "range": true,
+	"map": true, "chan": true, "with": true, "as": true, "in": true, "not": true,
+	"and": true, "or": true, "is": true, "lambda": true, "yield": true,
+	"async": true, "await": true, "goto": true, "fallthrough": true, "pass": true,
+	"where": true, "insert": true, "update": true, "delete": true,
+	"join": true, "create": true, "table": true,
+}
+
+// cloneToken is a normalized token and the line it starts on
+type cloneToken struct {
+	hash uint64
+	line int
+}
+
+// duplicationFile holds the normalized token stream of one file
+type duplicationFile struct {
+	path     string
+	language string
+	tokens   []cloneToken
+}
+
+// cloneMatch is a maximal pair of equal token runs
+type cloneMatch struct {
+	fileA, startA int
+	fileB, startB int
+	length        int
+}
+
+// DuplicationDetector finds duplicated blocks within and across files by
+// hashing windows of normalized tokens (Rabin-Karp) and extending matches
+type DuplicationDetector struct {
+	minTokens int
+	files     []*duplicationFile
+	byPath    map[string]*duplicationFile
+}
+
+// NewDuplicationDetector creates a detector reporting clones of at least minTokens tokens
+func NewDuplicationDetector(minTokens int) *DuplicationDetector {
+	if minTokens <= 0 {
+		minTokens = DefaultMinCloneTokens
+	}
+	return &DuplicationDetector{
+		minTokens: minTokens,
+		files:     []*duplicationFile{},
+		byPath:    make(map[string]*duplicationFile),
+	}
+}
+
+// AddFile tokenizes a file and adds it to the set being compared
+func (d *DuplicationDetector) AddFile(filePath, language string, content []byte) {
+	file := &duplicationFile{
+		path:     filePath,
+		language: strings.ToLower(language),
+		tokens:   tokenizeForClones(string(content), language),
+	}
+	d.files = append(d.files, file)
+	d.byPath[filePath] = file
+}
+
+// Detect finds clone groups across all added files. The duplicated percentage of
+// each matching result is recorded in its CodeDuplication field.
+func (d *DuplicationDetector) Detect(results []*parser.AnalysisResult) DuplicationAnalysis {
+	matches := d.findMatches()
+	groups := d.groupMatches(matches)
+
+	// Union of duplicated lines per file
+	duplicated := make(map[string]map[int]bool)
+	for _, group := range groups {
+		for _, instance := range group.Instances {
+			lines := duplicated[instance.FilePath]
+			if lines == nil {
+				lines = make(map[int]bool)
+				duplicated[instance.FilePath] = lines
+			}
+			for line := instance.LineStart; line <= instance.LineEnd; line++ {
+				lines[line] = true
+			}
+		}
+	}
+
+	analysis := DuplicationAnalysis{Groups: groups}
+	for _, result := range results {
+		total := result.CodeLines
+		if total == 0 {
+			total = result.LineCount
+		}
+		count := d.countCodeLines(result.FilePath, duplicated[result.FilePath])
+		if count > total {
+			count = total
+		}
+
+		result.CodeDuplication = 0
+		if total > 0 {
+			result.CodeDuplication = float64(count) / float64(total) * 100
+		}
+		analysis.DuplicatedLines += count
+		analysis.TotalLines += total
+	}
+	if analysis.TotalLines > 0 {
+		analysis.Percentage = float64(analysis.DuplicatedLines) / float64(analysis.TotalLines) * 100
+	}
+
+	return analysis
+}
+
+// findMatches returns maximal, non-overlapping pairs of equal token runs
+func (d *DuplicationDetector) findMatches() []cloneMatch {
+	type location struct{ file, start int }
+	type window struct {
+		language string
+		hash     uint64
+	}
+
+	// Index every window by its rolling hash; code is only compared with code
+	// of the same language
+	buckets := make(map[window][]location)
+	for fileIdx, file := range d.files {
+		forEachWindowHash(file.tokens, d.minTokens, func(start int, hash uint64) {
+			key := window{file.language, hash}
+			if len(buckets[key]) < maxCloneBucket {
+				buckets[key] = append(buckets[key], location{fileIdx, start})
+			}
+		})
+	}
+
+	var matches []cloneMatch
+	for _, locations := range buckets {
+		for i := 0; i < len(locations); i++ {
+			for j := i + 1; j < len(locations); j++ {
+				a, b := locations[i], locations[j]
+				tokensA, tokensB := d.files[a.file].tokens, d.files[b.file].tokens
+
+				// Only start at the beginning of a run; earlier windows cover the rest
+				if a.start > 0 && b.start > 0 && tokensA[a.start-1].hash == tokensB[b.start-1].hash {
+					continue
+				}
+
+				limit := len(tokensA) - a.start
+				if rest := len(tokensB) - b.start; rest < limit {
+					limit = rest
+				}
+				if a.file == b.file && b.start-a.start < limit {
+					// Occurrences in the same file must not overlap
+					limit = b.start - a.start
+				}
+				if limit < d.minTokens {
+					continue
+				}
+
+				length := 0
+				for length < limit && tokensA[a.start+length].hash == tokensB[b.start+length].hash {
+					length++
+				}
+				if length < d.minTokens {
+					// Hash collision
+					continue
+				}
+
+				matches = append(matches, cloneMatch{
+					fileA: a.file, startA: a.start,
+					fileB: b.file, startB: b.start,
+					length: length,
+				})
+			}
+		}
+	}
+
+	return matches
+}
+
+// groupMatches merges pairwise matches into clone groups. Occurrences that
+// overlap or contain one another in a file are merged into one instance, and
+// matches sharing an instance form one group, so the runs of slightly different
+// lengths found around the same code are reported once, at their widest.
+func (d *DuplicationDetector) groupMatches(matches []cloneMatch) []CloneGroup {
+	type occurrence struct{ file, start, end, match int }
+
+	var occurrences []occurrence
+	for i, match := range matches {
+		occurrences = append(occurrences,
+			occurrence{match.fileA, match.startA, match.startA + match.length, i},
+			occurrence{match.fileB, match.startB, match.startB + match.length, i})
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		if occurrences[i].file != occurrences[j].file {
+			return occurrences[i].file < occurrences[j].file
+		}
+		return occurrences[i].start < occurrences[j].start
+	})
+
+	// Merge overlapping occurrences into instances, keeping the instances of
+	// each match so they can be joined
+	type instance struct{ file, start, end int }
+	var instances []instance
+	matchInstances := make([][]int, len(matches))
+	for _, occ := range occurrences {
+		last := len(instances) - 1
+		if last < 0 || instances[last].file != occ.file || occ.start >= instances[last].end {
+			instances = append(instances, instance{occ.file, occ.start, occ.end})
+			last++
+		} else if occ.end > instances[last].end {
+			instances[last].end = occ.end
+		}
+		matchInstances[occ.match] = append(matchInstances[occ.match], last)
+	}
+
+	parent := make([]int, len(instances))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	longest := make(map[int]int)
+	for i, pair := range matchInstances {
+		rootA, rootB := find(pair[0]), find(pair[1])
+		if rootA != rootB {
+			parent[rootB] = rootA
+		}
+		longest[rootA] = max(longest[rootA], longest[rootB], matches[i].length)
+	}
+
+	groups := make(map[int]*CloneGroup)
+	var order []int
+	for i, inst := range instances {
+		root := find(i)
+		group, exists := groups[root]
+		if !exists {
+			group = &CloneGroup{Tokens: longest[root]}
+			groups[root] = group
+			order = append(order, root)
+		}
+		tokens := d.files[inst.file].tokens
+		group.Instances = append(group.Instances, CloneInstance{
+			FilePath:  d.files[inst.file].path,
+			LineStart: tokens[inst.start].line,
+			LineEnd:   tokens[inst.end-1].line,
+		})
+	}
+
+	result := make([]CloneGroup, 0, len(order))
+	for _, root := range order {
+		group := groups[root]
+		if len(group.Instances) < 2 {
+			// All occurrences of the clone overlap within one file
+			continue
+		}
+		sort.Slice(group.Instances, func(i, j int) bool {
+			if group.Instances[i].FilePath != group.Instances[j].FilePath {
+				return group.Instances[i].FilePath < group.Instances[j].FilePath
+			}
+			return group.Instances[i].LineStart < group.Instances[j].LineStart
+		})
+		first := group.Instances[0]
+		group.Lines = first.LineEnd - first.LineStart + 1
+		result = append(result, *group)
+	}
+
+	// Largest groups first: most duplicated lines, then longest token runs
+	sort.Slice(result, func(i, j int) bool {
+		sizeI := result[i].Lines * len(result[i].Instances)
+		sizeJ := result[j].Lines * len(result[j].Instances)
+		if sizeI != sizeJ {
+			return sizeI > sizeJ
+		}
+		if result[i].Tokens != result[j].Tokens {
+			return result[i].Tokens > result[j].Tokens
+		}
+		first, other := result[i].Instances[0], result[j].Instances[0]
+		if first.FilePath != other.FilePath {
+			return first.FilePath < other.FilePath
+		}
+		return first.LineStart < other.LineStart
+	})
+
+	return result
+}
+
+// countCodeLines counts the duplicated lines of a file that carry tokens, so
+// blank and comment lines inside a clone are not counted as duplicated code
+func (d *DuplicationDetector) countCodeLines(filePath string, lines map[int]bool) int {
+	if len(lines) == 0 {
+		return 0
+	}
+
+	file, exists := d.byPath[filePath]
+	if !exists {
+		return 0
+	}
+	count, lastLine := 0, 0
+	for _, token := range file.tokens {
+		if token.line != lastLine && lines[token.line] {
+			count++
+		}
+		lastLine = token.line
+	}
+	return count
+}
+
+// forEachWindowHash calls fn with the rolling hash of every window of size tokens
+func forEachWindowHash(tokens []cloneToken, size int, fn func(start int, hash uint64)) {
+	if len(tokens) < size {
+		return
+	}
+
+	const base = 1000003
+
+	// base^(size-1), the weight of the token leaving the window
+	var power uint64 = 1
+	for i := 1; i < size; i++ {
+		power *= base
+	}
+
+	var hash uint64
+	for i := 0; i < size; i++ {
+		hash = hash*base + tokens[i].hash
+	}
+	fn(0, hash)
+
+	for start := 1; start+size <= len(tokens); start++ {
+		hash = (hash-tokens[start-1].hash*power)*base + tokens[start+size-1].hash
+		fn(start, hash)
+	}
+}
+
+// tokenizeForClones splits source into normalized tokens. Identifiers become $id
+// and literals $lit, so renamed copies are still detected as clones.
+func tokenizeForClones(source, language string) []cloneToken {
+	language = strings.ToLower(language)
+	pattern := cStyleTokenPattern
+	lineComment := "//"
+	switch language {
+	case "python":
+		pattern, lineComment = hashStyleTokenPattern, "#"
+	case "sql":
+		pattern, lineComment = sqlStyleTokenPattern, "--"
+	}
+
+	tokens := []cloneToken{}
+	line, offset := 1, 0
+	for _, loc := range pattern.FindAllStringIndex(source, -1) {
+		line += strings.Count(source[offset:loc[0]], "\n")
+		offset = loc[0]
+		text := source[loc[0]:loc[1]]
+
+		if strings.HasPrefix(text, lineComment) || strings.HasPrefix(text, "/*") {
+			continue
+		}
+		if language == "python" && (strings.HasPrefix(text, `"""`) || strings.HasPrefix(text, "'''")) {
+			// Docstrings are documentation, not code
+			if isPythonDocstringPosition(tokens) {
+				continue
+			}
+		}
+
+		tokens = append(tokens, cloneToken{hash: tokenHash(normalizeCloneToken(text)), line: line})
+	}
+	return tokens
+}
+
+// isPythonDocstringPosition reports whether a string at this point starts a
+// statement (after a colon or at the beginning of the file)
+func isPythonDocstringPosition(tokens []cloneToken) bool {
+	return len(tokens) == 0 || tokens[len(tokens)-1].hash == tokenHash(":")
+}
+
+// normalizeCloneToken maps identifiers and literals to placeholders
+func normalizeCloneToken(text string) string {
+	first := text[0]
+	switch {
+	case first == '"' || first == '\'' || first == '`' || (first >= '0' && first <= '9'):
+		return "$lit"
+	case first == '_' || first == '$' || (first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z'):
+		if cloneKeywords[strings.ToLower(text)] {
+			return text
+		}
+		return "$id"
+	default:
+		return text
+	}
+}
+
+// tokenHash hashes a normalized token
+func tokenHash(text string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	return h.Sum64()
+}
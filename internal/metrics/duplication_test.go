@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+const duplicatedGoFunc = `func %s(items []int, limit int) int {
+	total := 0
+	for i, item := range items {
+		if item > limit {
+			total += item * 2
+		} else if i%%2 == 0 {
+			total -= item
+		}
+	}
+	return total
+}
+`
+
+func TestDuplicationDetector_CrossFileClone(t *testing.T) {
+	detector := NewDuplicationDetector(30)
+
+	// The same function with renamed identifiers in two files
+	first := "package a\n\n" + strings.ReplaceAll(duplicatedGoFunc, "%s", "sum") + "\nfunc other() {}\n"
+	second := "package b\n\nimport \"fmt\"\n\n" +
+		strings.NewReplacer("%s", "total", "items", "values", "limit", "max", "item", "v").Replace(duplicatedGoFunc)
+	first = strings.ReplaceAll(first, "%%", "%")
+	second = strings.ReplaceAll(second, "%%", "%")
+
+	detector.AddFile("a/a.go", "Go", []byte(first))
+	detector.AddFile("b/b.go", "Go", []byte(second))
+	detector.AddFile("c/c.go", "Go", []byte("package c\n\nfunc unrelated() string { return \"x\" }\n"))
+
+	results := []*parser.AnalysisResult{
+		{FilePath: "a/a.go", CodeLines: 13},
+		{FilePath: "b/b.go", CodeLines: 14},
+		{FilePath: "c/c.go", CodeLines: 2},
+	}
+	analysis := detector.Detect(results)
+
+	if len(analysis.Groups) != 1 {
+		t.Fatalf("Expected 1 clone group, got %d: %+v", len(analysis.Groups), analysis.Groups)
+	}
+	group := analysis.Groups[0]
+	if len(group.Instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(group.Instances))
+	}
+	if group.Instances[0].FilePath != "a/a.go" || group.Instances[0].LineStart != 3 || group.Instances[0].LineEnd != 13 {
+		t.Errorf("Unexpected first instance: %+v", group.Instances[0])
+	}
+	if group.Instances[1].FilePath != "b/b.go" || group.Instances[1].LineStart != 5 || group.Instances[1].LineEnd != 15 {
+		t.Errorf("Unexpected second instance: %+v", group.Instances[1])
+	}
+	if group.Lines != 11 {
+		t.Errorf("Expected clone of 11 lines, got %d", group.Lines)
+	}
+
+	if results[2].CodeDuplication != 0 {
+		t.Errorf("Expected no duplication in c.go, got %.1f", results[2].CodeDuplication)
+	}
+	if results[0].CodeDuplication < 84 || results[0].CodeDuplication > 85 {
+		t.Errorf("Expected 11/13 lines duplicated in a.go, got %.1f%%", results[0].CodeDuplication)
+	}
+	if analysis.DuplicatedLines != 22 || analysis.TotalLines != 29 {
+		t.Errorf("Expected 22 of 29 lines duplicated, got %d of %d", analysis.DuplicatedLines, analysis.TotalLines)
+	}
+}
+
+func TestDuplicationDetector_SameFileClonesDoNotOverlap(t *testing.T) {
+	detector := NewDuplicationDetector(30)
+
+	body := strings.ReplaceAll(duplicatedGoFunc, "%%", "%")
+	source := "package a\n\n" + strings.ReplaceAll(body, "%s", "one") + "\n" +
+		"// a comment between the copies\n" + strings.ReplaceAll(body, "%s", "two")
+	detector.AddFile("a.go", "Go", []byte(source))
+
+	analysis := detector.Detect([]*parser.AnalysisResult{{FilePath: "a.go", CodeLines: 21}})
+
+	if len(analysis.Groups) != 1 {
+		t.Fatalf("Expected 1 clone group, got %d", len(analysis.Groups))
+	}
+	instances := analysis.Groups[0].Instances
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+	if instances[0].LineEnd >= instances[1].LineStart {
+		t.Errorf("Expected non-overlapping instances, got %+v", instances)
+	}
+}
+
+func TestDuplicationDetector_OverlappingRunsFormOneGroup(t *testing.T) {
+	detector := NewDuplicationDetector(30)
+
+	// a.go and b.go also share the package clause and a setup line before the
+	// function, so the runs matched against c.go are shorter but cover the same code
+	body := strings.ReplaceAll(strings.ReplaceAll(duplicatedGoFunc, "%%", "%"), "%s", "sum")
+	setup := "var limits = []int{1, 2, 3}\n\n"
+	detector.AddFile("a.go", "Go", []byte("package a\n\n"+setup+body))
+	detector.AddFile("b.go", "Go", []byte("package b\n\n"+setup+body))
+	detector.AddFile("c.go", "Go", []byte("package c\n\nconst x = \"c\"\n\n"+body))
+
+	analysis := detector.Detect(nil)
+	if len(analysis.Groups) != 1 {
+		t.Fatalf("Expected 1 clone group, got %d: %+v", len(analysis.Groups), analysis.Groups)
+	}
+	group := analysis.Groups[0]
+	expected := []CloneInstance{
+		{FilePath: "a.go", LineStart: 1, LineEnd: 15},
+		{FilePath: "b.go", LineStart: 1, LineEnd: 15},
+		{FilePath: "c.go", LineStart: 5, LineEnd: 15},
+	}
+	if !reflect.DeepEqual(group.Instances, expected) {
+		t.Errorf("Expected instances %+v, got %+v", expected, group.Instances)
+	}
+}
+
+func TestDuplicationDetector_ComparesSameLanguageOnly(t *testing.T) {
+	detector := NewDuplicationDetector(20)
+
+	code := []byte("function sum(items, limit) {\n  let total = 0;\n  for (const item of items) {\n    if (item > limit) {\n      total += item * 2;\n    }\n  }\n  return total;\n}\n")
+	detector.AddFile("a.js", "JavaScript", code)
+	detector.AddFile("b.ts", "TypeScript", code)
+	if groups := detector.Detect(nil).Groups; len(groups) != 0 {
+		t.Errorf("Expected no clones across languages, got %+v", groups)
+	}
+
+	detector.AddFile("c.js", "JavaScript", code)
+	if groups := detector.Detect(nil).Groups; len(groups) != 1 || len(groups[0].Instances) != 2 {
+		t.Errorf("Expected one clone between the JavaScript files, got %+v", groups)
+	}
+}
+
+func TestDuplicationDetector_BelowThreshold(t *testing.T) {
+	detector := NewDuplicationDetector(0)
+
+	code := []byte("def f(x):\n    return x + 1\n")
+	detector.AddFile("a.py", "Python", code)
+	detector.AddFile("b.py", "Python", code)
+
+	analysis := detector.Detect([]*parser.AnalysisResult{{FilePath: "a.py", CodeLines: 2}, {FilePath: "b.py", CodeLines: 2}})
+	if len(analysis.Groups) != 0 {
+		t.Errorf("Expected no clones below %d tokens, got %d", DefaultMinCloneTokens, len(analysis.Groups))
+	}
+	if analysis.Percentage != 0 {
+		t.Errorf("Expected 0%% duplication, got %.1f", analysis.Percentage)
+	}
+}
+
+func TestTokenizeForClones(t *testing.T) {
+	tokens := tokenizeForClones("# comment\ndef add(a, b):\n    \"\"\"Docstring.\"\"\"\n    return a + 'x'\n", "Python")
+
+	var normalized []string
+	for _, token := range tokens {
+		for _, candidate := range []string{"def", "$id", "(", ",", ")", ":", "return", "+", "$lit"} {
+			if token.hash == tokenHash(candidate) {
+				normalized = append(normalized, candidate)
+			}
+		}
+	}
+
+	expected := "def $id ( $id , $id ) : return $id + $lit"
+	if got := strings.Join(normalized, " "); got != expected {
+		t.Errorf("Expected tokens %q, got %q", expected, got)
+	}
+	if tokens[0].line != 2 || tokens[len(tokens)-1].line != 4 {
+		t.Errorf("Expected tokens on lines 2-4, got %d-%d", tokens[0].line, tokens[len(tokens)-1].line)
+	}
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"sort"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// maxHotspots caps the number of file and function hotspots reported
+const maxHotspots = 100
+
+// calculateHotspots ranks files and functions by complexity combined with change
+// frequency. Scores multiply the normalized complexity by the normalized number
+// of changes, so only code that is both complex and often changed scores high.
+func (a *Aggregator) calculateHotspots(analysis *EnhancedProjectAnalysis) {
+	fileResults, ok := analysis.FileResults.([]*parser.AnalysisResult)
+	if !ok {
+		return
+	}
+
+	analysis.FileHotspots = a.fileHotspots(fileResults)
+	analysis.FunctionHotspots = a.functionHotspots(fileResults)
+}
+
+// fileHotspots scores files using cyclomatic complexity and technical debt
+func (a *Aggregator) fileHotspots(results []*parser.AnalysisResult) []Hotspot {
+	var maxComplexity, maxChanges int
+	var maxDebt float64
+	for _, result := range results {
+		if result.History == nil || result.History.CommitCount == 0 {
+			continue
+		}
+		maxComplexity = max(maxComplexity, result.CyclomaticComplexity)
+		maxChanges = max(maxChanges, result.History.CommitCount)
+		maxDebt = max(maxDebt, result.TechnicalDebt)
+	}
+	if maxComplexity == 0 || maxChanges == 0 {
+		return []Hotspot{}
+	}
+
+	hotspots := []Hotspot{}
+	for _, result := range results {
+		if result.History == nil || result.History.CommitCount == 0 || result.CyclomaticComplexity == 0 {
+			continue
+		}
+
+		// Complexity and debt weigh equally when debt is known
+		complexityFactor := float64(result.CyclomaticComplexity) / float64(maxComplexity)
+		if maxDebt > 0 {
+			complexityFactor = (complexityFactor + result.TechnicalDebt/maxDebt) / 2
+		}
+		changeFactor := float64(result.History.CommitCount) / float64(maxChanges)
+
+		hotspots = append(hotspots, Hotspot{
+			FilePath:      result.FilePath,
+			Complexity:    result.CyclomaticComplexity,
+			TechnicalDebt: result.TechnicalDebt,
+			Changes:       result.History.CommitCount,
+			Churn:         result.History.LinesAdded + result.History.LinesRemoved,
+			Score:         complexityFactor * changeFactor * 100,
+		})
+	}
+
+	return rankHotspots(hotspots)
+}
+
+// functionHotspots scores functions using their complexity and the commits that
+// changed them. Functions without a change count, such as those outside the
+// most changed files, are left out.
+func (a *Aggregator) functionHotspots(results []*parser.AnalysisResult) []Hotspot {
+	type candidate struct {
+		filePath string
+		name     string
+		fn       parser.FunctionInfo
+	}
+
+	var candidates []candidate
+	var maxComplexity, maxChanges int
+	for _, result := range results {
+		add := func(name string, fn parser.FunctionInfo) {
+			if fn.ChangeCount == 0 || fn.Complexity == 0 {
+				return
+			}
+			candidates = append(candidates, candidate{result.FilePath, name, fn})
+			maxComplexity = max(maxComplexity, fn.Complexity)
+			maxChanges = max(maxChanges, fn.ChangeCount)
+		}
+		for _, fn := range result.Functions {
+			add(fn.Name, fn)
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				add(class.Name+"."+method.Name, method)
+			}
+		}
+	}
+
+	hotspots := []Hotspot{}
+	for _, c := range candidates {
+		hotspots = append(hotspots, Hotspot{
+			FilePath:   c.filePath,
+			Function:   c.name,
+			Line:       c.fn.LineStart,
+			Complexity: c.fn.Complexity,
+			Changes:    c.fn.ChangeCount,
+			Score:      float64(c.fn.Complexity) / float64(maxComplexity) * float64(c.fn.ChangeCount) / float64(maxChanges) * 100,
+		})
+	}
+
+	return rankHotspots(hotspots)
+}
+
+// rankHotspots sorts hotspots by score and keeps the top maxHotspots
+func rankHotspots(hotspots []Hotspot) []Hotspot {
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		if hotspots[i].FilePath != hotspots[j].FilePath {
+			return hotspots[i].FilePath < hotspots[j].FilePath
+		}
+		return hotspots[i].Line < hotspots[j].Line
+	})
+	if len(hotspots) > maxHotspots {
+		hotspots = hotspots[:maxHotspots]
+	}
+	return hotspots
+}
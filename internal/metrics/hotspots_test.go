@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCalculateHotspots(t *testing.T) {
+	aggregator := NewAggregator()
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "core/engine.go", CyclomaticComplexity: 40, TechnicalDebt: 20,
+			History: &parser.GitHistory{CommitCount: 20, LinesAdded: 300, LinesRemoved: 100},
+			Functions: []parser.FunctionInfo{
+				{Name: "Run", LineStart: 10, Complexity: 15, ChangeCount: 8},
+				{Name: "stop", LineStart: 90, Complexity: 2, ChangeCount: 8},
+			},
+		},
+		{
+			// Complex but stable
+			FilePath: "core/legacy.go", CyclomaticComplexity: 40, TechnicalDebt: 20,
+			History: &parser.GitHistory{CommitCount: 1},
+		},
+		{
+			// Changes often but simple
+			FilePath: "config/version.go", CyclomaticComplexity: 4, TechnicalDebt: 0,
+			History: &parser.GitHistory{CommitCount: 20},
+			Classes: []parser.ClassInfo{{
+				Name:    "Version",
+				Methods: []parser.FunctionInfo{{Name: "String", LineStart: 5, Complexity: 1, ChangeCount: 2}},
+			}},
+		},
+		{FilePath: "untracked.go", CyclomaticComplexity: 50},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/test/project")
+
+	files := analysis.FileHotspots
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 file hotspots, got %d: %+v", len(files), files)
+	}
+	if files[0].FilePath != "core/engine.go" || math.Abs(files[0].Score-100) > 0.001 {
+		t.Errorf("Expected core/engine.go to score 100, got %+v", files[0])
+	}
+	if files[0].Churn != 400 {
+		t.Errorf("Expected churn 400, got %d", files[0].Churn)
+	}
+	// Busy but simple ((4/40 + 0) / 2 × 20/20) and complex but stable ((1 + 1) / 2 × 1/20) both score 5
+	for _, hotspot := range files[1:] {
+		if math.Abs(hotspot.Score-5) > 0.001 {
+			t.Errorf("Expected %s to score 5, got %.3f", hotspot.FilePath, hotspot.Score)
+		}
+	}
+
+	functions := analysis.FunctionHotspots
+	if len(functions) != 3 {
+		t.Fatalf("Expected 3 function hotspots, got %d", len(functions))
+	}
+	if functions[0].Function != "Run" || functions[0].Line != 10 || math.Abs(functions[0].Score-100) > 0.001 {
+		t.Errorf("Expected Run to be the top function hotspot, got %+v", functions[0])
+	}
+	if functions[2].Function != "Version.String" {
+		t.Errorf("Expected Version.String last, got %+v", functions[2])
+	}
+}
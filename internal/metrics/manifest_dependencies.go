@@ -0,0 +1,393 @@
+package metrics
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/manifest"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// pythonImportNames maps distributions whose import name differs from the
+// distribution name, keyed by normalized distribution name
+var pythonImportNames = map[string]string{
+	"beautifulsoup4":           "bs4",
+	"pyyaml":                   "yaml",
+	"pillow":                   "PIL",
+	"scikit-learn":             "sklearn",
+	"scikit-image":             "skimage",
+	"python-dateutil":          "dateutil",
+	"python-dotenv":            "dotenv",
+	"python-multipart":         "multipart",
+	"opencv-python":            "cv2",
+	"opencv-python-headless":   "cv2",
+	"protobuf":                 "google.protobuf",
+	"attrs":                    "attr",
+	"pyjwt":                    "jwt",
+	"psycopg2-binary":          "psycopg2",
+	"psycopg-binary":           "psycopg",
+	"djangorestframework":      "rest_framework",
+	"pyserial":                 "serial",
+	"pycryptodome":             "Crypto",
+	"google-api-python-client": "googleapiclient",
+	"msgpack-python":           "msgpack",
+	"pymupdf":                  "fitz",
+	"faiss-cpu":                "faiss",
+}
+
+// pythonStdlib lists the top-level modules of the Python standard library, as
+// reported by sys.stdlib_module_names in Python 3.11, which still includes the
+// modules later versions removed
+var pythonStdlib = toSet(
+	"__future__", "_abc", "_aix_support", "_ast", "_asyncio", "_bisect", "_blake2", "_bootsubprocess",
+	"_bz2", "_codecs", "_codecs_cn", "_codecs_hk", "_codecs_iso2022", "_codecs_jp", "_codecs_kr",
+	"_codecs_tw", "_collections", "_collections_abc", "_compat_pickle", "_compression",
+	"_contextvars", "_crypt", "_csv", "_ctypes", "_curses", "_curses_panel", "_datetime", "_dbm",
+	"_decimal", "_elementtree", "_frozen_importlib", "_frozen_importlib_external", "_functools",
+	"_gdbm", "_hashlib", "_heapq", "_imp", "_io", "_json", "_locale", "_lsprof", "_lzma",
+	"_markupbase", "_md5", "_msi", "_multibytecodec", "_multiprocessing", "_opcode", "_operator",
+	"_osx_support", "_overlapped", "_pickle", "_posixshmem", "_posixsubprocess", "_py_abc",
+	"_pydecimal", "_pyio", "_queue", "_random", "_scproxy", "_sha1", "_sha256", "_sha3", "_sha512",
+	"_signal", "_sitebuiltins", "_socket", "_sqlite3", "_sre", "_ssl", "_stat", "_statistics",
+	"_string", "_strptime", "_struct", "_symtable", "_thread", "_threading_local", "_tkinter",
+	"_tokenize", "_tracemalloc", "_typing", "_uuid", "_warnings", "_weakref", "_weakrefset",
+	"_winapi", "_zoneinfo", "abc", "aifc", "antigravity", "argparse", "array", "ast", "asynchat",
+	"asyncio", "asyncore", "atexit", "audioop", "base64", "bdb", "binascii", "bisect", "builtins",
+	"bz2", "cProfile", "calendar", "cgi", "cgitb", "chunk", "cmath", "cmd", "code", "codecs",
+	"codeop", "collections", "colorsys", "compileall", "concurrent", "configparser", "contextlib",
+	"contextvars", "copy", "copyreg", "crypt", "csv", "ctypes", "curses", "dataclasses", "datetime",
+	"dbm", "decimal", "difflib", "dis", "distutils", "doctest", "email", "encodings", "ensurepip",
+	"enum", "errno", "faulthandler", "fcntl", "filecmp", "fileinput", "fnmatch", "fractions",
+	"ftplib", "functools", "gc", "genericpath", "getopt", "getpass", "gettext", "glob", "graphlib",
+	"grp", "gzip", "hashlib", "heapq", "hmac", "html", "http", "idlelib", "imaplib", "imghdr", "imp",
+	"importlib", "inspect", "io", "ipaddress", "itertools", "json", "keyword", "lib2to3", "linecache",
+	"locale", "logging", "lzma", "mailbox", "mailcap", "marshal", "math", "mimetypes", "mmap",
+	"modulefinder", "msilib", "msvcrt", "multiprocessing", "netrc", "nis", "nntplib", "nt", "ntpath",
+	"nturl2path", "numbers", "opcode", "operator", "optparse", "os", "ossaudiodev", "pathlib", "pdb",
+	"pickle", "pickletools", "pipes", "pkgutil", "platform", "plistlib", "poplib", "posix",
+	"posixpath", "pprint", "profile", "pstats", "pty", "pwd", "py_compile", "pyclbr", "pydoc",
+	"pydoc_data", "pyexpat", "queue", "quopri", "random", "re", "readline", "reprlib", "resource",
+	"rlcompleter", "runpy", "sched", "secrets", "select", "selectors", "shelve", "shlex", "shutil",
+	"signal", "site", "smtpd", "smtplib", "sndhdr", "socket", "socketserver", "spwd", "sqlite3",
+	"sre_compile", "sre_constants", "sre_parse", "ssl", "stat", "statistics", "string", "stringprep",
+	"struct", "subprocess", "sunau", "symtable", "sys", "sysconfig", "syslog", "tabnanny", "tarfile",
+	"telnetlib", "tempfile", "termios", "textwrap", "this", "threading", "time", "timeit", "tkinter",
+	"token", "tokenize", "tomllib", "trace", "traceback", "tracemalloc", "tty", "turtle",
+	"turtledemo", "types", "typing", "unicodedata", "unittest", "urllib", "uu", "uuid", "venv",
+	"warnings", "wave", "weakref", "webbrowser", "winreg", "winsound", "wsgiref", "xdrlib", "xml",
+	"xmlrpc", "zipapp", "zipfile", "zipimport", "zlib", "zoneinfo",
+)
+
+// nodeBuiltins lists the modules built into Node.js
+var nodeBuiltins = toSet(
+	"assert", "async_hooks", "buffer", "child_process", "cluster", "console", "constants",
+	"crypto", "dgram", "dns", "events", "fs", "http", "http2", "https", "inspector", "module",
+	"net", "os", "path", "perf_hooks", "process", "punycode", "querystring", "readline", "repl",
+	"stream", "string_decoder", "timers", "tls", "tty", "url", "util", "v8", "vm", "worker_threads",
+	"zlib",
+)
+
+// declaredModule is a directory with one or more manifests of an ecosystem,
+// together with what its files import
+type declaredModule struct {
+	dir          string
+	ecosystem    string
+	module       string
+	manifests    []string
+	requirements []manifest.Requirement
+	index        map[string]int  // import key -> requirement
+	local        map[string]bool // Python names that refer to the module's own code
+	used         map[int]bool
+	undeclared   map[string]bool
+}
+
+// CheckModuleDependencies compares the dependencies declared in manifests with
+// the imports of the files each manifest covers, which are those in its
+// directory that have no closer manifest of the same ecosystem. It records
+// declared versions on the files' dependencies and returns, per module, the
+// dependencies declared but never imported and the packages imported without
+// being declared. Dev, optional and indirect requirements are not reported as
+// unused.
+func CheckModuleDependencies(manifests []*manifest.Manifest, results []*parser.AnalysisResult) []ModuleDependencies {
+	modules := make(map[string]*declaredModule)
+	for _, m := range manifests {
+		key := m.Dir() + "\x00" + m.Ecosystem
+		module := modules[key]
+		if module == nil {
+			module = &declaredModule{
+				dir:        m.Dir(),
+				ecosystem:  m.Ecosystem,
+				index:      make(map[string]int),
+				local:      make(map[string]bool),
+				used:       make(map[int]bool),
+				undeclared: make(map[string]bool),
+			}
+			modules[key] = module
+		}
+		if module.module == "" {
+			module.module = m.Module
+		}
+		module.manifests = append(module.manifests, m.Path)
+		for _, req := range m.Requirements {
+			key := module.requirementKey(req.Name)
+			if _, exists := module.index[key]; exists {
+				continue
+			}
+			module.index[key] = len(module.requirements)
+			module.requirements = append(module.requirements, req)
+			if alias, ok := pythonImportNames[key]; ok && module.ecosystem == manifest.EcosystemPython {
+				module.index[normalizePythonName(alias)] = module.index[key]
+			}
+		}
+	}
+	if len(modules) == 0 {
+		return []ModuleDependencies{}
+	}
+
+	nearest := func(result *parser.AnalysisResult) *declaredModule {
+		ecosystem := importEcosystem(result.Language)
+		dir := filepath.Dir(result.FilePath)
+		var best *declaredModule
+		for _, module := range modules {
+			if module.ecosystem != ecosystem || !isWithin(dir, module.dir) {
+				continue
+			}
+			if best == nil || len(module.dir) > len(best.dir) {
+				best = module
+			}
+		}
+		return best
+	}
+
+	// Top-level Python modules and packages of the project, at the module root
+	// or in a source root below it, are not dependencies
+	for _, result := range results {
+		if result.Language != "Python" {
+			continue
+		}
+		module := nearest(result)
+		if module == nil {
+			continue
+		}
+		rel, err := filepath.Rel(module.dir, result.FilePath)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, root := range sourceRoots {
+			within, ok := rel, root == "."
+			if !ok {
+				within, ok = strings.CutPrefix(rel, root+"/")
+			}
+			if ok {
+				top, _, _ := strings.Cut(within, "/")
+				module.local[strings.TrimSuffix(top, ".py")] = true
+			}
+		}
+	}
+
+	for _, result := range results {
+		module := nearest(result)
+		if module == nil {
+			continue
+		}
+
+		versions := make(map[string]string)
+		for _, imp := range result.Imports {
+			name, external := module.importedPackage(imp)
+			if !external {
+				continue
+			}
+			if i, ok := module.lookup(imp, name); ok {
+				module.used[i] = true
+				versions[imp] = module.requirements[i].Version
+			} else {
+				module.undeclared[name] = true
+			}
+		}
+		for i, dep := range result.Dependencies {
+			if version, ok := versions[dep.Name]; ok {
+				result.Dependencies[i].Version = version
+			}
+		}
+	}
+
+	checked := make([]ModuleDependencies, 0, len(modules))
+	for _, module := range modules {
+		deps := ModuleDependencies{
+			Path:       module.dir,
+			Ecosystem:  module.ecosystem,
+			Module:     module.module,
+			Manifests:  module.manifests,
+			Declared:   len(module.requirements),
+			Unused:     []string{},
+			Undeclared: sortedNames(module.undeclared),
+		}
+		for i, req := range module.requirements {
+			if module.used[i] || req.Dev || req.Indirect || strings.HasPrefix(req.Name, "@types/") {
+				continue
+			}
+			deps.Unused = append(deps.Unused, req.Name)
+		}
+		sort.Strings(deps.Manifests)
+		sort.Strings(deps.Unused)
+		checked = append(checked, deps)
+	}
+	sort.Slice(checked, func(i, j int) bool {
+		if checked[i].Path != checked[j].Path {
+			return checked[i].Path < checked[j].Path
+		}
+		return checked[i].Ecosystem < checked[j].Ecosystem
+	})
+
+	return checked
+}
+
+// SetModules records the per-module dependency checks on the graph, along with
+// the project-wide lists of unused and undeclared dependencies
+func (g *DependencyGraph) SetModules(modules []ModuleDependencies) {
+	unused, undeclared := make(map[string]bool), make(map[string]bool)
+	for _, module := range modules {
+		for _, name := range module.Unused {
+			unused[name] = true
+		}
+		for _, name := range module.Undeclared {
+			undeclared[name] = true
+		}
+	}
+	g.Modules = modules
+	g.UnusedDependencies = sortedNames(unused)
+	g.UndeclaredDependencies = sortedNames(undeclared)
+}
+
+// importedPackage returns the package an import belongs to, and false for the
+// standard library and the module's own code
+func (m *declaredModule) importedPackage(imp string) (string, bool) {
+	switch m.ecosystem {
+	case manifest.EcosystemGo:
+		first, _, _ := strings.Cut(imp, "/")
+		if !strings.Contains(first, ".") {
+			return "", false
+		}
+		if m.module != "" && (imp == m.module || strings.HasPrefix(imp, m.module+"/")) {
+			return "", false
+		}
+		// Report the repository rather than each of its packages
+		parts := strings.Split(imp, "/")
+		switch first {
+		case "github.com", "gitlab.com", "bitbucket.org":
+			if len(parts) > 3 {
+				return strings.Join(parts[:3], "/"), true
+			}
+		}
+		return imp, true
+
+	case manifest.EcosystemPython:
+		if strings.HasPrefix(imp, ".") {
+			return "", false
+		}
+		top, _, _ := strings.Cut(imp, ".")
+		if top == "" || pythonStdlib[top] || m.local[top] {
+			return "", false
+		}
+		return top, true
+
+	case manifest.EcosystemNPM:
+		if imp == "" || strings.ContainsAny(imp[:1], "./~#") || strings.HasPrefix(imp, "@/") || strings.Contains(imp, ":") {
+			return "", false
+		}
+		parts := strings.Split(imp, "/")
+		if strings.HasPrefix(imp, "@") && len(parts) > 1 {
+			return parts[0] + "/" + parts[1], true
+		}
+		if nodeBuiltins[parts[0]] {
+			return "", false
+		}
+		return parts[0], true
+	}
+	return "", false
+}
+
+// lookup finds the requirement that provides an import of package name
+func (m *declaredModule) lookup(imp, name string) (int, bool) {
+	switch m.ecosystem {
+	case manifest.EcosystemGo:
+		// The longest required module path containing the package
+		best, found := 0, false
+		for i, req := range m.requirements {
+			if imp != req.Name && !strings.HasPrefix(imp, req.Name+"/") {
+				continue
+			}
+			if !found || len(req.Name) > len(m.requirements[best].Name) {
+				best, found = i, true
+			}
+		}
+		return best, found
+
+	case manifest.EcosystemPython:
+		// Namespace packages such as google.cloud.storage are distributed as
+		// google-cloud-storage, so try the longest dotted prefix first
+		parts := strings.Split(imp, ".")
+		for n := len(parts); n >= 1; n-- {
+			if i, ok := m.index[normalizePythonName(strings.Join(parts[:n], "."))]; ok {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	i, ok := m.index[name]
+	return i, ok
+}
+
+// requirementKey returns the name a requirement is indexed under
+func (m *declaredModule) requirementKey(name string) string {
+	if m.ecosystem == manifest.EcosystemPython {
+		return normalizePythonName(name)
+	}
+	return name
+}
+
+// normalizePythonName normalizes a distribution or import name as PEP 503 does,
+// so that PyYAML, pyyaml and py_yaml compare equal
+func normalizePythonName(name string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// importEcosystem returns the manifest ecosystem that declares a language's imports
+func importEcosystem(language string) string {
+	switch language {
+	case "Go":
+		return manifest.EcosystemGo
+	case "Python":
+		return manifest.EcosystemPython
+	case "JavaScript", "TypeScript", "Vue", "Svelte", "HTML":
+		return manifest.EcosystemNPM
+	}
+	return ""
+}
+
+// isWithin reports whether dir is root or one of its subdirectories
+func isWithin(dir, root string) bool {
+	return dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))
+}
+
+// sortedNames returns the names in a set in order
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toSet builds a lookup set from names
+func toSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/manifest"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCheckModuleDependencies(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	at := func(rel string) string { return filepath.Join(root, filepath.FromSlash(rel)) }
+
+	manifests := []*manifest.Manifest{
+		{
+			Path:      at("go.mod"),
+			Ecosystem: manifest.EcosystemGo,
+			Module:    "example.com/app",
+			Requirements: []manifest.Requirement{
+				{Name: "github.com/spf13/cobra", Version: "v1.8.0"},
+				{Name: "golang.org/x/text", Version: "v0.14.0"},
+				{Name: "github.com/inconshreveable/mousetrap", Version: "v1.1.0", Indirect: true},
+			},
+		},
+		{
+			Path:      at("svc/requirements.txt"),
+			Ecosystem: manifest.EcosystemPython,
+			Requirements: []manifest.Requirement{
+				{Name: "PyYAML", Version: "==6.0"},
+				{Name: "google-cloud-storage"},
+				{Name: "flask"},
+				{Name: "pytest", Dev: true},
+			},
+		},
+	}
+
+	goFile := &parser.AnalysisResult{
+		FilePath: at("cmd/main.go"),
+		Language: "Go",
+		Imports:  []string{"fmt", "example.com/app/internal/core", "github.com/spf13/cobra", "github.com/pkg/errors/sub"},
+		Dependencies: []parser.Dependency{
+			{Name: "fmt"},
+			{Name: "github.com/spf13/cobra"},
+		},
+	}
+	pyFile := &parser.AnalysisResult{
+		FilePath: at("svc/app/main.py"),
+		Language: "Python",
+		Imports:  []string{"os.path", "yaml", "google.cloud.storage.Client", "app.models", "..util", "numpy"},
+	}
+	pyModels := &parser.AnalysisResult{FilePath: at("svc/app/models.py"), Language: "Python"}
+
+	modules := CheckModuleDependencies(manifests, []*parser.AnalysisResult{goFile, pyFile, pyModels})
+	if len(modules) != 2 {
+		t.Fatalf("Expected 2 modules, got %d", len(modules))
+	}
+
+	goModule, pyModule := modules[0], modules[1]
+	if goModule.Path != root || goModule.Ecosystem != manifest.EcosystemGo || goModule.Declared != 3 {
+		t.Errorf("Unexpected Go module %+v", goModule)
+	}
+	if !reflect.DeepEqual(goModule.Unused, []string{"golang.org/x/text"}) {
+		t.Errorf("Expected golang.org/x/text unused and the indirect requirement ignored, got %v", goModule.Unused)
+	}
+	if !reflect.DeepEqual(goModule.Undeclared, []string{"github.com/pkg/errors"}) {
+		t.Errorf("Expected the undeclared repository github.com/pkg/errors, got %v", goModule.Undeclared)
+	}
+	if goFile.Dependencies[1].Version != "v1.8.0" || goFile.Dependencies[0].Version != "" {
+		t.Errorf("Expected the cobra version on its dependency only, got %+v", goFile.Dependencies)
+	}
+
+	if pyModule.Path != at("svc") || pyModule.Ecosystem != manifest.EcosystemPython {
+		t.Errorf("Unexpected Python module %+v", pyModule)
+	}
+	if !reflect.DeepEqual(pyModule.Unused, []string{"flask"}) {
+		t.Errorf("Expected flask unused and the dev requirement ignored, got %v", pyModule.Unused)
+	}
+	if !reflect.DeepEqual(pyModule.Undeclared, []string{"numpy"}) {
+		t.Errorf("Expected numpy undeclared, got %v", pyModule.Undeclared)
+	}
+
+	var graph DependencyGraph
+	graph.SetModules(modules)
+	if !reflect.DeepEqual(graph.UnusedDependencies, []string{"flask", "golang.org/x/text"}) {
+		t.Errorf("Unexpected project unused dependencies %v", graph.UnusedDependencies)
+	}
+	if !reflect.DeepEqual(graph.UndeclaredDependencies, []string{"github.com/pkg/errors", "numpy"}) {
+		t.Errorf("Unexpected project undeclared dependencies %v", graph.UndeclaredDependencies)
+	}
+}
+
+func TestCheckModuleDependencies_NPM(t *testing.T) {
+	manifests := []*manifest.Manifest{{
+		Path:      filepath.FromSlash("/web/package.json"),
+		Ecosystem: manifest.EcosystemNPM,
+		Requirements: []manifest.Requirement{
+			{Name: "react"},
+			{Name: "@tanstack/query"},
+			{Name: "lodash"},
+			{Name: "@types/react", Dev: true},
+		},
+	}}
+	result := &parser.AnalysisResult{
+		FilePath: filepath.FromSlash("/web/src/App.vue"),
+		Language: "Vue",
+		Imports:  []string{"react", "@tanstack/query/core", "./util", "@/store", "node:fs", "path", "axios/lib"},
+	}
+
+	modules := CheckModuleDependencies(manifests, []*parser.AnalysisResult{result})
+	if len(modules) != 1 {
+		t.Fatalf("Expected 1 module, got %d", len(modules))
+	}
+	if !reflect.DeepEqual(modules[0].Unused, []string{"lodash"}) {
+		t.Errorf("Expected lodash unused, got %v", modules[0].Unused)
+	}
+	if !reflect.DeepEqual(modules[0].Undeclared, []string{"axios"}) {
+		t.Errorf("Expected axios undeclared, got %v", modules[0].Undeclared)
+	}
+}
+
+func TestCheckModuleDependencies_PythonStdlib(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	manifests := []*manifest.Manifest{{
+		Path:         filepath.Join(root, "requirements.txt"),
+		Ecosystem:    manifest.EcosystemPython,
+		Requirements: []manifest.Requirement{{Name: "requests"}},
+	}}
+	pyFile := &parser.AnalysisResult{
+		FilePath: filepath.Join(root, "cli.py"),
+		Language: "Python",
+		Imports: []string{"getopt.getopt", "optparse.OptionParser", "binascii.hexlify", "cProfile", "pwd.getpwnam",
+			"fcntl", "curses.wrapper", "posixpath.join", "marshal", "_thread", "requests", "rich.console"},
+	}
+
+	modules := CheckModuleDependencies(manifests, []*parser.AnalysisResult{pyFile})
+	if len(modules) != 1 {
+		t.Fatalf("Expected 1 module, got %d", len(modules))
+	}
+	if !reflect.DeepEqual(modules[0].Undeclared, []string{"rich"}) {
+		t.Errorf("Expected only rich undeclared and the standard library left out, got %v", modules[0].Undeclared)
+	}
+}
+
+func TestCheckModuleDependencies_PythonLocalPackages(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	manifests := []*manifest.Manifest{{
+		Path:         filepath.Join(root, "requirements.txt"),
+		Ecosystem:    manifest.EcosystemPython,
+		Requirements: []manifest.Requirement{{Name: "requests"}, {Name: "rich"}},
+	}}
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: filepath.Join(root, "src", "app", "main.py"),
+			Language: "Python",
+			Imports:  []string{"app.config", "api.http.requests", "requests", "rich.console"},
+		},
+		{FilePath: filepath.Join(root, "src", "app", "config.py"), Language: "Python"},
+		// Neither http nor requests are top-level packages
+		{FilePath: filepath.Join(root, "api", "http", "requests.py"), Language: "Python"},
+		{FilePath: filepath.Join(root, "rich.py"), Language: "Python"},
+	}
+
+	modules := CheckModuleDependencies(manifests, results)
+	if len(modules) != 1 {
+		t.Fatalf("Expected 1 module, got %d", len(modules))
+	}
+	if !reflect.DeepEqual(modules[0].Unused, []string{"rich"}) {
+		t.Errorf("Expected only rich, shadowed by a top-level module, to be unused, got %v", modules[0].Unused)
+	}
+	if len(modules[0].Undeclared) != 0 {
+		t.Errorf("Expected the project's own packages not to be undeclared, got %v", modules[0].Undeclared)
+	}
+}
@@ -0,0 +1,282 @@
+package metrics
+
+import (
+	"math"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/callgraph"
+	"github.com/tito-sala/codebasereaderv2/internal/manifest"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Zones for packages far from the main sequence
+const (
+	ZonePain        = "pain"        // stable and concrete: many dependents, hard to change
+	ZoneUselessness = "uselessness" // unstable and abstract: abstractions nobody depends on
+)
+
+// zoneDistance is the distance from the main sequence beyond which a package is
+// placed in a zone
+const zoneDistance = 0.5
+
+// analyzePackageGraph resolves imports between the project's packages and
+// computes package coupling metrics and dependency cycles from them
+func (a *Aggregator) analyzePackageGraph(analysis *EnhancedProjectAnalysis) {
+	fileResults, ok := analysis.FileResults.([]*parser.AnalysisResult)
+	if !ok {
+		return
+	}
+
+	imports := packageImports(analysis.RootPath, fileResults)
+	a.calculatePackageMetrics(analysis, fileResults, imports)
+
+	cycles := findDependencyCycles(imports)
+	analysis.DependencyGraph.Cycles = cycles
+	analysis.DependencyGraph.CircularDependencies = [][]string{}
+	for _, cycle := range cycles {
+		analysis.DependencyGraph.CircularDependencies = append(analysis.DependencyGraph.CircularDependencies, cycle.Cycles...)
+	}
+}
+
+// packageImports resolves the imports of each file to the analyzed package
+// directories they refer to, and returns the import sites for each importing
+// and imported package. Imports of other projects are left out.
+func packageImports(rootPath string, results []*parser.AnalysisResult) map[string]map[string][]ImportSite {
+	resolver := newPackageResolver(rootPath, results)
+	imports := make(map[string]map[string][]ImportSite)
+	for _, result := range results {
+		dir := filepath.Dir(result.FilePath)
+		for _, dep := range result.Dependencies {
+			target := resolver.resolve(result.FilePath, result.Language, dep)
+			if target == "" || target == dir {
+				continue
+			}
+			if imports[dir] == nil {
+				imports[dir] = make(map[string][]ImportSite)
+			}
+			imports[dir][target] = append(imports[dir][target], ImportSite{
+				From:     dir,
+				To:       target,
+				FilePath: result.FilePath,
+				Line:     dep.Line,
+				Import:   dep.Name,
+			})
+		}
+	}
+	return imports
+}
+
+// PackageDependencies returns, for each analyzed package directory, the
+// package directories of the project it imports, in order
+func PackageDependencies(rootPath string, results []*parser.AnalysisResult) map[string][]string {
+	dependencies := make(map[string][]string)
+	for from, targets := range packageImports(rootPath, results) {
+		for to := range targets {
+			dependencies[from] = append(dependencies[from], to)
+		}
+		sort.Strings(dependencies[from])
+	}
+	return dependencies
+}
+
+// calculatePackageMetrics computes afferent and efferent coupling, instability,
+// abstractness and distance from the main sequence for every package from the
+// resolved imports between packages
+func (a *Aggregator) calculatePackageMetrics(analysis *EnhancedProjectAnalysis, fileResults []*parser.AnalysisResult, imports map[string]map[string][]ImportSite) {
+	packages := make(map[string]*PackageMetrics)
+	for _, result := range fileResults {
+		dir := filepath.Dir(result.FilePath)
+		pkg, exists := packages[dir]
+		if !exists {
+			pkg = &PackageMetrics{Path: dir}
+			packages[dir] = pkg
+		}
+
+		pkg.FileCount++
+		for _, class := range result.Classes {
+			pkg.TypeCount++
+			if class.IsAbstract {
+				pkg.AbstractCount++
+			}
+		}
+	}
+
+	afferent := make(map[string]int)
+	for _, targets := range imports {
+		for target := range targets {
+			afferent[target]++
+		}
+	}
+
+	result := make([]PackageMetrics, 0, len(packages))
+	for dir, pkg := range packages {
+		pkg.Efferent = len(imports[dir])
+		pkg.Afferent = afferent[dir]
+		if coupling := pkg.Afferent + pkg.Efferent; coupling > 0 {
+			pkg.Instability = float64(pkg.Efferent) / float64(coupling)
+		}
+		if pkg.TypeCount > 0 {
+			pkg.Abstractness = float64(pkg.AbstractCount) / float64(pkg.TypeCount)
+		}
+		pkg.Distance = math.Abs(pkg.Abstractness + pkg.Instability - 1)
+
+		// Packages without any coupling are isolated rather than badly placed
+		if pkg.Afferent+pkg.Efferent > 0 && pkg.Distance > zoneDistance {
+			if pkg.Abstractness+pkg.Instability < 1 {
+				pkg.Zone = ZonePain
+			} else {
+				pkg.Zone = ZoneUselessness
+			}
+		}
+		result = append(result, *pkg)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Distance != result[j].Distance {
+			return result[i].Distance > result[j].Distance
+		}
+		return result[i].Path < result[j].Path
+	})
+
+	analysis.DependencyGraph.Packages = result
+}
+
+// packageResolver maps import names to the directories of analyzed packages
+type packageResolver struct {
+	root      string
+	dirs      map[string]string // package directory relative to the root -> directory
+	modules   map[string]string // file path relative to the root, without extension -> directory
+	goModules map[string]string // Go module path -> module directory relative to the root
+}
+
+// sourceRoots are the directories, relative to the root, that absolute Python
+// modules and aliased JavaScript paths are resolved from
+var sourceRoots = []string{".", "src"}
+
+// newPackageResolver indexes the packages and modules of the analyzed files and
+// reads the module paths of the go.mod files above the analyzed Go packages
+func newPackageResolver(rootPath string, results []*parser.AnalysisResult) *packageResolver {
+	r := &packageResolver{
+		root:      rootPath,
+		dirs:      make(map[string]string),
+		modules:   make(map[string]string),
+		goModules: make(map[string]string),
+	}
+	checked := make(map[string]bool)
+	for _, result := range results {
+		rel := r.relative(result.FilePath)
+		dir := filepath.Dir(result.FilePath)
+		r.dirs[path.Dir(rel)] = dir
+		r.modules[strings.TrimSuffix(rel, path.Ext(rel))] = dir
+
+		if result.Language != "Go" {
+			continue
+		}
+		for moduleDir := path.Dir(rel); !checked[moduleDir]; moduleDir = path.Dir(moduleDir) {
+			checked[moduleDir] = true
+			if m, err := manifest.Load(filepath.Join(rootPath, filepath.FromSlash(moduleDir), "go.mod")); err == nil && m.Module != "" {
+				r.goModules[m.Module] = moduleDir
+			}
+			if moduleDir == "." {
+				break
+			}
+		}
+	}
+	return r
+}
+
+// relative returns a slash-separated path relative to the root
+func (r *packageResolver) relative(filePath string) string {
+	if rel, err := filepath.Rel(r.root, filePath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filePath)
+}
+
+// resolve returns the package directory an import of the given file refers to, or
+// an empty string when it is not part of the project. Standard library imports
+// never are. Go imports resolve under the module path of a go.mod file, since
+// the project's own module is classified as external by its host. Relative
+// imports are resolved from the importing file, with Python's dots counting
+// packages up rather than path segments; absolute Python modules and aliased
+// JavaScript paths are looked up from the source roots, and other JavaScript
+// packages are external.
+func (r *packageResolver) resolve(filePath, language string, dep parser.Dependency) string {
+	name := dep.Name
+	if dep.Type == "standard" {
+		return ""
+	}
+
+	switch {
+	case language == "Go":
+		return r.resolveGo(name)
+	case strings.HasPrefix(name, ".") && language == "Python":
+		// "from ..pkg.module import name" is recorded as ..pkg.module.name; the
+		// imported names are dropped up to the package the dots refer to
+		dir := path.Dir(r.relative(filePath))
+		base := callgraph.RelativeModulePath(dir, name[:len(name)-len(strings.TrimLeft(name, "."))])
+		for module := callgraph.RelativeModulePath(dir, name); ; module = path.Dir(module) {
+			if dir := r.lookup(module); dir != "" {
+				return dir
+			}
+			if module == base || module == "." {
+				return ""
+			}
+		}
+	case language == "Python":
+		// "from pkg.module import name" is recorded as pkg.module.name. Names
+		// without dots are classified as external, but may be project modules.
+		for _, root := range sourceRoots {
+			for module := strings.ReplaceAll(name, ".", "/"); module != "." && module != "/"; module = path.Dir(module) {
+				if dir := r.lookup(path.Join(root, module)); dir != "" {
+					return dir
+				}
+			}
+		}
+		return ""
+	case strings.HasPrefix(name, "."):
+		return r.lookup(path.Join(path.Dir(r.relative(filePath)), name))
+	case strings.HasPrefix(name, "@/") || strings.HasPrefix(name, "~/"):
+		for _, root := range sourceRoots {
+			if dir := r.lookup(path.Join(root, name[2:])); dir != "" {
+				return dir
+			}
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// resolveGo returns the package directory of a Go import path under the module
+// path of one of the project's go.mod files. The longest module path wins, so
+// nested modules resolve to their own directories.
+func (r *packageResolver) resolveGo(name string) string {
+	bestModule, target := "", ""
+	for module, moduleDir := range r.goModules {
+		if len(module) <= len(bestModule) {
+			continue
+		}
+		if name == module {
+			bestModule, target = module, moduleDir
+		} else if strings.HasPrefix(name, module+"/") {
+			bestModule, target = module, path.Join(moduleDir, name[len(module)+1:])
+		}
+	}
+	if bestModule == "" {
+		return ""
+	}
+	return r.dirs[target]
+}
+
+// lookup returns the directory of the module or package at a path relative to
+// the root, preferring modules
+func (r *packageResolver) lookup(rel string) string {
+	if dir, exists := r.modules[rel]; exists {
+		return dir
+	}
+	return r.dirs[rel]
+}
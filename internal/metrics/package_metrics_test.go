@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// goModule returns a project root holding a go.mod file for the module path
+func goModule(t *testing.T, module string) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module "+module+"\n\ngo 1.25\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	return root
+}
+
+func TestCalculatePackageMetrics(t *testing.T) {
+	aggregator := NewAggregator()
+	root := goModule(t, "github.com/x/p")
+
+	deps := func(names ...string) []parser.Dependency {
+		var result []parser.Dependency
+		for _, name := range names {
+			result = append(result, parser.Dependency{Name: name, Type: "external"})
+		}
+		return result
+	}
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: root + "/store/store.go",
+			Language: "Go",
+			Classes:  []parser.ClassInfo{{Name: "Repository", IsAbstract: true}, {Name: "Store"}},
+		},
+		{
+			FilePath:     root + "/api/handler.go",
+			Language:     "Go",
+			Classes:      []parser.ClassInfo{{Name: "Handler"}},
+			Dependencies: deps("github.com/x/p/store", "github.com/x/p/util", "github.com/other/lib"),
+		},
+		{
+			FilePath:     root + "/cmd/main.go",
+			Language:     "Go",
+			Dependencies: append(deps("github.com/x/p/api", "github.com/x/p/util"), parser.Dependency{Name: "fmt", Type: "standard"}),
+		},
+		{
+			// Stable and concrete
+			FilePath: root + "/util/strings.go",
+			Language: "Go",
+			Classes:  []parser.ClassInfo{{Name: "Builder"}},
+		},
+		{
+			// Unstable and abstract
+			FilePath:     root + "/ports/ports.go",
+			Language:     "Go",
+			Classes:      []parser.ClassInfo{{Name: "Reader", IsAbstract: true}, {Name: "Writer", IsAbstract: true}},
+			Dependencies: deps("github.com/x/p/util"),
+		},
+		{
+			// from py.models.user import User
+			FilePath:     root + "/py/app/views.py",
+			Language:     "Python",
+			Dependencies: deps("py.models.user.User"),
+		},
+		{
+			FilePath: root + "/py/models/user.py",
+			Language: "Python",
+			Classes:  []parser.ClassInfo{{Name: "User"}},
+		},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, root)
+
+	packages := make(map[string]PackageMetrics)
+	for _, pkg := range analysis.DependencyGraph.Packages {
+		packages[pkg.Path] = pkg
+	}
+	if len(packages) != 7 {
+		t.Fatalf("Expected 7 packages, got %d: %+v", len(packages), analysis.DependencyGraph.Packages)
+	}
+
+	tests := []struct {
+		path         string
+		afferent     int
+		efferent     int
+		instability  float64
+		abstractness float64
+		distance     float64
+		zone         string
+	}{
+		{root + "/store", 1, 0, 0, 0.5, 0.5, ""},
+		{root + "/api", 1, 2, 2.0 / 3, 0, 1.0 / 3, ""},
+		{root + "/cmd", 0, 2, 1, 0, 0, ""},
+		{root + "/util", 3, 0, 0, 0, 1, ZonePain},
+		{root + "/ports", 0, 1, 1, 1, 1, ZoneUselessness},
+		{root + "/py/app", 0, 1, 1, 0, 0, ""},
+		{root + "/py/models", 1, 0, 0, 0, 1, ZonePain},
+	}
+
+	for _, tt := range tests {
+		pkg, exists := packages[tt.path]
+		if !exists {
+			t.Errorf("Expected package %s", tt.path)
+			continue
+		}
+		if pkg.Afferent != tt.afferent || pkg.Efferent != tt.efferent {
+			t.Errorf("%s: expected Ca=%d Ce=%d, got Ca=%d Ce=%d", tt.path, tt.afferent, tt.efferent, pkg.Afferent, pkg.Efferent)
+		}
+		if math.Abs(pkg.Instability-tt.instability) > 0.001 || math.Abs(pkg.Abstractness-tt.abstractness) > 0.001 {
+			t.Errorf("%s: expected I=%.2f A=%.2f, got I=%.2f A=%.2f", tt.path, tt.instability, tt.abstractness, pkg.Instability, pkg.Abstractness)
+		}
+		if math.Abs(pkg.Distance-tt.distance) > 0.001 || pkg.Zone != tt.zone {
+			t.Errorf("%s: expected D=%.2f zone %q, got D=%.2f zone %q", tt.path, tt.distance, tt.zone, pkg.Distance, pkg.Zone)
+		}
+	}
+
+	// Furthest from the main sequence first
+	if first := analysis.DependencyGraph.Packages[0]; first.Distance != 1 || first.Path != root+"/ports" {
+		t.Errorf("Expected /p/ports first, got %+v", first)
+	}
+
+	dependencies := PackageDependencies(root, results)
+	if api := dependencies[root+"/api"]; len(api) != 2 || api[0] != root+"/store" || api[1] != root+"/util" {
+		t.Errorf("Expected /p/api to depend on /p/store and /p/util, got %v", api)
+	}
+	if len(dependencies[root+"/util"]) != 0 {
+		t.Errorf("Expected /p/util to depend on nothing, got %v", dependencies[root+"/util"])
+	}
+}
+
+func TestCalculatePackageMetrics_PythonRelativeImports(t *testing.T) {
+	aggregator := NewAggregator()
+
+	deps := func(names ...string) []parser.Dependency {
+		var result []parser.Dependency
+		for _, name := range names {
+			result = append(result, parser.Dependency{Name: name, Type: "internal"})
+		}
+		return result
+	}
+
+	results := []*parser.AnalysisResult{
+		{
+			// from ..services.auth import login
+			FilePath:     "/p/app/models/user.py",
+			Language:     "Python",
+			Classes:      []parser.ClassInfo{{Name: "User"}},
+			Dependencies: deps("..services.auth.login"),
+		},
+		{
+			// from ..models.user import User; from .helpers import hash_password
+			FilePath:     "/p/app/services/auth.py",
+			Language:     "Python",
+			Dependencies: deps("..models.user.User", ".helpers.hash_password"),
+		},
+		{
+			FilePath: "/p/app/services/helpers.py",
+			Language: "Python",
+		},
+		{
+			// from . import services
+			FilePath:     "/p/app/main.py",
+			Language:     "Python",
+			Dependencies: deps(".services"),
+		},
+	}
+
+	analysis := aggregator.AggregateProjectMetrics(results, "/p")
+
+	packages := make(map[string]PackageMetrics)
+	for _, pkg := range analysis.DependencyGraph.Packages {
+		packages[pkg.Path] = pkg
+	}
+
+	tests := []struct {
+		path     string
+		afferent int
+		efferent int
+	}{
+		{"/p/app", 0, 1},
+		{"/p/app/models", 1, 1},
+		{"/p/app/services", 2, 1},
+	}
+	for _, tt := range tests {
+		pkg := packages[tt.path]
+		if pkg.Afferent != tt.afferent || pkg.Efferent != tt.efferent {
+			t.Errorf("%s: expected Ca=%d Ce=%d, got Ca=%d Ce=%d", tt.path, tt.afferent, tt.efferent, pkg.Afferent, pkg.Efferent)
+		}
+	}
+}
+
+func TestPackageDependencies_OtherProjects(t *testing.T) {
+	root := goModule(t, "github.com/x/p")
+
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: root + "/cmd/main.go",
+			Language: "Go",
+			Dependencies: []parser.Dependency{
+				{Name: "errors", Type: "standard"},
+				{Name: "github.com/pkg/errors", Type: "external"},
+				{Name: "github.com/x/p/internal/errors", Type: "external"},
+			},
+		},
+		{FilePath: root + "/internal/errors/errors.go", Language: "Go"},
+		{FilePath: root + "/errors/wrap.go", Language: "Go"},
+		{
+			FilePath:     root + "/api/client.py",
+			Language:     "Python",
+			Dependencies: []parser.Dependency{{Name: "requests", Type: "external"}},
+		},
+		{FilePath: root + "/api/http/requests.py", Language: "Python"},
+		{
+			FilePath:     root + "/web/app.js",
+			Language:     "JavaScript",
+			Dependencies: []parser.Dependency{{Name: "lodash/fp", Type: "external"}},
+		},
+		{FilePath: root + "/web/lodash/fp.js", Language: "JavaScript"},
+	}
+
+	dependencies := PackageDependencies(root, results)
+	expected := map[string][]string{root + "/cmd": {root + "/internal/errors"}}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("Expected only the module's own package to resolve, got %v", dependencies)
+	}
+}
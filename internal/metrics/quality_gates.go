@@ -0,0 +1,187 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Names of the quality gates
+const (
+	GateFunctionComplexity   = "function_complexity"
+	GateMaintainabilityIndex = "maintainability_index"
+	GateFileTechnicalDebt    = "file_technical_debt"
+	GateGrade                = "grade"
+	GateCircularDependencies = "circular_dependencies"
+	GateDocumentationRatio   = "documentation_ratio"
+)
+
+// grades from best to worst, as assigned by Calculator.CalculateQualityScore
+var grades = []string{"A", "B", "C", "D", "F"}
+
+// QualityGates are the thresholds a project must meet. Gates left at their
+// zero value are not checked, except MaxCircularDependencies, where zero is the
+// usual threshold and an unset value is nil.
+type QualityGates struct {
+	MaxFunctionComplexity   int     `json:"max_function_complexity,omitempty"`   // cyclomatic complexity of any function
+	MinMaintainabilityIndex float64 `json:"min_maintainability_index,omitempty"` // project average
+	MaxFileTechnicalDebt    float64 `json:"max_file_technical_debt,omitempty"`   // technical debt of any file
+	MinGrade                string  `json:"min_grade,omitempty"`                 // worst acceptable quality grade
+	MaxCircularDependencies *int    `json:"max_circular_dependencies,omitempty"` // groups of packages that depend on each other
+	MinDocumentationRatio   float64 `json:"min_documentation_ratio,omitempty"`   // percent of documented functions
+}
+
+// Validate reports thresholds that are out of range
+func (g QualityGates) Validate() error {
+	if g.MaxFunctionComplexity < 0 || g.MaxFileTechnicalDebt < 0 {
+		return fmt.Errorf("quality gate maximums must not be negative")
+	}
+	if g.MaxCircularDependencies != nil && *g.MaxCircularDependencies < 0 {
+		return fmt.Errorf("max_circular_dependencies must not be negative")
+	}
+	if g.MinGrade != "" && gradeRank(g.MinGrade) < 0 {
+		return fmt.Errorf("invalid min_grade %q, must be one of: %s", g.MinGrade, strings.Join(grades, ", "))
+	}
+	if g.MinDocumentationRatio > 100 || g.MinMaintainabilityIndex > 100 {
+		return fmt.Errorf("quality gate minimums must not exceed 100")
+	}
+	return nil
+}
+
+// EvaluateQualityGates checks the analysis against each configured gate, in a
+// fixed order. Gates on individual functions and files list the offenders,
+// worst first.
+func EvaluateQualityGates(gates QualityGates, analysis *EnhancedProjectAnalysis) []GateResult {
+	results := []GateResult{}
+	fileResults, _ := analysis.FileResults.([]*parser.AnalysisResult)
+	project := analysis.ProjectMetrics
+
+	if gates.MaxFunctionComplexity > 0 {
+		gate := GateResult{
+			Gate:      GateFunctionComplexity,
+			Threshold: fmt.Sprintf("<= %d", gates.MaxFunctionComplexity),
+		}
+		worst := 0
+		check := func(result *parser.AnalysisResult, name string, fn parser.FunctionInfo) {
+			worst = max(worst, fn.CyclomaticComplexity)
+			if fn.CyclomaticComplexity > gates.MaxFunctionComplexity {
+				gate.Failures = append(gate.Failures, GateFailure{
+					FilePath: result.FilePath,
+					Symbol:   name,
+					Line:     fn.LineStart,
+					Value:    float64(fn.CyclomaticComplexity),
+				})
+			}
+		}
+		for _, result := range fileResults {
+			for _, fn := range result.Functions {
+				name := methodName(fn.Name)
+				if fn.Receiver != "" {
+					name = fn.Receiver + "." + name
+				}
+				check(result, name, fn)
+			}
+			for _, class := range result.Classes {
+				for _, method := range class.Methods {
+					check(result, class.Name+"."+methodName(method.Name), method)
+				}
+			}
+		}
+		gate.Actual = fmt.Sprintf("max %d", worst)
+		gate.Value = float64(worst)
+		results = append(results, gate.finish())
+	}
+
+	if gates.MinMaintainabilityIndex > 0 {
+		results = append(results, GateResult{
+			Gate:      GateMaintainabilityIndex,
+			Threshold: fmt.Sprintf(">= %.1f", gates.MinMaintainabilityIndex),
+			Actual:    fmt.Sprintf("%.1f", project.MaintainabilityIndex),
+			Value:     project.MaintainabilityIndex,
+			Passed:    project.MaintainabilityIndex >= gates.MinMaintainabilityIndex,
+		})
+	}
+
+	if gates.MaxFileTechnicalDebt > 0 {
+		gate := GateResult{
+			Gate:      GateFileTechnicalDebt,
+			Threshold: fmt.Sprintf("<= %.1f", gates.MaxFileTechnicalDebt),
+		}
+		worst := 0.0
+		for _, result := range fileResults {
+			worst = max(worst, result.TechnicalDebt)
+			if result.TechnicalDebt > gates.MaxFileTechnicalDebt {
+				gate.Failures = append(gate.Failures, GateFailure{FilePath: result.FilePath, Value: result.TechnicalDebt})
+			}
+		}
+		gate.Actual = fmt.Sprintf("max %.1f", worst)
+		gate.Value = worst
+		results = append(results, gate.finish())
+	}
+
+	if gates.MinGrade != "" {
+		grade := analysis.QualityScore.Grade
+		results = append(results, GateResult{
+			Gate:      GateGrade,
+			Threshold: gates.MinGrade + " or better",
+			Actual:    grade,
+			Value:     float64(gradeRank(grade)),
+			Passed:    gradeRank(grade) >= 0 && gradeRank(grade) <= gradeRank(gates.MinGrade),
+		})
+	}
+
+	if gates.MaxCircularDependencies != nil {
+		cycles := len(analysis.DependencyGraph.Cycles)
+		results = append(results, GateResult{
+			Gate:      GateCircularDependencies,
+			Threshold: fmt.Sprintf("<= %d", *gates.MaxCircularDependencies),
+			Actual:    fmt.Sprintf("%d", cycles),
+			Value:     float64(cycles),
+			Passed:    cycles <= *gates.MaxCircularDependencies,
+		})
+	}
+
+	if gates.MinDocumentationRatio > 0 {
+		results = append(results, GateResult{
+			Gate:      GateDocumentationRatio,
+			Threshold: fmt.Sprintf(">= %.1f%%", gates.MinDocumentationRatio),
+			Actual:    fmt.Sprintf("%.1f%%", project.DocumentationRatio),
+			Value:     project.DocumentationRatio,
+			Passed:    project.DocumentationRatio >= gates.MinDocumentationRatio,
+		})
+	}
+
+	return results
+}
+
+// GatesPassed reports whether every evaluated gate passed
+func GatesPassed(results []GateResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// finish marks a gate on individual functions or files as passed when nothing
+// exceeded its threshold, and orders the offenders worst first
+func (g GateResult) finish() GateResult {
+	g.Passed = len(g.Failures) == 0
+	sort.SliceStable(g.Failures, func(i, j int) bool {
+		return g.Failures[i].Value > g.Failures[j].Value
+	})
+	return g
+}
+
+// gradeRank returns the position of a grade from best to worst, or -1
+func gradeRank(grade string) int {
+	for i, g := range grades {
+		if strings.EqualFold(g, grade) {
+			return i
+		}
+	}
+	return -1
+}
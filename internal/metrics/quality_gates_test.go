@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestEvaluateQualityGates(t *testing.T) {
+	results := []*parser.AnalysisResult{
+		{
+			FilePath:      "/p/a.go",
+			TechnicalDebt: 12,
+			Functions: []parser.FunctionInfo{
+				{Name: "simple", LineStart: 3, CyclomaticComplexity: 4},
+				{Name: "Handle", Receiver: "Server", LineStart: 10, CyclomaticComplexity: 18},
+			},
+		},
+		{
+			FilePath:      "/p/b.py",
+			TechnicalDebt: 3,
+			Classes: []parser.ClassInfo{{
+				Name:    "Parser",
+				Methods: []parser.FunctionInfo{{Name: "async parse", LineStart: 7, CyclomaticComplexity: 25}},
+			}},
+		},
+	}
+	noCycles := 0
+	analysis := &EnhancedProjectAnalysis{
+		FileResults:    results,
+		ProjectMetrics: ProjectMetrics{MaintainabilityIndex: 72.5, DocumentationRatio: 40},
+		QualityScore:   QualityScore{Grade: "C"},
+		DependencyGraph: DependencyGraph{
+			Cycles: []DependencyCycle{{Packages: []string{"/p/x", "/p/y"}}},
+		},
+	}
+
+	gates := QualityGates{
+		MaxFunctionComplexity:   15,
+		MinMaintainabilityIndex: 65,
+		MaxFileTechnicalDebt:    10,
+		MinGrade:                "B",
+		MaxCircularDependencies: &noCycles,
+		MinDocumentationRatio:   30,
+	}
+	if err := gates.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	gateResults := EvaluateQualityGates(gates, analysis)
+	expected := []struct {
+		gate   string
+		passed bool
+		actual string
+	}{
+		{GateFunctionComplexity, false, "max 25"},
+		{GateMaintainabilityIndex, true, "72.5"},
+		{GateFileTechnicalDebt, false, "max 12.0"},
+		{GateGrade, false, "C"},
+		{GateCircularDependencies, false, "1"},
+		{GateDocumentationRatio, true, "40.0%"},
+	}
+	if len(gateResults) != len(expected) {
+		t.Fatalf("Expected %d gate results, got %d: %+v", len(expected), len(gateResults), gateResults)
+	}
+	for i, e := range expected {
+		got := gateResults[i]
+		if got.Gate != e.gate || got.Passed != e.passed || got.Actual != e.actual {
+			t.Errorf("Gate %d: expected %s passed=%v actual=%s, got %+v", i, e.gate, e.passed, e.actual, got)
+		}
+	}
+
+	complexity := gateResults[0].Failures
+	if len(complexity) != 2 || complexity[0].Symbol != "Parser.parse" || complexity[1].Symbol != "Server.Handle" {
+		t.Errorf("Expected Parser.parse then Server.Handle over the complexity limit, got %+v", complexity)
+	}
+	if debt := gateResults[2].Failures; len(debt) != 1 || debt[0].FilePath != "/p/a.go" {
+		t.Errorf("Expected only a.go over the debt limit, got %+v", debt)
+	}
+	if GatesPassed(gateResults) {
+		t.Error("Expected the gates to fail")
+	}
+	if !GatesPassed(EvaluateQualityGates(QualityGates{MinGrade: "C"}, analysis)) {
+		t.Error("Expected grade C to pass a C gate")
+	}
+}
+
+func TestQualityGatesValidate(t *testing.T) {
+	negative := -1
+	invalid := []QualityGates{
+		{MinGrade: "A+"},
+		{MaxFunctionComplexity: -5},
+		{MaxCircularDependencies: &negative},
+		{MinDocumentationRatio: 150},
+	}
+	for _, gates := range invalid {
+		if err := gates.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", gates)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Statuses of functions and classes between two revisions
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+	DiffRenamed = "renamed"
+)
+
+// minRenameSimilarity is how similar a removed and an added function in the
+// same file must be to count as one renamed function
+const minRenameSimilarity = 0.75
+
+// RevisionFile is a changed file parsed at both revisions of a diff. Before is
+// nil for added files and After is nil for deleted ones.
+type RevisionFile struct {
+	Path    string // relative to the root at the head revision
+	OldPath string // relative to the root at the base revision
+	Status  string
+	Before  *parser.AnalysisResult
+	After   *parser.AnalysisResult
+	Skipped string // why the file was not analyzed, leaving Before and After nil
+}
+
+// CompareRevisions computes what a change did to the changed files: per-function
+// deltas, added and removed functions and classes, and the net change in
+// project totals. Functions and classes are matched by qualified name within a
+// file, following renames.
+func CompareRevisions(files []RevisionFile) RevisionDiff {
+	diff := RevisionDiff{
+		Files:     []FileDelta{},
+		Functions: []FunctionDelta{},
+		Classes:   []ClassDelta{},
+	}
+
+	var before, after []*parser.AnalysisResult
+	for _, file := range files {
+		delta := FileDelta{Path: file.Path, OldPath: file.OldPath, Status: file.Status, Skipped: file.Skipped}
+		if file.Before != nil {
+			delta.Before = fileSnapshot(file.Before)
+			before = append(before, file.Before)
+		}
+		if file.After != nil {
+			delta.After = fileSnapshot(file.After)
+			after = append(after, file.After)
+		}
+		diff.Files = append(diff.Files, delta)
+
+		diff.Functions = append(diff.Functions, compareFunctions(file)...)
+		diff.Classes = append(diff.Classes, compareClasses(file)...)
+	}
+
+	diff.Before = revisionTotals(before)
+	diff.After = revisionTotals(after)
+
+	// Largest complexity changes first, so review starts where it matters
+	sort.SliceStable(diff.Functions, func(i, j int) bool {
+		a, b := diff.Functions[i].ComplexityDelta(), diff.Functions[j].ComplexityDelta()
+		if abs(a) != abs(b) {
+			return abs(a) > abs(b)
+		}
+		return diff.Functions[i].FilePath < diff.Functions[j].FilePath
+	})
+
+	return diff
+}
+
+// ComplexityDelta is the change in cyclomatic complexity; added functions count
+// in full and removed ones negatively
+func (d FunctionDelta) ComplexityDelta() int {
+	delta := 0
+	if d.After != nil {
+		delta += d.After.Complexity
+	}
+	if d.Before != nil {
+		delta -= d.Before.Complexity
+	}
+	return delta
+}
+
+// namedFunction is a function with the name it is matched by across revisions
+type namedFunction struct {
+	name string
+	fn   parser.FunctionInfo
+}
+
+// functionsByName lists the functions and methods of a file by qualified name.
+// Names that occur more than once, such as Go init functions, are numbered in
+// order of appearance.
+func functionsByName(result *parser.AnalysisResult) map[string]parser.FunctionInfo {
+	var named []namedFunction
+	if result != nil {
+		for _, fn := range result.Functions {
+			name := methodName(fn.Name)
+			if fn.Receiver != "" {
+				name = fn.Receiver + "." + name
+			}
+			named = append(named, namedFunction{name, fn})
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				named = append(named, namedFunction{class.Name + "." + methodName(method.Name), method})
+			}
+		}
+	}
+
+	functions := make(map[string]parser.FunctionInfo, len(named))
+	seen := make(map[string]int)
+	for _, n := range named {
+		name := n.name
+		if seen[n.name] > 0 {
+			name = fmt.Sprintf("%s#%d", n.name, seen[n.name]+1)
+		}
+		seen[n.name]++
+		functions[name] = n.fn
+	}
+	return functions
+}
+
+// compareFunctions lists the functions of a file that were added, removed,
+// renamed or whose metrics changed. Functions that only moved are left out.
+func compareFunctions(file RevisionFile) []FunctionDelta {
+	before := functionsByName(file.Before)
+	after := functionsByName(file.After)
+
+	var deltas []FunctionDelta
+	for _, name := range unionKeys(before, after) {
+		delta := FunctionDelta{FilePath: file.Path, Name: name}
+		old, hadOld := before[name]
+		current, hasCurrent := after[name]
+		if hadOld {
+			snapshot := functionSnapshot(old)
+			delta.Before = &snapshot
+		}
+		if hasCurrent {
+			snapshot := functionSnapshot(current)
+			delta.After = &snapshot
+		}
+
+		switch {
+		case !hadOld:
+			delta.Status = DiffAdded
+		case !hasCurrent:
+			delta.Status = DiffRemoved
+			delta.FilePath = file.OldPath
+		case delta.Before.sameMetrics(*delta.After):
+			continue
+		default:
+			delta.Status = DiffChanged
+		}
+		deltas = append(deltas, delta)
+	}
+	return matchRenamedFunctions(deltas)
+}
+
+// matchRenamedFunctions pairs removed and added functions of a file that are
+// similar in name and shape, most similar first, into renamed ones
+func matchRenamedFunctions(deltas []FunctionDelta) []FunctionDelta {
+	type candidate struct {
+		removed, added int
+		similarity     float64
+	}
+	var candidates []candidate
+	for i, removed := range deltas {
+		if removed.Status != DiffRemoved {
+			continue
+		}
+		for j, added := range deltas {
+			if added.Status != DiffAdded {
+				continue
+			}
+			similarity := 0.4*NameSimilarity(removed.Name, added.Name) + 0.6*removed.Before.shapeSimilarity(*added.After)
+			if similarity >= minRenameSimilarity {
+				candidates = append(candidates, candidate{i, j, similarity})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return deltas
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	merged := make(map[int]bool)
+	for _, c := range candidates {
+		if merged[c.removed] || merged[c.added] {
+			continue
+		}
+		merged[c.removed], merged[c.added] = true, true
+		renamed := &deltas[c.added]
+		renamed.Status = DiffRenamed
+		renamed.OldName = deltas[c.removed].Name
+		renamed.Before = deltas[c.removed].Before
+	}
+
+	var matched []FunctionDelta
+	for i, delta := range deltas {
+		if merged[i] && delta.Status == DiffRemoved {
+			continue
+		}
+		matched = append(matched, delta)
+	}
+	return matched
+}
+
+// shapeSimilarity compares the size, complexity and parameters of two
+// functions, from 0 for nothing alike to 1 for identical
+func (s FunctionSnapshot) shapeSimilarity(other FunctionSnapshot) float64 {
+	ratio := func(a, b int) float64 {
+		if a == b {
+			return 1
+		}
+		return float64(min(a, b)) / float64(max(a, b))
+	}
+	parameters := 0.0
+	if s.Parameters == other.Parameters {
+		parameters = 1
+	}
+	return (ratio(s.LinesOfCode, other.LinesOfCode) + ratio(s.Complexity, other.Complexity) + parameters) / 3
+}
+
+// NameSimilarity compares two names by edit distance, from 0 for nothing in
+// common to 1 for equal names
+func NameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// compareClasses lists the classes of a file that were added, removed or whose
+// size or complexity changed
+func compareClasses(file RevisionFile) []ClassDelta {
+	byName := func(result *parser.AnalysisResult) map[string]parser.ClassInfo {
+		classes := make(map[string]parser.ClassInfo)
+		if result != nil {
+			for _, class := range result.Classes {
+				classes[class.Name] = class
+			}
+		}
+		return classes
+	}
+	before, after := byName(file.Before), byName(file.After)
+
+	var deltas []ClassDelta
+	for _, name := range unionKeys(before, after) {
+		delta := ClassDelta{FilePath: file.Path, Name: name}
+		old, hadOld := before[name]
+		current, hasCurrent := after[name]
+		if hadOld {
+			snapshot := classSnapshot(old)
+			delta.Before = &snapshot
+		}
+		if hasCurrent {
+			snapshot := classSnapshot(current)
+			delta.After = &snapshot
+		}
+
+		switch {
+		case !hadOld:
+			delta.Status = DiffAdded
+		case !hasCurrent:
+			delta.Status = DiffRemoved
+			delta.FilePath = file.OldPath
+		case delta.Before.Methods == delta.After.Methods && delta.Before.LinesOfCode == delta.After.LinesOfCode &&
+			delta.Before.Complexity == delta.After.Complexity:
+			continue
+		default:
+			delta.Status = DiffChanged
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+// functionSnapshot records the metrics a function delta compares
+func functionSnapshot(fn parser.FunctionInfo) FunctionSnapshot {
+	return FunctionSnapshot{
+		Line:          fn.LineStart,
+		Complexity:    fn.CyclomaticComplexity,
+		LinesOfCode:   fn.LinesOfCode,
+		Parameters:    fn.ParameterCount,
+		TechnicalDebt: functionDebt(fn),
+	}
+}
+
+// sameMetrics reports whether two snapshots differ only in position
+func (s FunctionSnapshot) sameMetrics(other FunctionSnapshot) bool {
+	s.Line = other.Line
+	return s == other
+}
+
+// classSnapshot records the size and complexity of a class
+func classSnapshot(class parser.ClassInfo) ClassSnapshot {
+	complexity := 0
+	for _, method := range class.Methods {
+		complexity += method.CyclomaticComplexity
+	}
+	return ClassSnapshot{
+		Line:        class.LineStart,
+		Methods:     len(class.Methods),
+		LinesOfCode: class.LinesOfCode,
+		Complexity:  complexity,
+	}
+}
+
+// fileSnapshot records the metrics of a file at one revision
+func fileSnapshot(result *parser.AnalysisResult) *FileSnapshot {
+	return &FileSnapshot{
+		Lines:                result.LineCount,
+		CodeLines:            result.CodeLines,
+		Complexity:           totalFunctionComplexity(result),
+		TechnicalDebt:        result.TechnicalDebt,
+		MaintainabilityIndex: result.MaintainabilityIndex,
+	}
+}
+
+// revisionTotals sums the changed files at one revision. Totals of unchanged
+// files are the same at both revisions, so differences between the totals are
+// the net change to the whole project.
+func revisionTotals(results []*parser.AnalysisResult) RevisionTotals {
+	totals := RevisionTotals{Files: len(results)}
+	functions := 0
+	maintainability := 0.0
+	for _, result := range results {
+		totals.Lines += result.LineCount
+		totals.CodeLines += result.CodeLines
+		totals.Classes += len(result.Classes)
+		totals.Complexity += totalFunctionComplexity(result)
+		totals.TechnicalDebt += result.TechnicalDebt
+		maintainability += result.MaintainabilityIndex
+		functions += len(functionsByName(result))
+	}
+	totals.Functions = functions
+	if functions > 0 {
+		totals.AverageComplexity = float64(totals.Complexity) / float64(functions)
+	}
+	if len(results) > 0 {
+		totals.MaintainabilityIndex = maintainability / float64(len(results))
+	}
+	return totals
+}
+
+// totalFunctionComplexity sums the cyclomatic complexity of a file's functions and methods
+func totalFunctionComplexity(result *parser.AnalysisResult) int {
+	total := 0
+	for _, fn := range functionsByName(result) {
+		total += fn.CyclomaticComplexity
+	}
+	return total
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make(map[string]bool, len(a)+len(b))
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return sortedNames(keys)
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
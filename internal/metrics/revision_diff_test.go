@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestCompareRevisions(t *testing.T) {
+	fn := func(name string, line, complexity, loc, params int) parser.FunctionInfo {
+		return parser.FunctionInfo{Name: name, LineStart: line, CyclomaticComplexity: complexity, LinesOfCode: loc, ParameterCount: params}
+	}
+
+	before := &parser.AnalysisResult{
+		FilePath:      "/p/old/a.go",
+		LineCount:     100,
+		TechnicalDebt: 2,
+		Functions: []parser.FunctionInfo{
+			fn("parse", 10, 8, 30, 2),
+			fn("moved", 50, 3, 10, 1),
+			fn("gone", 70, 2, 5, 0),
+		},
+	}
+	after := &parser.AnalysisResult{
+		FilePath:      "/p/new/a.go",
+		LineCount:     130,
+		TechnicalDebt: 4,
+		Functions: []parser.FunctionInfo{
+			fn("parse", 10, 12, 60, 2),
+			fn("moved", 80, 3, 10, 1),
+			fn("fresh", 100, 4, 12, 3),
+		},
+	}
+	added := &parser.AnalysisResult{
+		FilePath:  "/p/b.py",
+		LineCount: 20,
+		Classes: []parser.ClassInfo{{
+			Name:    "Loader",
+			Methods: []parser.FunctionInfo{fn("async load", 3, 5, 15, 1)},
+		}},
+	}
+
+	diff := CompareRevisions([]RevisionFile{
+		{Path: "new/a.go", OldPath: "old/a.go", Status: "renamed", Before: before, After: after},
+		{Path: "b.py", Status: "added", After: added},
+	})
+
+	if len(diff.Files) != 2 || diff.Files[1].Before != nil || diff.Files[0].After.Complexity != 19 {
+		t.Errorf("Unexpected file deltas: %+v", diff.Files)
+	}
+
+	// moved only changed position, so it is left out
+	expected := []struct {
+		name, status, path string
+		complexityDelta    int
+	}{
+		{"Loader.load", DiffAdded, "b.py", 5},
+		{"fresh", DiffAdded, "new/a.go", 4},
+		{"parse", DiffChanged, "new/a.go", 4},
+		{"gone", DiffRemoved, "old/a.go", -2},
+	}
+	if len(diff.Functions) != len(expected) {
+		t.Fatalf("Expected %d function deltas, got %+v", len(expected), diff.Functions)
+	}
+	for i, e := range expected {
+		got := diff.Functions[i]
+		if got.Name != e.name || got.Status != e.status || got.FilePath != e.path || got.ComplexityDelta() != e.complexityDelta {
+			t.Errorf("Function %d: expected %+v, got %+v (delta %d)", i, e, got, got.ComplexityDelta())
+		}
+	}
+	if parse := diff.Functions[2]; parse.Before.TechnicalDebt != 0 || parse.After.TechnicalDebt != 2 {
+		t.Errorf("Expected parse to take on 2 debt for its length and complexity, got %+v -> %+v", parse.Before, parse.After)
+	}
+
+	if len(diff.Classes) != 1 || diff.Classes[0].Name != "Loader" || diff.Classes[0].Status != DiffAdded {
+		t.Errorf("Expected Loader to be added, got %+v", diff.Classes)
+	}
+
+	if diff.Before.Files != 1 || diff.After.Files != 2 {
+		t.Errorf("Expected 1 file before and 2 after, got %d and %d", diff.Before.Files, diff.After.Files)
+	}
+	if diff.Before.Lines != 100 || diff.After.Lines != 150 || diff.Before.Functions != 3 || diff.After.Functions != 4 {
+		t.Errorf("Unexpected totals: %+v -> %+v", diff.Before, diff.After)
+	}
+	if diff.Before.Complexity != 13 || diff.After.Complexity != 24 || diff.After.TechnicalDebt != 4 {
+		t.Errorf("Unexpected complexity or debt totals: %+v -> %+v", diff.Before, diff.After)
+	}
+}
@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// CouplingOptions controls which file pairs are reported as temporally coupled
+type CouplingOptions struct {
+	Since             time.Time // ignore older commits; zero includes all history
+	MinSharedCommits  int       // pairs must change together at least this often
+	MinStrength       float64   // minimum coupling strength in percent
+	MaxFilesPerCommit int       // larger commits (bulk renames, formatting) are ignored
+	MaxPairs          int       // cap on reported pairs, strongest first
+}
+
+// DefaultCouplingOptions returns the thresholds used when none are configured
+func DefaultCouplingOptions() CouplingOptions {
+	return CouplingOptions{
+		MinSharedCommits:  3,
+		MinStrength:       30,
+		MaxFilesPerCommit: 30,
+		MaxPairs:          100,
+	}
+}
+
+// AnalyzeTemporalCoupling finds analyzed files that change together in the same
+// commits. Strength is the number of shared commits divided by the average number
+// of commits of the two files. Pairs whose files import each other are marked so
+// that hidden coupling, without a dependency, stands out.
+func AnalyzeTemporalCoupling(commits []git.Commit, results []*parser.AnalysisResult, rootPath string, options CouplingOptions) TemporalCoupling {
+	coupling := TemporalCoupling{Pairs: []CouplingPair{}, Clusters: [][]string{}}
+
+	// Only files that are part of the analysis take part
+	byRelPath := make(map[string]*parser.AnalysisResult)
+	for _, result := range results {
+		if rel, err := filepath.Rel(rootPath, result.FilePath); err == nil {
+			byRelPath[filepath.ToSlash(rel)] = result
+		}
+	}
+
+	revisions := make(map[string]int)
+	shared := make(map[[2]string]int)
+	for _, commit := range commits {
+		if !options.Since.IsZero() && commit.Time.Before(options.Since) {
+			continue
+		}
+
+		var files []string
+		for _, change := range commit.Changes {
+			if _, analyzed := byRelPath[change.Path]; analyzed {
+				files = append(files, change.Path)
+			}
+		}
+		if len(files) == 0 || (options.MaxFilesPerCommit > 0 && len(files) > options.MaxFilesPerCommit) {
+			continue
+		}
+
+		coupling.CommitsAnalyzed++
+		sort.Strings(files)
+		for i, a := range files {
+			revisions[a]++
+			for _, b := range files[i+1:] {
+				shared[[2]string{a, b}]++
+			}
+		}
+	}
+
+	for key, count := range shared {
+		if count < options.MinSharedCommits {
+			continue
+		}
+		average := float64(revisions[key[0]]+revisions[key[1]]) / 2
+		strength := float64(count) / average * 100
+		if strength < options.MinStrength {
+			continue
+		}
+
+		a, b := byRelPath[key[0]], byRelPath[key[1]]
+		coupling.Pairs = append(coupling.Pairs, CouplingPair{
+			FileA:         a.FilePath,
+			FileB:         b.FilePath,
+			SharedCommits: count,
+			Strength:      strength,
+			HasDependency: importsFile(a, key[0], key[1]) || importsFile(b, key[1], key[0]),
+		})
+	}
+
+	sort.Slice(coupling.Pairs, func(i, j int) bool {
+		pi, pj := coupling.Pairs[i], coupling.Pairs[j]
+		if pi.Strength != pj.Strength {
+			return pi.Strength > pj.Strength
+		}
+		if pi.SharedCommits != pj.SharedCommits {
+			return pi.SharedCommits > pj.SharedCommits
+		}
+		return pi.FileA+pi.FileB < pj.FileA+pj.FileB
+	})
+
+	coupling.Clusters = couplingClusters(coupling.Pairs)
+	if options.MaxPairs > 0 && len(coupling.Pairs) > options.MaxPairs {
+		coupling.Pairs = coupling.Pairs[:options.MaxPairs]
+	}
+
+	return coupling
+}
+
+// couplingClusters groups coupled files into connected components of three or
+// more files, largest first
+func couplingClusters(pairs []CouplingPair) [][]string {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(file string) string {
+		if parent[file] != file {
+			parent[file] = find(parent[file])
+		}
+		return parent[file]
+	}
+
+	for _, pair := range pairs {
+		for _, file := range []string{pair.FileA, pair.FileB} {
+			if _, exists := parent[file]; !exists {
+				parent[file] = file
+			}
+		}
+		parent[find(pair.FileA)] = find(pair.FileB)
+	}
+
+	components := make(map[string][]string)
+	for file := range parent {
+		root := find(file)
+		components[root] = append(components[root], file)
+	}
+
+	clusters := [][]string{}
+	for _, files := range components {
+		if len(files) < 3 {
+			continue
+		}
+		sort.Strings(files)
+		clusters = append(clusters, files)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+		return clusters[i][0] < clusters[j][0]
+	})
+	return clusters
+}
+
+// importsFile reports whether the file at fromPath has a dependency that resolves
+// to the file at toPath (both relative to the root). Go imports name the target's
+// package directory, Python imports its dotted module path and JavaScript imports
+// a path relative to the importing file. Module paths are matched by suffix since
+// the project's own module path may be classified as external.
+func importsFile(from *parser.AnalysisResult, fromPath, toPath string) bool {
+	target := strings.TrimSuffix(toPath, path.Ext(toPath))
+	targetDir := path.Dir(toPath)
+
+	for _, dep := range from.Dependencies {
+		if dep.Type == "standard" {
+			continue
+		}
+		name := dep.Name
+
+		switch {
+		case strings.HasPrefix(name, "."):
+			// Relative module path
+			resolved := path.Join(path.Dir(fromPath), name)
+			if resolved == target || resolved == toPath || resolved == targetDir {
+				return true
+			}
+		case strings.Contains(name, "/"):
+			// Package import path
+			if name == targetDir || strings.HasSuffix(name, "/"+targetDir) {
+				return true
+			}
+		default:
+			// Dotted module path
+			module := strings.ReplaceAll(name, ".", "/")
+			if module == target || strings.HasSuffix(target, "/"+module) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestAnalyzeTemporalCoupling(t *testing.T) {
+	results := []*parser.AnalysisResult{
+		{
+			FilePath: "/repo/api/handler.go",
+			Dependencies: []parser.Dependency{
+				{Name: "github.com/example/app/store", Type: "external"},
+				{Name: "fmt", Type: "standard"},
+			},
+		},
+		{FilePath: "/repo/api/routes.go"},
+		{FilePath: "/repo/store/db.go"},
+		{FilePath: "/repo/config.go"},
+	}
+
+	now := time.Now()
+	commit := func(age time.Duration, paths ...string) git.Commit {
+		c := git.Commit{Time: now.Add(-age)}
+		for _, path := range paths {
+			c.Changes = append(c.Changes, git.FileChange{Path: path})
+		}
+		return c
+	}
+	commits := []git.Commit{
+		commit(time.Hour, "api/handler.go", "store/db.go", "api/routes.go"),
+		commit(2*time.Hour, "api/handler.go", "store/db.go", "api/routes.go"),
+		commit(3*time.Hour, "api/handler.go", "store/db.go", "api/routes.go", "README.md"),
+		commit(4*time.Hour, "api/handler.go", "store/db.go"),
+		commit(5*time.Hour, "api/routes.go", "config.go"),
+		// Not analyzed
+		commit(6*time.Hour, "README.md"),
+		// Too many files
+		commit(7*time.Hour, "api/handler.go", "store/db.go", "api/routes.go", "config.go"),
+		// Outside the window
+		commit(1000*time.Hour, "api/handler.go", "config.go"),
+	}
+
+	options := DefaultCouplingOptions()
+	options.Since = now.Add(-100 * time.Hour)
+	options.MaxFilesPerCommit = 3
+
+	coupling := AnalyzeTemporalCoupling(commits, results, "/repo", options)
+
+	if coupling.CommitsAnalyzed != 5 {
+		t.Errorf("Expected 5 commits analyzed, got %d", coupling.CommitsAnalyzed)
+	}
+
+	pairs := coupling.Pairs
+	if len(pairs) != 3 {
+		t.Fatalf("Expected 3 coupled pairs, got %d: %+v", len(pairs), pairs)
+	}
+
+	first := pairs[0]
+	if first.FileA != "/repo/api/handler.go" || first.FileB != "/repo/store/db.go" {
+		t.Errorf("Expected handler.go and db.go to be most coupled, got %+v", first)
+	}
+	if first.SharedCommits != 4 || math.Abs(first.Strength-100) > 0.001 {
+		t.Errorf("Expected 4 shared commits at 100%%, got %+v", first)
+	}
+	if !first.HasDependency {
+		t.Error("Expected handler.go importing the store package to count as a dependency")
+	}
+
+	for _, pair := range pairs[1:] {
+		if pair.SharedCommits != 3 || math.Abs(pair.Strength-75) > 0.001 {
+			t.Errorf("Expected 3 shared commits at 75%%, got %+v", pair)
+		}
+		if pair.HasDependency {
+			t.Errorf("Expected hidden coupling without a dependency, got %+v", pair)
+		}
+	}
+
+	if len(coupling.Clusters) != 1 || len(coupling.Clusters[0]) != 3 {
+		t.Fatalf("Expected one cluster of 3 files, got %v", coupling.Clusters)
+	}
+	if coupling.Clusters[0][0] != "/repo/api/handler.go" {
+		t.Errorf("Expected cluster files sorted by path, got %v", coupling.Clusters[0])
+	}
+}
+
+func TestImportsFileRelative(t *testing.T) {
+	from := &parser.AnalysisResult{
+		Dependencies: []parser.Dependency{{Name: "../shared/format", Type: "internal"}},
+	}
+
+	if !importsFile(from, "web/pages/home.ts", "web/shared/format.ts") {
+		t.Error("Expected relative import to resolve to web/shared/format.ts")
+	}
+	if importsFile(from, "web/pages/home.ts", "web/pages/format.ts") {
+		t.Error("Expected relative import not to resolve to web/pages/format.ts")
+	}
+
+	python := &parser.AnalysisResult{
+		Dependencies: []parser.Dependency{{Name: "app.models.user", Type: "internal"}},
+	}
+	if !importsFile(python, "app/views.py", "src/app/models/user.py") {
+		t.Error("Expected dotted module to resolve to src/app/models/user.py")
+	}
+}
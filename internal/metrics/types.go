@@ -1,6 +1,10 @@
 package metrics
 
-import "time"
+import (
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/callgraph"
+)
 
 // ProjectMetrics contains overall project metrics
 type ProjectMetrics struct {
@@ -13,6 +17,18 @@ type ProjectMetrics struct {
 	TestCoverage         float64 `json:"test_coverage"`
 	DocumentationRatio   float64 `json:"documentation_ratio"`
 	CodeToCommentRatio   float64 `json:"code_to_comment_ratio"`
+
+	// Cognitive complexity (nesting-weighted understandability)
+	TotalCognitiveComplexity   int     `json:"total_cognitive_complexity"`
+	AverageCognitiveComplexity float64 `json:"average_cognitive_complexity"`
+	MaxCognitiveComplexity     int     `json:"max_cognitive_complexity"`
+
+	// Halstead metrics rolled up across files
+	TotalHalsteadVolume       float64 `json:"total_halstead_volume"`
+	AverageHalsteadVolume     float64 `json:"average_halstead_volume"`
+	AverageHalsteadDifficulty float64 `json:"average_halstead_difficulty"`
+	TotalHalsteadEffort       float64 `json:"total_halstead_effort"`
+	EstimatedBugs             float64 `json:"estimated_bugs"`
 }
 
 // DirectoryStats contains statistics for a specific directory
@@ -22,8 +38,20 @@ type DirectoryStats struct {
 	LineCount            int                      `json:"line_count"`
 	Languages            map[string]LanguageStats `json:"languages"`
 	Complexity           int                      `json:"complexity"`
+	CognitiveComplexity  int                      `json:"cognitive_complexity"`
 	MaintainabilityIndex float64                  `json:"maintainability_index"`
+	CodeDuplication      float64                  `json:"code_duplication"`
+	TestCoverage         float64                  `json:"test_coverage"`
 	SubDirectories       []string                 `json:"sub_directories"`
+
+	// Git history of the files in the directory (zero without history).
+	// Commit counts are summed per file, so a commit touching two files counts twice.
+	CommitCount  int       `json:"commit_count"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	AuthorCount  int       `json:"author_count"`
+	BusFactor    int       `json:"bus_factor"`
+	LastModified time.Time `json:"last_modified"`
 }
 
 // LanguageStats contains statistics for a specific programming language
@@ -34,6 +62,7 @@ type LanguageStats struct {
 	ClassCount           int     `json:"class_count"`
 	Complexity           int     `json:"complexity"`
 	CyclomaticComplexity int     `json:"cyclomatic_complexity"`
+	CognitiveComplexity  int     `json:"cognitive_complexity"`
 	AverageComplexity    float64 `json:"average_complexity"`
 	MaxComplexity        int     `json:"max_complexity"`
 	MaintainabilityIndex float64 `json:"maintainability_index"`
@@ -53,6 +82,167 @@ type DependencyGraph struct {
 	CircularDependencies [][]string          `json:"circular_dependencies"`
 	DependencyDepth      int                 `json:"dependency_depth"`
 	UnusedDependencies   []string            `json:"unused_dependencies"`
+	// Package-level coupling, furthest from the main sequence first
+	Packages []PackageMetrics `json:"packages"`
+	// Strongly connected components of the package graph, largest first
+	Cycles []DependencyCycle `json:"cycles"`
+	// Declared dependencies compared with imports, per module with a manifest
+	Modules                []ModuleDependencies `json:"modules"`
+	UndeclaredDependencies []string             `json:"undeclared_dependencies"`
+}
+
+// DependencyCycle is a strongly connected component of the package graph: a
+// group of packages that all depend on each other, directly or through others
+type DependencyCycle struct {
+	Packages []string     `json:"packages"`
+	Cycles   [][]string   `json:"cycles"`  // shortest cycle through each package, ending where it starts
+	Imports  []ImportSite `json:"imports"` // imports between packages of the component
+}
+
+// ImportSite is an import statement that makes one package depend on another
+type ImportSite struct {
+	From     string `json:"from"` // importing package directory
+	To       string `json:"to"`   // imported package directory
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Import   string `json:"import"`
+}
+
+// ArchitectureViolation is an import that breaks an architecture rule
+type ArchitectureViolation struct {
+	Rule     string `json:"rule"`
+	Reason   string `json:"reason,omitempty"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Import   string `json:"import"`
+	From     string `json:"from"`   // importing package, relative to the root
+	Target   string `json:"target"` // imported project package relative to the root, or the import path
+}
+
+// GateResult is the outcome of one quality gate
+type GateResult struct {
+	Gate      string        `json:"gate"`
+	Threshold string        `json:"threshold"`
+	Actual    string        `json:"actual"`
+	Value     float64       `json:"value"` // measured value behind Actual; grades count from A = 0
+	Passed    bool          `json:"passed"`
+	Failures  []GateFailure `json:"failures,omitempty"` // functions or files over the threshold
+}
+
+// GateFailure is a function or file that fails a quality gate
+type GateFailure struct {
+	FilePath string  `json:"file_path"`
+	Symbol   string  `json:"symbol,omitempty"` // function or method name; empty for file gates
+	Line     int     `json:"line,omitempty"`
+	Value    float64 `json:"value"`
+}
+
+// RevisionDiff is what a change between two revisions did to the code it touched
+type RevisionDiff struct {
+	Base      string          `json:"base"` // commit hashes of both ends
+	Head      string          `json:"head"`
+	Range     string          `json:"range"` // revision range as given
+	Files     []FileDelta     `json:"files"`
+	Functions []FunctionDelta `json:"functions"` // added, removed and changed, largest complexity change first
+	Classes   []ClassDelta    `json:"classes"`
+	// Totals of the changed files at both revisions
+	Before RevisionTotals `json:"before"`
+	After  RevisionTotals `json:"after"`
+}
+
+// FileDelta is a file changed between two revisions
+type FileDelta struct {
+	Path    string        `json:"path"`
+	OldPath string        `json:"old_path,omitempty"`
+	Status  string        `json:"status"`
+	Before  *FileSnapshot `json:"before,omitempty"`  // nil for added files
+	After   *FileSnapshot `json:"after,omitempty"`   // nil for deleted files
+	Skipped string        `json:"skipped,omitempty"` // why the file was not analyzed, leaving both snapshots nil
+}
+
+// FileSnapshot holds the metrics of a file at one revision
+type FileSnapshot struct {
+	Lines                int     `json:"lines"`
+	CodeLines            int     `json:"code_lines"`
+	Complexity           int     `json:"complexity"` // sum over functions and methods
+	TechnicalDebt        float64 `json:"technical_debt"`
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+}
+
+// FunctionDelta is a function or method added, removed, renamed or changed between two revisions
+type FunctionDelta struct {
+	FilePath string            `json:"file_path"`
+	Name     string            `json:"name"`               // Type.method for methods
+	OldName  string            `json:"old_name,omitempty"` // name before a rename
+	Status   string            `json:"status"`
+	Before   *FunctionSnapshot `json:"before,omitempty"`
+	After    *FunctionSnapshot `json:"after,omitempty"`
+}
+
+// FunctionSnapshot holds the metrics of a function at one revision
+type FunctionSnapshot struct {
+	Line          int     `json:"line"`
+	Complexity    int     `json:"complexity"`
+	LinesOfCode   int     `json:"lines_of_code"`
+	Parameters    int     `json:"parameters"`
+	TechnicalDebt float64 `json:"technical_debt"`
+}
+
+// ClassDelta is a class added, removed or changed between two revisions
+type ClassDelta struct {
+	FilePath string         `json:"file_path"`
+	Name     string         `json:"name"`
+	Status   string         `json:"status"`
+	Before   *ClassSnapshot `json:"before,omitempty"`
+	After    *ClassSnapshot `json:"after,omitempty"`
+}
+
+// ClassSnapshot holds the size and complexity of a class at one revision
+type ClassSnapshot struct {
+	Line        int `json:"line"`
+	Methods     int `json:"methods"`
+	LinesOfCode int `json:"lines_of_code"`
+	Complexity  int `json:"complexity"`
+}
+
+// RevisionTotals sums the metrics of the changed files at one revision
+type RevisionTotals struct {
+	Files                int     `json:"files"`
+	Lines                int     `json:"lines"`
+	CodeLines            int     `json:"code_lines"`
+	Functions            int     `json:"functions"`
+	Classes              int     `json:"classes"`
+	Complexity           int     `json:"complexity"`
+	AverageComplexity    float64 `json:"average_complexity"`
+	TechnicalDebt        float64 `json:"technical_debt"`
+	MaintainabilityIndex float64 `json:"maintainability_index"` // average over files
+}
+
+// ModuleDependencies compares the dependencies a module declares in its
+// manifests with the packages its files import
+type ModuleDependencies struct {
+	Path       string   `json:"path"` // directory holding the manifests
+	Ecosystem  string   `json:"ecosystem"`
+	Module     string   `json:"module,omitempty"`
+	Manifests  []string `json:"manifests"`
+	Declared   int      `json:"declared"`
+	Unused     []string `json:"unused"`     // declared but never imported
+	Undeclared []string `json:"undeclared"` // imported but not declared
+}
+
+// PackageMetrics holds Robert Martin's coupling metrics for a package, which is
+// the directory containing its files
+type PackageMetrics struct {
+	Path          string  `json:"path"`
+	FileCount     int     `json:"file_count"`
+	TypeCount     int     `json:"type_count"`     // classes, structs and interfaces
+	AbstractCount int     `json:"abstract_count"` // interfaces and abstract classes
+	Afferent      int     `json:"afferent"`       // Ca: packages that depend on this package
+	Efferent      int     `json:"efferent"`       // Ce: packages this package depends on
+	Instability   float64 `json:"instability"`    // I = Ce / (Ca + Ce)
+	Abstractness  float64 `json:"abstractness"`   // A = abstract types / types
+	Distance      float64 `json:"distance"`       // D = |A + I - 1|
+	Zone          string  `json:"zone,omitempty"` // "pain" or "uselessness" when far from the main sequence
 }
 
 // QualityScore represents overall code quality metrics
@@ -81,4 +271,111 @@ type EnhancedProjectAnalysis struct {
 	DirectoryStats  map[string]DirectoryStats `json:"directory_stats"`
 	DependencyGraph DependencyGraph           `json:"dependency_graph"`
 	QualityScore    QualityScore              `json:"quality_score"`
+	Duplication     DuplicationAnalysis       `json:"duplication"`
+	CoverageRisks   []CoverageRisk            `json:"coverage_risks"`
+	CoverageReports []string                  `json:"coverage_reports,omitempty"`
+	// Complex code that changes often, riskiest first
+	FileHotspots     []Hotspot `json:"file_hotspots"`
+	FunctionHotspots []Hotspot `json:"function_hotspots"`
+	// Files that change together in version control
+	TemporalCoupling TemporalCoupling `json:"temporal_coupling"`
+	// Cohesion of classes with methods, design smells first
+	ClassCohesion []ClassCohesion `json:"class_cohesion"`
+	// Calls between the project's functions
+	CallGraph *callgraph.Graph `json:"call_graph,omitempty"`
+	// Declarations nothing in the project refers to
+	DeadCode []DeadCode `json:"dead_code"`
+	// Imports that break the project's architecture rules
+	ArchitectureViolations []ArchitectureViolation `json:"architecture_violations"`
+	// Results of the project's quality gates, when it defines any
+	QualityGates []GateResult `json:"quality_gates,omitempty"`
+	// Violations compared with the project's baseline, when it has one
+	Baseline *BaselineComparison `json:"baseline,omitempty"`
+}
+
+// DeadCode is a function, method or type that is never referenced
+type DeadCode struct {
+	FilePath  string `json:"file_path"`
+	Name      string `json:"name"` // Type.method for methods
+	Kind      string `json:"kind"`
+	Language  string `json:"language"`
+	LineStart int    `json:"line_start"`
+	LineEnd   int    `json:"line_end"`
+}
+
+// ClassCohesion holds cohesion metrics and design smells for a class or, in Go,
+// a type and its methods
+type ClassCohesion struct {
+	FilePath    string   `json:"file_path"`
+	Class       string   `json:"class"`
+	LineStart   int      `json:"line_start"`
+	LineEnd     int      `json:"line_end"`
+	MethodCount int      `json:"method_count"`
+	FieldCount  int      `json:"field_count"`
+	WMC         int      `json:"wmc"`     // weighted methods per class: summed cyclomatic complexity
+	LCOM4       int      `json:"lcom4"`   // groups of methods sharing no fields or calls; 1 is cohesive
+	LCOMHS      float64  `json:"lcom_hs"` // Henderson-Sellers lack of cohesion; 0 is cohesive, 1 or more is not
+	TCC         float64  `json:"tcc"`     // tight class cohesion: share of method pairs using a common field
+	ATFD        int      `json:"atfd"`    // access to foreign data: other objects' fields used
+	Smells      []string `json:"smells,omitempty"`
+}
+
+// CloneInstance is one occurrence of a duplicated code block
+type CloneInstance struct {
+	FilePath  string `json:"file_path"`
+	LineStart int    `json:"line_start"`
+	LineEnd   int    `json:"line_end"`
+}
+
+// CloneGroup is a set of code blocks with identical normalized token sequences
+type CloneGroup struct {
+	Tokens    int             `json:"tokens"`
+	Lines     int             `json:"lines"`
+	Instances []CloneInstance `json:"instances"`
+}
+
+// DuplicationAnalysis summarizes duplicated code across the project
+type DuplicationAnalysis struct {
+	Groups          []CloneGroup `json:"groups"`
+	DuplicatedLines int          `json:"duplicated_lines"`
+	TotalLines      int          `json:"total_lines"`
+	Percentage      float64      `json:"percentage"`
+}
+
+// CoverageRisk is a function whose complexity is poorly covered by tests
+type CoverageRisk struct {
+	FilePath   string  `json:"file_path"`
+	Function   string  `json:"function"`
+	Line       int     `json:"line"`
+	Complexity int     `json:"complexity"`
+	Coverage   float64 `json:"coverage"`
+	CRAP       float64 `json:"crap"` // complexity² × (1 − coverage)³ + complexity
+}
+
+// Hotspot is a file or function that is both complex and frequently changed
+type Hotspot struct {
+	FilePath      string  `json:"file_path"`
+	Function      string  `json:"function,omitempty"` // empty for file hotspots
+	Line          int     `json:"line,omitempty"`
+	Complexity    int     `json:"complexity"`
+	TechnicalDebt float64 `json:"technical_debt"`
+	Changes       int     `json:"changes"` // commits in the history window
+	Churn         int     `json:"churn"`   // lines added plus removed; files only
+	Score         float64 `json:"score"`   // 0-100, relative to the rest of the project
+}
+
+// CouplingPair is two files that are often changed in the same commits
+type CouplingPair struct {
+	FileA         string  `json:"file_a"`
+	FileB         string  `json:"file_b"`
+	SharedCommits int     `json:"shared_commits"`
+	Strength      float64 `json:"strength"`       // shared commits as a percentage of the pair's average commits
+	HasDependency bool    `json:"has_dependency"` // false when neither file imports the other
+}
+
+// TemporalCoupling summarizes co-change analysis of the commit history
+type TemporalCoupling struct {
+	Pairs           []CouplingPair `json:"pairs"`    // strongest first
+	Clusters        [][]string     `json:"clusters"` // groups of three or more coupled files
+	CommitsAnalyzed int            `json:"commits_analyzed"`
 }
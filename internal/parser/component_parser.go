@@ -0,0 +1,428 @@
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ComponentParser implements the Parser interface for single-file components
+// (.vue, .svelte) and HTML documents that embed scripts and styles
+type ComponentParser struct {
+	registry *ParserRegistry
+
+	// Regex patterns for embedded blocks
+	openTagPattern   *regexp.Regexp
+	closeTagPatterns map[string]*regexp.Regexp
+	langAttrPattern  *regexp.Regexp
+	typeAttrPattern  *regexp.Regexp
+}
+
+// componentBlock is a <script> or <style> region found in a component file
+type componentBlock struct {
+	kind      string
+	language  string
+	content   string
+	tagLine   int
+	lineStart int
+	lineEnd   int
+}
+
+// NewComponentParser creates a new component parser. Script blocks are delegated
+// to the parser registered for the script's language in the given registry; a
+// block no parser is registered for is reported as a parse error.
+func NewComponentParser(registry *ParserRegistry) *ComponentParser {
+	return &ComponentParser{
+		registry:       registry,
+		openTagPattern: regexp.MustCompile(`(?i)<(script|style)\b([^>]*)>`),
+		closeTagPatterns: map[string]*regexp.Regexp{
+			"script": regexp.MustCompile(`(?i)</script`),
+			"style":  regexp.MustCompile(`(?i)</style`),
+		},
+		langAttrPattern: regexp.MustCompile(`(?i)\blang\s*=\s*["']?([\w-]+)`),
+		typeAttrPattern: regexp.MustCompile(`(?i)\btype\s*=\s*["']?([\w/+.-]+)`),
+	}
+}
+
+// Parse analyzes a component file and returns structured results
+func (c *ComponentParser) Parse(filePath string, content []byte) (*AnalysisResult, error) {
+	result := &AnalysisResult{
+		FilePath:     filePath,
+		Language:     c.languageForFile(filePath),
+		Functions:    []FunctionInfo{},
+		Classes:      []ClassInfo{},
+		Imports:      []string{},
+		Dependencies: []Dependency{},
+		Errors:       []ParseError{},
+		AnalyzedAt:   time.Now(),
+	}
+
+	source := string(content)
+	lines := strings.Split(source, "\n")
+	result.LineCount = len(lines)
+
+	blocks, parseErrors := c.extractBlocks(source)
+	result.Errors = append(result.Errors, parseErrors...)
+
+	// Lines owned by script and style blocks; everything else is markup
+	owned := make([]bool, len(lines)+1)
+
+	for _, block := range blocks {
+		if block.lineStart > block.lineEnd {
+			continue
+		}
+		for line := block.lineStart; line <= block.lineEnd; line++ {
+			owned[line] = true
+		}
+
+		langBlock := LanguageBlock{
+			Kind:      block.kind,
+			Language:  block.language,
+			LineStart: block.lineStart,
+			LineEnd:   block.lineEnd,
+			LineCount: block.lineEnd - block.lineStart + 1,
+		}
+		countBlockLines(&langBlock, lines[block.lineStart-1:block.lineEnd])
+		result.LanguageBlocks = append(result.LanguageBlocks, langBlock)
+
+		// JSON script blocks hold data, not code
+		if block.kind == "script" && block.language != "JSON" {
+			c.delegateScript(result, block)
+		}
+	}
+
+	// Template markup is the remainder of the file. Vue and Svelte templates
+	// extend HTML, so their lines count toward the component language.
+	var markupLines []string
+	markup := LanguageBlock{Kind: "template", Language: result.Language}
+	for i, line := range lines {
+		lineNum := i + 1
+		if owned[lineNum] {
+			continue
+		}
+		if markup.LineStart == 0 {
+			markup.LineStart = lineNum
+		}
+		markup.LineEnd = lineNum
+		markupLines = append(markupLines, line)
+	}
+	if len(markupLines) > 0 {
+		markup.LineCount = len(markupLines)
+		countBlockLines(&markup, markupLines)
+		result.LanguageBlocks = append([]LanguageBlock{markup}, result.LanguageBlocks...)
+	}
+
+	result.ImportCount = len(result.Imports)
+
+	return result, nil
+}
+
+// extractBlocks finds the <script> and <style> blocks in a component source
+func (c *ComponentParser) extractBlocks(source string) ([]componentBlock, []ParseError) {
+	var blocks []componentBlock
+	var errors []ParseError
+
+	// Blank out HTML comments so commented-out blocks are not picked up
+	masked := maskHTMLComments(source)
+
+	pos := 0
+	for pos < len(masked) {
+		loc := c.openTagPattern.FindStringSubmatchIndex(masked[pos:])
+		if loc == nil {
+			break
+		}
+
+		tagStart := pos + loc[0]
+		contentStart := pos + loc[1]
+		// Case folding also matches non-ASCII letters such as ſ, so the kind is
+		// told apart by the y only style has
+		kind := "script"
+		if strings.ContainsAny(masked[pos+loc[2]:pos+loc[3]], "yY") {
+			kind = "style"
+		}
+		attrs := masked[pos+loc[4] : pos+loc[5]]
+		tagLine := strings.Count(source[:tagStart], "\n") + 1
+
+		// Searched case-insensitively in place: lowercasing may change the byte
+		// length of non-ASCII text and shift the offsets
+		closeLoc := c.closeTagPatterns[kind].FindStringIndex(masked[contentStart:])
+		if closeLoc == nil {
+			errors = append(errors, ParseError{Line: tagLine, Message: "unclosed <" + kind + "> block"})
+			break
+		}
+		contentEnd := contentStart + closeLoc[0]
+		pos = contentStart + closeLoc[1]
+
+		language := c.blockLanguage(kind, attrs)
+		if language == "" {
+			continue
+		}
+
+		block := componentBlock{
+			kind:     kind,
+			language: language,
+			content:  source[contentStart:contentEnd],
+			tagLine:  strings.Count(source[:contentStart], "\n") + 1,
+		}
+
+		// Trim the remainder of the opening tag line and the line holding the
+		// closing tag; those lines belong to the surrounding markup
+		contentLines := strings.Split(block.content, "\n")
+		first, last := 0, len(contentLines)-1
+		if strings.TrimSpace(contentLines[first]) == "" {
+			first++
+		}
+		if last >= first && strings.TrimSpace(contentLines[last]) == "" {
+			last--
+		}
+		if first > last {
+			// Empty block or external script (src=...)
+			continue
+		}
+		block.lineStart = block.tagLine + first
+		block.lineEnd = block.tagLine + last
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, errors
+}
+
+// delegateScript parses a script block with the registered parser for its
+// language and merges the results with line numbers mapped back to the file
+func (c *ComponentParser) delegateScript(result *AnalysisResult, block componentBlock) {
+	if c.registry == nil {
+		return
+	}
+
+	scriptParser, err := c.registry.GetParser("block" + scriptExtension(block.language))
+	if err != nil {
+		result.Errors = append(result.Errors, ParseError{
+			Line:    block.lineStart,
+			Message: "no parser registered for " + block.language + " block",
+		})
+		return
+	}
+
+	scriptResult, err := scriptParser.Parse(result.FilePath, []byte(block.content))
+	if err != nil {
+		result.Errors = append(result.Errors, ParseError{
+			Line:    block.lineStart,
+			Message: "failed to parse " + block.language + " block: " + err.Error(),
+		})
+		return
+	}
+
+	offset := block.tagLine - 1
+
+	for _, fn := range scriptResult.Functions {
+		result.Functions = append(result.Functions, offsetFunction(fn, offset))
+	}
+	for _, class := range scriptResult.Classes {
+		class.LineStart += offset
+		class.LineEnd += offset
+		for i, method := range class.Methods {
+			class.Methods[i] = offsetFunction(method, offset)
+		}
+		result.Classes = append(result.Classes, class)
+	}
+	for _, parseErr := range scriptResult.Errors {
+		parseErr.Line += offset
+		result.Errors = append(result.Errors, parseErr)
+	}
+
+	for _, imp := range scriptResult.Imports {
+		line := scriptResult.ImportLines[imp]
+		if line > 0 {
+			line += offset
+		}
+		result.AddImport(imp, line)
+	}
+	result.Complexity += scriptResult.Complexity
+	result.ExportCount += scriptResult.ExportCount
+
+	// The maintainability index of the component covers its script code. The
+	// distinct counts of several blocks are summed, so names shared between
+	// blocks count once per block.
+	result.Halstead.DistinctOperators += scriptResult.Halstead.DistinctOperators
+	result.Halstead.DistinctOperands += scriptResult.Halstead.DistinctOperands
+	result.Halstead.TotalOperators += scriptResult.Halstead.TotalOperators
+	result.Halstead.TotalOperands += scriptResult.Halstead.TotalOperands
+}
+
+// blockLanguage determines the language of a block from its lang/type attributes.
+// It returns an empty string for blocks that should be treated as markup.
+func (c *ComponentParser) blockLanguage(kind, attrs string) string {
+	lang := ""
+	if matches := c.langAttrPattern.FindStringSubmatch(attrs); matches != nil {
+		lang = strings.ToLower(matches[1])
+	}
+
+	if kind == "style" {
+		switch lang {
+		case "scss":
+			return "SCSS"
+		case "sass":
+			return "Sass"
+		case "less":
+			return "Less"
+		case "stylus", "styl":
+			return "Stylus"
+		default:
+			return "CSS"
+		}
+	}
+
+	if matches := c.typeAttrPattern.FindStringSubmatch(attrs); matches != nil && lang == "" {
+		scriptType := strings.ToLower(matches[1])
+		switch {
+		case strings.Contains(scriptType, "json"):
+			return "JSON"
+		case strings.Contains(scriptType, "typescript"):
+			return "TypeScript"
+		case scriptType == "module" || strings.Contains(scriptType, "javascript") || strings.Contains(scriptType, "ecmascript"):
+			return "JavaScript"
+		default:
+			// Templates and other non-executable script types are markup
+			return ""
+		}
+	}
+
+	switch lang {
+	case "ts", "typescript", "tsx":
+		return "TypeScript"
+	default:
+		return "JavaScript"
+	}
+}
+
+// languageForFile returns the component language for a file extension
+func (c *ComponentParser) languageForFile(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".vue":
+		return "Vue"
+	case ".svelte":
+		return "Svelte"
+	default:
+		return "HTML"
+	}
+}
+
+// GetSupportedExtensions returns the file extensions supported by this parser
+func (c *ComponentParser) GetSupportedExtensions() []string {
+	return []string{".vue", ".svelte", ".html", ".htm"}
+}
+
+// GetLanguageName returns the human-readable language name
+func (c *ComponentParser) GetLanguageName() string {
+	return "Vue/Svelte/HTML"
+}
+
+// scriptExtension maps an embedded script language to the extension its parser is registered under
+func scriptExtension(language string) string {
+	switch language {
+	case "TypeScript":
+		return ".ts"
+	default:
+		return ".js"
+	}
+}
+
+// offsetFunction shifts a function's line numbers by offset
+func offsetFunction(fn FunctionInfo, offset int) FunctionInfo {
+	fn.LineStart += offset
+	fn.LineEnd += offset
+	return fn
+}
+
+// maskHTMLComments replaces HTML comments with spaces, preserving newlines and offsets
+func maskHTMLComments(source string) string {
+	if !strings.Contains(source, "<!--") {
+		return source
+	}
+
+	masked := []byte(source)
+	pos := 0
+	for {
+		start := strings.Index(source[pos:], "<!--")
+		if start < 0 {
+			break
+		}
+		start += pos
+		end := strings.Index(source[start+4:], "-->")
+		if end < 0 {
+			end = len(source)
+		} else {
+			end += start + 4 + 3
+		}
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+		pos = end
+		if pos >= len(source) {
+			break
+		}
+	}
+	return string(masked)
+}
+
+// countBlockLines classifies the lines of a block as code, comment or blank
+// using the comment syntax of the block's language
+func countBlockLines(block *LanguageBlock, lines []string) {
+	var lineComment, blockOpen, blockClose string
+	switch block.Language {
+	case "HTML", "Vue", "Svelte":
+		blockOpen, blockClose = "<!--", "-->"
+	case "CSS":
+		blockOpen, blockClose = "/*", "*/"
+	case "JSON":
+		// JSON has no comments
+	default:
+		// JavaScript, TypeScript and the CSS preprocessors
+		lineComment, blockOpen, blockClose = "//", "/*", "*/"
+	}
+
+	inComment := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			block.BlankLines++
+		case inComment:
+			block.CommentLines++
+			if idx := strings.Index(trimmed, blockClose); idx >= 0 {
+				inComment = false
+				if strings.TrimSpace(trimmed[idx+len(blockClose):]) != "" {
+					// Code follows the end of the comment
+					block.CommentLines--
+					block.CodeLines++
+				}
+			}
+		case lineComment != "" && strings.HasPrefix(trimmed, lineComment):
+			block.CommentLines++
+		case blockOpen != "" && strings.HasPrefix(trimmed, blockOpen):
+			rest := trimmed[len(blockOpen):]
+			if idx := strings.Index(rest, blockClose); idx >= 0 {
+				if strings.TrimSpace(rest[idx+len(blockClose):]) != "" {
+					block.CodeLines++
+				} else {
+					block.CommentLines++
+				}
+			} else {
+				block.CommentLines++
+				inComment = true
+			}
+		default:
+			block.CodeLines++
+			if blockOpen != "" {
+				if idx := strings.LastIndex(trimmed, blockOpen); idx >= 0 && !strings.Contains(trimmed[idx:], blockClose) {
+					inComment = true
+				}
+			}
+		}
+	}
+}
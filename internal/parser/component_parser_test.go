@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptStubParser reports every line starting with "function " as a function
+type scriptStubParser struct {
+	language   string
+	extensions []string
+}
+
+func (s *scriptStubParser) Parse(filePath string, content []byte) (*AnalysisResult, error) {
+	result := &AnalysisResult{
+		FilePath:   filePath,
+		Language:   s.language,
+		Functions:  []FunctionInfo{},
+		Imports:    []string{},
+		Errors:     []ParseError{},
+		Complexity: 1,
+		AnalyzedAt: time.Now(),
+	}
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "function ") {
+			name := strings.TrimPrefix(line, "function ")
+			name = name[:strings.Index(name, "(")]
+			result.Functions = append(result.Functions, FunctionInfo{Name: name, LineStart: i + 1, LineEnd: i + 1})
+		}
+		if strings.HasPrefix(line, "import ") {
+			fields := strings.Fields(line)
+			result.Imports = append(result.Imports, strings.Trim(fields[len(fields)-1], `"';`))
+		}
+	}
+	return result, nil
+}
+
+func (s *scriptStubParser) GetSupportedExtensions() []string {
+	return s.extensions
+}
+
+func (s *scriptStubParser) GetLanguageName() string {
+	return s.language
+}
+
+func findBlock(blocks []LanguageBlock, language string) *LanguageBlock {
+	for i := range blocks {
+		if blocks[i].Language == language {
+			return &blocks[i]
+		}
+	}
+	return nil
+}
+
+func TestComponentParser_GetSupportedExtensions(t *testing.T) {
+	parser := NewComponentParser(nil)
+	extensions := parser.GetSupportedExtensions()
+
+	expected := map[string]bool{".vue": true, ".svelte": true, ".html": true, ".htm": true}
+	if len(extensions) != len(expected) {
+		t.Errorf("Expected %d extensions, got %d", len(expected), len(extensions))
+	}
+	for _, ext := range extensions {
+		if !expected[ext] {
+			t.Errorf("Unexpected extension: %s", ext)
+		}
+	}
+}
+
+func TestComponentParser_ParseVue(t *testing.T) {
+	registry := NewParserRegistry()
+	registry.RegisterParser(&scriptStubParser{language: "TypeScript", extensions: []string{".ts"}})
+	parser := NewComponentParser(registry)
+
+	code := `<template>
+  <div class="greeting">
+    <!-- greeting text -->
+    <template v-if="name">{{ name }}</template>
+  </div>
+</template>
+
+<script lang="ts">
+import { ref } from 'vue'
+// Greets the user
+function greet(name) {
+  return 'Hello ' + name
+}
+</script>
+
+<style scoped lang="scss">
+/* layout */
+.greeting { color: red; }
+</style>
+`
+
+	result, err := parser.Parse("Greeting.vue", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.Language != "Vue" {
+		t.Errorf("Expected language 'Vue', got '%s'", result.Language)
+	}
+
+	script := findBlock(result.LanguageBlocks, "TypeScript")
+	if script == nil {
+		t.Fatal("Expected a TypeScript block")
+	}
+	if script.LineStart != 9 || script.LineEnd != 13 {
+		t.Errorf("Expected script on lines 9-13, got %d-%d", script.LineStart, script.LineEnd)
+	}
+	if script.CodeLines != 4 || script.CommentLines != 1 {
+		t.Errorf("Expected 4 code and 1 comment lines in script, got %d and %d", script.CodeLines, script.CommentLines)
+	}
+
+	style := findBlock(result.LanguageBlocks, "SCSS")
+	if style == nil {
+		t.Fatal("Expected an SCSS block")
+	}
+	if style.LineCount != 2 || style.CommentLines != 1 {
+		t.Errorf("Expected 2 style lines with 1 comment, got %d lines and %d comments", style.LineCount, style.CommentLines)
+	}
+
+	markup := findBlock(result.LanguageBlocks, "Vue")
+	if markup == nil {
+		t.Fatal("Expected a Vue template block")
+	}
+	if markup.CommentLines != 1 {
+		t.Errorf("Expected 1 HTML comment line, got %d", markup.CommentLines)
+	}
+
+	// Every line of the file belongs to exactly one block
+	total := 0
+	for _, block := range result.LanguageBlocks {
+		total += block.LineCount
+	}
+	if total != result.LineCount {
+		t.Errorf("Expected blocks to cover %d lines, got %d", result.LineCount, total)
+	}
+
+	// Script functions are reported at their position in the component file
+	fn := findFunction(result.Functions, "greet")
+	if fn == nil {
+		t.Fatal("Expected to find delegated function 'greet'")
+	}
+	if fn.LineStart != 11 {
+		t.Errorf("Expected greet on line 11, got %d", fn.LineStart)
+	}
+
+	if len(result.Imports) != 1 || result.Imports[0] != "vue" {
+		t.Errorf("Expected imports [vue], got %v", result.Imports)
+	}
+}
+
+func TestComponentParser_ParseSvelteWithoutScriptParser(t *testing.T) {
+	parser := NewComponentParser(NewParserRegistry())
+
+	code := `<script>
+  let count = 0;
+</script>
+
+<button on:click={() => count++}>{count}</button>`
+
+	result, err := parser.Parse("Counter.svelte", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.Language != "Svelte" {
+		t.Errorf("Expected language 'Svelte', got '%s'", result.Language)
+	}
+	if len(result.Functions) != 0 {
+		t.Errorf("Expected no functions without a script parser, got %d", len(result.Functions))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Line != 2 {
+		t.Errorf("Expected the unparsed script block to be reported on line 2, got %+v", result.Errors)
+	}
+
+	lines := result.LanguageLines()
+	if lines["JavaScript"] != 1 {
+		t.Errorf("Expected 1 JavaScript line, got %d", lines["JavaScript"])
+	}
+	if lines["Svelte"] != 4 {
+		t.Errorf("Expected the 4 template lines to count as Svelte, got %d", lines["Svelte"])
+	}
+	if lines["HTML"] != 0 {
+		t.Errorf("Expected no HTML lines in a Svelte component, got %d", lines["HTML"])
+	}
+}
+
+func TestComponentParser_ParseWithJavaScriptParser(t *testing.T) {
+	registry := NewParserRegistry()
+	registry.RegisterParser(NewJavaScriptParser())
+	parser := NewComponentParser(registry)
+
+	code := `<template>
+  <button @click="increment">{{ count }}</button>
+</template>
+
+<script>
+import { ref } from 'vue'
+
+export default {
+  setup() {
+    const count = ref(0)
+    const increment = () => {
+      if (count.value < 10) count.value++
+    }
+    return { count, increment }
+  },
+}
+</script>
+`
+
+	result, err := parser.Parse("Counter.vue", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no parse errors, got %+v", result.Errors)
+	}
+
+	setup := findFunction(result.Functions, "setup")
+	if setup == nil || setup.LineStart != 9 || setup.LineEnd != 15 {
+		t.Fatalf("Expected setup on lines 9-15, got %+v", setup)
+	}
+	increment := findFunction(result.Functions, "increment")
+	if increment == nil || increment.LineStart != 11 || increment.CyclomaticComplexity != 2 {
+		t.Errorf("Expected increment on line 11 with complexity 2, got %+v", increment)
+	}
+	if len(result.Imports) != 1 || result.ImportLines["vue"] != 6 {
+		t.Errorf("Expected vue imported on line 6, got %v %v", result.Imports, result.ImportLines)
+	}
+
+	lines := result.LanguageLines()
+	if lines["Vue"] != 7 || lines["JavaScript"] != 11 {
+		t.Errorf("Expected 7 Vue and 11 JavaScript lines, got %v", lines)
+	}
+
+	script := code[strings.Index(code, "<script>")+len("<script>") : strings.Index(code, "</script>")]
+	scriptResult, err := NewJavaScriptParser().Parse("Counter.js", []byte(script))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.Halstead != scriptResult.Halstead || result.Halstead.TotalOperands == 0 {
+		t.Errorf("Expected the Halstead counts of the script %+v, got %+v", scriptResult.Halstead, result.Halstead)
+	}
+}
+
+func TestComponentParser_ParseHTML(t *testing.T) {
+	parser := NewComponentParser(nil)
+
+	code := `<html>
+<head>
+<!-- <script>ignored()</script> -->
+<script type="application/ld+json">{"@type": "Organization"}</script>
+<script type="text/x-template"><p>not code</p></script>
+<script src="/app.js"></script>
+</head>
+</html>`
+
+	result, err := parser.Parse("index.html", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.Language != "HTML" {
+		t.Errorf("Expected language 'HTML', got '%s'", result.Language)
+	}
+	if findBlock(result.LanguageBlocks, "JavaScript") != nil {
+		t.Error("Expected commented-out and external scripts to be ignored")
+	}
+
+	json := findBlock(result.LanguageBlocks, "JSON")
+	if json == nil {
+		t.Fatal("Expected a JSON block for structured data")
+	}
+	if json.LineStart != 4 || json.LineEnd != 4 {
+		t.Errorf("Expected JSON block on line 4, got %d-%d", json.LineStart, json.LineEnd)
+	}
+}
+
+func TestComponentParser_NonASCII(t *testing.T) {
+	parser := NewComponentParser(nil)
+
+	// Lowercasing Ⱥ takes one byte more, so offsets into lowercased text drift
+	code := "<p>ȺȺȺȺ</p>\n<SCRIPT>ȺȺȺȺȺȺȺȺȺȺ</SCRIPT>\n<ſtyle>\nȺ { color: red; }\n</style>\n"
+	result, err := parser.Parse("Page.html", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no parse errors, got %+v", result.Errors)
+	}
+	script := findBlock(result.LanguageBlocks, "JavaScript")
+	if script == nil || script.LineStart != 2 || script.LineEnd != 2 {
+		t.Errorf("Expected a script block on line 2, got %+v", script)
+	}
+	style := findBlock(result.LanguageBlocks, "CSS")
+	if style == nil || style.LineStart != 4 || style.LineEnd != 4 {
+		t.Errorf("Expected a style block on line 4, got %+v", style)
+	}
+}
+
+func TestComponentParser_UnclosedBlock(t *testing.T) {
+	parser := NewComponentParser(nil)
+
+	result, err := parser.Parse("Broken.vue", []byte("<script>\nlet x = 1;\n"))
+	if err != nil {
+		t.Fatalf("Parse should not return error, got: %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("Expected a parse error for unclosed script block")
+	}
+}
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// goBuiltins are predeclared functions and conversions that never resolve to project code
+var goBuiltins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true, "recover": true,
+	"bool": true, "byte": true, "error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"any": true,
+}
+
+// goVersionSuffix matches major version path elements such as v2
+var goVersionSuffix = regexp.MustCompile(`^v\d+$`)
+
+// goTypeRef is a named type, with the import path when declared in another package
+type goTypeRef struct {
+	name    string
+	pkgPath string
+}
+
+// goImportNames maps the names a file uses for its imports to their import paths
+func goImportNames(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		if imp.Name != nil {
+			if imp.Name.Name != "_" && imp.Name.Name != "." {
+				imports[imp.Name.Name] = importPath
+			}
+			continue
+		}
+
+		// Default name: last path element, skipping major versions (pkg/v2, yaml.v3)
+		name := path.Base(importPath)
+		if goVersionSuffix.MatchString(name) {
+			name = path.Base(path.Dir(importPath))
+		}
+		if dot := strings.Index(name, ".v"); dot > 0 {
+			name = name[:dot]
+		}
+		imports[strings.ReplaceAll(name, "-", "")] = importPath
+	}
+	return imports
+}
+
+// extractCalls records the calls a function makes. Calls through the receiver,
+// parameters and local variables carry the variable's type when it is declared
+// or constructed in the function; calls into imported packages carry the import path.
+func (g *GoParser) extractCalls(fset *token.FileSet, funcDecl *ast.FuncDecl, imports map[string]string) []CallSite {
+	if funcDecl.Body == nil {
+		return nil
+	}
+
+	vars := make(map[string]goTypeRef)
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 && len(funcDecl.Recv.List[0].Names) > 0 {
+		vars[funcDecl.Recv.List[0].Names[0].Name] = goTypeRef{name: g.receiverTypeName(funcDecl.Recv.List[0].Type)}
+	}
+	if funcDecl.Type.Params != nil {
+		for _, param := range funcDecl.Type.Params.List {
+			ref := g.typeRef(param.Type, imports)
+			for _, name := range param.Names {
+				vars[name.Name] = ref
+			}
+		}
+	}
+
+	// Variable types from declarations and constructors
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE || len(x.Rhs) == 0 {
+				return true
+			}
+			for i, lhs := range x.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				switch {
+				case len(x.Rhs) == len(x.Lhs):
+					vars[ident.Name] = g.valueTypeRef(x.Rhs[i], imports)
+				case i == 0:
+					// v, err := NewT()
+					vars[ident.Name] = g.valueTypeRef(x.Rhs[0], imports)
+				default:
+					vars[ident.Name] = goTypeRef{}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range x.Names {
+				switch {
+				case x.Type != nil:
+					vars[name.Name] = g.typeRef(x.Type, imports)
+				case i < len(x.Values):
+					vars[name.Name] = g.valueTypeRef(x.Values[i], imports)
+				}
+			}
+		}
+		return true
+	})
+
+	var calls []CallSite
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		site := CallSite{Line: fset.Position(call.Pos()).Line}
+
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			if goBuiltins[fn.Name] {
+				return true
+			}
+			site.Name = fn.Name
+		case *ast.SelectorExpr:
+			site.Name = fn.Sel.Name
+			ident, isIdent := fn.X.(*ast.Ident)
+			ref, isVar := goTypeRef{}, false
+			if isIdent {
+				ref, isVar = vars[ident.Name]
+			}
+			switch {
+			case isIdent && !isVar && imports[ident.Name] != "":
+				site.Package = imports[ident.Name]
+			default:
+				site.Method = true
+				site.Type = ref.name
+				site.Package = ref.pkgPath
+			}
+		default:
+			return true
+		}
+
+		calls = append(calls, site)
+		return true
+	})
+	return calls
+}
+
+// typeRef returns the named type of a type expression such as *T, pkg.T or T[K]
+func (g *GoParser) typeRef(expr ast.Expr, imports map[string]string) goTypeRef {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return g.typeRef(t.X, imports)
+	case *ast.IndexExpr:
+		return g.typeRef(t.X, imports)
+	case *ast.IndexListExpr:
+		return g.typeRef(t.X, imports)
+	case *ast.Ident:
+		if goBuiltins[t.Name] {
+			return goTypeRef{}
+		}
+		return goTypeRef{name: t.Name}
+	case *ast.SelectorExpr:
+		if ident, ok := t.X.(*ast.Ident); ok && imports[ident.Name] != "" {
+			return goTypeRef{name: t.Sel.Name, pkgPath: imports[ident.Name]}
+		}
+	}
+	return goTypeRef{}
+}
+
+// valueTypeRef infers the type of T{}, &T{} and NewT() expressions
+func (g *GoParser) valueTypeRef(expr ast.Expr, imports map[string]string) goTypeRef {
+	switch v := expr.(type) {
+	case *ast.UnaryExpr:
+		if v.Op == token.AND {
+			return g.valueTypeRef(v.X, imports)
+		}
+	case *ast.CompositeLit:
+		if v.Type != nil {
+			return g.typeRef(v.Type, imports)
+		}
+	case *ast.CallExpr:
+		// Constructors conventionally return the type they are named after
+		switch fn := v.Fun.(type) {
+		case *ast.Ident:
+			if name, ok := strings.CutPrefix(fn.Name, "New"); ok && name != "" {
+				return goTypeRef{name: name}
+			}
+		case *ast.SelectorExpr:
+			ident, ok := fn.X.(*ast.Ident)
+			if !ok || imports[ident.Name] == "" {
+				break
+			}
+			if name, ok := strings.CutPrefix(fn.Sel.Name, "New"); ok && name != "" {
+				return goTypeRef{name: name, pkgPath: imports[ident.Name]}
+			}
+		}
+	}
+	return goTypeRef{}
+}
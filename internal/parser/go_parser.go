@@ -62,20 +62,32 @@ func (g *GoParser) Parse(filePath string, content []byte) (*AnalysisResult, erro
 	// Extract and categorize imports
 	for _, imp := range node.Imports {
 		importPath := strings.Trim(imp.Path.Value, `"`)
-		result.Imports = append(result.Imports, importPath)
+		line := fset.Position(imp.Pos()).Line
+		result.AddImport(importPath, line)
 
 		// Create dependency with proper categorization
 		dependency := g.categorizeImport(importPath, filePath)
+		dependency.Line = line
 		result.Dependencies = append(result.Dependencies, dependency)
 	}
 
 	result.ImportCount = len(result.Imports)
 
+	// Tokenize once for Halstead operator/operand counts
+	halsteadTokens := g.scanHalsteadTokens(content)
+	result.Halstead = g.halsteadForRange(halsteadTokens, 0, len(content))
+
+	importNames := goImportNames(node)
+
 	// Walk the AST to extract information
 	ast.Inspect(node, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.FuncDecl:
 			funcInfo := g.extractFunctionInfo(fset, x)
+			g.extractMemberAccess(x, &funcInfo)
+			funcInfo.Calls = g.extractCalls(fset, x, importNames)
+			funcInfo.Halstead = g.halsteadForRange(halsteadTokens,
+				fset.Position(x.Pos()).Offset, fset.Position(x.End()).Offset)
 			result.Functions = append(result.Functions, funcInfo)
 			result.Complexity += funcInfo.Complexity
 
@@ -97,6 +109,8 @@ func (g *GoParser) Parse(filePath string, content []byte) (*AnalysisResult, erro
 		return true
 	})
 
+	result.References = g.extractReferences(node)
+
 	return result, nil
 }
 
@@ -113,6 +127,7 @@ func (g *GoParser) extractFunctionInfo(fset *token.FileSet, funcDecl *ast.FuncDe
 		ReturnType:           "",
 		Complexity:           g.calculateComplexity(funcDecl),
 		CyclomaticComplexity: g.calculateComplexity(funcDecl),
+		CognitiveComplexity:  g.calculateCognitiveComplexity(funcDecl),
 		LinesOfCode:          endPos.Line - startPos.Line + 1,
 		IsPublic:             g.isPublicFunction(funcDecl.Name.Name),
 		IsAsync:              false, // Go doesn't have async functions like Python
@@ -151,6 +166,108 @@ func (g *GoParser) extractFunctionInfo(fset *token.FileSet, funcDecl *ast.FuncDe
 	return funcInfo
 }
 
+// extractMemberAccess records the receiver's fields and methods a method uses, and
+// the fields of other values it reads directly or through getters
+func (g *GoParser) extractMemberAccess(funcDecl *ast.FuncDecl, funcInfo *FunctionInfo) {
+	if funcDecl.Body == nil {
+		return
+	}
+
+	receiver := ""
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+		field := funcDecl.Recv.List[0]
+		funcInfo.Receiver = g.receiverTypeName(field.Type)
+		if len(field.Names) > 0 {
+			receiver = field.Names[0].Name
+		}
+	}
+
+	// Parameters and local variables; package names and types are never in here
+	locals := make(map[string]bool)
+	if funcDecl.Type.Params != nil {
+		for _, param := range funcDecl.Type.Params.List {
+			for _, name := range param.Names {
+				locals[name.Name] = true
+			}
+		}
+	}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok == token.DEFINE {
+				for _, lhs := range x.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						locals[ident.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range x.Names {
+				locals[name.Name] = true
+			}
+		case *ast.RangeStmt:
+			for _, expr := range []ast.Expr{x.Key, x.Value} {
+				if ident, ok := expr.(*ast.Ident); ok {
+					locals[ident.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	delete(locals, receiver)
+	delete(locals, "_")
+
+	fields := make(map[string]bool)
+	methods := make(map[string]bool)
+	foreign := make(map[string]bool)
+	called := make(map[*ast.SelectorExpr]bool)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CallExpr:
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok {
+				called[sel] = true
+			}
+		case *ast.SelectorExpr:
+			ident, ok := x.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			name := x.Sel.Name
+			switch {
+			case receiver != "" && ident.Name == receiver && called[x]:
+				methods[name] = true
+			case receiver != "" && ident.Name == receiver:
+				fields[name] = true
+			case locals[ident.Name] && !called[x]:
+				foreign[ident.Name+"."+name] = true
+			case locals[ident.Name] && (strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "get")):
+				foreign[ident.Name+"."+name] = true
+			}
+		}
+		return true
+	})
+
+	funcInfo.FieldsAccessed = sortedKeys(fields)
+	funcInfo.MethodsCalled = sortedKeys(methods)
+	funcInfo.ForeignData = sortedKeys(foreign)
+}
+
+// receiverTypeName returns the type name of a method receiver such as *T or T[K]
+func (g *GoParser) receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return g.receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return g.receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return g.receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
 // extractStructInfo extracts information about a struct declaration
 func (g *GoParser) extractStructInfo(fset *token.FileSet, typeSpec *ast.TypeSpec, structType *ast.StructType) ClassInfo {
 	startPos := fset.Position(structType.Pos())
@@ -193,11 +310,13 @@ func (g *GoParser) extractInterfaceInfo(fset *token.FileSet, typeSpec *ast.TypeS
 	endPos := fset.Position(interfaceType.End())
 
 	classInfo := ClassInfo{
-		Name:      typeSpec.Name.Name,
-		LineStart: startPos.Line,
-		LineEnd:   endPos.Line,
-		Methods:   []FunctionInfo{},
-		Fields:    []string{},
+		Name:       typeSpec.Name.Name,
+		LineStart:  startPos.Line,
+		LineEnd:    endPos.Line,
+		Methods:    []FunctionInfo{},
+		Fields:     []string{},
+		IsPublic:   g.isPublicType(typeSpec.Name.Name),
+		IsAbstract: true,
 	}
 
 	// Extract methods from interface
@@ -271,6 +390,238 @@ func (g *GoParser) calculateComplexity(funcDecl *ast.FuncDecl) int {
 	return complexity
 }
 
+// calculateCognitiveComplexity calculates cognitive complexity for a function.
+// Breaks in linear flow add one plus their nesting depth; else branches, labeled
+// jumps, sequences of boolean operators and recursive calls add one each.
+func (g *GoParser) calculateCognitiveComplexity(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	visitor := &cognitiveVisitor{funcName: funcDecl.Name.Name}
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 && len(funcDecl.Recv.List[0].Names) > 0 {
+		visitor.receiver = funcDecl.Recv.List[0].Names[0].Name
+	}
+	visitor.walk(funcDecl.Body, 0)
+
+	return visitor.complexity
+}
+
+// cognitiveVisitor accumulates cognitive complexity while tracking nesting depth
+type cognitiveVisitor struct {
+	funcName   string
+	receiver   string
+	complexity int
+}
+
+// walk visits a subtree at the given nesting depth
+func (v *cognitiveVisitor) walk(node ast.Node, nesting int) {
+	if node == nil {
+		return
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.IfStmt:
+			v.visitIf(x, nesting, false)
+			return false
+
+		case *ast.ForStmt:
+			v.complexity += 1 + nesting
+			v.walk(x.Init, nesting)
+			v.walk(x.Cond, nesting)
+			v.walk(x.Post, nesting)
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.RangeStmt:
+			v.complexity += 1 + nesting
+			v.walk(x.X, nesting)
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.SwitchStmt:
+			v.complexity += 1 + nesting
+			v.walk(x.Init, nesting)
+			v.walk(x.Tag, nesting)
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.TypeSwitchStmt:
+			v.complexity += 1 + nesting
+			v.walk(x.Init, nesting)
+			v.walk(x.Assign, nesting)
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.SelectStmt:
+			v.complexity += 1 + nesting
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.FuncLit:
+			// Closures increase nesting without adding to the score themselves
+			v.walk(x.Body, nesting+1)
+			return false
+
+		case *ast.BranchStmt:
+			if x.Tok == token.GOTO || x.Label != nil {
+				v.complexity++
+			}
+
+		case *ast.BinaryExpr:
+			if x.Op == token.LAND || x.Op == token.LOR {
+				v.visitLogical(x, nesting)
+				return false
+			}
+
+		case *ast.CallExpr:
+			if v.isRecursiveCall(x) {
+				v.complexity++
+			}
+		}
+		return true
+	})
+}
+
+// visitIf scores an if statement and its else-if chain
+func (v *cognitiveVisitor) visitIf(stmt *ast.IfStmt, nesting int, isElseIf bool) {
+	if isElseIf {
+		v.complexity++
+	} else {
+		v.complexity += 1 + nesting
+	}
+
+	v.walk(stmt.Init, nesting)
+	v.walk(stmt.Cond, nesting)
+	v.walk(stmt.Body, nesting+1)
+
+	switch elseStmt := stmt.Else.(type) {
+	case *ast.IfStmt:
+		v.visitIf(elseStmt, nesting, true)
+	case *ast.BlockStmt:
+		v.complexity++
+		v.walk(elseStmt, nesting+1)
+	}
+}
+
+// visitLogical scores a chain of && and || operators: each run of the same
+// operator adds one, so "a && b && c" scores 1 and "a && b || c" scores 2
+func (v *cognitiveVisitor) visitLogical(expr *ast.BinaryExpr, nesting int) {
+	var operators []token.Token
+	var operands []ast.Expr
+
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if bin, ok := e.(*ast.BinaryExpr); ok && (bin.Op == token.LAND || bin.Op == token.LOR) {
+			flatten(bin.X)
+			operators = append(operators, bin.Op)
+			flatten(bin.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(expr)
+
+	for i, op := range operators {
+		if i == 0 || op != operators[i-1] {
+			v.complexity++
+		}
+	}
+
+	// Operands may contain their own (parenthesized) sequences, closures or calls
+	for _, operand := range operands {
+		v.walk(operand, nesting)
+	}
+}
+
+// isRecursiveCall reports whether a call invokes the function being scored
+func (v *cognitiveVisitor) isRecursiveCall(call *ast.CallExpr) bool {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return v.receiver == "" && fn.Name == v.funcName
+	case *ast.SelectorExpr:
+		if v.receiver == "" || fn.Sel.Name != v.funcName {
+			return false
+		}
+		// Method calls on the receiver or on values reached through it (t.left.Walk)
+		x := fn.X
+		for {
+			switch e := x.(type) {
+			case *ast.Ident:
+				return e.Name == v.receiver
+			case *ast.SelectorExpr:
+				x = e.X
+			case *ast.IndexExpr:
+				x = e.X
+			case *ast.ParenExpr:
+				x = e.X
+			default:
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// goHalsteadToken is a lexical token classified as a Halstead operator or operand
+type goHalsteadToken struct {
+	offset   int
+	operator bool
+	text     string
+}
+
+// scanHalsteadTokens tokenizes Go source into Halstead operators and operands.
+// Keywords, operators and punctuation are operators; identifiers and literals are operands.
+func (g *GoParser) scanHalsteadTokens(content []byte) []goHalsteadToken {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(content))
+
+	var s scanner.Scanner
+	s.Init(file, content, nil, 0)
+
+	var tokens []goHalsteadToken
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		switch {
+		case tok == token.ILLEGAL:
+			continue
+		case tok == token.SEMICOLON && lit == "\n":
+			// Automatically inserted semicolons are not part of the source
+			continue
+		case tok == token.RPAREN || tok == token.RBRACK || tok == token.RBRACE:
+			// Brackets are counted once, at the opening bracket
+			continue
+		case tok.IsLiteral():
+			tokens = append(tokens, goHalsteadToken{offset: file.Offset(pos), text: lit})
+		default:
+			tokens = append(tokens, goHalsteadToken{offset: file.Offset(pos), operator: true, text: tok.String()})
+		}
+	}
+
+	return tokens
+}
+
+// halsteadForRange counts the Halstead operators and operands between two byte offsets
+func (g *GoParser) halsteadForRange(tokens []goHalsteadToken, start, end int) HalsteadMetrics {
+	counter := newHalsteadCounter()
+	for _, tok := range tokens {
+		if tok.offset < start || tok.offset >= end {
+			continue
+		}
+		if tok.operator {
+			counter.addOperator(tok.text)
+		} else {
+			counter.addOperand(tok.text)
+		}
+	}
+	return counter.counts()
+}
+
 // typeToString converts an AST type to its string representation
 func (g *GoParser) typeToString(expr ast.Expr) string {
 	switch t := expr.(type) {
@@ -343,8 +694,6 @@ func (g *GoParser) categorizeImport(importPath, filePath string) Dependency {
 		dependency.Type = "internal"
 	} else {
 		dependency.Type = "external"
-		// Try to extract version from module path if available
-		dependency.Version = g.extractVersion(importPath)
 	}
 
 	return dependency
@@ -441,11 +790,3 @@ func (g *GoParser) isInternalImport(importPath, filePath string) bool {
 
 	return false
 }
-
-// extractVersion attempts to extract version information from import path
-func (g *GoParser) extractVersion(importPath string) string {
-	// For now, we don't have access to go.mod parsing
-	// In a full implementation, we'd parse go.mod or use go list
-	// This is a placeholder for future enhancement
-	return ""
-}
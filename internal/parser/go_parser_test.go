@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -209,6 +210,9 @@ type ReadWriter interface {
 		if len(writerInterface.Methods) != 1 {
 			t.Errorf("Expected Writer interface to have 1 method, got %d", len(writerInterface.Methods))
 		}
+		if !writerInterface.IsAbstract {
+			t.Error("Expected Writer interface to be abstract")
+		}
 
 		if len(writerInterface.Methods) > 0 {
 			writeMethod := writerInterface.Methods[0]
@@ -346,6 +350,135 @@ func main() {
 			t.Errorf("Expected import '%s', got '%s'", expected, result.Imports[i])
 		}
 	}
+	if result.ImportLines["fmt"] != 4 || result.ImportLines["path/filepath"] != 7 {
+		t.Errorf("Expected fmt on line 4 and path/filepath on line 7, got %v", result.ImportLines)
+	}
+	if result.Dependencies[1].Line != 5 {
+		t.Errorf("Expected the strings dependency on line 5, got %d", result.Dependencies[1].Line)
+	}
+}
+
+func TestGoParser_CognitiveComplexity(t *testing.T) {
+	parser := NewGoParser()
+	code := `package main
+
+func sumOfPrimes(max int) int {
+	total := 0
+OUT:
+	for i := 1; i <= max; i++ {
+		for j := 2; j < i; j++ {
+			if i%j == 0 {
+				continue OUT
+			}
+		}
+		total += i
+	}
+	return total
+}
+
+func getWords(number int) string {
+	switch number {
+	case 1:
+		return "one"
+	case 2:
+		return "a couple"
+	default:
+		return "lots"
+	}
+}
+
+func classify(a, b, c bool, n int) int {
+	if a && b || c {
+		return 1
+	} else if n > 0 {
+		return classify(a, b, c, n-1)
+	} else {
+		run := func() {
+			if a {
+				n++
+			}
+		}
+		run()
+	}
+	return 0
+}
+
+func (t *Tree) Walk(depth int) {
+	if t.left != nil {
+		t.left.Walk(depth + 1)
+	}
+	t.Walk(depth - 1)
+}`
+
+	result, err := parser.Parse("test.go", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cognitive   int
+		description string
+	}{
+		// for (+1), nested for (+2), nested if (+3), labeled continue (+1)
+		{"sumOfPrimes", 7, "nesting-weighted loops"},
+		// A switch counts once regardless of the number of cases
+		{"getWords", 1, "switch"},
+		// if (+1), && || (+2), else if (+1), recursion (+1), else (+1), if in closure in else (+3)
+		{"classify", 9, "boolean sequences, else branches, recursion and closures"},
+		// if (+1), recursion on the receiver and on its fields (+1 each)
+		{"Walk", 3, "method recursion"},
+	}
+
+	for _, test := range tests {
+		fn := findFunction(result.Functions, test.name)
+		if fn == nil {
+			t.Fatalf("Expected to find function '%s'", test.name)
+		}
+		if fn.CognitiveComplexity != test.cognitive {
+			t.Errorf("%s (%s): expected cognitive complexity %d, got %d",
+				test.name, test.description, test.cognitive, fn.CognitiveComplexity)
+		}
+	}
+
+	// Cyclomatic complexity is tracked separately
+	if fn := findFunction(result.Functions, "getWords"); fn != nil && fn.Complexity == fn.CognitiveComplexity {
+		t.Errorf("Expected cyclomatic and cognitive complexity to differ for getWords, both are %d", fn.Complexity)
+	}
+}
+
+func TestGoParser_HalsteadCounts(t *testing.T) {
+	parser := NewGoParser()
+	code := `package main
+
+func add(a, b int) int {
+	return a + b
+}`
+
+	result, err := parser.Parse("test.go", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := findFunction(result.Functions, "add")
+	if fn == nil {
+		t.Fatal("Expected to find function 'add'")
+	}
+
+	// Operators: func ( , { return +  -> 6 distinct, 6 total
+	// Operands: add a b int int a b -> 4 distinct (add, a, b, int), 7 total
+	h := fn.Halstead
+	if h.DistinctOperators != 6 || h.TotalOperators != 6 {
+		t.Errorf("Expected 6 distinct/6 total operators, got %d/%d", h.DistinctOperators, h.TotalOperators)
+	}
+	if h.DistinctOperands != 4 || h.TotalOperands != 7 {
+		t.Errorf("Expected 4 distinct/7 total operands, got %d/%d", h.DistinctOperands, h.TotalOperands)
+	}
+
+	// The file includes the package clause as well
+	if result.Halstead.TotalOperands != h.TotalOperands+1 {
+		t.Errorf("Expected file operands to include the package name, got %d", result.Halstead.TotalOperands)
+	}
 }
 
 // Helper functions for tests
@@ -367,3 +500,132 @@ func findClass(classes []ClassInfo, name string) *ClassInfo {
 	}
 	return nil
 }
+
+func TestGoParser_MemberAccess(t *testing.T) {
+	parser := NewGoParser()
+	code := `package main
+
+import "strings"
+
+type Cart struct {
+	items []Item
+	total int
+}
+
+func (c *Cart) Add(item Item, user *User) {
+	c.items = append(c.items, item)
+	c.recalculate()
+	name := strings.ToUpper(user.Name)
+	_ = user.GetAddress()
+	_ = item.Validate()
+	_ = name
+}`
+
+	result, err := parser.Parse("test.go", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := findFunction(result.Functions, "Add")
+	if fn == nil {
+		t.Fatal("Expected to find method 'Add'")
+	}
+	if fn.Receiver != "Cart" {
+		t.Errorf("Expected receiver 'Cart', got '%s'", fn.Receiver)
+	}
+	if !reflect.DeepEqual(fn.FieldsAccessed, []string{"items"}) {
+		t.Errorf("Expected fields [items], got %v", fn.FieldsAccessed)
+	}
+	if !reflect.DeepEqual(fn.MethodsCalled, []string{"recalculate"}) {
+		t.Errorf("Expected methods [recalculate], got %v", fn.MethodsCalled)
+	}
+	// Package selectors and ordinary method calls are not foreign data
+	if !reflect.DeepEqual(fn.ForeignData, []string{"user.GetAddress", "user.Name"}) {
+		t.Errorf("Expected foreign data [user.GetAddress user.Name], got %v", fn.ForeignData)
+	}
+}
+
+func TestGoParser_Calls(t *testing.T) {
+	parser := NewGoParser()
+	code := `package main
+
+import (
+	"fmt"
+	store "example.com/app/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+func (s *Server) Handle(req *Request) {
+	db := store.NewDB()
+	cache := &Cache{}
+	var log store.Logger
+	s.validate(req)
+	req.Parse()
+	db.Query()
+	cache.Get()
+	log.Print()
+	fmt.Println(len(req.Body))
+	helper()
+	_ = yaml.Marshal(nil)
+	s.client.Do()
+}`
+
+	result, err := parser.Parse("test.go", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := findFunction(result.Functions, "Handle")
+	if fn == nil {
+		t.Fatal("Expected to find method 'Handle'")
+	}
+
+	expected := []CallSite{
+		{Name: "NewDB", Package: "example.com/app/internal/storage", Line: 10},
+		{Name: "validate", Type: "Server", Method: true, Line: 13},
+		{Name: "Parse", Type: "Request", Method: true, Line: 14},
+		{Name: "Query", Type: "DB", Package: "example.com/app/internal/storage", Method: true, Line: 15},
+		{Name: "Get", Type: "Cache", Method: true, Line: 16},
+		{Name: "Print", Type: "Logger", Package: "example.com/app/internal/storage", Method: true, Line: 17},
+		{Name: "Println", Package: "fmt", Line: 18},
+		{Name: "helper", Line: 19},
+		{Name: "Marshal", Package: "gopkg.in/yaml.v3", Line: 20},
+		{Name: "Do", Method: true, Line: 21},
+	}
+	if !reflect.DeepEqual(fn.Calls, expected) {
+		t.Errorf("Expected calls:\n%+v\ngot:\n%+v", expected, fn.Calls)
+	}
+}
+
+func TestGoParser_References(t *testing.T) {
+	parser := NewGoParser()
+	code := `package main
+
+import "net/http"
+
+type server struct{}
+
+func (s *server) walk(n int) {
+	s.walk(n - 1)
+}
+
+func fib(n int) int {
+	return fib(n-1) + fib(n-2)
+}
+
+func main() {
+	http.HandleFunc("/", index)
+	register("handlers.health")
+}`
+
+	result, err := parser.Parse("test.go", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Declarations, receivers and recursive calls are not references
+	expected := []string{"HandleFunc", "health", "http", "index", "int", "n", "register", "s"}
+	if !reflect.DeepEqual(result.References, expected) {
+		t.Errorf("Expected references %v, got %v", expected, result.References)
+	}
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// registeredNamePattern matches string literals that may name a function or
+// type registered by name, such as "handleIndex" or "views.index"
+var registeredNamePattern = regexp.MustCompile(`^[A-Za-z_][\w.]*$`)
+
+// extractReferences returns the identifiers a file uses. Declared function and
+// type names, method receivers and a function's calls to itself are left out so
+// that declaring or recursing into a function doesn't count as using it. Names in
+// string literals count, since handlers are sometimes registered by name.
+func (g *GoParser) extractReferences(file *ast.File) []string {
+	refs := make(map[string]bool)
+
+	for _, decl := range file.Decls {
+		skip := make(map[*ast.Ident]bool)
+		self, recv := "", ""
+
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			skip[d.Name] = true
+			self = d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				if len(d.Recv.List[0].Names) > 0 {
+					recv = d.Recv.List[0].Names[0].Name
+				}
+				ast.Inspect(d.Recv.List[0].Type, func(n ast.Node) bool {
+					if ident, ok := n.(*ast.Ident); ok {
+						skip[ident] = true
+					}
+					return true
+				})
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					skip[typeSpec.Name] = true
+				}
+			}
+		}
+
+		// Selector names are members, not package-level identifiers
+		selectors := make(map[*ast.Ident]bool)
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.SelectorExpr:
+				selectors[x.Sel] = true
+				// A method calling itself through its receiver
+				if ident, ok := x.X.(*ast.Ident); ok && recv != "" && ident.Name == recv && x.Sel.Name == self {
+					skip[x.Sel] = true
+				}
+			case *ast.Ident:
+				if skip[x] || x.Name == "_" {
+					return true
+				}
+				// A function calling itself
+				if recv == "" && x.Name == self && !selectors[x] {
+					return true
+				}
+				refs[x.Name] = true
+			case *ast.BasicLit:
+				if x.Kind != token.STRING {
+					return true
+				}
+				if value, err := strconv.Unquote(x.Value); err == nil && registeredNamePattern.MatchString(value) {
+					refs[lastSegment(value)] = true
+				}
+			}
+			return true
+		})
+	}
+
+	return sortedKeys(refs)
+}
+
+// lastSegment returns the part of a dotted name after the last dot
+func lastSegment(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,40 @@
+package parser
+
+// halsteadCounter accumulates operator and operand occurrences for Halstead metrics
+type halsteadCounter struct {
+	operators map[string]int
+	operands  map[string]int
+}
+
+// newHalsteadCounter creates an empty Halstead counter
+func newHalsteadCounter() *halsteadCounter {
+	return &halsteadCounter{
+		operators: make(map[string]int),
+		operands:  make(map[string]int),
+	}
+}
+
+// addOperator records one occurrence of an operator
+func (h *halsteadCounter) addOperator(operator string) {
+	h.operators[operator]++
+}
+
+// addOperand records one occurrence of an operand
+func (h *halsteadCounter) addOperand(operand string) {
+	h.operands[operand]++
+}
+
+// counts returns the distinct and total operator and operand counts
+func (h *halsteadCounter) counts() HalsteadMetrics {
+	metrics := HalsteadMetrics{
+		DistinctOperators: len(h.operators),
+		DistinctOperands:  len(h.operands),
+	}
+	for _, count := range h.operators {
+		metrics.TotalOperators += count
+	}
+	for _, count := range h.operands {
+		metrics.TotalOperands += count
+	}
+	return metrics
+}
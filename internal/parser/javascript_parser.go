@@ -0,0 +1,593 @@
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JavaScriptParser implements the Parser interface for JavaScript and TypeScript
+// files. It recognizes declarations line by line and finds their bodies by
+// matching braces, with strings and comments blanked out, so it needs no full
+// grammar of either language.
+type JavaScriptParser struct {
+	// Regex patterns for JavaScript constructs
+	classPattern    *regexp.Regexp
+	functionPattern *regexp.Regexp
+	variablePattern *regexp.Regexp
+	propertyPattern *regexp.Regexp
+	methodPattern   *regexp.Regexp
+	importPattern   *regexp.Regexp
+	requirePattern  *regexp.Regexp
+	decisionPattern *regexp.Regexp
+	flowPattern     *regexp.Regexp
+	callPattern     *regexp.Regexp
+	tokenPattern    *regexp.Regexp
+}
+
+// jsClass is a class declaration and the offsets of the braces around its body
+type jsClass struct {
+	info      ClassInfo
+	bodyStart int
+	bodyEnd   int
+	depth     int // brace depth outside the class body
+}
+
+// NewJavaScriptParser creates a new JavaScript and TypeScript parser instance
+func NewJavaScriptParser() *JavaScriptParser {
+	const ident = `[A-Za-z_$][\w$]*`
+	return &JavaScriptParser{
+		classPattern:    regexp.MustCompile(`^\s*(export\s+(?:default\s+)?)?(abstract\s+)?class\s+(` + ident + `)(?:\s*<[^>{]*>)?(?:\s+extends\s+([\w$.]+))?`),
+		functionPattern: regexp.MustCompile(`^\s*(export\s+(?:default\s+)?)?(?:async\s+)?function\b\s*\*?\s*(` + ident + `)\s*(?:<[^>(]*>)?\s*\(`),
+		// const handle = (event) => { ... } and const handle = function (event) { ... }
+		variablePattern: regexp.MustCompile(`^\s*(export\s+)?(?:const|let|var)\s+(` + ident + `)\s*(?::[^=]*)?=\s*(?:async\s+)?(?:function\b\s*\*?\s*(?:` + ident + `)?\s*\(|(\([^()]*\)|` + ident + `)\s*(?::\s*[^=]+?)?\s*=>)`),
+		// handle: function (event) { ... } and handle: (event) => { ... } in object literals
+		propertyPattern: regexp.MustCompile(`^\s*(` + ident + `)\s*:\s*(?:async\s+)?(?:function\b\s*\*?\s*(?:` + ident + `)?\s*\(|(\([^()]*\)|` + ident + `)\s*=>)`),
+		// handle(event) { ... } in classes and object literals
+		methodPattern:   regexp.MustCompile(`^\s*((?:(?:public|private|protected|static|readonly|override|abstract|async|get|set)\s+)*)\*?\s*(#?` + ident + `)\s*(?:<[^>(]*>)?\s*\(`),
+		importPattern:   regexp.MustCompile(`^\s*(?:import|export)\s+(?:type\s+)?(?:[^'"]*?\s+from\s+)?['"]([^'"]+)['"]`),
+		requirePattern:  regexp.MustCompile(`\b(?:require|import)\s*\(\s*['"]([^'"]+)['"]\s*\)`),
+		decisionPattern: regexp.MustCompile(`\b(?:if|for|while|case|catch)\b|&&|\|\||\?\?|\?\.|\?`),
+		flowPattern:     regexp.MustCompile(`\belse\s+if\b|\b(?:if|else|for|while|do|switch|catch)\b|&&|\|\||\?\?|\?\.|\?|[{}\n]`),
+		// Calls of plain names and this methods, for recursion
+		callPattern: regexp.MustCompile(`(?:^|[^\w$.#]|\bthis\.)#?(` + ident + `)\s*\(`),
+		tokenPattern: regexp.MustCompile(`"(?:\\.|[^"\\\n])*"|'(?:\\.|[^'\\\n])*'|` + "`(?:\\\\.|[^`\\\\])*`" + `|//[^\n]*|/\*[\s\S]*?\*/|` +
+			`\d[\w.]*|[A-Za-z_$][\w$]*|===|!==|\*\*=|>>>=?|<<=|>>=|\.\.\.|=>|&&=?|\|\|=?|\?\?=?|\?\.|[-+*/%&|^<>=!]=|\+\+|--|<<|>>|\*\*|` +
+			`[-+*/%&|^~<>=!?.,:;()\[\]{}]`),
+	}
+}
+
+// Parse analyzes JavaScript or TypeScript source code and returns structured results
+func (p *JavaScriptParser) Parse(filePath string, content []byte) (*AnalysisResult, error) {
+	result := &AnalysisResult{
+		FilePath:     filePath,
+		Language:     p.languageForFile(filePath),
+		Functions:    []FunctionInfo{},
+		Classes:      []ClassInfo{},
+		Imports:      []string{},
+		Dependencies: []Dependency{},
+		Errors:       []ParseError{},
+		AnalyzedAt:   time.Now(),
+	}
+
+	source := string(content)
+	lines := strings.Split(source, "\n")
+	result.LineCount = len(lines)
+	result.Halstead = p.countHalstead(source)
+
+	masked := maskJavaScript(source)
+	maskedLines := strings.Split(masked, "\n")
+	lineStarts := make([]int, len(lines))
+	for i, offset := 1, 0; i < len(lines); i++ {
+		offset += len(lines[i-1]) + 1
+		lineStarts[i] = offset
+	}
+	lineOf := func(offset int) int {
+		return sort.SearchInts(lineStarts, offset+1)
+	}
+	depths := braceDepths(maskedLines)
+
+	var classes []*jsClass
+	for i, line := range maskedLines {
+		lineNum := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "export ") || strings.HasPrefix(trimmed, "module.exports") || strings.HasPrefix(trimmed, "exports.") {
+			result.ExportCount++
+		}
+
+		// Module paths are blanked in the masked source, so they are read from the original line
+		if strings.HasPrefix(trimmed, "import") || strings.HasPrefix(trimmed, "export") {
+			if matches := p.importPattern.FindStringSubmatch(lines[i]); matches != nil {
+				result.AddImport(matches[1], lineNum)
+				continue
+			}
+		}
+		if strings.Contains(line, "require") || strings.Contains(line, "import") {
+			for _, matches := range p.requirePattern.FindAllStringSubmatch(lines[i], -1) {
+				result.AddImport(matches[1], lineNum)
+			}
+		}
+
+		if matches := p.classPattern.FindStringSubmatchIndex(line); matches != nil {
+			open := strings.IndexByte(masked[lineStarts[i]+matches[1]:], '{')
+			if open < 0 {
+				continue
+			}
+			open += lineStarts[i] + matches[1]
+			close := matchingBrace(masked, open)
+			if close < 0 {
+				result.Errors = append(result.Errors, ParseError{Line: lineNum, Message: "unterminated body of class " + line[matches[6]:matches[7]]})
+				continue
+			}
+			class := &jsClass{
+				info: ClassInfo{
+					Name:         line[matches[6]:matches[7]],
+					LineStart:    lineNum,
+					LineEnd:      lineOf(close),
+					Methods:      []FunctionInfo{},
+					Fields:       []string{},
+					IsPublic:     matches[2] >= 0,
+					IsAbstract:   matches[4] >= 0,
+					HasDocstring: hasJSDoc(lines, lineNum),
+				},
+				bodyStart: open,
+				bodyEnd:   close,
+				depth:     depths[i],
+			}
+			if matches[8] >= 0 {
+				class.info.BaseClasses = []string{line[matches[8]:matches[9]]}
+			}
+			classes = append(classes, class)
+			continue
+		}
+
+		fn, ok := p.parseFunction(masked, lines, lineStarts[i], lineNum, lineOf)
+		if !ok {
+			continue
+		}
+
+		// A function directly inside a class body is a method of the class
+		var owner *jsClass
+		for _, class := range classes {
+			if lineStarts[i] > class.bodyStart && lineStarts[i] < class.bodyEnd && depths[i] == class.depth+1 {
+				owner = class
+			}
+		}
+		if owner != nil {
+			fn.IsPublic = fn.IsPublic && !strings.HasPrefix(fn.Name, "#")
+			owner.info.Methods = append(owner.info.Methods, fn)
+		} else {
+			result.Functions = append(result.Functions, fn)
+		}
+	}
+
+	for _, class := range classes {
+		class.info.MethodCount = len(class.info.Methods)
+		for _, method := range class.info.Methods {
+			class.info.Complexity += method.Complexity
+		}
+		result.Classes = append(result.Classes, class.info)
+	}
+
+	// Calculate total complexity
+	for _, fn := range result.Functions {
+		result.Complexity += fn.Complexity
+	}
+	for _, class := range result.Classes {
+		for _, method := range class.Methods {
+			result.Complexity += method.Complexity
+		}
+	}
+	result.ImportCount = len(result.Imports)
+
+	return result, nil
+}
+
+// parseFunction recognizes a function, arrow function or method declared on the
+// line starting at offset and measures its body
+func (p *JavaScriptParser) parseFunction(masked string, lines []string, offset, lineNum int, lineOf func(int) int) (FunctionInfo, bool) {
+	line := masked[offset:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+
+	var matches []int
+	group := func(n int) string {
+		if 2*n+1 >= len(matches) || matches[2*n] < 0 {
+			return ""
+		}
+		return line[matches[2*n]:matches[2*n+1]]
+	}
+
+	var name, arrowParams string
+	exported, method := false, false
+	switch {
+	case p.functionPattern.MatchString(line):
+		matches = p.functionPattern.FindStringSubmatchIndex(line)
+		exported, name = group(1) != "", group(2)
+	case p.variablePattern.MatchString(line):
+		matches = p.variablePattern.FindStringSubmatchIndex(line)
+		exported, name, arrowParams = group(1) != "", group(2), group(3)
+	case p.propertyPattern.MatchString(line):
+		matches = p.propertyPattern.FindStringSubmatchIndex(line)
+		name, arrowParams = group(1), group(2)
+	case p.methodPattern.MatchString(line):
+		matches = p.methodPattern.FindStringSubmatchIndex(line)
+		name = group(2)
+		if jsKeywords[name] {
+			return FunctionInfo{}, false
+		}
+		method = true
+	default:
+		return FunctionInfo{}, false
+	}
+	modifiers := line[:matches[1]]
+
+	var params string
+	var bodyStart, bodyEnd int
+	if arrowParams != "" {
+		// Arrow function: parameters are in the match, the body follows =>
+		params = strings.TrimSuffix(strings.TrimPrefix(arrowParams, "("), ")")
+		bodyStart = skipJSSpace(masked, offset+matches[1])
+		if bodyStart >= len(masked) {
+			// An arrow at the end of the input has no body
+			return FunctionInfo{}, false
+		}
+		if masked[bodyStart] == '{' {
+			bodyEnd = matchingBrace(masked, bodyStart)
+		} else {
+			bodyEnd = expressionEnd(masked, bodyStart)
+		}
+	} else {
+		open := offset + matches[1] - 1
+		close := matchingBrace(masked, open)
+		if close < 0 {
+			return FunctionInfo{}, false
+		}
+		params = masked[open+1 : close]
+		// The body follows the parameters and an optional return type
+		bodyStart = skipJSSpace(masked, close+1)
+		if bodyStart < len(masked) && masked[bodyStart] == ':' {
+			next := strings.IndexAny(masked[bodyStart:], "{;")
+			if next < 0 {
+				return FunctionInfo{}, false
+			}
+			bodyStart += next
+		}
+		if bodyStart >= len(masked) || masked[bodyStart] != '{' {
+			// Overloads, abstract methods and calls have no body
+			return FunctionInfo{}, false
+		}
+		bodyEnd = matchingBrace(masked, bodyStart)
+	}
+	if bodyEnd < bodyStart {
+		return FunctionInfo{}, false
+	}
+
+	lineEnd := lineOf(bodyEnd)
+	body := masked[bodyStart:bodyEnd]
+	parameters := p.parseParameters(params)
+	complexity := p.calculateComplexity(body)
+
+	return FunctionInfo{
+		Name:                 name,
+		LineStart:            lineNum,
+		LineEnd:              lineEnd,
+		Parameters:           parameters,
+		Complexity:           complexity,
+		CyclomaticComplexity: complexity,
+		CognitiveComplexity:  p.calculateCognitiveComplexity(body, name),
+		ParameterCount:       len(parameters),
+		IsPublic:             exported || (method && !strings.Contains(modifiers, "private ") && !strings.Contains(modifiers, "protected ")),
+		IsAsync:              strings.Contains(modifiers, "async "),
+		HasDocstring:         hasJSDoc(lines, lineNum),
+		Halstead:             p.countHalstead(strings.Join(lines[lineNum-1:lineEnd], "\n")),
+	}, true
+}
+
+// parseParameters returns the parameter names of a parameter list, dropping
+// defaults and type annotations. Destructured parameters keep their pattern.
+func (p *JavaScriptParser) parseParameters(paramStr string) []string {
+	var params []string
+	for _, param := range splitJSTopLevel(paramStr) {
+		param = strings.TrimSpace(param)
+		for _, modifier := range []string{"public ", "private ", "protected ", "readonly ", "override "} {
+			param = strings.TrimPrefix(param, modifier)
+		}
+		param = strings.TrimPrefix(param, "...")
+		if end := jsTopLevelIndex(param, ":="); end >= 0 {
+			param = param[:end]
+		}
+		param = strings.TrimSuffix(strings.TrimSpace(param), "?")
+		if param != "" && param != "this" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// calculateComplexity calculates cyclomatic complexity from a function body:
+// one plus each branch, loop, case, catch, logical operator and conditional
+func (p *JavaScriptParser) calculateComplexity(body string) int {
+	complexity := 1
+	for _, tok := range p.decisionPattern.FindAllString(body, -1) {
+		if tok != "?." {
+			complexity++
+		}
+	}
+	return complexity
+}
+
+// calculateCognitiveComplexity calculates cognitive complexity from a function
+// body. Breaks in linear flow add one plus their nesting depth; else branches,
+// sequences of logical operators and recursive calls add one each.
+func (p *JavaScriptParser) calculateCognitiveComplexity(body, funcName string) int {
+	complexity := 0
+	depth := -1 // the body's own brace
+	previous := ""
+	for _, tok := range p.flowPattern.FindAllString(body, -1) {
+		switch {
+		case tok == "{":
+			depth++
+		case tok == "}":
+			depth--
+		case tok == "\n":
+			previous = ""
+		case tok == "&&" || tok == "||" || tok == "??":
+			if tok != previous {
+				complexity++
+			}
+			previous = tok
+		case tok == "?.":
+			// Optional chaining is not a branch
+		case strings.HasPrefix(tok, "else"):
+			complexity++
+		default:
+			complexity += 1 + max(depth, 0)
+		}
+	}
+
+	name := strings.TrimPrefix(funcName, "#")
+	for _, call := range p.callPattern.FindAllStringSubmatch(body, -1) {
+		if call[1] == name {
+			complexity++
+		}
+	}
+	return complexity
+}
+
+// countHalstead counts Halstead operators and operands in JavaScript source.
+// Keywords, operators and punctuation are operators; names and literals are operands.
+func (p *JavaScriptParser) countHalstead(source string) HalsteadMetrics {
+	counter := newHalsteadCounter()
+
+	for _, tok := range p.tokenPattern.FindAllString(source, -1) {
+		switch {
+		case strings.HasPrefix(tok, "//") || strings.HasPrefix(tok, "/*"):
+			continue
+		case tok == ")" || tok == "]" || tok == "}":
+			// Brackets are counted once, at the opening bracket
+			continue
+		case jsKeywords[tok]:
+			counter.addOperator(tok)
+		case isJSName(tok) || (tok[0] >= '0' && tok[0] <= '9') || tok[0] == '"' || tok[0] == '\'' || tok[0] == '`':
+			counter.addOperand(tok)
+		default:
+			counter.addOperator(tok)
+		}
+	}
+
+	return counter.counts()
+}
+
+// jsKeywords are the JavaScript and TypeScript keywords counted as Halstead
+// operators, which are never names of declared functions
+var jsKeywords = map[string]bool{
+	"async": true, "await": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "export": true, "extends": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "let": true, "new": true,
+	"of": true, "return": true, "super": true, "switch": true, "throw": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true, "yield": true,
+}
+
+// isJSName reports whether a token is an identifier
+func isJSName(tok string) bool {
+	c := tok[0]
+	return c == '_' || c == '$' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// languageForFile returns TypeScript for TypeScript extensions and JavaScript otherwise
+func (p *JavaScriptParser) languageForFile(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".ts", ".tsx", ".mts", ".cts":
+		return "TypeScript"
+	default:
+		return "JavaScript"
+	}
+}
+
+// GetSupportedExtensions returns the file extensions supported by this parser
+func (p *JavaScriptParser) GetSupportedExtensions() []string {
+	return []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts"}
+}
+
+// GetLanguageName returns the human-readable language name
+func (p *JavaScriptParser) GetLanguageName() string {
+	return "JavaScript/TypeScript"
+}
+
+// maskJavaScript blanks out comments and the contents of string and template
+// literals, keeping quotes, newlines and offsets, so braces and keywords in them
+// are ignored
+func maskJavaScript(source string) string {
+	masked := []byte(source)
+	for i := 0; i < len(masked); i++ {
+		switch c := masked[i]; {
+		case c == '/' && i+1 < len(masked) && masked[i+1] == '/':
+			for ; i < len(masked) && masked[i] != '\n'; i++ {
+				masked[i] = ' '
+			}
+		case c == '/' && i+1 < len(masked) && masked[i+1] == '*':
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				end = len(source)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				if masked[i] != '\n' {
+					masked[i] = ' '
+				}
+			}
+			i--
+		case c == '"' || c == '\'' || c == '`':
+			for i++; i < len(masked) && masked[i] != c; i++ {
+				if masked[i] == '\n' && c != '`' {
+					break // unterminated string
+				}
+				escaped := masked[i] == '\\'
+				if masked[i] != '\n' {
+					masked[i] = ' '
+				}
+				if escaped && i+1 < len(masked) && masked[i+1] != '\n' {
+					i++
+					masked[i] = ' '
+				}
+			}
+		}
+	}
+	return string(masked)
+}
+
+// braceDepths returns the brace depth at the start of each line
+func braceDepths(lines []string) []int {
+	depths := make([]int, len(lines))
+	depth := 0
+	for i, line := range lines {
+		depths[i] = depth
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+	return depths
+}
+
+// matchingBrace returns the offset of the bracket closing the one at open, or
+// -1 when it is never closed
+func matchingBrace(masked string, open int) int {
+	opening := masked[open]
+	closing := map[byte]byte{'{': '}', '(': ')', '[': ']'}[opening]
+	depth := 0
+	for i := open; i < len(masked); i++ {
+		switch masked[i] {
+		case opening:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expressionEnd returns the offset where the expression body of an arrow
+// function starting at start ends: at a semicolon, comma, closing bracket of
+// the enclosing code or the end of its last line
+func expressionEnd(masked string, start int) int {
+	depth := 0
+	for i := start; i < len(masked); i++ {
+		switch masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ';', ',':
+			if depth == 0 {
+				return i
+			}
+		case '\n':
+			if depth == 0 && strings.TrimSpace(masked[start:i]) != "" {
+				return i
+			}
+		}
+	}
+	return len(masked) - 1
+}
+
+// skipJSSpace returns the offset of the first non-space character at or after i
+func skipJSSpace(masked string, i int) int {
+	for i < len(masked) && (masked[i] == ' ' || masked[i] == '\t' || masked[i] == '\n' || masked[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// splitJSTopLevel splits a parameter list on commas that are not nested in brackets
+func splitJSTopLevel(text string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// jsTopLevelIndex returns the offset of the first of chars not nested in
+// brackets, or -1
+func jsTopLevelIndex(text, chars string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case depth == 0 && strings.IndexByte(chars, c) >= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// hasJSDoc reports whether a JSDoc comment ends on the line before lineNum
+func hasJSDoc(lines []string, lineNum int) bool {
+	for i := lineNum - 2; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if !strings.HasSuffix(line, "*/") {
+			return false
+		}
+		for ; i >= 0; i-- {
+			if strings.Contains(lines[i], "/**") {
+				return true
+			}
+			if strings.Contains(lines[i], "/*") {
+				return false
+			}
+		}
+	}
+	return false
+}
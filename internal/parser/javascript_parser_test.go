@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJavaScriptParser_Parse_Functions(t *testing.T) {
+	parser := NewJavaScriptParser()
+	content := `import { ref } from 'vue';
+const api = require("./api");
+
+/**
+ * Formats a name.
+ */
+export function format(name, { upper = false } = {}) {
+  if (!name || name === "}") {
+    return "";
+  }
+  return upper ? name.toUpperCase() : name;
+}
+
+const double = (n) => n * 2;
+
+export const load = async (id) => {
+  try {
+    return await api.get(id);
+  } catch (err) {
+    return null;
+  }
+};
+
+describe("format", () => {
+  it("works", () => {});
+});
+`
+
+	result, err := parser.Parse("util.js", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.Language != "JavaScript" {
+		t.Errorf("Expected language 'JavaScript', got '%s'", result.Language)
+	}
+	if !reflect.DeepEqual(result.Imports, []string{"vue", "./api"}) {
+		t.Errorf("Expected imports vue and ./api, got %v", result.Imports)
+	}
+	if result.ImportLines["./api"] != 2 {
+		t.Errorf("Expected ./api to be imported on line 2, got %d", result.ImportLines["./api"])
+	}
+
+	expected := []struct {
+		name       string
+		start, end int
+		params     []string
+		complexity int
+		public     bool
+		async      bool
+	}{
+		{"format", 7, 12, []string{"name", "{ upper = false }"}, 4, true, false},
+		{"double", 14, 14, []string{"n"}, 1, false, false},
+		{"load", 16, 22, []string{"id"}, 2, true, true},
+	}
+	if len(result.Functions) != len(expected) {
+		t.Fatalf("Expected %d functions, got %+v", len(expected), result.Functions)
+	}
+	for i, want := range expected {
+		fn := result.Functions[i]
+		if fn.Name != want.name || fn.LineStart != want.start || fn.LineEnd != want.end {
+			t.Errorf("Function %d: expected %s at %d-%d, got %s at %d-%d", i, want.name, want.start, want.end, fn.Name, fn.LineStart, fn.LineEnd)
+		}
+		if !reflect.DeepEqual(fn.Parameters, want.params) {
+			t.Errorf("Function %s: expected parameters %v, got %v", want.name, want.params, fn.Parameters)
+		}
+		if fn.CyclomaticComplexity != want.complexity {
+			t.Errorf("Function %s: expected complexity %d, got %d", want.name, want.complexity, fn.CyclomaticComplexity)
+		}
+		if fn.IsPublic != want.public || fn.IsAsync != want.async {
+			t.Errorf("Function %s: expected public %v and async %v, got %v and %v", want.name, want.public, want.async, fn.IsPublic, fn.IsAsync)
+		}
+	}
+	if !result.Functions[0].HasDocstring {
+		t.Error("Expected format to have a JSDoc comment")
+	}
+}
+
+func TestJavaScriptParser_Parse_TypeScriptClass(t *testing.T) {
+	parser := NewJavaScriptParser()
+	content := `export class UserService extends BaseService {
+  private cache = new Map<string, User>();
+
+  constructor(private readonly http: HttpClient) {
+    super();
+  }
+
+  find(id: string): User | undefined;
+  async find(id?: string): Promise<User | undefined> {
+    for (const user of this.cache.values()) {
+      if (user.id === id && user.active) {
+        return user;
+      }
+    }
+    return undefined;
+  }
+
+  #reset(): void {
+    this.cache.clear();
+  }
+}
+`
+
+	result, err := parser.Parse("user.service.ts", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if result.Language != "TypeScript" {
+		t.Errorf("Expected language 'TypeScript', got '%s'", result.Language)
+	}
+	if len(result.Functions) != 0 {
+		t.Errorf("Expected methods only, got functions %+v", result.Functions)
+	}
+	if len(result.Classes) != 1 {
+		t.Fatalf("Expected 1 class, got %d", len(result.Classes))
+	}
+
+	class := result.Classes[0]
+	if class.Name != "UserService" || class.LineStart != 1 || class.LineEnd != 21 || !class.IsPublic {
+		t.Errorf("Unexpected class: %+v", class)
+	}
+	if !reflect.DeepEqual(class.BaseClasses, []string{"BaseService"}) {
+		t.Errorf("Expected base class BaseService, got %v", class.BaseClasses)
+	}
+
+	var names []string
+	for _, method := range class.Methods {
+		names = append(names, method.Name)
+	}
+	// The overload without a body is not a method of its own
+	if !reflect.DeepEqual(names, []string{"constructor", "find", "#reset"}) {
+		t.Fatalf("Expected methods constructor, find and #reset, got %v", names)
+	}
+	find := class.Methods[1]
+	if find.LineStart != 9 || find.LineEnd != 16 || find.CyclomaticComplexity != 4 || !find.IsAsync {
+		t.Errorf("Unexpected find method: %+v", find)
+	}
+	if !reflect.DeepEqual(find.Parameters, []string{"id"}) {
+		t.Errorf("Expected parameter id, got %v", find.Parameters)
+	}
+	if !reflect.DeepEqual(class.Methods[0].Parameters, []string{"http"}) {
+		t.Errorf("Expected the constructor parameter http, got %v", class.Methods[0].Parameters)
+	}
+	if class.Methods[2].IsPublic {
+		t.Error("Expected the private #reset method not to be public")
+	}
+	if class.Complexity != 1+4+1 {
+		t.Errorf("Expected class complexity 6, got %d", class.Complexity)
+	}
+}
+
+func TestJavaScriptParser_CognitiveComplexity(t *testing.T) {
+	parser := NewJavaScriptParser()
+	content := `function walk(node) {
+  if (node.left && node.right) {
+    for (const child of node.children) {
+      walk(child);
+    }
+  } else if (node.leaf) {
+    return node.value || node.fallback ? 1 : 0;
+  } else {
+    return 0;
+  }
+}
+`
+
+	result, err := parser.Parse("walk.js", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Functions) != 1 {
+		t.Fatalf("Expected 1 function, got %d", len(result.Functions))
+	}
+
+	// if +1, && +1, for +2 (nested), recursion +1, else if +1, || +1, ?: +2 (nested), else +1
+	if got := result.Functions[0].CognitiveComplexity; got != 10 {
+		t.Errorf("Expected cognitive complexity 10, got %d", got)
+	}
+}
+
+func TestJavaScriptParser_Parse_ArrowWithoutBody(t *testing.T) {
+	parser := NewJavaScriptParser()
+
+	for _, content := range []string{"const f = () =>", "const f = () =>\n", "const f = (a) =>\n\n"} {
+		result, err := parser.Parse("partial.js", []byte(content))
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", content, err)
+		}
+		if len(result.Functions) != 0 {
+			t.Errorf("Parse(%q): expected no functions, got %+v", content, result.Functions)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package parser
+
+import "sort"
+
+// sortedKeys returns the keys of a set in sorted order, or nil when it is empty
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// pythonCallPattern matches a call with an optional dotted qualifier, as in
+// run(), self.run() and pkg.module.run()
+var pythonCallPattern = regexp.MustCompile(`\b(?:([A-Za-z_][\w.]*)\.)?([A-Za-z_]\w*)\s*\(`)
+
+// pythonNotCalls are keywords that can precede a parenthesis and builtins that
+// never resolve to project code
+var pythonNotCalls = map[string]bool{
+	"and": true, "as": true, "assert": true, "await": true, "class": true, "def": true, "del": true,
+	"elif": true, "else": true, "except": true, "for": true, "from": true, "if": true, "import": true,
+	"in": true, "is": true, "lambda": true, "not": true, "or": true, "raise": true, "return": true,
+	"while": true, "with": true, "yield": true, "print": true, "len": true, "range": true, "str": true,
+	"int": true, "float": true, "bool": true, "list": true, "dict": true, "set": true, "tuple": true,
+	"isinstance": true, "issubclass": true, "super": true, "type": true, "enumerate": true, "zip": true,
+	"map": true, "filter": true, "sorted": true, "reversed": true, "min": true, "max": true, "sum": true,
+	"any": true, "all": true, "abs": true, "round": true, "open": true, "repr": true, "hasattr": true,
+	"getattr": true, "setattr": true, "iter": true, "next": true, "id": true, "hash": true,
+	"format": true, "vars": true, "callable": true, "object": true, "bytes": true, "frozenset": true,
+}
+
+// addImportNames records the names an import statement binds, mapped to what they
+// refer to: "import a.b as c" binds c to a.b, "from m import f" binds f to m.f
+func (p *PythonParser) addImportNames(names map[string]string, module, importStr string) {
+	for _, part := range strings.Split(strings.Trim(importStr, "()"), ",") {
+		target, alias, hasAlias := strings.Cut(strings.TrimSpace(part), " as ")
+		target, alias = strings.TrimSpace(target), strings.TrimSpace(alias)
+		if target == "" || target == "*" {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(module, "."):
+			// from . import sibling
+			target = module + target
+		case module != "":
+			target = module + "." + target
+		}
+		switch {
+		case hasAlias:
+			names[alias] = target
+		case module != "":
+			names[target[strings.LastIndex(target, ".")+1:]] = target
+		default:
+			// import a.b binds a, but a.b.f() is written out in full
+			names[target] = target
+		}
+	}
+}
+
+// extractCalls records the calls made in a function body. Calls on self and cls
+// are attributed to the enclosing class, calls through imported names to their
+// module, and calls on capitalized names to that class.
+func (p *PythonParser) extractCalls(body []string, lineStart int, className string, imports map[string]string) []CallSite {
+	var calls []CallSite
+
+	// Skip the signature line
+	for i, line := range body[1:] {
+		code := stripPythonStrings(line)
+		for _, match := range pythonCallPattern.FindAllStringSubmatchIndex(code, -1) {
+			name := code[match[4]:match[5]]
+			qualifier := ""
+			if match[2] >= 0 {
+				qualifier = code[match[2]:match[3]]
+			}
+			if pythonNotCalls[name] && qualifier == "" {
+				continue
+			}
+			if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+				continue
+			}
+			// Nested definitions are not calls
+			if before := strings.TrimSpace(code[:match[0]]); strings.HasSuffix(before, "def") || strings.HasSuffix(before, "class") {
+				continue
+			}
+
+			site := CallSite{Name: name, Line: lineStart + 1 + i}
+			switch {
+			case match[0] > 0 && code[match[0]-1] == '.':
+				// Method on the result of an expression, such as a().b()
+				site.Method = true
+			case qualifier == "":
+				if target, ok := imports[name]; ok {
+					if dot := strings.LastIndex(target, "."); dot >= 0 {
+						site.Package = target[:dot]
+					}
+				}
+			case qualifier == "self" || qualifier == "cls":
+				site.Method = true
+				site.Type = className
+			case imports[qualifier] != "":
+				site.Package = imports[qualifier]
+			case imports[strings.Split(qualifier, ".")[0]] != "":
+				head, rest, _ := strings.Cut(qualifier, ".")
+				site.Package = imports[head] + "." + rest
+			case isCapitalized(qualifier):
+				site.Type = qualifier
+			default:
+				site.Method = true
+			}
+			calls = append(calls, site)
+		}
+	}
+	return calls
+}
+
+// isCapitalized reports whether a name starts with an upper case letter, the
+// Python convention for class names
+func isCapitalized(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -16,6 +17,9 @@ type PythonParser struct {
 	importPattern        *regexp.Regexp
 	fromImportPattern    *regexp.Regexp
 	decoratorPattern     *regexp.Regexp
+	tokenPattern         *regexp.Regexp
+	memberPattern        *regexp.Regexp
+	selfAssignPattern    *regexp.Regexp
 }
 
 // NewPythonParser creates a new Python parser instance
@@ -28,6 +32,11 @@ func NewPythonParser() *PythonParser {
 		importPattern:        regexp.MustCompile(`^import\s+(.+)$`),
 		fromImportPattern:    regexp.MustCompile(`^from\s+(\S+)\s+import\s+(.+)$`),
 		decoratorPattern:     regexp.MustCompile(`^(\s*)@(\w+(?:\.\w+)*)`),
+		tokenPattern: regexp.MustCompile(`[rRbBuUfF]{0,2}"""[\s\S]*?"""|[rRbBuUfF]{0,2}'''[\s\S]*?'''|` +
+			`[rRbBuUfF]{0,2}"(?:\\.|[^"\\\n])*"|[rRbBuUfF]{0,2}'(?:\\.|[^'\\\n])*'|#[^\n]*|` +
+			`\d[\w.]*|[A-Za-z_]\w*|\*\*=?|//=?|>>=?|<<=?|->|:=|[-+*/%@&|^<>=!]=|[-+*/%@&|^~<>=.,:;()\[\]{}]`),
+		memberPattern:     regexp.MustCompile(`\b([A-Za-z_]\w*)\.([A-Za-z_]\w*)(\s*\()?`),
+		selfAssignPattern: regexp.MustCompile(`\bself\.([A-Za-z_]\w*)\s*(?:\*\*|//|>>|<<|[-+*/%@&|^])?=(?:[^=]|$)`),
 	}
 }
 
@@ -46,10 +55,12 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 
 	lines := strings.Split(string(content), "\n")
 	result.LineCount = len(lines)
+	result.Halstead = p.countHalstead(string(content))
 
 	// Track current context for nested structures
 	var currentClass *ClassInfo
 	var decorators []string
+	importNames := make(map[string]string)
 
 	for lineNum, line := range lines {
 		lineNum++ // Convert to 1-based indexing
@@ -68,17 +79,25 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 
 		// Handle imports
 		if matches := p.importPattern.FindStringSubmatch(trimmedLine); matches != nil {
-			imports := p.parseImports(matches[1])
-			result.Imports = append(result.Imports, imports...)
+			for _, imp := range p.parseImports(matches[1]) {
+				result.AddImport(imp, lineNum)
+			}
+			p.addImportNames(importNames, "", matches[1])
 			continue
 		}
 
 		if matches := p.fromImportPattern.FindStringSubmatch(trimmedLine); matches != nil {
 			module := matches[1]
 			imports := p.parseImports(matches[2])
+			// "from . import models" names the module .models, not ..models
+			prefix := module + "."
+			if strings.Trim(module, ".") == "" {
+				prefix = module
+			}
 			for _, imp := range imports {
-				result.Imports = append(result.Imports, module+"."+imp)
+				result.AddImport(prefix+imp, lineNum)
 			}
+			p.addImportNames(importNames, module, matches[2])
 			continue
 		}
 
@@ -112,6 +131,7 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 				BaseClasses:  baseClasses,
 				IsPublic:     p.isPublicClass(className),
 				HasDocstring: p.hasDocstring(lines, lineNum),
+				IsAbstract:   p.hasAbstractBase(baseClasses),
 			}
 
 			// Clear decorators after use
@@ -138,19 +158,23 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 			}
 			params := p.parseParameters(funcMatches[3])
 			returnType := strings.TrimSpace(funcMatches[4])
+			lineEnd := p.findBlockEnd(lines, lineNum-1, indent)
 
 			funcInfo := FunctionInfo{
 				Name:                 funcName,
 				LineStart:            lineNum,
-				LineEnd:              lineNum, // Will be updated when function ends
+				LineEnd:              lineEnd,
 				Parameters:           params,
 				ReturnType:           returnType,
 				Complexity:           p.calculateComplexity(lines, lineNum-1, indent),
 				CyclomaticComplexity: p.calculateComplexity(lines, lineNum-1, indent),
+				CognitiveComplexity:  p.calculateCognitiveComplexity(lines, lineNum-1, indent, funcMatches[2]),
 				ParameterCount:       len(params),
 				IsPublic:             p.isPublicFunction(funcMatches[2]),
 				IsAsync:              isAsync,
 				HasDocstring:         p.hasDocstring(lines, lineNum),
+				Halstead:             p.countHalstead(strings.Join(lines[lineNum-1:lineEnd], "\n")),
+				Decorators:           decorators,
 			}
 
 			// Determine if this is a method or standalone function
@@ -160,14 +184,26 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 					classIndent = p.getIndentLevel(lines[currentClass.LineStart-2])
 				}
 				if indent > classIndent {
-					// This is a method
+					// This is a method; abstract methods make the class abstract
+					funcInfo.Calls = p.extractCalls(lines[lineNum-1:lineEnd], lineNum, currentClass.Name, importNames)
+					assigned := p.extractMemberAccess(lines[lineNum-1:lineEnd], params, &funcInfo)
+					for _, field := range assigned {
+						if !slices.Contains(currentClass.Fields, field) {
+							currentClass.Fields = append(currentClass.Fields, field)
+						}
+					}
 					currentClass.Methods = append(currentClass.Methods, funcInfo)
+					if p.hasAbstractDecorator(decorators) {
+						currentClass.IsAbstract = true
+					}
 				} else {
 					// This is a standalone function
+					funcInfo.Calls = p.extractCalls(lines[lineNum-1:lineEnd], lineNum, "", importNames)
 					result.Functions = append(result.Functions, funcInfo)
 				}
 			} else {
 				// This is a standalone function
+				funcInfo.Calls = p.extractCalls(lines[lineNum-1:lineEnd], lineNum, "", importNames)
 				result.Functions = append(result.Functions, funcInfo)
 			}
 
@@ -193,6 +229,9 @@ func (p *PythonParser) Parse(filePath string, content []byte) (*AnalysisResult,
 		result.Classes = append(result.Classes, *currentClass)
 	}
 
+	result.References = p.extractReferences(lines)
+	result.Exports = p.extractExports(string(content))
+
 	// Calculate total complexity
 	for _, fn := range result.Functions {
 		result.Complexity += fn.Complexity
@@ -259,6 +298,71 @@ func (p *PythonParser) parseBaseClasses(baseStr string) []string {
 	return bases
 }
 
+// hasAbstractBase checks whether a class derives from ABC or Protocol, or uses the ABCMeta metaclass
+func (p *PythonParser) hasAbstractBase(bases []string) bool {
+	for _, base := range bases {
+		switch strings.ReplaceAll(base, " ", "") {
+		case "ABC", "abc.ABC", "Protocol", "typing.Protocol", "metaclass=ABCMeta", "metaclass=abc.ABCMeta":
+			return true
+		}
+	}
+	return false
+}
+
+// hasAbstractDecorator checks whether a method is marked with @abstractmethod
+func (p *PythonParser) hasAbstractDecorator(decorators []string) bool {
+	for _, decorator := range decorators {
+		if decorator == "abstractmethod" || decorator == "abc.abstractmethod" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractMemberAccess records the attributes and methods of self a method uses and
+// the attributes of its parameters it reads directly or through getters. It returns
+// the attributes the method assigns on self, which become the class's fields.
+func (p *PythonParser) extractMemberAccess(body []string, params []string, funcInfo *FunctionInfo) []string {
+	paramNames := make(map[string]bool)
+	for _, param := range params {
+		name := strings.TrimLeft(strings.TrimSpace(strings.SplitN(param, ":", 2)[0]), "*")
+		if name != "self" && name != "cls" {
+			paramNames[name] = true
+		}
+	}
+
+	fields := make(map[string]bool)
+	methods := make(map[string]bool)
+	foreign := make(map[string]bool)
+	var assigned []string
+
+	// Skip the signature line
+	for _, line := range body[1:] {
+		code := stripPythonStrings(line)
+		for _, match := range p.memberPattern.FindAllStringSubmatch(code, -1) {
+			object, member, isCall := match[1], match[2], match[3] != ""
+			switch {
+			case object == "self" && isCall:
+				methods[member] = true
+			case object == "self":
+				fields[member] = true
+			case paramNames[object] && (!isCall || strings.HasPrefix(member, "get")):
+				foreign[object+"."+member] = true
+			}
+		}
+		for _, match := range p.selfAssignPattern.FindAllStringSubmatch(code, -1) {
+			if !slices.Contains(assigned, match[1]) {
+				assigned = append(assigned, match[1])
+			}
+		}
+	}
+
+	funcInfo.FieldsAccessed = sortedKeys(fields)
+	funcInfo.MethodsCalled = sortedKeys(methods)
+	funcInfo.ForeignData = sortedKeys(foreign)
+	return assigned
+}
+
 // parseParameters parses function parameters
 func (p *PythonParser) parseParameters(paramStr string) []string {
 	if paramStr == "" {
@@ -341,6 +445,178 @@ func (p *PythonParser) calculateComplexity(lines []string, startLine, baseIndent
 	return complexity
 }
 
+// findBlockEnd returns the 1-based line number of the last line of the indented
+// block that starts on the given 0-based line
+func (p *PythonParser) findBlockEnd(lines []string, startLine, baseIndent int) int {
+	end := startLine + 1
+	for i := startLine + 1; i < len(lines); i++ {
+		trimmedLine := strings.TrimSpace(lines[i])
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+		if p.getIndentLevel(lines[i]) <= baseIndent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// countHalstead counts Halstead operators and operands in Python source. Keywords,
+// operators and punctuation are operators; names and literals are operands.
+func (p *PythonParser) countHalstead(source string) HalsteadMetrics {
+	counter := newHalsteadCounter()
+
+	for _, tok := range p.tokenPattern.FindAllString(source, -1) {
+		switch {
+		case strings.HasPrefix(tok, "#"):
+			continue
+		case tok == ")" || tok == "]" || tok == "}":
+			// Brackets are counted once, at the opening bracket
+			continue
+		case pythonKeywords[tok]:
+			counter.addOperator(tok)
+		case isPythonName(tok) || (tok[0] >= '0' && tok[0] <= '9') || tok[0] == '"' || tok[0] == '\'':
+			// Names, numbers and string literals (including prefixed strings like r"...")
+			counter.addOperand(tok)
+		default:
+			counter.addOperator(tok)
+		}
+	}
+
+	return counter.counts()
+}
+
+// pythonKeywords are the Python keywords counted as Halstead operators
+var pythonKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true,
+	"or": true, "pass": true, "raise": true, "return": true, "try": true, "while": true,
+	"with": true, "yield": true,
+}
+
+// isPythonName reports whether a token is an identifier
+func isPythonName(tok string) bool {
+	c := tok[0]
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// calculateCognitiveComplexity calculates cognitive complexity for a Python function.
+// Breaks in linear flow add one plus their nesting depth; elif/else branches,
+// sequences of boolean operators and recursive calls add one each.
+func (p *PythonParser) calculateCognitiveComplexity(lines []string, startLine, baseIndent int, funcName string) int {
+	complexity := 0
+	recursionPattern := regexp.MustCompile(`(?:^|[^\w.]|\b(?:self|cls)\.)` + regexp.QuoteMeta(funcName) + `\s*\(`)
+
+	// Indentation of the structures enclosing the current line
+	var nesting []int
+
+	for i := startLine + 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmedLine := strings.TrimSpace(line)
+
+		// Skip empty lines and comments
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			continue
+		}
+
+		// If we've reached a line with equal or less indentation, we're done
+		currentIndent := p.getIndentLevel(line)
+		if currentIndent <= baseIndent {
+			break
+		}
+
+		// Leave structures whose bodies have ended
+		for len(nesting) > 0 && currentIndent <= nesting[len(nesting)-1] {
+			nesting = nesting[:len(nesting)-1]
+		}
+		depth := len(nesting)
+
+		code := stripPythonStrings(trimmedLine)
+
+		switch {
+		case strings.HasPrefix(code, "if "),
+			strings.HasPrefix(code, "for "),
+			strings.HasPrefix(code, "async for "),
+			strings.HasPrefix(code, "while "),
+			strings.HasPrefix(code, "except ") || strings.HasPrefix(code, "except:"),
+			strings.HasPrefix(code, "match "):
+			complexity += 1 + depth
+			nesting = append(nesting, currentIndent)
+		case strings.HasPrefix(code, "elif "), strings.HasPrefix(code, "else:"):
+			complexity++
+			nesting = append(nesting, currentIndent)
+		case strings.HasPrefix(code, "def "), strings.HasPrefix(code, "async def "):
+			// Nested functions increase nesting without adding to the score themselves
+			nesting = append(nesting, currentIndent)
+		}
+
+		// Conditional expressions: "a if cond else b"
+		if strings.Contains(code, " if ") && strings.Contains(code, " else ") && !strings.HasSuffix(code, ":") {
+			complexity += 1 + depth
+		}
+
+		complexity += p.countBooleanSequences(code)
+
+		if recursionPattern.MatchString(code) {
+			complexity++
+		}
+	}
+
+	return complexity
+}
+
+// countBooleanSequences counts runs of the same boolean operator, so
+// "a and b and c" scores 1 and "a and b or c" scores 2
+func (p *PythonParser) countBooleanSequences(code string) int {
+	count := 0
+	previous := ""
+	for _, word := range strings.FieldsFunc(code, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')' || r == '\t' || r == ':'
+	}) {
+		if word != "and" && word != "or" {
+			continue
+		}
+		if word != previous {
+			count++
+		}
+		previous = word
+	}
+	return count
+}
+
+// stripPythonStrings blanks out string literals so keywords inside them are ignored
+func stripPythonStrings(line string) string {
+	var b strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+				b.WriteRune(r)
+				continue
+			}
+			b.WriteRune('_')
+		case r == '"' || r == '\'':
+			quote = r
+			b.WriteRune(r)
+		case r == '#':
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
 // GetSupportedExtensions returns the file extensions supported by this parser
 func (p *PythonParser) GetSupportedExtensions() []string {
 	return []string{".py", ".pyw"}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -152,13 +153,50 @@ func TestPythonParser_Parse_ClassWithInheritance(t *testing.T) {
 	}
 }
 
+func TestPythonParser_Parse_AbstractClasses(t *testing.T) {
+	parser := NewPythonParser()
+	content := `class Repository(ABC):
+    def find(self, key):
+        pass
+
+class Shape:
+    @abstractmethod
+    def area(self):
+        pass
+
+class Meta(metaclass=ABCMeta):
+    pass
+
+class Square(Shape):
+    def area(self):
+        return self.side ** 2`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := map[string]bool{"Repository": true, "Shape": true, "Meta": true, "Square": false}
+	if len(result.Classes) != len(expected) {
+		t.Fatalf("Expected %d classes, got %d", len(expected), len(result.Classes))
+	}
+	for _, class := range result.Classes {
+		if class.IsAbstract != expected[class.Name] {
+			t.Errorf("Expected %s IsAbstract=%v, got %v", class.Name, expected[class.Name], class.IsAbstract)
+		}
+	}
+}
+
 func TestPythonParser_Parse_Imports(t *testing.T) {
 	parser := NewPythonParser()
 	content := `import os
 import sys, json
 from collections import defaultdict, Counter
 from typing import List, Dict as DictType
-import numpy as np`
+import numpy as np
+from . import models
+from .. import settings
+from ..services.auth import login`
 
 	result, err := parser.Parse("test.py", []byte(content))
 	if err != nil {
@@ -174,6 +212,9 @@ import numpy as np`
 		"typing.List",
 		"typing.Dict",
 		"numpy",
+		".models",
+		"..settings",
+		"..services.auth.login",
 	}
 
 	if len(result.Imports) != len(expectedImports) {
@@ -185,6 +226,9 @@ import numpy as np`
 			t.Errorf("Expected import '%s', got '%s'", expected, result.Imports[i])
 		}
 	}
+	if result.ImportLines["json"] != 2 || result.ImportLines["typing.Dict"] != 4 || result.ImportLines["numpy"] != 5 {
+		t.Errorf("Expected import lines 2, 4 and 5, got %v", result.ImportLines)
+	}
 }
 
 func TestPythonParser_Parse_ComplexityCalculation(t *testing.T) {
@@ -546,3 +590,219 @@ func TestPythonParser_Parse_ExceptionHandling(t *testing.T) {
 		t.Errorf("Expected complexity >= 6 for function with multiple exception handlers, got %d", fn.Complexity)
 	}
 }
+
+func TestPythonParser_Parse_CognitiveComplexity(t *testing.T) {
+	parser := NewPythonParser()
+	content := `def sum_of_primes(max):
+    total = 0
+    for i in range(1, max + 1):
+        for j in range(2, i):
+            if i % j == 0:
+                break
+        else:
+            total += i
+    return total
+
+def classify(a, b, c, n):
+    if a and b or c:
+        return 1
+    elif n > 0:
+        return classify(a, b, c, n - 1)
+    else:
+        label = "and or" if n else "none"
+    try:
+        risky()
+    except ValueError:
+        pass
+    return 0`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// for (+1), nested for (+2), nested if (+3), for-else (+1)
+	primes := findFunction(result.Functions, "sum_of_primes")
+	if primes == nil {
+		t.Fatal("Expected to find function 'sum_of_primes'")
+	}
+	if primes.CognitiveComplexity != 7 {
+		t.Errorf("Expected cognitive complexity 7 for sum_of_primes, got %d", primes.CognitiveComplexity)
+	}
+
+	// if (+1), and/or (+2), elif (+1), recursion (+1), else (+1),
+	// conditional expression inside else (+2), except (+1)
+	classify := findFunction(result.Functions, "classify")
+	if classify == nil {
+		t.Fatal("Expected to find function 'classify'")
+	}
+	if classify.CognitiveComplexity != 9 {
+		t.Errorf("Expected cognitive complexity 9 for classify, got %d", classify.CognitiveComplexity)
+	}
+}
+
+func TestPythonParser_Parse_HalsteadCounts(t *testing.T) {
+	parser := NewPythonParser()
+	content := `def add(a, b):
+    """Add two numbers."""
+    # comments are ignored
+    return a + b
+
+x = add(1, 2)`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := findFunction(result.Functions, "add")
+	if fn == nil {
+		t.Fatal("Expected to find function 'add'")
+	}
+	if fn.LineEnd != 4 {
+		t.Errorf("Expected function to end at line 4, got %d", fn.LineEnd)
+	}
+
+	// Operators: def ( , : return +  -> 6 distinct, 6 total
+	// Operands: add a b "docstring" a b -> 4 distinct, 6 total
+	h := fn.Halstead
+	if h.DistinctOperators != 6 || h.TotalOperators != 6 {
+		t.Errorf("Expected 6 distinct/6 total operators, got %d/%d", h.DistinctOperators, h.TotalOperators)
+	}
+	if h.DistinctOperands != 4 || h.TotalOperands != 6 {
+		t.Errorf("Expected 4 distinct/6 total operands, got %d/%d", h.DistinctOperands, h.TotalOperands)
+	}
+
+	if result.Halstead.TotalOperands <= h.TotalOperands {
+		t.Errorf("Expected file operands to include module-level code, got %d", result.Halstead.TotalOperands)
+	}
+}
+
+func TestPythonParser_Parse_MemberAccess(t *testing.T) {
+	parser := NewPythonParser()
+	content := `class Cart:
+    def __init__(self):
+        self.items = []
+        self.total = 0
+
+    def add(self, item, user: User):
+        self.items.append(item)
+        self.total += item.price
+        self.recalculate()
+        label = "self.ignored"
+        return user.get_address()`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Classes) != 1 {
+		t.Fatalf("Expected 1 class, got %d", len(result.Classes))
+	}
+	class := result.Classes[0]
+	if !reflect.DeepEqual(class.Fields, []string{"items", "total"}) {
+		t.Errorf("Expected fields [items total], got %v", class.Fields)
+	}
+
+	add := class.Methods[1]
+	if !reflect.DeepEqual(add.FieldsAccessed, []string{"items", "total"}) {
+		t.Errorf("Expected fields accessed [items total], got %v", add.FieldsAccessed)
+	}
+	if !reflect.DeepEqual(add.MethodsCalled, []string{"recalculate"}) {
+		t.Errorf("Expected methods called [recalculate], got %v", add.MethodsCalled)
+	}
+	if !reflect.DeepEqual(add.ForeignData, []string{"item.price", "user.get_address"}) {
+		t.Errorf("Expected foreign data [item.price user.get_address], got %v", add.ForeignData)
+	}
+}
+
+func TestPythonParser_Parse_Calls(t *testing.T) {
+	parser := NewPythonParser()
+	content := `import json
+import os.path as osp
+from .models import User as Account, Order
+from app import services
+
+class Checkout:
+    def run(self, items):
+        order = Order(items)
+        self.validate(order)
+        Account.lookup(order.user_id)
+        services.billing.charge(order)
+        data = json.dumps(order.to_dict())
+        print(len(items), "done()")
+        return osp.join(helper(), data).strip()
+
+def helper():
+    return build().value()`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Classes) != 1 || len(result.Classes[0].Methods) != 1 {
+		t.Fatalf("Expected 1 class with 1 method, got %+v", result.Classes)
+	}
+	expected := []CallSite{
+		{Name: "Order", Package: ".models", Line: 8},
+		{Name: "validate", Type: "Checkout", Method: true, Line: 9},
+		{Name: "lookup", Package: ".models.User", Line: 10},
+		{Name: "charge", Package: "app.services.billing", Line: 11},
+		{Name: "dumps", Package: "json", Line: 12},
+		{Name: "to_dict", Method: true, Line: 12},
+		{Name: "join", Package: "os.path", Line: 14},
+		{Name: "helper", Line: 14},
+		{Name: "strip", Method: true, Line: 14},
+	}
+	if calls := result.Classes[0].Methods[0].Calls; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls:\n%+v\ngot:\n%+v", expected, calls)
+	}
+
+	if len(result.Functions) != 1 {
+		t.Fatalf("Expected 1 function, got %d", len(result.Functions))
+	}
+	expected = []CallSite{
+		{Name: "build", Line: 17},
+		{Name: "value", Method: true, Line: 17},
+	}
+	if calls := result.Functions[0].Calls; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Expected calls:\n%+v\ngot:\n%+v", expected, calls)
+	}
+}
+
+func TestPythonParser_Parse_References(t *testing.T) {
+	parser := NewPythonParser()
+	content := `__all__ = [
+    "public_api",
+    'Client',
+]
+
+@app.route("/")
+def index():
+    return render("views.home")
+
+class Client:
+    @property
+    def name(self):
+        return self._name`
+
+	result, err := parser.Parse("test.py", []byte(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Exports, []string{"Client", "public_api"}) {
+		t.Errorf("Expected exports [Client public_api], got %v", result.Exports)
+	}
+	if len(result.Functions) != 1 || !reflect.DeepEqual(result.Functions[0].Decorators, []string{"app.route"}) {
+		t.Errorf("Expected index decorated with app.route, got %+v", result.Functions)
+	}
+
+	// Names after def and class are declarations; quoted names are references
+	expected := []string{"Client", "__all__", "_name", "app", "home", "property", "public_api", "render", "return", "route", "self"}
+	if !reflect.DeepEqual(result.References, expected) {
+		t.Errorf("Expected references %v, got %v", expected, result.References)
+	}
+}
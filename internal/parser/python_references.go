@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Patterns for names declared and used in Python code
+var (
+	pythonNamePattern    = regexp.MustCompile(`[A-Za-z_]\w*`)
+	pythonDeclPattern    = regexp.MustCompile(`\b(?:def|class)\s+[A-Za-z_]\w*`)
+	pythonStringPattern  = regexp.MustCompile(`["']([A-Za-z_][\w.]*)["']`)
+	pythonAllPattern     = regexp.MustCompile(`(?m)^__all__\s*\+?=\s*[\[(]([^\])]*)[\])]`)
+	pythonAllNamePattern = regexp.MustCompile(`["']([A-Za-z_]\w*)["']`)
+)
+
+// extractReferences returns the names used in a module other than in def and
+// class statements. Names in string literals count, since views and handlers
+// are often registered by dotted name.
+func (p *PythonParser) extractReferences(lines []string) []string {
+	refs := make(map[string]bool)
+	for _, line := range lines {
+		for _, match := range pythonStringPattern.FindAllStringSubmatch(line, -1) {
+			refs[lastSegment(match[1])] = true
+		}
+		code := pythonDeclPattern.ReplaceAllString(stripPythonStrings(line), "")
+		for _, name := range pythonNamePattern.FindAllString(code, -1) {
+			// Blanked out string contents are all underscores
+			if strings.Trim(name, "_") != "" {
+				refs[name] = true
+			}
+		}
+	}
+	return sortedKeys(refs)
+}
+
+// extractExports returns the names listed in the module's __all__
+func (p *PythonParser) extractExports(content string) []string {
+	exports := make(map[string]bool)
+	for _, match := range pythonAllPattern.FindAllStringSubmatch(content, -1) {
+		for _, name := range pythonAllNamePattern.FindAllStringSubmatch(match[1], -1) {
+			exports[name[1]] = true
+		}
+	}
+	return sortedKeys(exports)
+}
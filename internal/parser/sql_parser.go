@@ -0,0 +1,881 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SQLParser implements the Parser interface for SQL schema and migration files
+type SQLParser struct {
+	// Regex patterns for SQL constructs
+	tablePattern      *regexp.Regexp
+	viewPattern       *regexp.Regexp
+	routinePattern    *regexp.Regexp
+	triggerPattern    *regexp.Regexp
+	triggerOnPattern  *regexp.Regexp
+	returnsPattern    *regexp.Regexp
+	referencePattern  *regexp.Regexp
+	sourcePattern     *regexp.Regexp
+	alterPattern      *regexp.Regexp
+	dropPattern       *regexp.Regexp
+	insertPattern     *regexp.Regexp
+	updatePattern     *regexp.Regexp
+	indexPattern      *regexp.Regexp
+	truncatePattern   *regexp.Regexp
+	ctePattern        *regexp.Regexp
+	delimiterPattern  *regexp.Regexp
+	wordPattern       *regexp.Regexp
+	identifierPattern string
+}
+
+// sqlStatement is a single statement split out of a SQL file
+type sqlStatement struct {
+	text      string
+	lineStart int
+	lineEnd   int
+}
+
+// NewSQLParser creates a new SQL parser instance
+func NewSQLParser() *SQLParser {
+	ident := `((?:[\w$]+|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\])(?:\s*\.\s*(?:[\w$]+|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\]))*)`
+
+	return &SQLParser{
+		tablePattern:      regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + ident + `\s*\(`),
+		viewPattern:       regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:TEMP(?:ORARY)?|RECURSIVE|MATERIALIZED)\s+)*VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?` + ident),
+		routinePattern:    regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:DEFINER\s*=\s*\S+\s+)?(FUNCTION|PROCEDURE|PROC)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + ident + `\s*\(?`),
+		triggerPattern:    regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:CONSTRAINT\s+)?TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?` + ident),
+		triggerOnPattern:  regexp.MustCompile(`(?is)\bON\s+` + ident),
+		returnsPattern:    regexp.MustCompile(`(?is)^\s*RETURNS\s+(.+?)\s*(?:\bAS\b|\bLANGUAGE\b|\bBEGIN\b|\bDETERMINISTIC\b|\bIMMUTABLE\b|\bSTABLE\b|\bVOLATILE\b|\$|$)`),
+		referencePattern:  regexp.MustCompile(`(?is)\bREFERENCES\s+` + ident),
+		sourcePattern:     regexp.MustCompile(`(?is)\b(?:FROM|JOIN)\s+(?:ONLY\s+)?` + ident),
+		alterPattern:      regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + ident),
+		dropPattern:       regexp.MustCompile(`(?is)^DROP\s+(?:TABLE|VIEW|MATERIALIZED\s+VIEW)\s+(?:IF\s+EXISTS\s+)?(.+)`),
+		insertPattern:     regexp.MustCompile(`(?is)\bINSERT\s+(?:IGNORE\s+)?INTO\s+` + ident),
+		updatePattern:     regexp.MustCompile(`(?is)(?:^|;|\bTHEN\b|\bBEGIN\b|\bDO\b|\bLOOP\b|\bAS\b|\$\$)\s*UPDATE\s+(?:ONLY\s+)?` + ident + `\s+SET\b`),
+		indexPattern:      regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\b.*?\bON\s+(?:ONLY\s+)?` + ident),
+		truncatePattern:   regexp.MustCompile(`(?is)^TRUNCATE\s+(?:TABLE\s+)?(?:ONLY\s+)?` + ident),
+		ctePattern:        regexp.MustCompile(`(?is)(?:\bWITH\s+(?:RECURSIVE\s+)?|,\s*)([\w$]+)\s*(?:\([^)]*\)\s*)?AS\s*(?:NOT\s+)?(?:MATERIALIZED\s+)?\(`),
+		delimiterPattern:  regexp.MustCompile(`(?i)^\s*DELIMITER\s+(\S+)\s*$`),
+		wordPattern:       regexp.MustCompile(`[A-Za-z_][\w$]*|;`),
+		identifierPattern: ident,
+	}
+}
+
+// Parse analyzes SQL source code and returns structured results
+func (s *SQLParser) Parse(filePath string, content []byte) (*AnalysisResult, error) {
+	result := &AnalysisResult{
+		FilePath:     filePath,
+		Language:     "SQL",
+		Functions:    []FunctionInfo{},
+		Classes:      []ClassInfo{},
+		Imports:      []string{},
+		Dependencies: []Dependency{},
+		Errors:       []ParseError{},
+		AnalyzedAt:   time.Now(),
+	}
+
+	source := string(content)
+	lines := strings.Split(source, "\n")
+	result.LineCount = len(lines)
+
+	statements, parseErrors := s.splitStatements(source)
+	result.Errors = append(result.Errors, parseErrors...)
+
+	referenced := make(map[string]bool)
+	var referenceOrder []string
+	addReference := func(name string) {
+		if name == "" || referenced[name] {
+			return
+		}
+		referenced[name] = true
+		referenceOrder = append(referenceOrder, name)
+	}
+
+	for _, stmt := range statements {
+		text := stmt.text
+
+		switch {
+		case s.tablePattern.MatchString(text):
+			classInfo, refs := s.extractTableInfo(stmt, lines)
+			result.Classes = append(result.Classes, classInfo)
+			for _, ref := range refs {
+				if ref != classInfo.Name {
+					addReference(ref)
+				}
+			}
+
+		case s.viewPattern.MatchString(text):
+			classInfo, refs := s.extractViewInfo(stmt, lines)
+			result.Classes = append(result.Classes, classInfo)
+			for _, ref := range refs {
+				addReference(ref)
+			}
+
+		case s.routinePattern.MatchString(text):
+			funcInfo, parseError := s.extractRoutineInfo(stmt, lines)
+			if parseError != nil {
+				result.Errors = append(result.Errors, *parseError)
+			}
+			result.Functions = append(result.Functions, funcInfo)
+			result.Complexity += funcInfo.Complexity
+			for _, ref := range s.extractTableReferences(text) {
+				addReference(ref)
+			}
+
+		case s.triggerPattern.MatchString(text):
+			funcInfo, table := s.extractTriggerInfo(stmt, lines)
+			result.Functions = append(result.Functions, funcInfo)
+			result.Complexity += funcInfo.Complexity
+			addReference(table)
+			for _, ref := range s.extractTableReferences(text) {
+				addReference(ref)
+			}
+
+		default:
+			for _, ref := range s.extractTouchedTables(text) {
+				addReference(ref)
+			}
+		}
+	}
+
+	// Tables created in this file are declarations, not references
+	defined := make(map[string]bool)
+	for _, class := range result.Classes {
+		defined[class.Name] = true
+	}
+
+	for _, name := range referenceOrder {
+		if defined[name] {
+			continue
+		}
+		result.Imports = append(result.Imports, name)
+		result.Dependencies = append(result.Dependencies, Dependency{
+			Name:        name,
+			Type:        "internal",
+			UsageCount:  1,
+			IsDirectDep: true,
+			FilePath:    filePath,
+		})
+	}
+	result.ImportCount = len(result.Imports)
+
+	return result, nil
+}
+
+// splitStatements splits SQL source into statements, respecting strings,
+// comments, dollar-quoted bodies, DELIMITER directives and BEGIN...END blocks
+func (s *SQLParser) splitStatements(source string) ([]sqlStatement, []ParseError) {
+	var statements []sqlStatement
+	var errors []ParseError
+
+	delimiter := ";"
+	line := 1
+	var current strings.Builder
+	stmtStart := 0
+	blockDepth := 0
+
+	flush := func(endLine int) {
+		text := strings.TrimSpace(current.String())
+		if text != "" {
+			statements = append(statements, sqlStatement{
+				text:      text,
+				lineStart: stmtStart,
+				lineEnd:   endLine,
+			})
+		}
+		current.Reset()
+		stmtStart = 0
+		blockDepth = 0
+	}
+
+	runes := []rune(source)
+	n := len(runes)
+	atLineStart := true
+
+	for i := 0; i < n; i++ {
+		r := runes[i]
+
+		// DELIMITER directives (MySQL clients) are only valid at the start of a line
+		if atLineStart && strings.TrimSpace(current.String()) == "" {
+			end := i
+			for end < n && runes[end] != '\n' {
+				end++
+			}
+			if matches := s.delimiterPattern.FindStringSubmatch(string(runes[i:end])); matches != nil {
+				current.Reset()
+				delimiter = matches[1]
+				i = end - 1
+				atLineStart = false
+				continue
+			}
+		}
+		atLineStart = false
+
+		switch {
+		case r == '\n':
+			current.WriteRune(r)
+			line++
+			atLineStart = true
+			continue
+
+		case r == '-' && i+1 < n && runes[i+1] == '-':
+			// Line comment - skip to end of line
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+			i--
+			continue
+
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			// Block comment - skip, keeping line numbers in sync
+			commentLine := line
+			i += 2
+			closed := false
+			for ; i < n; i++ {
+				if runes[i] == '\n' {
+					line++
+					current.WriteRune('\n')
+				}
+				if runes[i] == '*' && i+1 < n && runes[i+1] == '/' {
+					i++
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				errors = append(errors, ParseError{Line: commentLine, Message: "unterminated block comment"})
+			}
+			current.WriteRune(' ')
+			continue
+
+		case r == '\'' || r == '"' || r == '`':
+			if stmtStart == 0 {
+				stmtStart = line
+			}
+			quoteLine := line
+			current.WriteRune(r)
+			closed := false
+			for i++; i < n; i++ {
+				current.WriteRune(runes[i])
+				if runes[i] == '\n' {
+					line++
+				}
+				if runes[i] == r {
+					// Doubled quotes are escapes
+					if i+1 < n && runes[i+1] == r {
+						i++
+						current.WriteRune(runes[i])
+						continue
+					}
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				errors = append(errors, ParseError{Line: quoteLine, Message: "unterminated quoted string"})
+			}
+			continue
+
+		case r == '$':
+			// Dollar-quoted body ($$ ... $$ or $tag$ ... $tag$)
+			if tag := s.dollarTag(runes, i); tag != "" {
+				if stmtStart == 0 {
+					stmtStart = line
+				}
+				quoteLine := line
+				current.WriteString(tag)
+				i += len([]rune(tag))
+				closeIdx := strings.Index(string(runes[i:]), tag)
+				if closeIdx < 0 {
+					errors = append(errors, ParseError{Line: quoteLine, Message: "unterminated dollar-quoted string"})
+					body := string(runes[i:])
+					current.WriteString(body)
+					line += strings.Count(body, "\n")
+					i = n
+					continue
+				}
+				body := string(runes[i:])[:closeIdx]
+				current.WriteString(body)
+				current.WriteString(tag)
+				line += strings.Count(body, "\n")
+				i += len([]rune(body)) + len([]rune(tag)) - 1
+				continue
+			}
+		}
+
+		if stmtStart == 0 && !isSQLSpace(r) {
+			stmtStart = line
+		}
+
+		// Track BEGIN...END blocks in routine bodies that are not dollar-quoted
+		if isSQLWordStart(runes, i) {
+			word := readSQLWord(runes, i)
+			upper := strings.ToUpper(word)
+			if s.isRoutineHeader(current.String()) || blockDepth > 0 {
+				switch upper {
+				case "BEGIN", "CASE":
+					if upper == "BEGIN" && s.isTransactionBegin(runes, i+len([]rune(word))) {
+						break
+					}
+					blockDepth++
+				case "END":
+					next := strings.ToUpper(readSQLWord(runes, skipSQLSpace(runes, i+len([]rune(word)))))
+					if next != "IF" && next != "LOOP" && next != "WHILE" && next != "REPEAT" && blockDepth > 0 {
+						blockDepth--
+					}
+				}
+			}
+			current.WriteString(word)
+			i += len([]rune(word)) - 1
+			continue
+		}
+
+		// Statement delimiter
+		if blockDepth == 0 && strings.HasPrefix(string(runes[i:min(n, i+len(delimiter))]), delimiter) {
+			flush(line)
+			i += len([]rune(delimiter)) - 1
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	flush(line)
+	return statements, errors
+}
+
+// extractTableInfo extracts a table definition as a ClassInfo with columns as fields
+func (s *SQLParser) extractTableInfo(stmt sqlStatement, lines []string) (ClassInfo, []string) {
+	matches := s.tablePattern.FindStringSubmatchIndex(stmt.text)
+	name := normalizeSQLName(stmt.text[matches[2]:matches[3]])
+
+	classInfo := ClassInfo{
+		Name:         name,
+		LineStart:    stmt.lineStart,
+		LineEnd:      stmt.lineEnd,
+		Methods:      []FunctionInfo{},
+		Fields:       []string{},
+		LinesOfCode:  stmt.lineEnd - stmt.lineStart + 1,
+		IsPublic:     true,
+		BaseClasses:  []string{},
+		HasDocstring: s.hasDocComment(lines, stmt.lineStart),
+	}
+
+	body, _ := extractParenthesized(stmt.text, matches[1]-1)
+	var refs []string
+
+	for _, element := range splitTopLevel(body, ',') {
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+
+		for _, ref := range s.referencePattern.FindAllStringSubmatch(element, -1) {
+			refs = append(refs, normalizeSQLName(ref[1]))
+		}
+
+		if isSQLConstraint(element) {
+			continue
+		}
+
+		fields := strings.Fields(element)
+		column := normalizeSQLName(fields[0])
+		var columnType []string
+		for _, token := range fields[1:] {
+			if isSQLColumnModifier(token) {
+				break
+			}
+			columnType = append(columnType, token)
+		}
+
+		field := column
+		if len(columnType) > 0 {
+			field += " " + strings.Join(columnType, " ")
+		}
+		classInfo.Fields = append(classInfo.Fields, field)
+	}
+
+	// Tables defined with LIKE/INHERITS also depend on their source tables
+	upper := strings.ToUpper(stmt.text)
+	if idx := strings.Index(upper, "INHERITS"); idx >= 0 {
+		parents, _ := extractParenthesized(stmt.text, strings.Index(stmt.text[idx:], "(")+idx)
+		for _, parent := range splitTopLevel(parents, ',') {
+			parentName := normalizeSQLName(strings.TrimSpace(parent))
+			if parentName != "" {
+				classInfo.BaseClasses = append(classInfo.BaseClasses, parentName)
+				refs = append(refs, parentName)
+			}
+		}
+	}
+
+	classInfo.FieldCount = len(classInfo.Fields)
+	return classInfo, refs
+}
+
+// extractViewInfo extracts a view definition and the tables it selects from
+func (s *SQLParser) extractViewInfo(stmt sqlStatement, lines []string) (ClassInfo, []string) {
+	matches := s.viewPattern.FindStringSubmatch(stmt.text)
+	name := normalizeSQLName(matches[1])
+
+	classInfo := ClassInfo{
+		Name:         name,
+		LineStart:    stmt.lineStart,
+		LineEnd:      stmt.lineEnd,
+		Methods:      []FunctionInfo{},
+		Fields:       []string{},
+		LinesOfCode:  stmt.lineEnd - stmt.lineStart + 1,
+		IsPublic:     true,
+		BaseClasses:  []string{},
+		HasDocstring: s.hasDocComment(lines, stmt.lineStart),
+	}
+
+	var refs []string
+	for _, ref := range s.extractTableReferences(stmt.text) {
+		if ref != name {
+			refs = append(refs, ref)
+		}
+	}
+
+	return classInfo, refs
+}
+
+// extractRoutineInfo extracts a stored function or procedure. A parameter list
+// that is never closed, as in a truncated migration, is reported as an error
+// and ends the extraction with the parameters read so far.
+func (s *SQLParser) extractRoutineInfo(stmt sqlStatement, lines []string) (FunctionInfo, *ParseError) {
+	matches := s.routinePattern.FindStringSubmatchIndex(stmt.text)
+	name := normalizeSQLName(stmt.text[matches[4]:matches[5]])
+
+	funcInfo := FunctionInfo{
+		Name:         name,
+		LineStart:    stmt.lineStart,
+		LineEnd:      stmt.lineEnd,
+		Parameters:   []string{},
+		LinesOfCode:  stmt.lineEnd - stmt.lineStart + 1,
+		IsPublic:     true,
+		HasDocstring: s.hasDocComment(lines, stmt.lineStart),
+	}
+
+	rest := stmt.text[matches[5]:]
+	trimmed := strings.TrimLeft(rest, " \t\r\n")
+	if strings.HasPrefix(trimmed, "(") {
+		offset := len(rest) - len(trimmed)
+		params, closed := extractParenthesized(rest, offset)
+		for _, param := range splitTopLevel(params, ',') {
+			param = strings.Join(strings.Fields(param), " ")
+			if param != "" {
+				funcInfo.Parameters = append(funcInfo.Parameters, param)
+			}
+		}
+		if !closed {
+			funcInfo.ParameterCount = len(funcInfo.Parameters)
+			return funcInfo, &ParseError{Line: stmt.lineStart, Message: "unterminated parameter list of routine " + name}
+		}
+		rest = rest[offset+len(params)+2:]
+	}
+
+	if returns := s.returnsPattern.FindStringSubmatch(rest); returns != nil {
+		funcInfo.ReturnType = strings.Join(strings.Fields(returns[1]), " ")
+	}
+
+	funcInfo.ParameterCount = len(funcInfo.Parameters)
+	funcInfo.Complexity = s.calculateComplexity(rest)
+	funcInfo.CyclomaticComplexity = funcInfo.Complexity
+
+	return funcInfo, nil
+}
+
+// extractTriggerInfo extracts a trigger definition and the table it is attached to
+func (s *SQLParser) extractTriggerInfo(stmt sqlStatement, lines []string) (FunctionInfo, string) {
+	matches := s.triggerPattern.FindStringSubmatchIndex(stmt.text)
+	name := normalizeSQLName(stmt.text[matches[2]:matches[3]])
+	rest := stmt.text[matches[3]:]
+
+	table := ""
+	if on := s.triggerOnPattern.FindStringSubmatch(rest); on != nil {
+		table = normalizeSQLName(on[1])
+	}
+
+	funcInfo := FunctionInfo{
+		Name:         name,
+		LineStart:    stmt.lineStart,
+		LineEnd:      stmt.lineEnd,
+		Parameters:   []string{},
+		ReturnType:   "trigger",
+		LinesOfCode:  stmt.lineEnd - stmt.lineStart + 1,
+		IsPublic:     true,
+		HasDocstring: s.hasDocComment(lines, stmt.lineStart),
+	}
+	funcInfo.Complexity = s.calculateComplexity(rest)
+	funcInfo.CyclomaticComplexity = funcInfo.Complexity
+
+	return funcInfo, table
+}
+
+// extractTouchedTables returns the tables modified or read by a plain DDL/DML statement
+func (s *SQLParser) extractTouchedTables(text string) []string {
+	var tables []string
+
+	if matches := s.alterPattern.FindStringSubmatch(text); matches != nil {
+		tables = append(tables, normalizeSQLName(matches[1]))
+		for _, ref := range s.referencePattern.FindAllStringSubmatch(text, -1) {
+			tables = append(tables, normalizeSQLName(ref[1]))
+		}
+		return tables
+	}
+
+	if matches := s.dropPattern.FindStringSubmatch(text); matches != nil {
+		for _, name := range strings.Split(matches[1], ",") {
+			fields := strings.Fields(name)
+			if len(fields) > 0 {
+				tables = append(tables, normalizeSQLName(fields[0]))
+			}
+		}
+		return tables
+	}
+
+	if matches := s.indexPattern.FindStringSubmatch(text); matches != nil {
+		return append(tables, normalizeSQLName(matches[1]))
+	}
+
+	if matches := s.truncatePattern.FindStringSubmatch(text); matches != nil {
+		return append(tables, normalizeSQLName(matches[1]))
+	}
+
+	return s.extractTableReferences(text)
+}
+
+// extractTableReferences finds tables read or written inside a statement body
+func (s *SQLParser) extractTableReferences(text string) []string {
+	cteNames := make(map[string]bool)
+	for _, cte := range s.ctePattern.FindAllStringSubmatch(text, -1) {
+		cteNames[strings.ToLower(cte[1])] = true
+	}
+
+	var tables []string
+	add := func(name string) {
+		if name == "" || cteNames[name] || sqlReservedWords[strings.ToUpper(name)] {
+			return
+		}
+		tables = append(tables, name)
+	}
+
+	for _, loc := range s.sourcePattern.FindAllStringSubmatchIndex(text, -1) {
+		nameEnd := loc[3]
+		// Table functions such as generate_series(...) are not tables
+		if next := strings.TrimLeft(text[nameEnd:], " \t\r\n"); strings.HasPrefix(next, "(") {
+			continue
+		}
+		// FROM inside EXTRACT(...)/SUBSTRING(...) is not a table source
+		if !isQueryContext(text, loc[0]) {
+			continue
+		}
+		add(normalizeSQLName(text[loc[2]:loc[3]]))
+	}
+
+	for _, matches := range s.insertPattern.FindAllStringSubmatch(text, -1) {
+		add(normalizeSQLName(matches[1]))
+	}
+	for _, matches := range s.updatePattern.FindAllStringSubmatch(text, -1) {
+		add(normalizeSQLName(matches[1]))
+	}
+	for _, ref := range s.referencePattern.FindAllStringSubmatch(text, -1) {
+		add(normalizeSQLName(ref[1]))
+	}
+
+	return tables
+}
+
+// calculateComplexity calculates cyclomatic complexity for a stored routine body
+func (s *SQLParser) calculateComplexity(body string) int {
+	complexity := 1 // Base complexity
+
+	words := s.wordPattern.FindAllString(body, -1)
+	inCondition := false
+	pendingLoop := false
+	afterBetween := false
+
+	for i, word := range words {
+		upper := strings.ToUpper(word)
+		prev := ""
+		if i > 0 {
+			prev = strings.ToUpper(words[i-1])
+		}
+		next := ""
+		if i+1 < len(words) {
+			next = strings.ToUpper(words[i+1])
+		}
+
+		switch upper {
+		case "IF":
+			if prev == "END" || next == "EXISTS" || next == "NOT" && i+2 < len(words) && strings.ToUpper(words[i+2]) == "EXISTS" {
+				continue
+			}
+			complexity++
+			inCondition = true
+		case "ELSIF", "ELSEIF", "WHEN":
+			complexity++
+			inCondition = true
+		case "WHILE", "REPEAT":
+			if prev == "END" {
+				continue
+			}
+			complexity++
+			inCondition = true
+			pendingLoop = true
+		case "FOR":
+			// FOR EACH ROW / FOR UPDATE are not loops
+			if prev == "END" || next == "EACH" || next == "UPDATE" || next == "SHARE" || next == "NO" {
+				continue
+			}
+			complexity++
+			pendingLoop = true
+		case "LOOP":
+			if prev == "END" {
+				continue
+			}
+			if pendingLoop {
+				pendingLoop = false
+			} else {
+				complexity++
+			}
+			inCondition = false
+		case "UNTIL":
+			inCondition = true
+		case "BETWEEN":
+			afterBetween = true
+		case "AND", "OR":
+			if upper == "AND" && afterBetween {
+				afterBetween = false
+				continue
+			}
+			if inCondition {
+				complexity++
+			}
+		case "THEN", "DO", "BEGIN", ";":
+			inCondition = false
+			if upper == ";" {
+				pendingLoop = false
+			}
+		}
+	}
+
+	return complexity
+}
+
+// isRoutineHeader reports whether the text so far opens a routine or trigger whose
+// body may contain nested statement delimiters
+func (s *SQLParser) isRoutineHeader(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return false
+	}
+	return s.routinePattern.MatchString(trimmed) || s.triggerPattern.MatchString(trimmed)
+}
+
+// isTransactionBegin reports whether a BEGIN keyword starts a transaction rather than a block
+func (s *SQLParser) isTransactionBegin(runes []rune, pos int) bool {
+	next := strings.ToUpper(readSQLWord(runes, skipSQLSpace(runes, pos)))
+	if next == "TRANSACTION" || next == "WORK" {
+		return true
+	}
+	pos = skipSQLSpace(runes, pos)
+	return pos < len(runes) && runes[pos] == ';'
+}
+
+// dollarTag returns the dollar-quote tag starting at position i, if any
+func (s *SQLParser) dollarTag(runes []rune, i int) string {
+	j := i + 1
+	for j < len(runes) && (runes[j] == '_' || isSQLAlnum(runes[j])) {
+		j++
+	}
+	if j < len(runes) && runes[j] == '$' {
+		// Positional parameters like $1 are not tags
+		if j > i+1 && runes[i+1] >= '0' && runes[i+1] <= '9' {
+			return ""
+		}
+		return string(runes[i : j+1])
+	}
+	return ""
+}
+
+// hasDocComment checks whether a statement is preceded by a comment
+func (s *SQLParser) hasDocComment(lines []string, lineStart int) bool {
+	for i := lineStart - 2; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			return false
+		}
+		return strings.HasPrefix(line, "--") || strings.HasSuffix(line, "*/")
+	}
+	return false
+}
+
+// GetSupportedExtensions returns the file extensions supported by this parser
+func (s *SQLParser) GetSupportedExtensions() []string {
+	return []string{".sql"}
+}
+
+// GetLanguageName returns the human-readable language name
+func (s *SQLParser) GetLanguageName() string {
+	return "SQL"
+}
+
+// sqlReservedWords are keywords that can follow FROM/JOIN but are not table names
+var sqlReservedWords = map[string]bool{
+	"SELECT": true, "LATERAL": true, "ONLY": true, "DUAL": true, "WHERE": true,
+	"VALUES": true, "UNNEST": true, "NEW": true, "OLD": true, "INSERTED": true,
+	"DELETED": true, "EXCLUDED": true,
+}
+
+// sqlColumnModifiers end the type portion of a column definition
+var sqlColumnModifiers = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "REFERENCES": true,
+	"UNIQUE": true, "CHECK": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "IDENTITY": true, "COMMENT": true,
+	"ON": true,
+}
+
+// isSQLConstraint reports whether a table element is a table-level constraint
+func isSQLConstraint(element string) bool {
+	upper := strings.ToUpper(element)
+	for _, prefix := range []string{"CONSTRAINT", "PRIMARY KEY", "FOREIGN KEY", "UNIQUE", "CHECK", "INDEX", "KEY", "EXCLUDE", "FULLTEXT", "SPATIAL", "LIKE"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSQLColumnModifier reports whether a token starts the constraint part of a column
+func isSQLColumnModifier(token string) bool {
+	return sqlColumnModifiers[strings.ToUpper(token)]
+}
+
+// normalizeSQLName strips identifier quoting and folds unquoted names to lower case
+func normalizeSQLName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		part = strings.Trim(part, "\"`[]")
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// extractParenthesized returns the contents of the parenthesized group opening
+// at index open, and whether the group is closed. An unclosed group extends to
+// the end of text.
+func extractParenthesized(text string, open int) (string, bool) {
+	if open < 0 || open >= len(text) || text[open] != '(' {
+		return "", false
+	}
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[open+1 : i], true
+			}
+		}
+	}
+	return text[open+1:], false
+}
+
+// splitTopLevel splits text on a separator that is not nested in parentheses or quotes
+func splitTopLevel(text string, sep byte) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// isQueryContext reports whether the keyword at pos is part of a query rather than a
+// function argument list such as EXTRACT(YEAR FROM created_at)
+func isQueryContext(text string, pos int) bool {
+	depth := 0
+	for i := pos - 1; i >= 0; i-- {
+		switch text[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth == 0 {
+				inner := strings.ToUpper(text[i+1 : pos])
+				return strings.Contains(inner, "SELECT") || strings.Contains(inner, "DELETE")
+			}
+			depth--
+		}
+	}
+	return true
+}
+
+// isSQLWordStart reports whether position i begins a keyword or identifier
+func isSQLWordStart(runes []rune, i int) bool {
+	r := runes[i]
+	if !(r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+		return false
+	}
+	return i == 0 || !(runes[i-1] == '_' || runes[i-1] == '$' || isSQLAlnum(runes[i-1]))
+}
+
+// readSQLWord reads the identifier or keyword starting at position i
+func readSQLWord(runes []rune, i int) string {
+	j := i
+	for j < len(runes) && (runes[j] == '_' || runes[j] == '$' || isSQLAlnum(runes[j])) {
+		j++
+	}
+	return string(runes[i:j])
+}
+
+// skipSQLSpace returns the index of the next non-space rune at or after i
+func skipSQLSpace(runes []rune, i int) int {
+	for i < len(runes) && isSQLSpace(runes[i]) {
+		i++
+	}
+	return i
+}
+
+func isSQLAlnum(r rune) bool {
+	return (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+func isSQLSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
@@ -0,0 +1,290 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestSQLParser_GetSupportedExtensions(t *testing.T) {
+	parser := NewSQLParser()
+	extensions := parser.GetSupportedExtensions()
+
+	if len(extensions) != 1 || extensions[0] != ".sql" {
+		t.Errorf("Expected ['.sql'], got %v", extensions)
+	}
+}
+
+func TestSQLParser_GetLanguageName(t *testing.T) {
+	parser := NewSQLParser()
+	if parser.GetLanguageName() != "SQL" {
+		t.Errorf("Expected 'SQL', got '%s'", parser.GetLanguageName())
+	}
+}
+
+func TestSQLParser_ParseTables(t *testing.T) {
+	parser := NewSQLParser()
+	code := `-- Users of the application
+CREATE TABLE IF NOT EXISTS "Users" (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    price NUMERIC(10, 2) DEFAULT 0,
+    created_at TIMESTAMP
+);
+
+CREATE TABLE orders (
+    id BIGINT PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id),
+    product_id INTEGER,
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES public.products (id)
+);`
+
+	result, err := parser.Parse("schema.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Classes) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(result.Classes))
+	}
+
+	users := findClass(result.Classes, "users")
+	if users == nil {
+		t.Fatal("Expected to find table 'users'")
+	}
+	if users.LineStart != 2 || users.LineEnd != 7 {
+		t.Errorf("Expected users on lines 2-7, got %d-%d", users.LineStart, users.LineEnd)
+	}
+	if !users.HasDocstring {
+		t.Error("Expected users table to have a leading comment")
+	}
+
+	expectedFields := []string{"id SERIAL", "email VARCHAR(255)", "price NUMERIC(10, 2)", "created_at TIMESTAMP"}
+	if len(users.Fields) != len(expectedFields) {
+		t.Fatalf("Expected %d columns, got %d: %v", len(expectedFields), len(users.Fields), users.Fields)
+	}
+	for i, field := range expectedFields {
+		if users.Fields[i] != field {
+			t.Errorf("Expected column %d to be '%s', got '%s'", i, field, users.Fields[i])
+		}
+	}
+
+	orders := findClass(result.Classes, "orders")
+	if orders == nil {
+		t.Fatal("Expected to find table 'orders'")
+	}
+	if orders.FieldCount != 3 {
+		t.Errorf("Expected 3 columns on orders, got %d", orders.FieldCount)
+	}
+
+	// users is defined in this file, so only products is an external reference
+	if len(result.Imports) != 1 || result.Imports[0] != "public.products" {
+		t.Errorf("Expected imports [public.products], got %v", result.Imports)
+	}
+}
+
+func TestSQLParser_ParseMigration(t *testing.T) {
+	parser := NewSQLParser()
+	code := `ALTER TABLE accounts ADD COLUMN team_id INTEGER REFERENCES teams(id);
+CREATE INDEX idx_accounts_email ON accounts (email);
+INSERT INTO audit_log (action) VALUES ('migrate; step 1');
+UPDATE accounts SET active = true WHERE id IN (SELECT account_id FROM memberships);
+DROP TABLE IF EXISTS legacy_accounts;
+/* multi-line
+   comment; with delimiter */
+TRUNCATE TABLE sessions;`
+
+	result, err := parser.Parse("0002_accounts.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []string{"accounts", "teams", "audit_log", "memberships", "legacy_accounts", "sessions"}
+	for _, name := range expected {
+		found := false
+		for _, imp := range result.Imports {
+			if imp == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected table reference '%s', got %v", name, result.Imports)
+		}
+	}
+
+	if len(result.Imports) != len(expected) {
+		t.Errorf("Expected %d references, got %d: %v", len(expected), len(result.Imports), result.Imports)
+	}
+
+	for _, dep := range result.Dependencies {
+		if dep.Type != "internal" {
+			t.Errorf("Expected dependency '%s' to be internal, got '%s'", dep.Name, dep.Type)
+		}
+	}
+
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no parse errors, got %v", result.Errors)
+	}
+}
+
+func TestSQLParser_ParseFunctions(t *testing.T) {
+	parser := NewSQLParser()
+	code := `CREATE OR REPLACE VIEW active_users AS
+SELECT u.id, u.email, EXTRACT(YEAR FROM u.created_at) AS year
+FROM users u
+JOIN memberships m ON m.user_id = u.id
+WHERE u.active;
+
+-- Calculates the discount for a customer
+CREATE FUNCTION calc_discount(customer_id INTEGER, amount NUMERIC) RETURNS NUMERIC AS $$
+DECLARE
+    total NUMERIC;
+BEGIN
+    SELECT SUM(o.amount) INTO total FROM orders o WHERE o.customer_id = customer_id;
+    IF total > 1000 AND amount > 100 THEN
+        RETURN amount * 0.9;
+    ELSIF total > 500 THEN
+        RETURN amount * 0.95;
+    END IF;
+    FOR i IN 1..3 LOOP
+        total := total + i;
+    END LOOP;
+    RETURN amount;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_audit AFTER INSERT ON orders
+FOR EACH ROW EXECUTE FUNCTION log_order();`
+
+	result, err := parser.Parse("functions.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	view := findClass(result.Classes, "active_users")
+	if view == nil {
+		t.Fatal("Expected to find view 'active_users'")
+	}
+
+	if len(result.Functions) != 2 {
+		t.Fatalf("Expected 2 routines, got %d", len(result.Functions))
+	}
+
+	fn := findFunction(result.Functions, "calc_discount")
+	if fn == nil {
+		t.Fatal("Expected to find function 'calc_discount'")
+	}
+	if fn.ParameterCount != 2 {
+		t.Errorf("Expected 2 parameters, got %d: %v", fn.ParameterCount, fn.Parameters)
+	}
+	if fn.ReturnType != "NUMERIC" {
+		t.Errorf("Expected return type 'NUMERIC', got '%s'", fn.ReturnType)
+	}
+	if !fn.HasDocstring {
+		t.Error("Expected calc_discount to have a leading comment")
+	}
+	// 1 base + IF + AND + ELSIF + FOR
+	if fn.Complexity != 5 {
+		t.Errorf("Expected complexity 5, got %d", fn.Complexity)
+	}
+	if fn.LineStart != 8 || fn.LineEnd != 23 {
+		t.Errorf("Expected calc_discount on lines 8-23, got %d-%d", fn.LineStart, fn.LineEnd)
+	}
+
+	trigger := findFunction(result.Functions, "orders_audit")
+	if trigger == nil {
+		t.Fatal("Expected to find trigger 'orders_audit'")
+	}
+	if trigger.Complexity != 1 {
+		t.Errorf("Expected trigger complexity 1, got %d", trigger.Complexity)
+	}
+
+	expected := map[string]bool{"users": true, "memberships": true, "orders": true}
+	if len(result.Imports) != len(expected) {
+		t.Errorf("Expected %d references, got %v", len(expected), result.Imports)
+	}
+	for _, imp := range result.Imports {
+		if !expected[imp] {
+			t.Errorf("Unexpected table reference '%s'", imp)
+		}
+	}
+}
+
+func TestSQLParser_ParseDelimiterBlocks(t *testing.T) {
+	parser := NewSQLParser()
+	code := `DELIMITER //
+CREATE PROCEDURE archive_orders(IN cutoff DATE)
+BEGIN
+    WHILE cutoff < NOW() DO
+        INSERT INTO orders_archive SELECT * FROM orders WHERE created_at < cutoff;
+        SET cutoff = cutoff + INTERVAL 1 DAY;
+    END WHILE;
+END //
+DELIMITER ;
+
+CREATE PROCEDURE noop()
+BEGIN
+    SELECT 1;
+END;`
+
+	result, err := parser.Parse("procs.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Functions) != 2 {
+		t.Fatalf("Expected 2 procedures, got %d", len(result.Functions))
+	}
+
+	archive := findFunction(result.Functions, "archive_orders")
+	if archive == nil {
+		t.Fatal("Expected to find procedure 'archive_orders'")
+	}
+	if archive.Complexity != 2 {
+		t.Errorf("Expected complexity 2, got %d", archive.Complexity)
+	}
+
+	noop := findFunction(result.Functions, "noop")
+	if noop == nil {
+		t.Fatal("Expected to find procedure 'noop'")
+	}
+	if noop.LineStart != 11 || noop.LineEnd != 14 {
+		t.Errorf("Expected noop on lines 11-14, got %d-%d", noop.LineStart, noop.LineEnd)
+	}
+}
+
+func TestSQLParser_ParseErrors(t *testing.T) {
+	parser := NewSQLParser()
+	code := `INSERT INTO notes (body) VALUES ('never closed);`
+
+	result, err := parser.Parse("broken.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse should not return error, got: %v", err)
+	}
+
+	if len(result.Errors) == 0 {
+		t.Error("Expected a parse error for unterminated string")
+	}
+}
+
+func TestSQLParser_ParseUnterminatedRoutineSignature(t *testing.T) {
+	parser := NewSQLParser()
+	code := "CREATE TABLE users (id int);\n\nCREATE FUNCTION f(a int"
+
+	result, err := parser.Parse("truncated.sql", []byte(code))
+	if err != nil {
+		t.Fatalf("Parse should not return error, got: %v", err)
+	}
+
+	if len(result.Errors) != 1 || result.Errors[0].Line != 3 {
+		t.Fatalf("Expected one parse error on line 3, got %+v", result.Errors)
+	}
+	if len(result.Functions) != 1 || result.Functions[0].Name != "f" {
+		t.Fatalf("Expected function f, got %+v", result.Functions)
+	}
+	if params := result.Functions[0].Parameters; len(params) != 1 || params[0] != "a int" {
+		t.Errorf("Expected the parameters read before the end, got %v", params)
+	}
+	if len(result.Classes) != 1 {
+		t.Errorf("Expected the table before the routine to be parsed, got %d classes", len(result.Classes))
+	}
+}
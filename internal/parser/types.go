@@ -17,6 +17,7 @@ type Dependency struct {
 	UsageCount  int    `json:"usage_count"`
 	IsDirectDep bool   `json:"is_direct_dependency"`
 	FilePath    string `json:"file_path"`
+	Line        int    `json:"line,omitempty"` // line of the import statement, when known
 }
 
 // FunctionInfo contains information about a function or method
@@ -28,11 +29,40 @@ type FunctionInfo struct {
 	ReturnType           string   `json:"return_type"`
 	Complexity           int      `json:"complexity"`
 	CyclomaticComplexity int      `json:"cyclomatic_complexity"`
+	CognitiveComplexity  int      `json:"cognitive_complexity"`
 	LinesOfCode          int      `json:"lines_of_code"`
 	ParameterCount       int      `json:"parameter_count"`
 	IsPublic             bool     `json:"is_public"`
 	IsAsync              bool     `json:"is_async"`
 	HasDocstring         bool     `json:"has_docstring"`
+	// Halstead metrics
+	Halstead HalsteadMetrics `json:"halstead"`
+	// Test coverage from imported coverage reports
+	TestCoverage   float64 `json:"test_coverage"`
+	CoveredLines   int     `json:"covered_lines"`
+	CoverableLines int     `json:"coverable_lines"`
+	// Commits in the history window that changed lines of the function; only
+	// counted for the most frequently changed files, 0 elsewhere
+	ChangeCount int `json:"change_count"`
+	// Class members used by the function, for cohesion metrics
+	Receiver       string   `json:"receiver,omitempty"`        // type a Go method is declared on
+	FieldsAccessed []string `json:"fields_accessed,omitempty"` // own fields read or written
+	MethodsCalled  []string `json:"methods_called,omitempty"`  // own methods called
+	ForeignData    []string `json:"foreign_data,omitempty"`    // other objects' fields used directly or through getters
+	// Calls made by the function, resolved into the call graph by the engine
+	Calls []CallSite `json:"calls,omitempty"`
+	// Python decorators, which may register the function with a framework
+	Decorators []string `json:"decorators,omitempty"`
+}
+
+// CallSite is a call made from a function. Package and Type are filled in when
+// the parser can tell what the call refers to.
+type CallSite struct {
+	Name    string `json:"name"`
+	Package string `json:"package,omitempty"` // import path (Go) or module (Python) of the callee
+	Type    string `json:"type,omitempty"`    // receiver type or class of a method call
+	Method  bool   `json:"method,omitempty"`  // called on a value, even if its type is unknown
+	Line    int    `json:"line"`
 }
 
 // ClassInfo contains information about a class or struct
@@ -49,6 +79,8 @@ type ClassInfo struct {
 	BaseClasses  []string       `json:"base_classes"`
 	HasDocstring bool           `json:"has_docstring"`
 	Complexity   int            `json:"complexity"`
+	// Interfaces, protocols and abstract base classes
+	IsAbstract bool `json:"is_abstract"`
 }
 
 // AnalysisResult contains the complete analysis results for a single file
@@ -61,6 +93,7 @@ type AnalysisResult struct {
 	Imports              []string       `json:"imports"`
 	Complexity           int            `json:"complexity"`
 	CyclomaticComplexity int            `json:"cyclomatic_complexity"`
+	CognitiveComplexity  int            `json:"cognitive_complexity"`
 	Errors               []ParseError   `json:"errors"`
 	AnalyzedAt           time.Time      `json:"analyzed_at"`
 	// Quality metrics
@@ -68,6 +101,10 @@ type AnalysisResult struct {
 	TechnicalDebt        float64 `json:"technical_debt"`
 	CodeDuplication      float64 `json:"code_duplication"`
 	TestCoverage         float64 `json:"test_coverage"`
+	CoveredLines         int     `json:"covered_lines"`
+	CoverableLines       int     `json:"coverable_lines"` // 0 when no coverage data matched the file
+	// Halstead metrics
+	Halstead HalsteadMetrics `json:"halstead"`
 	// Dependency metrics
 	Dependencies []Dependency `json:"dependencies"`
 	ImportCount  int          `json:"import_count"`
@@ -78,6 +115,90 @@ type AnalysisResult struct {
 	CodeLines         int     `json:"code_lines"`
 	AverageLineLength float64 `json:"average_line_length"`
 	MaxLineLength     int     `json:"max_line_length"`
+	// Embedded language regions (single-file components)
+	LanguageBlocks []LanguageBlock `json:"language_blocks,omitempty"`
+	// Version control history, when the project is a git repository
+	History *GitHistory `json:"history,omitempty"`
+	// Names used in the file other than where they are declared, for dead code detection
+	References []string `json:"references,omitempty"`
+	Exports    []string `json:"exports,omitempty"` // names a Python module lists in __all__
+	// Line of the first import of each name in Imports
+	ImportLines map[string]int `json:"import_lines,omitempty"`
+}
+
+// HalsteadMetrics contains Halstead software science measures. Parsers report the
+// operator and operand counts; the derived measures are filled in by the metrics calculator.
+type HalsteadMetrics struct {
+	DistinctOperators int     `json:"distinct_operators"` // n1
+	DistinctOperands  int     `json:"distinct_operands"`  // n2
+	TotalOperators    int     `json:"total_operators"`    // N1
+	TotalOperands     int     `json:"total_operands"`     // N2
+	Vocabulary        int     `json:"vocabulary"`         // n = n1 + n2
+	Length            int     `json:"length"`             // N = N1 + N2
+	Volume            float64 `json:"volume"`             // V = N * log2(n)
+	Difficulty        float64 `json:"difficulty"`         // D = (n1 / 2) * (N2 / n2)
+	Effort            float64 `json:"effort"`             // E = D * V
+	EstimatedBugs     float64 `json:"estimated_bugs"`     // B = V / 3000
+}
+
+// GitHistory summarizes the version control history of a file. Commit and line
+// counts cover the configured history window; authorship and dates cover all history.
+type GitHistory struct {
+	CommitCount   int                  `json:"commit_count"`
+	LinesAdded    int                  `json:"lines_added"`
+	LinesRemoved  int                  `json:"lines_removed"`
+	AuthorCount   int                  `json:"author_count"`
+	BusFactor     int                  `json:"bus_factor"` // fewest authors owning more than half the changes
+	FirstModified time.Time            `json:"first_modified"`
+	LastModified  time.Time            `json:"last_modified"`
+	Authors       []AuthorContribution `json:"authors"`
+}
+
+// AuthorContribution records one author's changes to a file
+type AuthorContribution struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+	Lines   int    `json:"lines"` // lines added plus removed
+}
+
+// LanguageBlock describes a region of a file written in a single embedded language,
+// such as the <script> or <style> block of a single-file component
+type LanguageBlock struct {
+	Kind         string `json:"kind"` // "script", "style", "template"
+	Language     string `json:"language"`
+	LineStart    int    `json:"line_start"`
+	LineEnd      int    `json:"line_end"`
+	LineCount    int    `json:"line_count"`
+	CodeLines    int    `json:"code_lines"`
+	CommentLines int    `json:"comment_lines"`
+	BlankLines   int    `json:"blank_lines"`
+}
+
+// LanguageLines returns the number of lines the file contributes to each language.
+// Lines not covered by a language block are attributed to the file's own language.
+func (r *AnalysisResult) LanguageLines() map[string]int {
+	lines := make(map[string]int)
+	covered := 0
+	for _, block := range r.LanguageBlocks {
+		lines[block.Language] += block.LineCount
+		covered += block.LineCount
+	}
+	if remaining := r.LineCount - covered; remaining > 0 || len(r.LanguageBlocks) == 0 {
+		lines[r.Language] += remaining
+	}
+	return lines
+}
+
+// AddImport records an imported name and the line it is imported on
+func (r *AnalysisResult) AddImport(name string, line int) {
+	r.Imports = append(r.Imports, name)
+	if r.ImportLines == nil {
+		r.ImportLines = make(map[string]int)
+	}
+	if _, exists := r.ImportLines[name]; !exists {
+		r.ImportLines[name] = line
+	}
 }
 
 // Parser defines the interface that all language parsers must implement
@@ -0,0 +1,493 @@
+// Package report exports analyses as documents to share outside the terminal
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+//go:embed report.html
+var htmlTemplate string
+
+// Size of the drawings in the HTML report
+const (
+	treemapWidth  = 960
+	treemapHeight = 420
+	graphSize     = 640
+	// Packages drawn in the dependency graph, most coupled first
+	maxGraphPackages = 60
+	// Larger files are listed without their annotated source
+	maxSourceBytes = 512 * 1024
+)
+
+// languageColors are assigned to languages in order of size
+var languageColors = []string{"#4FC3F7", "#66BB6A", "#FFCA28", "#AB47BC", "#FF7043", "#26C6DA", "#EC407A", "#8D6E63", "#78909C"}
+
+// htmlReport is the data the HTML report template renders
+type htmlReport struct {
+	Root         string
+	GeneratedAt  string
+	Score        metrics.QualityScore
+	GradeColor   string
+	TotalFiles   int
+	TotalLines   int
+	Functions    int
+	Metrics      metrics.ProjectMetrics
+	Languages    []languageSlice
+	Files        []fileRow
+	FunctionRows []functionRow
+	Treemap      []treemapTile
+	Graph        graphView
+	Gates        []metrics.GateResult
+	Cycles       [][]string // packages of each dependency cycle
+	Pages        []filePage
+}
+
+// languageSlice is one language's share of the composition bar
+type languageSlice struct {
+	Name    string
+	Files   int
+	Lines   int
+	Percent float64
+	Offset  float64 // percent of the bar before this language
+	Color   string
+}
+
+// fileRow is a row of the file table
+type fileRow struct {
+	ID              string
+	Path            string
+	Language        string
+	Lines           int
+	Functions       int
+	Complexity      int
+	Maintainability float64
+	Debt            float64
+	Duplication     float64
+}
+
+// functionRow is a row of the function table
+type functionRow struct {
+	FileID     string
+	FilePath   string
+	Name       string
+	Line       int
+	Complexity int
+	Cognitive  int
+	Lines      int
+	Parameters int
+	Level      int
+}
+
+// treemapTile is a directory in the treemap, sized by lines and colored by
+// maintainability
+type treemapTile struct {
+	Path            string
+	Files           int
+	Lines           int
+	Complexity      int
+	Maintainability float64
+	X, Y, W, H      float64
+	Color           string
+	Label           bool // whether the tile is large enough for its name
+}
+
+// graphView is the package dependency graph laid out on a circle
+type graphView struct {
+	Size    float64
+	Nodes   []graphNode
+	Edges   []graphEdge
+	Omitted int // packages left out of the drawing
+}
+
+// graphNode is a package of the dependency graph
+type graphNode struct {
+	Path     string
+	X, Y     float64
+	Afferent int
+	Efferent int
+	InCycle  bool
+}
+
+// graphEdge is an import between two packages
+type graphEdge struct {
+	X1, Y1, X2, Y2 float64
+	Cycle          bool // both packages are in the same dependency cycle
+}
+
+// filePage is a file's page with its source annotated by function complexity
+type filePage struct {
+	ID        string
+	Path      string
+	Language  string
+	Functions []functionRow
+	Lines     []sourceLine
+	Missing   string // why the source is not shown
+}
+
+// sourceLine is a line of a file's source
+type sourceLine struct {
+	Number     int
+	Text       string
+	Level      int    // complexity level of the innermost function on the line, 0 outside functions
+	Annotation string // set on the first line of a function
+}
+
+// WriteHTML writes a self-contained HTML report of an analysis, with every
+// style, script and chart inlined so it can be opened offline or attached to
+// release notes. Sources are read from the analyzed files for the per-file pages.
+func WriteHTML(w io.Writer, analysis *metrics.EnhancedProjectAnalysis) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"percent": func(value float64) string { return fmt.Sprintf("%.1f%%", value) },
+		"decimal": func(value float64) string { return fmt.Sprintf("%.1f", value) },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse report template: %w", err)
+	}
+	if err := tmpl.Execute(w, newHTMLReport(analysis)); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+// newHTMLReport prepares the data of the HTML report
+func newHTMLReport(analysis *metrics.EnhancedProjectAnalysis) *htmlReport {
+	results := fileResults(analysis)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FilePath < results[j].FilePath
+	})
+
+	report := &htmlReport{
+		Root:        analysis.RootPath,
+		GeneratedAt: analysis.GeneratedAt.Format("2006-01-02 15:04"),
+		Score:       analysis.QualityScore,
+		GradeColor:  gradeColor(analysis.QualityScore.Grade),
+		TotalFiles:  analysis.TotalFiles,
+		TotalLines:  analysis.TotalLines,
+		Metrics:     analysis.ProjectMetrics,
+		Languages:   languageSlices(analysis.Languages),
+		Treemap:     treemapTiles(analysis),
+		Graph:       dependencyGraph(analysis, results),
+		Gates:       analysis.QualityGates,
+	}
+
+	for i, result := range results {
+		id := fmt.Sprintf("file-%d", i+1)
+		rel := relativePath(analysis.RootPath, result.FilePath)
+		page := filePage{ID: id, Path: rel, Language: result.Language}
+
+		for _, fn := range functionsOf(result) {
+			row := functionRow{
+				FileID:     id,
+				FilePath:   rel,
+				Name:       fn.Name,
+				Line:       fn.Info.LineStart,
+				Complexity: fn.Info.CyclomaticComplexity,
+				Cognitive:  fn.Info.CognitiveComplexity,
+				Lines:      fn.Info.LinesOfCode,
+				Parameters: fn.Info.ParameterCount,
+				Level:      complexityLevel(fn.Info.CyclomaticComplexity),
+			}
+			page.Functions = append(page.Functions, row)
+			report.FunctionRows = append(report.FunctionRows, row)
+		}
+		report.Functions += len(page.Functions)
+
+		report.Files = append(report.Files, fileRow{
+			ID:              id,
+			Path:            rel,
+			Language:        result.Language,
+			Lines:           result.LineCount,
+			Functions:       len(page.Functions),
+			Complexity:      result.CyclomaticComplexity,
+			Maintainability: result.MaintainabilityIndex,
+			Debt:            result.TechnicalDebt,
+			Duplication:     result.CodeDuplication,
+		})
+
+		page.Lines, page.Missing = annotatedSource(result)
+		report.Pages = append(report.Pages, page)
+	}
+
+	for _, cycle := range analysis.DependencyGraph.Cycles {
+		var packages []string
+		for _, pkg := range cycle.Packages {
+			packages = append(packages, relativePath(analysis.RootPath, pkg))
+		}
+		report.Cycles = append(report.Cycles, packages)
+	}
+
+	sort.SliceStable(report.FunctionRows, func(i, j int) bool {
+		return report.FunctionRows[i].Complexity > report.FunctionRows[j].Complexity
+	})
+	return report
+}
+
+// languageSlices splits the composition bar between languages by lines, largest first
+func languageSlices(languages map[string]metrics.LanguageStats) []languageSlice {
+	total := 0
+	for _, stats := range languages {
+		total += stats.LineCount
+	}
+
+	var slices []languageSlice
+	for name, stats := range languages {
+		slices = append(slices, languageSlice{Name: name, Files: stats.FileCount, Lines: stats.LineCount})
+	}
+	sort.Slice(slices, func(i, j int) bool {
+		if slices[i].Lines != slices[j].Lines {
+			return slices[i].Lines > slices[j].Lines
+		}
+		return slices[i].Name < slices[j].Name
+	})
+
+	offset := 0.0
+	for i := range slices {
+		if total > 0 {
+			slices[i].Percent = float64(slices[i].Lines) / float64(total) * 100
+		}
+		slices[i].Offset = offset
+		slices[i].Color = languageColors[i%len(languageColors)]
+		offset += slices[i].Percent
+	}
+	return slices
+}
+
+// treemapTiles lays the directories out by their lines
+func treemapTiles(analysis *metrics.EnhancedProjectAnalysis) []treemapTile {
+	var tiles []treemapTile
+	for dir, stats := range analysis.DirectoryStats {
+		if stats.LineCount > 0 {
+			tiles = append(tiles, treemapTile{
+				Path:            relativePath(analysis.RootPath, dir),
+				Files:           stats.FileCount,
+				Lines:           stats.LineCount,
+				Complexity:      stats.Complexity,
+				Maintainability: stats.MaintainabilityIndex,
+				Color:           maintainabilityColor(stats.MaintainabilityIndex),
+			})
+		}
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].Lines != tiles[j].Lines {
+			return tiles[i].Lines > tiles[j].Lines
+		}
+		return tiles[i].Path < tiles[j].Path
+	})
+
+	values := make([]float64, len(tiles))
+	for i, tile := range tiles {
+		values[i] = float64(tile.Lines)
+	}
+	for i, area := range squarify(values, rect{W: treemapWidth, H: treemapHeight}) {
+		tiles[i].X, tiles[i].Y, tiles[i].W, tiles[i].H = area.X, area.Y, area.W, area.H
+		tiles[i].Label = area.W >= 7*float64(len(tiles[i].Path)) && area.H >= 18
+	}
+	return tiles
+}
+
+// dependencyGraph lays the most coupled packages out on a circle with the
+// imports between them, marking the ones in dependency cycles
+func dependencyGraph(analysis *metrics.EnhancedProjectAnalysis, results []*parser.AnalysisResult) graphView {
+	graph := graphView{Size: graphSize}
+
+	packages := append([]metrics.PackageMetrics(nil), analysis.DependencyGraph.Packages...)
+	sort.SliceStable(packages, func(i, j int) bool {
+		if ci, cj := packages[i].Afferent+packages[i].Efferent, packages[j].Afferent+packages[j].Efferent; ci != cj {
+			return ci > cj
+		}
+		return packages[i].Path < packages[j].Path
+	})
+	if len(packages) > maxGraphPackages {
+		graph.Omitted = len(packages) - maxGraphPackages
+		packages = packages[:maxGraphPackages]
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	cycleOf := make(map[string]int)
+	for i, cycle := range analysis.DependencyGraph.Cycles {
+		for _, pkg := range cycle.Packages {
+			cycleOf[pkg] = i + 1
+		}
+	}
+
+	center := graphSize / 2.0
+	points := circle(len(packages), center, center, center-90)
+	index := make(map[string]int, len(packages))
+	for i, pkg := range packages {
+		index[pkg.Path] = i
+		graph.Nodes = append(graph.Nodes, graphNode{
+			Path:     relativePath(analysis.RootPath, pkg.Path),
+			X:        points[i][0],
+			Y:        points[i][1],
+			Afferent: pkg.Afferent,
+			Efferent: pkg.Efferent,
+			InCycle:  cycleOf[pkg.Path] > 0,
+		})
+	}
+
+	dependencies := metrics.PackageDependencies(analysis.RootPath, results)
+	for _, from := range sortedKeys(dependencies) {
+		i, drawn := index[from]
+		if !drawn {
+			continue
+		}
+		for _, to := range dependencies[from] {
+			j, drawn := index[to]
+			if !drawn {
+				continue
+			}
+			graph.Edges = append(graph.Edges, graphEdge{
+				X1: points[i][0], Y1: points[i][1],
+				X2: points[j][0], Y2: points[j][1],
+				Cycle: cycleOf[from] > 0 && cycleOf[from] == cycleOf[to],
+			})
+		}
+	}
+	return graph
+}
+
+// annotatedSource reads a file's source and marks each line with the
+// complexity of the innermost function it belongs to
+func annotatedSource(result *parser.AnalysisResult) ([]sourceLine, string) {
+	info, err := os.Stat(result.FilePath)
+	if err != nil {
+		return nil, "Source not available: " + err.Error()
+	}
+	if info.Size() > maxSourceBytes {
+		return nil, fmt.Sprintf("Source not shown: %d bytes is over the %d byte limit", info.Size(), maxSourceBytes)
+	}
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return nil, "Source not available: " + err.Error()
+	}
+
+	texts := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	lines := make([]sourceLine, len(texts))
+	spans := make([]int, len(texts)) // length of the innermost function seen on each line
+	for i, text := range texts {
+		lines[i] = sourceLine{Number: i + 1, Text: text}
+	}
+
+	for _, fn := range functionsOf(result) {
+		start, end := fn.Info.LineStart, max(fn.Info.LineEnd, fn.Info.LineStart)
+		if start < 1 || start > len(lines) {
+			continue
+		}
+		level := complexityLevel(fn.Info.CyclomaticComplexity)
+		for n := start; n <= min(end, len(lines)); n++ {
+			if spans[n-1] == 0 || end-start < spans[n-1] {
+				spans[n-1] = end - start
+				lines[n-1].Level = level
+			}
+		}
+		lines[start-1].Annotation = fmt.Sprintf("%s: complexity %d, cognitive %d, %d lines",
+			fn.Name, fn.Info.CyclomaticComplexity, fn.Info.CognitiveComplexity, fn.Info.LinesOfCode)
+	}
+	return lines, ""
+}
+
+// function is a function or method of a file with its qualified name
+type function struct {
+	Name string // Type.method for methods
+	Info parser.FunctionInfo
+}
+
+// functionsOf returns the functions and methods of a file
+func functionsOf(result *parser.AnalysisResult) []function {
+	var functions []function
+	for _, fn := range result.Functions {
+		name := strings.TrimPrefix(fn.Name, "async ")
+		if fn.Receiver != "" {
+			name = fn.Receiver + "." + name
+		}
+		functions = append(functions, function{Name: name, Info: fn})
+	}
+	for _, class := range result.Classes {
+		for _, method := range class.Methods {
+			functions = append(functions, function{Name: class.Name + "." + strings.TrimPrefix(method.Name, "async "), Info: method})
+		}
+	}
+	return functions
+}
+
+// complexityLevel buckets a cyclomatic complexity: 1 simple, 2 moderate, 3
+// complex and 4 very complex
+func complexityLevel(complexity int) int {
+	switch {
+	case complexity > 20:
+		return 4
+	case complexity > 10:
+		return 3
+	case complexity > 5:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// gradeColor returns the color of a quality grade
+func gradeColor(grade string) string {
+	switch grade {
+	case "A":
+		return "#2E7D32"
+	case "B":
+		return "#66BB6A"
+	case "C":
+		return "#F9A825"
+	case "D":
+		return "#FB8C00"
+	default:
+		return "#E53935"
+	}
+}
+
+// maintainabilityColor returns the color of a maintainability index
+func maintainabilityColor(index float64) string {
+	switch {
+	case index >= 80:
+		return "#66BB6A"
+	case index >= 65:
+		return "#D4E157"
+	case index >= 50:
+		return "#FFB74D"
+	default:
+		return "#E57373"
+	}
+}
+
+// fileResults returns the parsed files of an analysis
+func fileResults(analysis *metrics.EnhancedProjectAnalysis) []*parser.AnalysisResult {
+	results, _ := analysis.FileResults.([]*parser.AnalysisResult)
+	return append([]*parser.AnalysisResult(nil), results...)
+}
+
+// relativePath returns a slash-separated path relative to root, or path itself
+// when it is not under root
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
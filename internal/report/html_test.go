@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestSquarify(t *testing.T) {
+	values := []float64{6, 6, 4, 3, 2, 2, 1}
+	bounds := rect{W: 6, H: 4}
+	tiles := squarify(values, bounds)
+	if len(tiles) != len(values) {
+		t.Fatalf("Expected %d tiles, got %d", len(values), len(tiles))
+	}
+
+	for i, tile := range tiles {
+		if area := tile.W * tile.H; math.Abs(area-values[i]) > 1e-9 {
+			t.Errorf("Tile %d: expected area %v, got %v", i, values[i], area)
+		}
+		if tile.X < -1e-9 || tile.Y < -1e-9 || tile.X+tile.W > bounds.W+1e-9 || tile.Y+tile.H > bounds.H+1e-9 {
+			t.Errorf("Tile %d is outside the bounds: %+v", i, tile)
+		}
+	}
+
+	if squarify([]float64{0}, bounds) != nil {
+		t.Error("Expected no tiles for a zero total")
+	}
+}
+
+func TestAnnotatedSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	source := "package main\n\nfunc outer() {\n\tinner := func() {\n\t}\n}\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := &parser.AnalysisResult{
+		FilePath: path,
+		Functions: []parser.FunctionInfo{
+			{Name: "outer", LineStart: 3, LineEnd: 6, CyclomaticComplexity: 12},
+			{Name: "inner", LineStart: 4, LineEnd: 5, CyclomaticComplexity: 2},
+		},
+	}
+
+	lines, missing := annotatedSource(result)
+	if missing != "" {
+		t.Fatalf("Expected the source, got %q", missing)
+	}
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %d", len(lines))
+	}
+	levels := []int{0, 0, 3, 1, 1, 3}
+	for i, level := range levels {
+		if lines[i].Level != level {
+			t.Errorf("Line %d: expected level %d, got %d", i+1, level, lines[i].Level)
+		}
+	}
+	if !strings.HasPrefix(lines[2].Annotation, "outer: complexity 12") {
+		t.Errorf("Expected line 3 to annotate outer, got %q", lines[2].Annotation)
+	}
+
+	result.FilePath = filepath.Join(t.TempDir(), "missing.go")
+	if _, missing := annotatedSource(result); !strings.HasPrefix(missing, "Source not available") {
+		t.Errorf("Expected a missing source, got %q", missing)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "store"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apiFile := filepath.Join(root, "api", "handler.go")
+	if err := os.WriteFile(apiFile, []byte("package api\n\nfunc Handle(a, b int) {\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis := &metrics.EnhancedProjectAnalysis{
+		RootPath:    root,
+		TotalFiles:  2,
+		TotalLines:  60,
+		GeneratedAt: time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		Languages: map[string]metrics.LanguageStats{
+			"Go":     {FileCount: 1, LineCount: 40},
+			"Python": {FileCount: 1, LineCount: 20},
+		},
+		FileResults: []*parser.AnalysisResult{
+			{
+				FilePath:  apiFile,
+				Language:  "Go",
+				LineCount: 4,
+				Functions: []parser.FunctionInfo{
+					{Name: "Handle", LineStart: 3, LineEnd: 4, CyclomaticComplexity: 25, ParameterCount: 2},
+				},
+			},
+			{
+				FilePath:  filepath.Join(root, "store", "db.py"),
+				Language:  "Python",
+				LineCount: 20,
+				Classes: []parser.ClassInfo{
+					{Name: "Store", Methods: []parser.FunctionInfo{{Name: "save", LineStart: 2, CyclomaticComplexity: 1}}},
+				},
+			},
+		},
+		DirectoryStats: map[string]metrics.DirectoryStats{
+			filepath.Join(root, "api"):   {FileCount: 1, LineCount: 40, MaintainabilityIndex: 90},
+			filepath.Join(root, "store"): {FileCount: 1, LineCount: 20, MaintainabilityIndex: 40},
+		},
+		QualityScore: metrics.QualityScore{Overall: 82.5, Grade: "B"},
+		QualityGates: []metrics.GateResult{{Gate: "max_complexity", Threshold: "20", Actual: "25", Passed: false}},
+		DependencyGraph: metrics.DependencyGraph{
+			Cycles: []metrics.DependencyCycle{{Packages: []string{filepath.Join(root, "api"), filepath.Join(root, "store")}}},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := WriteHTML(&out, analysis); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	html := out.String()
+
+	for _, want := range []string{
+		`<div class="grade" style="background: #66BB6A">B</div>`,
+		`Go 66.7%`,
+		`<a href="#file-1">api/handler.go</a>`,
+		`<td>Store.save</td>`,
+		`<td class="num level-4">25</td>`,
+		`<span class="note">Handle: complexity 25`,
+		`<span class="fail">failed</span>`,
+		`api ↔ store`,
+		`Source not available`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the report to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "ZgotmplZ") {
+		t.Error("Expected no value to be rejected by the template escaper")
+	}
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("Expected the report to load nothing from the network")
+	}
+}
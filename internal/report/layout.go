@@ -0,0 +1,102 @@
+package report
+
+import "math"
+
+// rect is an area of an SVG drawing
+type rect struct {
+	X, Y, W, H float64
+}
+
+// squarify lays values out as tiles of bounds with areas proportional to them,
+// keeping tiles close to square. Values must be positive and sorted largest
+// first; tiles are returned in the same order.
+func squarify(values []float64, bounds rect) []rect {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	scale := bounds.W * bounds.H / total
+	areas := make([]float64, len(values))
+	for i, value := range values {
+		areas[i] = value * scale
+	}
+
+	tiles := make([]rect, 0, len(values))
+	var row []float64
+	for i := 0; i < len(areas); {
+		side := math.Min(bounds.W, bounds.H)
+		if len(row) == 0 || worstRatio(append(row, areas[i]), side) <= worstRatio(row, side) {
+			row = append(row, areas[i])
+			i++
+			continue
+		}
+		tiles = append(tiles, layoutRow(row, &bounds)...)
+		row = nil
+	}
+	if len(row) > 0 {
+		tiles = append(tiles, layoutRow(row, &bounds)...)
+	}
+	return tiles
+}
+
+// worstRatio returns the largest aspect ratio of a row of areas laid along a
+// side of the given length
+func worstRatio(row []float64, side float64) float64 {
+	sum, smallest, largest := 0.0, math.Inf(1), 0.0
+	for _, area := range row {
+		sum += area
+		smallest = math.Min(smallest, area)
+		largest = math.Max(largest, area)
+	}
+	if sum == 0 || smallest == 0 {
+		return math.Inf(1)
+	}
+	return math.Max(side*side*largest/(sum*sum), sum*sum/(side*side*smallest))
+}
+
+// layoutRow places a row of areas along the shorter side of bounds and
+// removes the space it takes from bounds
+func layoutRow(row []float64, bounds *rect) []rect {
+	sum := 0.0
+	for _, area := range row {
+		sum += area
+	}
+
+	tiles := make([]rect, 0, len(row))
+	if bounds.W >= bounds.H {
+		width := sum / bounds.H
+		y := bounds.Y
+		for _, area := range row {
+			height := area / width
+			tiles = append(tiles, rect{X: bounds.X, Y: y, W: width, H: height})
+			y += height
+		}
+		bounds.X += width
+		bounds.W -= width
+	} else {
+		height := sum / bounds.W
+		x := bounds.X
+		for _, area := range row {
+			width := area / height
+			tiles = append(tiles, rect{X: x, Y: bounds.Y, W: width, H: height})
+			x += width
+		}
+		bounds.Y += height
+		bounds.H -= height
+	}
+	return tiles
+}
+
+// circle places n points evenly on a circle, starting at the top
+func circle(n int, centerX, centerY, radius float64) [][2]float64 {
+	points := make([][2]float64, n)
+	for i := range points {
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		points[i] = [2]float64{centerX + radius*math.Cos(angle), centerY + radius*math.Sin(angle)}
+	}
+	return points
+}
@@ -0,0 +1,405 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// DefaultMarkdownLength keeps a summary under the 65536 character limit of
+// GitHub comments
+const DefaultMarkdownLength = 65000
+
+// MarkdownOptions limits the size of a Markdown summary
+type MarkdownOptions struct {
+	MaxLength int // bytes; 0 for no limit
+	Top       int // entries per section; 0 for all
+}
+
+// WriteMarkdown writes a compact Markdown summary of an analysis to post on a
+// pull request. With a base analysis, such as one of the target branch, it
+// reports what changed since: grade and score, complex functions added, new
+// dependency cycles and functions that got more complex. Sections that do not
+// fit in MaxLength are shortened, then left out.
+func WriteMarkdown(w io.Writer, base, analysis *metrics.EnhancedProjectAnalysis, options MarkdownOptions) error {
+	if _, err := io.WriteString(w, Markdown(base, analysis, options)); err != nil {
+		return fmt.Errorf("failed to write Markdown summary: %w", err)
+	}
+	return nil
+}
+
+// Markdown returns the summary written by WriteMarkdown
+func Markdown(base, analysis *metrics.EnhancedProjectAnalysis, options MarkdownOptions) string {
+	var head string
+	var sections []markdownSection
+	if base == nil {
+		head = markdownHeadline(analysis)
+		sections = []markdownSection{
+			metricsSection(nil, analysis),
+			gatesSection(nil, analysis),
+			complexFunctionsSection(analysis, options.Top),
+			cyclesSection("Circular dependencies", analysis, analysis.DependencyGraph.Cycles),
+			gateFailuresSection(analysis),
+		}
+	} else {
+		comparison := compare.Compare(base, analysis)
+		head = markdownChangeHeadline(comparison)
+		sections = []markdownSection{
+			metricsSection(base, analysis),
+			gatesSection(base, analysis),
+			addedFunctionsSection(comparison, options.Top),
+			cyclesSection("New circular dependencies", analysis, newCycles(base, analysis)),
+			regressionsSection(comparison),
+			regressedDirectoriesSection(comparison),
+			gateFailuresSection(analysis),
+		}
+	}
+	return fitMarkdown(head, sections, options.MaxLength)
+}
+
+// markdownSection is a table of a summary, collapsed when details is set
+type markdownSection struct {
+	title   string
+	text    string // paragraph before the table
+	header  string // header and separator lines of the table
+	rows    []string
+	details bool
+}
+
+// render renders the section with its first n rows
+func (s markdownSection) render(n int) string {
+	var b strings.Builder
+	if s.details {
+		b.WriteString(fmt.Sprintf("<details>\n<summary>%s (%d)</summary>\n\n", s.title, len(s.rows)))
+	} else {
+		b.WriteString("### " + s.title + "\n\n")
+	}
+	if s.text != "" {
+		b.WriteString(s.text + "\n\n")
+	}
+	if n > 0 {
+		b.WriteString(s.header)
+		for _, row := range s.rows[:n] {
+			b.WriteString(row + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if n < len(s.rows) {
+		b.WriteString(fmt.Sprintf("…and %d more\n\n", len(s.rows)-n))
+	}
+	if s.details {
+		b.WriteString("</details>\n\n")
+	}
+	return b.String()
+}
+
+// truncationNote ends a summary that was shortened to fit its maximum length
+const truncationNote = "_Shortened to fit the comment size limit; run `codebasereader report -format markdown` for the full summary._\n"
+
+// fitMarkdown joins the headline and the non-empty sections in order. When
+// they do not fit in maxLength, sections lose rows from the end and are left
+// out when not even their title fits. A limit too small for the headline and
+// the truncation note cuts the summary at the last whole line that fits.
+func fitMarkdown(head string, sections []markdownSection, maxLength int) string {
+	var b strings.Builder
+	b.WriteString(head)
+
+	budget := func() int {
+		return maxLength - b.Len() - len(truncationNote)
+	}
+	truncated := false
+	for _, section := range sections {
+		if section.text == "" && len(section.rows) == 0 {
+			continue
+		}
+		full := section.render(len(section.rows))
+		if maxLength <= 0 || len(full) <= budget() {
+			b.WriteString(full)
+			continue
+		}
+
+		truncated = true
+		for n := len(section.rows) - 1; n >= 0; n-- {
+			if shortened := section.render(n); len(shortened) <= budget() {
+				b.WriteString(shortened)
+				break
+			}
+		}
+	}
+
+	if truncated {
+		b.WriteString(truncationNote)
+	}
+	summary := b.String()
+	if maxLength > 0 && len(summary) > maxLength {
+		summary = summary[:maxLength]
+		if end := strings.LastIndexByte(summary, '\n'); end >= 0 {
+			summary = summary[:end+1]
+		} else {
+			summary = strings.ToValidUTF8(summary, "")
+		}
+	}
+	return summary
+}
+
+// markdownHeadline summarizes the grade and size of an analysis
+func markdownHeadline(analysis *metrics.EnhancedProjectAnalysis) string {
+	return fmt.Sprintf("## Code quality: %s (%.1f)\n\n%s, %s\n\n", analysis.QualityScore.Grade, analysis.QualityScore.Overall,
+		plural(analysis.TotalFiles, "file"), plural(analysis.TotalLines, "line"))
+}
+
+// markdownChangeHeadline summarizes how the grade, score and size changed
+func markdownChangeHeadline(comparison *compare.Comparison) string {
+	score := comparison.QualityScore
+	grade := score.After.Grade
+	if score.Before.Grade != score.After.Grade {
+		grade = score.Before.Grade + " → " + score.After.Grade
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("## Code quality: %s (%.1f → %.1f, %s)\n\n", grade, score.Before.Overall, score.After.Overall,
+		signed(score.After.Overall-score.Before.Overall)))
+	if score.Regressed {
+		b.WriteString("⚠️ The quality score went down.\n\n")
+	}
+	changes := comparison.Changes
+	b.WriteString(fmt.Sprintf("%s, %s (%s); %s and %s changed\n\n", plural(comparison.After.TotalFiles, "file"),
+		plural(comparison.After.TotalLines, "line"), signedInt(comparison.After.TotalLines-comparison.Before.TotalLines),
+		plural(len(changes.Files), "file"), plural(len(changes.Functions), "function")))
+	return b.String()
+}
+
+// metricsSection tabulates the project metrics, with their change when base is set
+func metricsSection(base, analysis *metrics.EnhancedProjectAnalysis) markdownSection {
+	section := markdownSection{title: "Metrics", header: "| Metric | Value |\n|---|---:|\n"}
+	if base != nil {
+		section.header = "| Metric | Before | After | Change |\n|---|---:|---:|---:|\n"
+	}
+
+	row := func(name string, value func(metrics.ProjectMetrics) float64, format string) {
+		after := value(analysis.ProjectMetrics)
+		if base == nil {
+			section.rows = append(section.rows, fmt.Sprintf("| %s | "+format+" |", name, after))
+			return
+		}
+		before := value(base.ProjectMetrics)
+		section.rows = append(section.rows, fmt.Sprintf("| %s | "+format+" | "+format+" | %s |", name, before, after, signed(after-before)))
+	}
+	row("Complexity", func(m metrics.ProjectMetrics) float64 { return float64(m.TotalComplexity) }, "%.0f")
+	row("Average complexity", func(m metrics.ProjectMetrics) float64 { return m.AverageComplexity }, "%.2f")
+	row("Max complexity", func(m metrics.ProjectMetrics) float64 { return float64(m.MaxComplexity) }, "%.0f")
+	row("Maintainability", func(m metrics.ProjectMetrics) float64 { return m.MaintainabilityIndex }, "%.1f")
+	row("Technical debt (h)", func(m metrics.ProjectMetrics) float64 { return m.TechnicalDebt }, "%.1f")
+	row("Duplication %", func(m metrics.ProjectMetrics) float64 { return m.CodeDuplication }, "%.1f")
+	row("Test coverage %", func(m metrics.ProjectMetrics) float64 { return m.TestCoverage }, "%.1f")
+	return section
+}
+
+// gatesSection tabulates the quality gates, with each gate's previous value
+// when base is set
+func gatesSection(base, analysis *metrics.EnhancedProjectAnalysis) markdownSection {
+	section := markdownSection{title: "Quality gates", header: "| Gate | Threshold | Actual | Result |\n|---|---|---|---|\n"}
+	previous := make(map[string]string)
+	if base != nil {
+		section.header = "| Gate | Threshold | Before | Actual | Result |\n|---|---|---|---|---|\n"
+		for _, gate := range base.QualityGates {
+			previous[gate.Gate] = gate.Actual
+		}
+	}
+
+	failed := 0
+	for _, gate := range analysis.QualityGates {
+		result := "✅ passed"
+		if !gate.Passed {
+			result = "❌ failed"
+			failed++
+		}
+		if base == nil {
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %s | %s | %s |", gate.Gate, gate.Threshold, gate.Actual, result))
+		} else {
+			before := previous[gate.Gate]
+			if before == "" {
+				before = "–"
+			}
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %s | %s | %s | %s |", gate.Gate, gate.Threshold, before, gate.Actual, result))
+		}
+	}
+	if len(section.rows) > 0 {
+		section.text = fmt.Sprintf("%d of %d gates passed.", len(section.rows)-failed, len(section.rows))
+	}
+	return section
+}
+
+// complexFunctionsSection lists the most complex functions of an analysis
+func complexFunctionsSection(analysis *metrics.EnhancedProjectAnalysis, top int) markdownSection {
+	section := markdownSection{title: "Most complex functions", header: "| Function | Location | Complexity | Lines |\n|---|---|---:|---:|\n"}
+
+	type located struct {
+		function
+		path string
+	}
+	var functions []located
+	for _, result := range fileResults(analysis) {
+		for _, fn := range functionsOf(result) {
+			functions = append(functions, located{fn, relativePath(analysis.RootPath, result.FilePath)})
+		}
+	}
+	sort.SliceStable(functions, func(i, j int) bool {
+		if functions[i].Info.CyclomaticComplexity != functions[j].Info.CyclomaticComplexity {
+			return functions[i].Info.CyclomaticComplexity > functions[j].Info.CyclomaticComplexity
+		}
+		return functions[i].path < functions[j].path
+	})
+
+	for i, fn := range functions {
+		if top > 0 && i >= top {
+			break
+		}
+		section.rows = append(section.rows, fmt.Sprintf("| `%s` | %s:%d | %d | %d |", markdownCell(fn.Name), markdownCell(fn.path),
+			fn.Info.LineStart, fn.Info.CyclomaticComplexity, fn.Info.LinesOfCode))
+	}
+	return section
+}
+
+// addedFunctionsSection lists the most complex functions added since the base
+func addedFunctionsSection(comparison *compare.Comparison, top int) markdownSection {
+	section := markdownSection{title: "Complex functions added", header: "| Function | Location | Complexity | Lines |\n|---|---|---:|---:|\n"}
+
+	var added []metrics.FunctionDelta
+	for _, fn := range comparison.Changes.Functions {
+		if fn.Status == metrics.DiffAdded && fn.After != nil {
+			added = append(added, fn)
+		}
+	}
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].After.Complexity > added[j].After.Complexity
+	})
+
+	for i, fn := range added {
+		if top > 0 && i >= top {
+			break
+		}
+		section.rows = append(section.rows, fmt.Sprintf("| `%s` | %s:%d | %d | %d |", markdownCell(fn.Name), markdownCell(fn.FilePath),
+			fn.After.Line, fn.After.Complexity, fn.After.LinesOfCode))
+	}
+	return section
+}
+
+// cyclesSection lists dependency cycles by their packages
+func cyclesSection(title string, analysis *metrics.EnhancedProjectAnalysis, cycles []metrics.DependencyCycle) markdownSection {
+	section := markdownSection{title: title, header: "| Packages |\n|---|\n"}
+	for _, cycle := range cycles {
+		section.rows = append(section.rows, "| "+markdownCell(strings.Join(cyclePackages(analysis.RootPath, cycle), " ↔ "))+" |")
+	}
+	if len(section.rows) > 0 {
+		section.text = "⚠️ These packages import each other, directly or through other packages."
+	}
+	return section
+}
+
+// newCycles returns the dependency cycles of an analysis whose packages do not
+// form a cycle in the base analysis
+func newCycles(base, analysis *metrics.EnhancedProjectAnalysis) []metrics.DependencyCycle {
+	known := make(map[string]bool)
+	for _, cycle := range base.DependencyGraph.Cycles {
+		known[strings.Join(cyclePackages(base.RootPath, cycle), "\n")] = true
+	}
+
+	var cycles []metrics.DependencyCycle
+	for _, cycle := range analysis.DependencyGraph.Cycles {
+		if !known[strings.Join(cyclePackages(analysis.RootPath, cycle), "\n")] {
+			cycles = append(cycles, cycle)
+		}
+	}
+	return cycles
+}
+
+// cyclePackages returns the packages of a cycle relative to root, in order
+func cyclePackages(root string, cycle metrics.DependencyCycle) []string {
+	packages := make([]string, 0, len(cycle.Packages))
+	for _, pkg := range cycle.Packages {
+		packages = append(packages, relativePath(root, pkg))
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// regressionsSection lists the functions that got more complex or accrued debt
+func regressionsSection(comparison *compare.Comparison) markdownSection {
+	section := markdownSection{
+		title:   "Functions that got more complex",
+		header:  "| Function | Location | Complexity | Debt (h) |\n|---|---|---:|---:|\n",
+		details: true,
+	}
+	for _, fn := range comparison.Regressions {
+		section.rows = append(section.rows, fmt.Sprintf("| `%s` | %s:%d | %d → %d | %.1f → %.1f |", markdownCell(fn.Name),
+			markdownCell(fn.FilePath), fn.After.Line, fn.Before.Complexity, fn.After.Complexity, fn.Before.TechnicalDebt, fn.After.TechnicalDebt))
+	}
+	return section
+}
+
+// regressedDirectoriesSection lists the directories that got more complex or
+// less maintainable
+func regressedDirectoriesSection(comparison *compare.Comparison) markdownSection {
+	section := markdownSection{
+		title:   "Directories that got worse",
+		header:  "| Directory | Complexity | Maintainability |\n|---|---:|---:|\n",
+		details: true,
+	}
+	for _, dir := range comparison.Directories {
+		if dir.Regressed {
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %d → %d | %.1f → %.1f |", markdownCell(dir.Path),
+				dir.Before.Complexity, dir.After.Complexity, dir.Before.MaintainabilityIndex, dir.After.MaintainabilityIndex))
+		}
+	}
+	return section
+}
+
+// gateFailuresSection lists the functions and files that fail quality gates
+func gateFailuresSection(analysis *metrics.EnhancedProjectAnalysis) markdownSection {
+	section := markdownSection{
+		title:   "Quality gate failures",
+		header:  "| Gate | Location | Value |\n|---|---|---:|\n",
+		details: true,
+	}
+	for _, gate := range analysis.QualityGates {
+		for _, failure := range gate.Failures {
+			location := relativePath(analysis.RootPath, failure.FilePath)
+			if failure.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, failure.Line)
+			}
+			if failure.Symbol != "" {
+				location = fmt.Sprintf("`%s` %s", failure.Symbol, location)
+			}
+			section.rows = append(section.rows, fmt.Sprintf("| %s | %s | %.1f |", gate.Gate, markdownCell(location), failure.Value))
+		}
+	}
+	return section
+}
+
+// markdownCell escapes the pipes that would end a table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// signed formats a change with its sign
+func signed(value float64) string {
+	return fmt.Sprintf("%+.1f", value)
+}
+
+// signedInt formats a whole change with its sign
+func signedInt(value int) string {
+	return fmt.Sprintf("%+d", value)
+}
+
+// plural formats a number of things, such as "1 file" or "2 files"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// markdownAnalysis builds an analysis of /repo with one Go file holding the
+// given functions
+func markdownAnalysis(score float64, grade string, functions ...parser.FunctionInfo) *metrics.EnhancedProjectAnalysis {
+	return &metrics.EnhancedProjectAnalysis{
+		RootPath:     "/repo",
+		TotalFiles:   1,
+		TotalLines:   100,
+		QualityScore: metrics.QualityScore{Overall: score, Grade: grade},
+		FileResults: []*parser.AnalysisResult{
+			{FilePath: "/repo/api/handler.go", Language: "Go", LineCount: 100, Functions: functions},
+		},
+	}
+}
+
+func TestMarkdown_SingleAnalysis(t *testing.T) {
+	analysis := markdownAnalysis(72.4, "C",
+		parser.FunctionInfo{Name: "Simple", LineStart: 3, CyclomaticComplexity: 2},
+		parser.FunctionInfo{Name: "Route", Receiver: "Router", LineStart: 10, CyclomaticComplexity: 18, LinesOfCode: 40},
+	)
+	analysis.QualityGates = []metrics.GateResult{{
+		Gate: "max_function_complexity", Threshold: "15", Actual: "18", Passed: false,
+		Failures: []metrics.GateFailure{{FilePath: "/repo/api/handler.go", Symbol: "Router.Route", Line: 10, Value: 18}},
+	}}
+
+	summary := Markdown(nil, analysis, MarkdownOptions{Top: 1})
+
+	for _, want := range []string{
+		"## Code quality: C (72.4)",
+		"0 of 1 gates passed.",
+		"| max_function_complexity | 15 | 18 | ❌ failed |",
+		"| `Router.Route` | api/handler.go:10 | 18 | 40 |",
+		"<summary>Quality gate failures (1)</summary>",
+		"`Router.Route` api/handler.go:10",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected the summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "`Simple`") {
+		t.Error("Expected only the most complex function with Top 1")
+	}
+	if strings.Contains(summary, "Circular dependencies") {
+		t.Error("Expected no cycles section without cycles")
+	}
+}
+
+func TestMarkdown_Changes(t *testing.T) {
+	base := markdownAnalysis(80, "B",
+		parser.FunctionInfo{Name: "Handle", LineStart: 3, LineEnd: 20, CyclomaticComplexity: 4},
+	)
+	base.DependencyGraph.Cycles = []metrics.DependencyCycle{{Packages: []string{"/repo/a", "/repo/b"}}}
+
+	analysis := markdownAnalysis(70, "C",
+		parser.FunctionInfo{Name: "Handle", LineStart: 3, LineEnd: 30, CyclomaticComplexity: 9},
+		parser.FunctionInfo{Name: "Parse", LineStart: 40, LineEnd: 90, CyclomaticComplexity: 14, LinesOfCode: 50},
+	)
+	analysis.DependencyGraph.Cycles = []metrics.DependencyCycle{
+		{Packages: []string{"/repo/b", "/repo/a"}},
+		{Packages: []string{"/repo/api", "/repo/store"}},
+	}
+
+	summary := Markdown(base, analysis, MarkdownOptions{})
+
+	for _, want := range []string{
+		"## Code quality: B → C (80.0 → 70.0, -10.0)",
+		"⚠️ The quality score went down.",
+		"1 file, 100 lines (+0); 1 file and 2 functions changed",
+		"### Complex functions added",
+		"| `Parse` | api/handler.go:40 | 14 | 50 |",
+		"### New circular dependencies",
+		"| api ↔ store |",
+		"<summary>Functions that got more complex (1)</summary>",
+		"| `Handle` | api/handler.go:3 | 4 → 9 |",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected the summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "a ↔ b") {
+		t.Error("Expected the cycle present in the base not to be reported as new")
+	}
+}
+
+func TestMarkdown_MaxLength(t *testing.T) {
+	var functions []parser.FunctionInfo
+	for i := 0; i < 200; i++ {
+		functions = append(functions, parser.FunctionInfo{Name: fmt.Sprintf("Function%d", i), LineStart: i + 1, CyclomaticComplexity: 200 - i})
+	}
+	analysis := markdownAnalysis(50, "D", functions...)
+
+	full := Markdown(nil, analysis, MarkdownOptions{})
+	summary := Markdown(nil, analysis, MarkdownOptions{MaxLength: 2000})
+
+	if len(full) <= 2000 {
+		t.Fatalf("Expected the full summary to be over the limit, got %d bytes", len(full))
+	}
+	if len(summary) > 2000 {
+		t.Errorf("Expected at most 2000 bytes, got %d", len(summary))
+	}
+	if !strings.Contains(summary, "`Function0`") || strings.Contains(summary, "`Function199`") {
+		t.Error("Expected the most complex functions to be kept and the rest dropped")
+	}
+	if !strings.Contains(summary, "more\n") || !strings.HasSuffix(summary, truncationNote) {
+		t.Errorf("Expected the summary to say it was shortened, got:\n%s", summary)
+	}
+}
+
+func TestMarkdown_MaxLengthBelowHeadline(t *testing.T) {
+	var functions []parser.FunctionInfo
+	for i := 0; i < 20; i++ {
+		functions = append(functions, parser.FunctionInfo{Name: fmt.Sprintf("Function%d", i), LineStart: i + 1, CyclomaticComplexity: 30})
+	}
+	analysis := markdownAnalysis(50, "D", functions...)
+
+	for _, limit := range []int{100, 40, 10} {
+		summary := Markdown(nil, analysis, MarkdownOptions{MaxLength: limit})
+		if len(summary) > limit {
+			t.Errorf("Limit %d: expected at most %d bytes, got %d:\n%s", limit, limit, len(summary), summary)
+		}
+	}
+	if summary := Markdown(nil, analysis, MarkdownOptions{MaxLength: 45}); summary != "## Code quality: D (50.0)\n\n1 file, 100 lines\n" {
+		t.Errorf("Expected the summary cut after the last whole line, got %q", summary)
+	}
+}
@@ -0,0 +1,395 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// SARIF levels of results
+const (
+	sarifError   = "error"
+	sarifWarning = "warning"
+	sarifNote    = "note"
+)
+
+// Rule IDs of the findings exported as SARIF
+const (
+	RuleComplexity   = "CBR001"
+	RuleCycle        = "CBR002"
+	RuleParseError   = "CBR003"
+	RuleDeadCode     = "CBR004"
+	RuleDuplication  = "CBR005"
+	RuleArchitecture = "CBR006"
+)
+
+// srcRoot is the base of the artifact URIs, so results point at files
+// relative to the repository wherever it is checked out
+const srcRoot = "SRCROOT"
+
+// Default complexity thresholds of SARIF findings, the bounds of the
+// "complex" and "very complex" levels of the HTML report
+const (
+	DefaultComplexityWarning = 10
+	DefaultComplexityError   = 20
+)
+
+// SARIFOptions sets the thresholds of SARIF findings
+type SARIFOptions struct {
+	ComplexityWarning int // functions over this cyclomatic complexity are warnings
+	ComplexityError   int // and errors over this one
+}
+
+// sarifRules describes the findings exported as SARIF, in rule ID order
+var sarifRules = []sarifRule{
+	{
+		ID:                   RuleComplexity,
+		Name:                 "HighComplexity",
+		ShortDescription:     sarifMessage{Text: "Function is too complex"},
+		FullDescription:      sarifMessage{Text: "The cyclomatic complexity of the function, the number of independent paths through it, is over the threshold."},
+		Help:                 sarifHelp{Text: "Complex functions are hard to understand and to test. Split the function into smaller ones, replace nested conditions with early returns and move branches on types or states into separate functions."},
+		DefaultConfiguration: sarifConfiguration{Level: sarifWarning},
+		Properties:           sarifRuleProperties{Tags: []string{"maintainability", "complexity"}},
+	},
+	{
+		ID:                   RuleCycle,
+		Name:                 "CircularDependency",
+		ShortDescription:     sarifMessage{Text: "Packages depend on each other in a cycle"},
+		FullDescription:      sarifMessage{Text: "The package imports a package that, directly or through other packages, imports it back."},
+		Help:                 sarifHelp{Text: "Packages in a cycle cannot be understood, tested or reused separately. Move the code both packages need into a new package, or invert one of the imports with an interface owned by the importing package."},
+		DefaultConfiguration: sarifConfiguration{Level: sarifError},
+		Properties:           sarifRuleProperties{Tags: []string{"architecture", "dependencies"}},
+	},
+	{
+		ID:                   RuleParseError,
+		Name:                 "ParseError",
+		ShortDescription:     sarifMessage{Text: "File could not be parsed"},
+		FullDescription:      sarifMessage{Text: "The parser reported an error, so the metrics of the file are incomplete."},
+		Help:                 sarifHelp{Text: "Fix the syntax error, or exclude the file from the analysis when it is generated or not meant to be valid source."},
+		DefaultConfiguration: sarifConfiguration{Level: sarifError},
+		Properties:           sarifRuleProperties{Tags: []string{"correctness"}},
+	},
+	{
+		ID:               RuleDeadCode,
+		Name:             "UnreferencedCode",
+		ShortDescription: sarifMessage{Text: "Declaration is never referenced"},
+		FullDescription:  sarifMessage{Text: "Nothing in the project refers to the function, method or type."},
+		Help: sarifHelp{
+			Text:     "Remove the declaration, or list it with -entry-points when it is used from outside the project, such as a handler registered by name.",
+			Markdown: "Remove the declaration, or list it with `-entry-points` when it is used from outside the project, such as a handler registered by name.",
+		},
+		DefaultConfiguration: sarifConfiguration{Level: sarifNote},
+		Properties:           sarifRuleProperties{Tags: []string{"maintainability", "dead-code"}},
+	},
+	{
+		ID:                   RuleDuplication,
+		Name:                 "DuplicatedCode",
+		ShortDescription:     sarifMessage{Text: "Code is duplicated"},
+		FullDescription:      sarifMessage{Text: "The block has the same token sequence as blocks elsewhere in the project, ignoring names and literals."},
+		Help:                 sarifHelp{Text: "Duplicated code has to be changed in every copy. Extract the block into a function shared by its copies."},
+		DefaultConfiguration: sarifConfiguration{Level: sarifWarning},
+		Properties:           sarifRuleProperties{Tags: []string{"maintainability", "duplication"}},
+	},
+	{
+		ID:               RuleArchitecture,
+		Name:             "ArchitectureViolation",
+		ShortDescription: sarifMessage{Text: "Import breaks an architecture rule"},
+		FullDescription:  sarifMessage{Text: "The import is forbidden by an architecture rule of the project config."},
+		Help: sarifHelp{
+			Text:     "Depend on the package through one the rule allows, or change the rule in the project config when the dependency is intended.",
+			Markdown: "Depend on the package through one the rule allows, or change the rule in `.codebasereader.json` when the dependency is intended.",
+		},
+		DefaultConfiguration: sarifConfiguration{Level: sarifError},
+		Properties:           sarifRuleProperties{Tags: []string{"architecture"}},
+	},
+}
+
+// sarifLog is a SARIF 2.1.0 log
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun and the types below mirror the SARIF objects of the same names
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactURI `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      sarifMessage        `json:"fullDescription"`
+	Help                 sarifHelp           `json:"help"`
+	DefaultConfiguration sarifConfiguration  `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifHelp struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"` // when it differs from the text
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                    `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactURI `json:"artifactLocation"`
+	Region           *sarifRegion     `json:"region,omitempty"`
+}
+
+type sarifArtifactURI struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the findings of an analysis as a SARIF 2.1.0 log for code
+// scanning tools: functions over the complexity thresholds, dependency
+// cycles, parse errors, unreferenced code, duplicated blocks and architecture
+// violations. Locations are relative to the analyzed root.
+func WriteSARIF(w io.Writer, analysis *metrics.EnhancedProjectAnalysis, options SARIFOptions) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(newSARIFLog(analysis, options)); err != nil {
+		return fmt.Errorf("failed to write SARIF log: %w", err)
+	}
+	return nil
+}
+
+// newSARIFLog converts the findings of an analysis into a SARIF log
+func newSARIFLog(analysis *metrics.EnhancedProjectAnalysis, options SARIFOptions) sarifLog {
+	s := &sarifBuilder{root: analysis.RootPath, results: []sarifResult{}}
+	s.complexity(analysis, options)
+	s.cycles(analysis.DependencyGraph.Cycles)
+	s.parseErrors(analysis)
+	s.deadCode(analysis.DeadCode)
+	s.duplication(analysis.Duplication.Groups)
+	s.architecture(analysis.ArchitectureViolations)
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "codebasereader",
+			InformationURI: "https://github.com/tito-sala/codebasereaderv2",
+			Rules:          sarifRules,
+		}},
+		Results: s.results,
+	}
+	if analysis.RootPath != "" {
+		root := filepath.ToSlash(analysis.RootPath)
+		if !strings.HasSuffix(root, "/") {
+			root += "/"
+		}
+		run.OriginalURIBaseIDs = map[string]sarifArtifactURI{srcRoot: {URI: (&url.URL{Scheme: "file", Path: root}).String()}}
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifBuilder collects the results of a SARIF run
+type sarifBuilder struct {
+	root    string
+	results []sarifResult
+}
+
+// add records a result of a rule with its level and locations
+func (s *sarifBuilder) add(ruleID, level, message string, locations []sarifLocation, related []sarifLocation) {
+	index := sort.Search(len(sarifRules), func(i int) bool { return sarifRules[i].ID >= ruleID })
+	s.results = append(s.results, sarifResult{
+		RuleID:           ruleID,
+		RuleIndex:        index,
+		Level:            level,
+		Message:          sarifMessage{Text: message},
+		Locations:        locations,
+		RelatedLocations: related,
+	})
+}
+
+// location points at lines of a file; lines below 1 point at the whole file
+func (s *sarifBuilder) location(path string, startLine, endLine int) sarifLocation {
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactURI{URI: relativePath(s.root, path), URIBaseID: srcRoot},
+	}}
+	if filepath.IsAbs(location.PhysicalLocation.ArtifactLocation.URI) {
+		location.PhysicalLocation.ArtifactLocation = sarifArtifactURI{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()}
+	}
+	if startLine > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: startLine}
+		if endLine > startLine {
+			location.PhysicalLocation.Region.EndLine = endLine
+		}
+	}
+	return location
+}
+
+// complexity reports the functions over the complexity thresholds
+func (s *sarifBuilder) complexity(analysis *metrics.EnhancedProjectAnalysis, options SARIFOptions) {
+	if options.ComplexityWarning <= 0 {
+		options.ComplexityWarning = DefaultComplexityWarning
+	}
+	if options.ComplexityError <= 0 {
+		options.ComplexityError = DefaultComplexityError
+	}
+
+	results := fileResults(analysis)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FilePath < results[j].FilePath
+	})
+	for _, result := range results {
+		for _, fn := range functionsOf(result) {
+			complexity := fn.Info.CyclomaticComplexity
+			if complexity <= options.ComplexityWarning {
+				continue
+			}
+			level, threshold := sarifWarning, options.ComplexityWarning
+			if complexity > options.ComplexityError {
+				level, threshold = sarifError, options.ComplexityError
+			}
+
+			location := s.location(result.FilePath, fn.Info.LineStart, fn.Info.LineEnd)
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: fn.Name, Kind: "function"}}
+			s.add(RuleComplexity, level, fmt.Sprintf("%s has a cyclomatic complexity of %d, over the threshold of %d (cognitive complexity %d).",
+				fn.Name, complexity, threshold, fn.Info.CognitiveComplexity), []sarifLocation{location}, nil)
+		}
+	}
+}
+
+// cycles reports each dependency cycle at its first import, with the other
+// imports of the cycle as related locations
+func (s *sarifBuilder) cycles(cycles []metrics.DependencyCycle) {
+	for _, cycle := range cycles {
+		if len(cycle.Imports) == 0 {
+			continue
+		}
+		var locations []sarifLocation
+		for i, site := range cycle.Imports {
+			location := s.location(site.FilePath, site.Line, 0)
+			location.ID = i + 1
+			location.Message = &sarifMessage{Text: fmt.Sprintf("%s imports %s", relativePath(s.root, site.From), relativePath(s.root, site.To))}
+			locations = append(locations, location)
+		}
+
+		primary := locations[0]
+		primary.ID, primary.Message = 0, nil
+		s.add(RuleCycle, sarifError, fmt.Sprintf("Packages %s depend on each other in a cycle.",
+			strings.Join(cyclePackages(s.root, cycle), ", ")), []sarifLocation{primary}, locations)
+	}
+}
+
+// parseErrors reports the errors of the parsers
+func (s *sarifBuilder) parseErrors(analysis *metrics.EnhancedProjectAnalysis) {
+	results := fileResults(analysis)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FilePath < results[j].FilePath
+	})
+	for _, result := range results {
+		for _, parseError := range result.Errors {
+			location := s.location(result.FilePath, parseError.Line, 0)
+			if region := location.PhysicalLocation.Region; region != nil && parseError.Column > 0 {
+				region.StartColumn = parseError.Column
+			}
+			s.add(RuleParseError, sarifError, parseError.Message, []sarifLocation{location}, nil)
+		}
+	}
+}
+
+// deadCode reports the declarations nothing refers to
+func (s *sarifBuilder) deadCode(deadCode []metrics.DeadCode) {
+	for _, item := range deadCode {
+		location := s.location(item.FilePath, item.LineStart, item.LineEnd)
+		location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: item.Name, Kind: item.Kind}}
+		s.add(RuleDeadCode, sarifNote, fmt.Sprintf("The %s %s is never referenced in the project.", item.Kind, item.Name),
+			[]sarifLocation{location}, nil)
+	}
+}
+
+// duplication reports each clone group at its first instance, with every
+// instance as a related location
+func (s *sarifBuilder) duplication(groups []metrics.CloneGroup) {
+	for _, group := range groups {
+		if len(group.Instances) < 2 {
+			continue
+		}
+		var locations []sarifLocation
+		for i, instance := range group.Instances {
+			location := s.location(instance.FilePath, instance.LineStart, instance.LineEnd)
+			location.ID = i + 1
+			location.Message = &sarifMessage{Text: fmt.Sprintf("copy %d of %d", i+1, len(group.Instances))}
+			locations = append(locations, location)
+		}
+
+		primary := locations[0]
+		primary.ID, primary.Message = 0, nil
+		s.add(RuleDuplication, sarifWarning, fmt.Sprintf("%d lines (%d tokens) are duplicated in %d places.",
+			group.Lines, group.Tokens, len(group.Instances)), []sarifLocation{primary}, locations)
+	}
+}
+
+// architecture reports the imports that break architecture rules
+func (s *sarifBuilder) architecture(violations []metrics.ArchitectureViolation) {
+	for _, violation := range violations {
+		message := fmt.Sprintf("%s imports %s, which breaks the rule %q.", violation.From, violation.Target, violation.Rule)
+		if violation.Reason != "" {
+			message += " " + violation.Reason
+		}
+		s.add(RuleArchitecture, sarifError, message, []sarifLocation{s.location(violation.FilePath, violation.Line, 0)}, nil)
+	}
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	analysis := &metrics.EnhancedProjectAnalysis{
+		RootPath: "/repo",
+		FileResults: []*parser.AnalysisResult{
+			{
+				FilePath: "/repo/api/handler.go",
+				Functions: []parser.FunctionInfo{
+					{Name: "Simple", LineStart: 3, LineEnd: 8, CyclomaticComplexity: 4},
+					{Name: "Route", Receiver: "Router", LineStart: 10, LineEnd: 60, CyclomaticComplexity: 14},
+					{Name: "Dispatch", LineStart: 70, LineEnd: 160, CyclomaticComplexity: 31},
+				},
+				Errors: []parser.ParseError{{Line: 12, Column: 5, Message: "expected ';', found '}'"}},
+			},
+		},
+		DependencyGraph: metrics.DependencyGraph{
+			Cycles: []metrics.DependencyCycle{{
+				Packages: []string{"/repo/api", "/repo/store"},
+				Imports: []metrics.ImportSite{
+					{From: "/repo/api", To: "/repo/store", FilePath: "/repo/api/handler.go", Line: 4},
+					{From: "/repo/store", To: "/repo/api", FilePath: "/repo/store/db.go", Line: 6},
+				},
+			}},
+		},
+		DeadCode: []metrics.DeadCode{{FilePath: "/repo/store/db.go", Name: "Store.flush", Kind: metrics.DeadMethod, LineStart: 20, LineEnd: 25}},
+		Duplication: metrics.DuplicationAnalysis{Groups: []metrics.CloneGroup{{
+			Tokens: 80, Lines: 12,
+			Instances: []metrics.CloneInstance{
+				{FilePath: "/repo/api/handler.go", LineStart: 100, LineEnd: 111},
+				{FilePath: "/repo/store/db.go", LineStart: 30, LineEnd: 41},
+			},
+		}}},
+		ArchitectureViolations: []metrics.ArchitectureViolation{{
+			Rule: "api must not import db", FilePath: "/repo/api/handler.go", Line: 5, From: "api", Target: "internal/db",
+		}},
+	}
+
+	var out bytes.Buffer
+	if err := WriteSARIF(&out, analysis, SARIFOptions{ComplexityWarning: 10, ComplexityError: 20}); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Expected one SARIF 2.1.0 run, got version %q with %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if got := run.OriginalURIBaseIDs[srcRoot].URI; got != "file:///repo/" {
+		t.Errorf("Expected the source root file:///repo/, got %q", got)
+	}
+
+	type finding struct {
+		rule, level, uri string
+		line, column     int
+	}
+	var findings []finding
+	for _, result := range run.Results {
+		if run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("Result of %s points at rule %d", result.RuleID, result.RuleIndex)
+		}
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URIBaseID != srcRoot {
+			t.Errorf("Expected %s to be relative to %s", location.ArtifactLocation.URI, srcRoot)
+		}
+		findings = append(findings, finding{result.RuleID, result.Level, location.ArtifactLocation.URI,
+			location.Region.StartLine, location.Region.StartColumn})
+	}
+
+	expected := []finding{
+		{RuleComplexity, "warning", "api/handler.go", 10, 0},
+		{RuleComplexity, "error", "api/handler.go", 70, 0},
+		{RuleCycle, "error", "api/handler.go", 4, 0},
+		{RuleParseError, "error", "api/handler.go", 12, 5},
+		{RuleDeadCode, "note", "store/db.go", 20, 0},
+		{RuleDuplication, "warning", "api/handler.go", 100, 0},
+		{RuleArchitecture, "error", "api/handler.go", 5, 0},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d results, got %d: %+v", len(expected), len(findings), findings)
+	}
+	for i, want := range expected {
+		if findings[i] != want {
+			t.Errorf("Result %d: expected %+v, got %+v", i, want, findings[i])
+		}
+	}
+
+	cycle := run.Results[2]
+	if len(cycle.RelatedLocations) != 2 || cycle.RelatedLocations[1].PhysicalLocation.ArtifactLocation.URI != "store/db.go" {
+		t.Errorf("Expected both imports of the cycle as related locations, got %+v", cycle.RelatedLocations)
+	}
+	if name := run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName; name != "Router.Route" {
+		t.Errorf("Expected the function name Router.Route, got %q", name)
+	}
+}
@@ -0,0 +1,193 @@
+// Package trends keeps a history of analysis snapshots, one per run, so the
+// evolution of a project's metrics can be charted over time
+package trends
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// Metrics tracked over time, for the project and for each directory
+const (
+	Complexity        = "complexity"
+	AverageComplexity = "average_complexity"
+	Maintainability   = "maintainability"
+	TechnicalDebt     = "technical_debt"
+	Duplication       = "duplication"
+	TestCoverage      = "test_coverage"
+	Score             = "score"
+)
+
+// ProjectMetrics lists the metrics recorded for the whole project, in display order
+var ProjectMetrics = []string{Complexity, AverageComplexity, Maintainability, TechnicalDebt, Duplication, TestCoverage, Score}
+
+// DirectoryMetrics lists the metrics recorded for each directory, in display order
+var DirectoryMetrics = []string{Complexity, Maintainability, TechnicalDebt, Duplication}
+
+// Snapshot records the metrics of one analysis run
+type Snapshot struct {
+	Timestamp time.Time `json:"timestamp"`
+	Commit    string    `json:"commit,omitempty"` // empty outside a git working tree
+
+	TotalFiles     int                              `json:"total_files"`
+	TotalLines     int                              `json:"total_lines"`
+	ProjectMetrics metrics.ProjectMetrics           `json:"project_metrics"`
+	QualityScore   metrics.QualityScore             `json:"quality_score"`
+	Languages      map[string]metrics.LanguageStats `json:"languages"`
+	// Keyed by slash-separated path relative to the analyzed root, "." for the root
+	Directories map[string]DirectorySnapshot `json:"directories"`
+}
+
+// DirectorySnapshot records the metrics of a directory in one analysis run
+type DirectorySnapshot struct {
+	Files                int     `json:"files"`
+	Lines                int     `json:"lines"`
+	Complexity           int     `json:"complexity"`
+	MaintainabilityIndex float64 `json:"maintainability_index"`
+	TechnicalDebt        float64 `json:"technical_debt"`
+	CodeDuplication      float64 `json:"code_duplication"`
+}
+
+// Point is the value of a metric in one snapshot
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Commit    string    `json:"commit,omitempty"`
+	Value     float64   `json:"value"`
+}
+
+// NewSnapshot records the metrics of an analysis of rootPath, taken at commit
+func NewSnapshot(analysis *metrics.EnhancedProjectAnalysis, rootPath, commit string) Snapshot {
+	snapshot := Snapshot{
+		Timestamp:      analysis.GeneratedAt,
+		Commit:         commit,
+		TotalFiles:     analysis.TotalFiles,
+		TotalLines:     analysis.TotalLines,
+		ProjectMetrics: analysis.ProjectMetrics,
+		QualityScore:   analysis.QualityScore,
+		Languages:      analysis.Languages,
+		Directories:    make(map[string]DirectorySnapshot, len(analysis.DirectoryStats)),
+	}
+
+	// Directory stats carry no debt, so it is summed from the files directly in each
+	debt := make(map[string]float64)
+	if results, ok := analysis.FileResults.([]*parser.AnalysisResult); ok {
+		for _, result := range results {
+			debt[relativePath(rootPath, filepath.Dir(result.FilePath))] += result.TechnicalDebt
+		}
+	}
+
+	for dir, stats := range analysis.DirectoryStats {
+		rel := relativePath(rootPath, dir)
+		snapshot.Directories[rel] = DirectorySnapshot{
+			Files:                stats.FileCount,
+			Lines:                stats.LineCount,
+			Complexity:           stats.Complexity,
+			MaintainabilityIndex: stats.MaintainabilityIndex,
+			TechnicalDebt:        debt[rel],
+			CodeDuplication:      stats.CodeDuplication,
+		}
+	}
+	return snapshot
+}
+
+// Value returns a project metric of the snapshot
+func (s Snapshot) Value(metric string) float64 {
+	switch metric {
+	case Complexity:
+		return float64(s.ProjectMetrics.TotalComplexity)
+	case AverageComplexity:
+		return s.ProjectMetrics.AverageComplexity
+	case Maintainability:
+		return s.ProjectMetrics.MaintainabilityIndex
+	case TechnicalDebt:
+		return s.ProjectMetrics.TechnicalDebt
+	case Duplication:
+		return s.ProjectMetrics.CodeDuplication
+	case TestCoverage:
+		return s.ProjectMetrics.TestCoverage
+	case Score:
+		return s.QualityScore.Overall
+	}
+	return 0
+}
+
+// Value returns a directory metric of the snapshot
+func (d DirectorySnapshot) Value(metric string) float64 {
+	switch metric {
+	case Complexity:
+		return float64(d.Complexity)
+	case Maintainability:
+		return d.MaintainabilityIndex
+	case TechnicalDebt:
+		return d.TechnicalDebt
+	case Duplication:
+		return d.CodeDuplication
+	}
+	return 0
+}
+
+// Series returns a project metric over time
+func Series(snapshots []Snapshot, metric string) []Point {
+	points := make([]Point, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		points = append(points, Point{Timestamp: snapshot.Timestamp, Commit: snapshot.Commit, Value: snapshot.Value(metric)})
+	}
+	return points
+}
+
+// DirectorySeries returns a metric of a directory over time, skipping the
+// snapshots taken before the directory existed or after it was removed
+func DirectorySeries(snapshots []Snapshot, dir, metric string) []Point {
+	var points []Point
+	for _, snapshot := range snapshots {
+		if stats, ok := snapshot.Directories[dir]; ok {
+			points = append(points, Point{Timestamp: snapshot.Timestamp, Commit: snapshot.Commit, Value: stats.Value(metric)})
+		}
+	}
+	return points
+}
+
+// Directories returns the directories of the latest snapshot, most complex first
+func Directories(snapshots []Snapshot) []string {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	latest := snapshots[len(snapshots)-1].Directories
+	dirs := make([]string, 0, len(latest))
+	for dir := range latest {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if latest[dirs[i]].Complexity != latest[dirs[j]].Complexity {
+			return latest[dirs[i]].Complexity > latest[dirs[j]].Complexity
+		}
+		return dirs[i] < dirs[j]
+	})
+	return dirs
+}
+
+// Values returns the values of a series
+func Values(points []Point) []float64 {
+	values := make([]float64, len(points))
+	for i, point := range points {
+		values[i] = point.Value
+	}
+	return values
+}
+
+// HigherIsBetter reports whether an increase of a metric is an improvement
+func HigherIsBetter(metric string) bool {
+	return metric == Maintainability || metric == TestCoverage || metric == Score
+}
+
+// relativePath returns a slash-separated path relative to root
+func relativePath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
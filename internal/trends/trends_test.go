@@ -0,0 +1,100 @@
+package trends
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	analysis := &metrics.EnhancedProjectAnalysis{
+		RootPath:       "/repo",
+		TotalFiles:     3,
+		GeneratedAt:    time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		ProjectMetrics: metrics.ProjectMetrics{TotalComplexity: 12, TechnicalDebt: 5},
+		QualityScore:   metrics.QualityScore{Overall: 82, Grade: "B"},
+		DirectoryStats: map[string]metrics.DirectoryStats{
+			"/repo":     {FileCount: 1, Complexity: 2},
+			"/repo/pkg": {FileCount: 2, Complexity: 10, MaintainabilityIndex: 70, CodeDuplication: 4},
+		},
+		FileResults: []*parser.AnalysisResult{
+			{FilePath: "/repo/main.go", TechnicalDebt: 1},
+			{FilePath: "/repo/pkg/a.go", TechnicalDebt: 1.5},
+			{FilePath: "/repo/pkg/b.go", TechnicalDebt: 2.5},
+		},
+	}
+
+	snapshot := NewSnapshot(analysis, "/repo", "abc123")
+
+	if snapshot.Commit != "abc123" || !snapshot.Timestamp.Equal(analysis.GeneratedAt) {
+		t.Errorf("Expected the commit and analysis time, got %q at %v", snapshot.Commit, snapshot.Timestamp)
+	}
+	if snapshot.Value(Complexity) != 12 || snapshot.Value(Score) != 82 {
+		t.Errorf("Expected complexity 12 and score 82, got %v and %v", snapshot.Value(Complexity), snapshot.Value(Score))
+	}
+	pkg := snapshot.Directories["pkg"]
+	if pkg.Complexity != 10 || pkg.TechnicalDebt != 4 || pkg.CodeDuplication != 4 {
+		t.Errorf("Expected pkg complexity 10, debt 4 and duplication 4, got %+v", pkg)
+	}
+	if root := snapshot.Directories["."]; root.TechnicalDebt != 1 {
+		t.Errorf("Expected root debt 1, got %+v", root)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "trends.jsonl"))
+
+	snapshots, err := store.Load()
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("Expected an empty store, got %d snapshots, %v", len(snapshots), err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	for _, snapshot := range []Snapshot{
+		{Timestamp: day(3), Commit: "c", ProjectMetrics: metrics.ProjectMetrics{TotalComplexity: 30},
+			Directories: map[string]DirectorySnapshot{"pkg": {Complexity: 20}}},
+		{Timestamp: day(1), Commit: "a", ProjectMetrics: metrics.ProjectMetrics{TotalComplexity: 10}},
+		{Timestamp: day(2), Commit: "b", ProjectMetrics: metrics.ProjectMetrics{TotalComplexity: 20},
+			Directories: map[string]DirectorySnapshot{"pkg": {Complexity: 15}}},
+	} {
+		if err := store.Append(snapshot); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	snapshots, err = store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(snapshots) != 3 || snapshots[0].Commit != "a" || snapshots[2].Commit != "c" {
+		t.Fatalf("Expected three snapshots oldest first, got %+v", snapshots)
+	}
+
+	if values := Values(Series(snapshots, Complexity)); len(values) != 3 || values[0] != 10 || values[2] != 30 {
+		t.Errorf("Expected project complexity 10, 20, 30, got %v", values)
+	}
+	if points := DirectorySeries(snapshots, "pkg", Complexity); len(points) != 2 || points[0].Commit != "b" {
+		t.Errorf("Expected pkg complexity from commit b on, got %+v", points)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{nil, 0, ""},
+		{[]float64{5, 5, 5}, 0, "▁▁▁"},
+		{[]float64{0, 7, 14}, 0, "▁▅█"},
+		{[]float64{100, 0, 7, 14}, 3, "▁▅█"},
+	}
+	for _, test := range tests {
+		if got := Sparkline(test.values, test.width); got != test.want {
+			t.Errorf("Sparkline(%v, %d) = %q, want %q", test.values, test.width, got, test.want)
+		}
+	}
+}
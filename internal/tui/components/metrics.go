@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -257,6 +258,14 @@ func (m *MetricsDisplay) renderQuality(analysis *metrics.EnhancedProjectAnalysis
 	b.WriteString(m.renderTechnicalDebt(analysis.ProjectMetrics))
 	b.WriteString("\n")
 
+	// Complex functions lacking tests
+	b.WriteString(m.renderCoverageRisks(analysis))
+	b.WriteString("\n")
+
+	// God classes, data classes and classes lacking cohesion
+	b.WriteString(m.renderClassDesign(analysis.ClassCohesion))
+	b.WriteString("\n")
+
 	// Maintainability insights
 	b.WriteString(m.renderMaintainabilityInsights(analysis))
 	b.WriteString("\n")
@@ -297,8 +306,26 @@ func (m *MetricsDisplay) renderDependencies(analysis *metrics.EnhancedProjectAna
 	}
 
 	// Circular dependencies
-	if len(analysis.DependencyGraph.CircularDependencies) > 0 {
-		b.WriteString(m.renderCircularDependencies(analysis.DependencyGraph))
+	if len(analysis.DependencyGraph.Cycles) > 0 {
+		b.WriteString(m.renderCircularDependencies(analysis.DependencyGraph, analysis.RootPath))
+		b.WriteString("\n")
+	}
+
+	// Imports that break the project's layering rules
+	if len(analysis.ArchitectureViolations) > 0 {
+		b.WriteString(m.renderArchitectureViolations(analysis.ArchitectureViolations, analysis.RootPath))
+		b.WriteString("\n")
+	}
+
+	// Declared dependencies compared with imports
+	if len(analysis.DependencyGraph.Modules) > 0 {
+		b.WriteString(m.renderModuleDependencies(analysis.DependencyGraph, analysis.RootPath))
+		b.WriteString("\n")
+	}
+
+	// Files that change together in version control
+	if len(analysis.TemporalCoupling.Pairs) > 0 {
+		b.WriteString(m.renderTemporalCoupling(analysis.TemporalCoupling))
 		b.WriteString("\n")
 	}
 
@@ -400,7 +427,16 @@ func (m *MetricsDisplay) renderComplexityAnalysis(analysis *metrics.EnhancedProj
 	b.WriteString(fmt.Sprintf("📊 Total Complexity: %s\n", tui.FormatNumber(analysis.ProjectMetrics.TotalComplexity)))
 	b.WriteString(fmt.Sprintf("📈 Average Complexity: %.1f\n", analysis.ProjectMetrics.AverageComplexity))
 	b.WriteString(fmt.Sprintf("⚠️  Maximum Complexity: %s\n", tui.FormatNumber(analysis.ProjectMetrics.MaxComplexity)))
+	b.WriteString(fmt.Sprintf("🧠 Cognitive Complexity: %s (avg %.1f, max %s per file)\n",
+		tui.FormatNumber(analysis.ProjectMetrics.TotalCognitiveComplexity),
+		analysis.ProjectMetrics.AverageCognitiveComplexity,
+		tui.FormatNumber(analysis.ProjectMetrics.MaxCognitiveComplexity)))
 	b.WriteString(fmt.Sprintf("🏗️  Maintainability Index: %.1f%%\n", analysis.ProjectMetrics.MaintainabilityIndex))
+	if analysis.ProjectMetrics.TotalHalsteadVolume > 0 {
+		b.WriteString(fmt.Sprintf("🔬 Halstead Volume: %.0f (avg %.0f per file) • 🐛 Estimated Bugs: %.1f\n",
+			analysis.ProjectMetrics.TotalHalsteadVolume, analysis.ProjectMetrics.AverageHalsteadVolume,
+			analysis.ProjectMetrics.EstimatedBugs))
+	}
 
 	// Technical debt
 	b.WriteString(fmt.Sprintf("🔧 Technical Debt: %.1f hours\n", analysis.ProjectMetrics.TechnicalDebt))
@@ -485,6 +521,13 @@ func (m *MetricsDisplay) renderProjectMetrics(metrics metrics.ProjectMetrics) st
 	b.WriteString(fmt.Sprintf("🧮 Total Complexity: %s\n", tui.FormatNumber(metrics.TotalComplexity)))
 	b.WriteString(fmt.Sprintf("📊 Average Complexity: %.2f\n", metrics.AverageComplexity))
 	b.WriteString(fmt.Sprintf("⚠️  Maximum Complexity: %s\n", tui.FormatNumber(metrics.MaxComplexity)))
+	b.WriteString(fmt.Sprintf("🧠 Total Cognitive Complexity: %s\n", tui.FormatNumber(metrics.TotalCognitiveComplexity)))
+	b.WriteString(fmt.Sprintf("🧠 Average Cognitive Complexity: %.2f\n", metrics.AverageCognitiveComplexity))
+	b.WriteString(fmt.Sprintf("🧠 Maximum Cognitive Complexity: %s\n", tui.FormatNumber(metrics.MaxCognitiveComplexity)))
+	b.WriteString(fmt.Sprintf("🔬 Halstead Volume: %.0f (avg %.1f per file)\n", metrics.TotalHalsteadVolume, metrics.AverageHalsteadVolume))
+	b.WriteString(fmt.Sprintf("🔬 Halstead Difficulty: %.2f (avg per file)\n", metrics.AverageHalsteadDifficulty))
+	b.WriteString(fmt.Sprintf("🔬 Halstead Effort: %.0f\n", metrics.TotalHalsteadEffort))
+	b.WriteString(fmt.Sprintf("🐛 Estimated Bugs: %.2f\n", metrics.EstimatedBugs))
 	b.WriteString(fmt.Sprintf("🏗️  Technical Debt Score: %.2f\n", metrics.TechnicalDebt))
 	b.WriteString(fmt.Sprintf("🔧 Maintainability Index: %.2f%%\n", metrics.MaintainabilityIndex))
 	b.WriteString(fmt.Sprintf("📚 Documentation Ratio: %.2f%%\n", metrics.DocumentationRatio))
@@ -525,10 +568,17 @@ func (m *MetricsDisplay) renderDirectoryStats(dirStats map[string]metrics.Direct
 		stats := dirStat.stats
 
 		b.WriteString(fmt.Sprintf("📁 %s:\n", path))
-		b.WriteString(fmt.Sprintf("  📄 Files: %s • 📝 Lines: %s • 🧮 Complexity: %s\n",
-			tui.FormatNumber(stats.FileCount), tui.FormatNumber(stats.LineCount), tui.FormatNumber(stats.Complexity)))
-		b.WriteString(fmt.Sprintf("  🏗️  Maintainability: %.1f%%\n", stats.MaintainabilityIndex))
-		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("  📄 Files: %s • 📝 Lines: %s • 🧮 Complexity: %s • 🧠 Cognitive: %s\n",
+			tui.FormatNumber(stats.FileCount), tui.FormatNumber(stats.LineCount), tui.FormatNumber(stats.Complexity),
+			tui.FormatNumber(stats.CognitiveComplexity)))
+		b.WriteString(fmt.Sprintf("  🏗️  Maintainability: %.1f%%", stats.MaintainabilityIndex))
+		if stats.TestCoverage > 0 {
+			b.WriteString(fmt.Sprintf(" • 🧪 Coverage: %.1f%%", stats.TestCoverage))
+		}
+		if stats.CodeDuplication > 0 {
+			b.WriteString(fmt.Sprintf(" • 📋 Duplication: %.1f%%", stats.CodeDuplication))
+		}
+		b.WriteString("\n\n")
 	}
 
 	return b.String()
@@ -555,6 +605,9 @@ func (m *MetricsDisplay) renderLanguageDetails(languages map[string]metrics.Lang
 			tui.FormatNumber(stats.FunctionCount), tui.FormatNumber(stats.ClassCount)))
 		b.WriteString(fmt.Sprintf("  🧮 Complexity: %s (Avg: %.1f, Max: %s)\n",
 			tui.FormatNumber(stats.Complexity), stats.AverageComplexity, tui.FormatNumber(stats.MaxComplexity)))
+		if stats.CognitiveComplexity > 0 {
+			b.WriteString(fmt.Sprintf("  🧠 Cognitive Complexity: %s\n", tui.FormatNumber(stats.CognitiveComplexity)))
+		}
 		b.WriteString(fmt.Sprintf("  🏗️  Maintainability: %.1f%% • 🏗️ Technical Debt: %.1f\n",
 			stats.MaintainabilityIndex, stats.TechnicalDebt))
 		if stats.TestFiles > 0 {
@@ -653,6 +706,92 @@ func (m *MetricsDisplay) renderTechnicalDebt(projectMetrics metrics.ProjectMetri
 	return b.String()
 }
 
+// renderCoverageRisks renders functions whose complexity is poorly covered by tests
+func (m *MetricsDisplay) renderCoverageRisks(analysis *metrics.EnhancedProjectAnalysis) string {
+	var b strings.Builder
+
+	SectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	b.WriteString(SectionStyle.Render("🎯 Coverage Risks") + "\n")
+
+	if len(analysis.CoverageReports) == 0 {
+		b.WriteString("No coverage reports found (coverage.out, coverage.xml, lcov.info, jacoco.xml)\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("🧪 Test Coverage: %.1f%% from %d report(s)\n",
+		analysis.ProjectMetrics.TestCoverage, len(analysis.CoverageReports)))
+
+	if len(analysis.CoverageRisks) == 0 {
+		b.WriteString("✅ No complex functions lacking test coverage\n")
+		return b.String()
+	}
+
+	for i, risk := range analysis.CoverageRisks {
+		if i >= 10 {
+			b.WriteString(fmt.Sprintf("   ... and %d more\n", len(analysis.CoverageRisks)-10))
+			break
+		}
+		b.WriteString(fmt.Sprintf("⚠️  %s (%s:%d) • CRAP %.0f • Complexity %d • Coverage %.0f%%\n",
+			risk.Function, filepath.Base(risk.FilePath), risk.Line, risk.CRAP, risk.Complexity, risk.Coverage))
+	}
+
+	return b.String()
+}
+
+// renderClassDesign renders classes with design smells and the least cohesive classes
+func (m *MetricsDisplay) renderClassDesign(classes []metrics.ClassCohesion) string {
+	var b strings.Builder
+
+	SectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	b.WriteString(SectionStyle.Render("🧱 Class Design") + "\n")
+
+	if len(classes) == 0 {
+		b.WriteString("No classes with methods found\n")
+		return b.String()
+	}
+
+	var smelly, incohesive []metrics.ClassCohesion
+	for _, class := range classes {
+		if len(class.Smells) > 0 {
+			smelly = append(smelly, class)
+		} else if class.LCOM4 > 1 {
+			incohesive = append(incohesive, class)
+		}
+	}
+
+	if len(smelly) == 0 {
+		b.WriteString("✅ No god classes or data classes\n")
+	}
+	for i, class := range smelly {
+		if i >= 10 {
+			b.WriteString(fmt.Sprintf("   ... and %d more\n", len(smelly)-10))
+			break
+		}
+		b.WriteString(fmt.Sprintf("⚠️  %s: %s (%s:%d-%d) • WMC %d • TCC %.2f • ATFD %d • LCOM4 %d\n",
+			strings.Join(class.Smells, ", "), class.Class, filepath.Base(class.FilePath), class.LineStart, class.LineEnd,
+			class.WMC, class.TCC, class.ATFD, class.LCOM4))
+	}
+
+	// Classes whose methods fall into unrelated groups could be split
+	for i, class := range incohesive {
+		if i >= 5 {
+			b.WriteString(fmt.Sprintf("   ... and %d more classes lacking cohesion\n", len(incohesive)-5))
+			break
+		}
+		b.WriteString(fmt.Sprintf("🧩 %s (%s:%d-%d) • LCOM4 %d • LCOM-HS %.2f • %d methods, %d fields\n",
+			class.Class, filepath.Base(class.FilePath), class.LineStart, class.LineEnd,
+			class.LCOM4, class.LCOMHS, class.MethodCount, class.FieldCount))
+	}
+
+	return b.String()
+}
+
 // renderMaintainabilityInsights renders maintainability insights
 func (m *MetricsDisplay) renderMaintainabilityInsights(analysis *metrics.EnhancedProjectAnalysis) string {
 	var b strings.Builder
@@ -686,9 +825,10 @@ func (m *MetricsDisplay) renderDependencyOverview(graph metrics.DependencyGraph)
 	b.WriteString(fmt.Sprintf("🏠 Internal Dependencies: %d\n", len(graph.InternalDependencies)))
 	b.WriteString(fmt.Sprintf("🌐 External Dependencies: %d\n", len(graph.ExternalDependencies)))
 	b.WriteString(fmt.Sprintf("📚 Standard Library: %d\n", len(graph.StandardDependencies)))
-	b.WriteString(fmt.Sprintf("🔄 Circular Dependencies: %d\n", len(graph.CircularDependencies)))
+	b.WriteString(fmt.Sprintf("🔄 Circular Dependencies: %d\n", len(graph.Cycles)))
 	b.WriteString(fmt.Sprintf("📊 Dependency Depth: %d\n", graph.DependencyDepth))
 	b.WriteString(fmt.Sprintf("🗑️  Unused Dependencies: %d\n", len(graph.UnusedDependencies)))
+	b.WriteString(fmt.Sprintf("❓ Undeclared Dependencies: %d\n", len(graph.UndeclaredDependencies)))
 
 	return b.String()
 }
@@ -769,22 +909,138 @@ func (m *MetricsDisplay) renderStandardDependencies(graph metrics.DependencyGrap
 	return b.String()
 }
 
-// renderCircularDependencies renders circular dependencies
-func (m *MetricsDisplay) renderCircularDependencies(graph metrics.DependencyGraph) string {
+// renderCircularDependencies renders each group of packages that depend on each
+// other, with its shortest cycles and the imports that close them
+func (m *MetricsDisplay) renderCircularDependencies(graph metrics.DependencyGraph, rootPath string) string {
 	var b strings.Builder
 
 	SectionStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FF6B6B")).
 		Bold(true)
 
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(rootPath, path); err == nil {
+			return rel
+		}
+		return path
+	}
+
 	b.WriteString(SectionStyle.Render("🔄 Circular Dependencies (Issues)") + "\n")
 
-	for i, cycle := range graph.CircularDependencies {
+	for i, cycle := range graph.Cycles {
 		if i >= 5 { // Limit display
-			b.WriteString(fmt.Sprintf("  ... and %d more cycles\n", len(graph.CircularDependencies)-5))
+			b.WriteString(fmt.Sprintf("  ... and %d more cycles\n", len(graph.Cycles)-5))
+			break
+		}
+		b.WriteString(fmt.Sprintf("⚠️  Cycle %d: %d packages, %d imports\n", i+1, len(cycle.Packages), len(cycle.Imports)))
+		for j, path := range cycle.Cycles {
+			if j >= 3 { // Limit paths per cycle
+				b.WriteString(fmt.Sprintf("      ... and %d more paths\n", len(cycle.Cycles)-3))
+				break
+			}
+			names := make([]string, len(path))
+			for k, pkg := range path {
+				names[k] = relative(pkg)
+			}
+			b.WriteString(fmt.Sprintf("    🔄 %s\n", strings.Join(names, " → ")))
+		}
+		for j, site := range cycle.Imports {
+			if j >= 5 { // Limit imports per cycle
+				b.WriteString(fmt.Sprintf("      ... and %d more imports\n", len(cycle.Imports)-5))
+				break
+			}
+			b.WriteString(fmt.Sprintf("    📄 %s:%d imports %s\n", relative(site.FilePath), site.Line, site.Import))
+		}
+	}
+
+	return b.String()
+}
+
+// renderArchitectureViolations renders the imports that break the project's
+// architecture rules
+func (m *MetricsDisplay) renderArchitectureViolations(violations []metrics.ArchitectureViolation, rootPath string) string {
+	var b strings.Builder
+
+	SectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF6B6B")).
+		Bold(true)
+
+	b.WriteString(SectionStyle.Render(fmt.Sprintf("🚫 Architecture Violations (%d)", len(violations))) + "\n")
+
+	for i, v := range violations {
+		if i >= 10 { // Limit display
+			b.WriteString(fmt.Sprintf("  ... and %d more violations\n", len(violations)-10))
 			break
 		}
-		b.WriteString(fmt.Sprintf("⚠️  Cycle %d: %s\n", i+1, strings.Join(cycle, " → ")))
+		location := v.FilePath
+		if rel, err := filepath.Rel(rootPath, v.FilePath); err == nil {
+			location = rel
+		}
+		b.WriteString(fmt.Sprintf("⛔ %s:%d imports %s\n", location, v.Line, v.Target))
+		b.WriteString(fmt.Sprintf("    breaks %s\n", v.Rule))
+	}
+
+	return b.String()
+}
+
+// renderModuleDependencies renders, per manifest, the declared dependencies
+// that are never imported and the imported packages that are not declared
+func (m *MetricsDisplay) renderModuleDependencies(graph metrics.DependencyGraph, rootPath string) string {
+	var b strings.Builder
+
+	SectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#87CEEB")).
+		Bold(true)
+
+	b.WriteString(SectionStyle.Render("📦 Declared Dependencies") + "\n")
+
+	for _, module := range graph.Modules {
+		location := module.Path
+		if rel, err := filepath.Rel(rootPath, module.Path); err == nil {
+			location = rel
+		}
+		b.WriteString(fmt.Sprintf("📄 %s (%s, %d declared)\n", location, module.Ecosystem, module.Declared))
+		for i, name := range module.Unused {
+			if i >= 5 { // Limit deps per module
+				b.WriteString(fmt.Sprintf("      ... and %d more unused\n", len(module.Unused)-5))
+				break
+			}
+			b.WriteString(fmt.Sprintf("    🗑️  %s (declared, never imported)\n", name))
+		}
+		for i, name := range module.Undeclared {
+			if i >= 5 { // Limit deps per module
+				b.WriteString(fmt.Sprintf("      ... and %d more undeclared\n", len(module.Undeclared)-5))
+				break
+			}
+			b.WriteString(fmt.Sprintf("    ❓ %s (imported, not declared)\n", name))
+		}
+	}
+
+	return b.String()
+}
+
+// renderTemporalCoupling renders file pairs that change together, flagging
+// pairs that have no import between them
+func (m *MetricsDisplay) renderTemporalCoupling(coupling metrics.TemporalCoupling) string {
+	var b strings.Builder
+
+	SectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFA500")).
+		Bold(true)
+
+	b.WriteString(SectionStyle.Render("🔗 Temporal Coupling") + "\n")
+
+	for i, pair := range coupling.Pairs {
+		if i >= 10 { // Limit display
+			b.WriteString(fmt.Sprintf("  ... and %d more pairs\n", len(coupling.Pairs)-10))
+			break
+		}
+		note := ""
+		if !pair.HasDependency {
+			note = " ⚠️  no import"
+		}
+		b.WriteString(fmt.Sprintf("📄 %s ⇄ %s: %.0f%% (%d commits)%s\n",
+			filepath.Base(pair.FileA), filepath.Base(pair.FileB), pair.Strength, pair.SharedCommits, note))
 	}
 
 	return b.String()
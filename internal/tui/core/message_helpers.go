@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -22,6 +23,17 @@ func NewErrorMsg(err error) tea.Cmd {
 	}
 }
 
+// NewFileSelectedMsg reads a file and creates a message that opens it at the given line
+func NewFileSelectedMsg(filePath string, line int) tea.Cmd {
+	return func() tea.Msg {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+		return FileSelectedMsg{FilePath: filePath, Content: string(content), Line: line}
+	}
+}
+
 // NewProgressUpdateMsg creates a progress update message
 func NewProgressUpdateMsg(current, total int, filePath, message string) tea.Cmd {
 	return func() tea.Msg {
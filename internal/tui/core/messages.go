@@ -1,6 +1,7 @@
 package core
 
 import (
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
 	"github.com/tito-sala/codebasereaderv2/internal/engine"
 	"github.com/tito-sala/codebasereaderv2/internal/metrics"
 	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
@@ -27,6 +28,16 @@ type EnhancedAnalysisCompleteMsg struct {
 	Summary          string
 }
 
+// RevisionDiffCompleteMsg is sent when the comparison of two revisions is complete
+type RevisionDiffCompleteMsg struct {
+	Diff *metrics.RevisionDiff
+}
+
+// ComparisonCompleteMsg is sent when two saved analyses have been compared
+type ComparisonCompleteMsg struct {
+	Comparison *compare.Comparison
+}
+
 // ErrorMsg is an alias for shared.ErrorMsg
 type ErrorMsg = shared.ErrorMsg
 
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
 	"github.com/tito-sala/codebasereaderv2/internal/engine"
 	"github.com/tito-sala/codebasereaderv2/internal/parser"
 	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
@@ -48,6 +49,9 @@ func NewMainModel() *MainModel {
 	// Register parsers
 	analysisEngine.GetParserRegistry().RegisterParser(parser.NewGoParser())
 	analysisEngine.GetParserRegistry().RegisterParser(parser.NewPythonParser())
+	analysisEngine.GetParserRegistry().RegisterParser(parser.NewSQLParser())
+	analysisEngine.GetParserRegistry().RegisterParser(parser.NewJavaScriptParser())
+	analysisEngine.GetParserRegistry().RegisterParser(parser.NewComponentParser(analysisEngine.GetParserRegistry()))
 
 	// Create progress bar with custom styling
 	prog := progress.New(progress.WithDefaultGradient())
@@ -217,7 +221,12 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case VisualizationView:
-			// Handle visualization navigation
+			// Open the selected declaration, or handle visualization navigation
+			if msg.String() == "enter" {
+				if filePath, line, ok := m.visualizationView.SelectedSource(); ok {
+					return m, NewFileSelectedMsg(filePath, line)
+				}
+			}
 			m.visualizationView.Update(msg.String())
 
 		case HelpView:
@@ -237,6 +246,10 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case FileSelectedMsg:
 		m.currentView = ContentView
 		m.contentView.SetContent(msg.FilePath, msg.Content)
+		if msg.Line > 0 {
+			m.tabs.SetActiveTab(1)
+			m.contentView.ScrollToLine(msg.Line)
+		}
 		return m, nil
 
 	case AnalysisCompleteMsg:
@@ -277,10 +290,31 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Summary:                 m.analysisData.Summary,
 		}
 		m.visualizationView.SetAnalysisData(vizData)
+		if m.analysisEngine != nil {
+			if snapshots, err := m.analysisEngine.LoadTrends(msg.EnhancedAnalysis.RootPath); err == nil {
+				m.visualizationView.SetTrends(snapshots)
+			}
+		}
 		// Stay in current view, let user decide when to switch
 
 		return m, nil
 
+	case RevisionDiffCompleteMsg:
+		m.visualizationView.SetRevisionDiff(msg.Diff)
+		m.visualizationView.SetMode(views.RevisionDiffMode)
+		m.tabs.SetActiveTab(2)
+		m.currentView = VisualizationView
+		m.statusBar.SetMessage(fmt.Sprintf("Compared %s - %d files, %d functions changed", msg.Diff.Range, len(msg.Diff.Files), len(msg.Diff.Functions)))
+		return m, nil
+
+	case ComparisonCompleteMsg:
+		m.visualizationView.SetComparison(msg.Comparison)
+		m.visualizationView.SetMode(views.CompareMode)
+		m.tabs.SetActiveTab(2)
+		m.currentView = VisualizationView
+		m.statusBar.SetMessage(fmt.Sprintf("Compared analyses - %d files, %d regressed functions", len(msg.Comparison.Changes.Files), len(msg.Comparison.Regressions)))
+		return m, nil
+
 	case ErrorMsg:
 		m.error = msg.Error
 		m.loading = false
@@ -560,6 +594,9 @@ func (m *MainModel) renderConfigView(width int) string {
 		"set api_key <key>                  - Set API key",
 		"set max_workers <number>           - Set worker count",
 		"set timeout <seconds>              - Set timeout",
+		"set record_trends <true|false>     - Record runs in the trend store",
+		"diff <base>..<head>                - Compare two git revisions",
+		"compare <before.json> <after.json> - Compare two saved analyses",
 		"add_exclude <pattern>              - Add exclude pattern",
 		"remove_exclude <pattern>           - Remove exclude pattern",
 		"show config                        - Show current config",
@@ -893,7 +930,13 @@ func (m *MainModel) updateStatusBarKeyBinds() {
 		keyBinds = append(keyBinds, components.KeyBind{Key: " ↑↓", Description: "scroll"})
 	case VisualizationView:
 		if m.analysisData != nil {
-			keyBinds = append(keyBinds, components.KeyBind{Key: " 1-6", Description: "vis modes"})
+			keyBinds = append(keyBinds, components.KeyBind{Key: " 0-9/d", Description: "vis modes"})
+			switch m.visualizationView.GetCurrentMode() {
+			case views.FunctionUsageMode:
+				keyBinds = append(keyBinds, components.KeyBind{Key: " n/p", Description: "call root"})
+			case views.DeadCodeMode:
+				keyBinds = append(keyBinds, components.KeyBind{Key: " enter", Description: "open source"})
+			}
 		}
 		keyBinds = append(keyBinds, components.KeyBind{Key: " ↑↓", Description: "scroll"})
 	case ConfigView:
@@ -943,6 +986,22 @@ func (m *MainModel) processConfigCommand(command string) tea.Cmd {
 			return m.resetConfig()
 		}
 
+	case "diff":
+		if len(parts) != 2 {
+			return func() tea.Msg {
+				return StatusUpdateMsg{Message: "Usage: diff <base>..<head>"}
+			}
+		}
+		return m.compareRevisions(parts[1])
+
+	case "compare":
+		if len(parts) != 3 {
+			return func() tea.Msg {
+				return StatusUpdateMsg{Message: "Usage: compare <before.json> <after.json>"}
+			}
+		}
+		return m.compareAnalyses(parts[1], parts[2])
+
 	case "add_exclude":
 		if len(parts) < 2 {
 			return func() tea.Msg {
@@ -972,6 +1031,48 @@ func (m *MainModel) processConfigCommand(command string) tea.Cmd {
 	}
 }
 
+// compareRevisions compares the files changed in a revision range of the
+// analyzed directory, or of the explorer's directory before any analysis
+func (m *MainModel) compareRevisions(revisionRange string) tea.Cmd {
+	if m.analysisEngine == nil {
+		return func() tea.Msg {
+			return StatusUpdateMsg{Message: "Analysis engine not initialized"}
+		}
+	}
+
+	root := m.fileTree.GetRootPath()
+	if m.analysisData != nil && m.analysisData.EnhancedProjectAnalysis != nil {
+		root = m.analysisData.EnhancedProjectAnalysis.RootPath
+	}
+	m.statusBar.SetMessage(fmt.Sprintf("Comparing %s...", revisionRange))
+
+	analysisEngine := m.analysisEngine
+	return func() tea.Msg {
+		diff, err := analysisEngine.AnalyzeRevisions(root, revisionRange)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+		return RevisionDiffCompleteMsg{Diff: diff}
+	}
+}
+
+// compareAnalyses compares two analyses saved with "codebasereader analyze -json"
+func (m *MainModel) compareAnalyses(beforePath, afterPath string) tea.Cmd {
+	m.statusBar.SetMessage(fmt.Sprintf("Comparing %s with %s...", beforePath, afterPath))
+
+	return func() tea.Msg {
+		before, err := compare.Load(beforePath)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+		after, err := compare.Load(afterPath)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+		return ComparisonCompleteMsg{Comparison: compare.Compare(before, after)}
+	}
+}
+
 // updateConfig updates a configuration value
 func (m *MainModel) updateConfig(key, value string) tea.Cmd {
 	if m.analysisEngine == nil {
@@ -1013,6 +1114,18 @@ func (m *MainModel) updateConfig(key, value string) tea.Cmd {
 				return StatusUpdateMsg{Message: "Max workers must be between 1 and 16"}
 			}
 		}
+	case "record_trends":
+		if value == "true" || value == "false" {
+			config.RecordTrends = value == "true"
+			m.inputField.SetValue("")
+			return func() tea.Msg {
+				return StatusUpdateMsg{Message: fmt.Sprintf("Recording trends set to %s", value)}
+			}
+		} else {
+			return func() tea.Msg {
+				return StatusUpdateMsg{Message: "record_trends must be 'true' or 'false'"}
+			}
+		}
 	case "timeout":
 		if timeout := parseInt(value); timeout > 0 && timeout <= 300 {
 			config.Timeout = timeout
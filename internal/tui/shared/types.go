@@ -51,6 +51,7 @@ type ErrorMsg struct {
 type FileSelectedMsg struct {
 	FilePath string
 	Content  string
+	Line     int // line to scroll to, when jumping to a declaration
 }
 
 // ShowConfirmationMsg is sent to show a confirmation dialog
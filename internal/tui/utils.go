@@ -71,6 +71,16 @@ func GetLangIcon(lang string) string {
 		return "PHP"
 	case "ruby":
 		return "💎"
+	case "sql":
+		return "🗄️"
+	case "vue":
+		return "💚"
+	case "svelte":
+		return "🔥"
+	case "html":
+		return "🌐"
+	case "css", "scss", "sass", "less", "stylus":
+		return "🎨"
 	default:
 		return "📄"
 	}
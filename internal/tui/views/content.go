@@ -281,6 +281,11 @@ func (m *ContentViewModel) SetContent(filePath, content string) {
 	m.cachedContent = ""
 }
 
+// ScrollToLine scrolls so that the given 1-based line is near the top
+func (m *ContentViewModel) ScrollToLine(line int) {
+	m.scrollY = max(0, line-3)
+}
+
 // SetMetrics sets metrics content
 func (m *ContentViewModel) SetMetrics(metrics string) {
 	if m.showMetrics {
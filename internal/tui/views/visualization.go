@@ -2,10 +2,15 @@ package views
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
 	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+	"github.com/tito-sala/codebasereaderv2/internal/trends"
 	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
 )
 
@@ -19,6 +24,14 @@ const (
 	QualityGaugesMode
 	TechnicalDebtMode
 	FunctionUsageMode
+	DuplicationMode
+	GitHistoryMode
+	HotspotsMode
+	PackageCouplingMode
+	DeadCodeMode
+	RevisionDiffMode
+	CompareMode
+	TrendsMode
 )
 
 // VisualizationViewModel handles the visualization system
@@ -31,6 +44,17 @@ type VisualizationViewModel struct {
 	height        int
 	analysisData  *AnalysisData
 	filterOptions FilterOptions
+	// Root and depth of the call tree in the function usage view
+	selectedFunction int
+	callTreeDepth    int
+	// Selected entry in the dead code view
+	selectedDeadCode int
+	// Comparison of two revisions, loaded separately from the analysis
+	revisionDiff *metrics.RevisionDiff
+	// Comparison of two saved analyses
+	comparison *compare.Comparison
+	// Snapshots recorded in the trend store of the analyzed project
+	trendSnapshots []trends.Snapshot
 }
 
 // VisualizationModeInfo contains information about a visualization mode
@@ -59,10 +83,11 @@ type AnalysisData struct {
 // NewVisualizationViewModel creates a new visualization view model
 func NewVisualizationViewModel() *VisualizationViewModel {
 	return &VisualizationViewModel{
-		currentMode: DependencyTreeMode,
-		modes:       createVisualizationModes(),
-		scrollY:     0,
-		maxScroll:   0,
+		currentMode:   DependencyTreeMode,
+		modes:         createVisualizationModes(),
+		scrollY:       0,
+		maxScroll:     0,
+		callTreeDepth: defaultCallTreeDepth,
 		filterOptions: FilterOptions{
 			Language:      "",
 			MinComplexity: 0,
@@ -112,6 +137,54 @@ func createVisualizationModes() []VisualizationModeInfo {
 			Description: "Function call frequency and usage patterns",
 			ShortKey:    "6",
 		},
+		{
+			Name:        "Code Duplication",
+			Icon:        "📋",
+			Description: "Largest clone groups across the project",
+			ShortKey:    "7",
+		},
+		{
+			Name:        "Git History",
+			Icon:        "🕰️",
+			Description: "Churn, authorship and age of files and directories",
+			ShortKey:    "8",
+		},
+		{
+			Name:        "Hotspots",
+			Icon:        "🎯",
+			Description: "Complex code that changes often",
+			ShortKey:    "9",
+		},
+		{
+			Name:        "Package Coupling",
+			Icon:        "📦",
+			Description: "Afferent and efferent coupling, instability and abstractness",
+			ShortKey:    "0",
+		},
+		{
+			Name:        "Dead Code",
+			Icon:        "🪦",
+			Description: "Unreferenced functions, methods and types",
+			ShortKey:    "d",
+		},
+		{
+			Name:        "Revision Diff",
+			Icon:        "🔀",
+			Description: "Function and metric changes between two git revisions",
+			ShortKey:    "r",
+		},
+		{
+			Name:        "Compare",
+			Icon:        "⚖️",
+			Description: "Differences between two saved analyses",
+			ShortKey:    "v",
+		},
+		{
+			Name:        "Trends",
+			Icon:        "📈",
+			Description: "Metrics recorded over time, per project and directory",
+			ShortKey:    "t",
+		},
 	}
 }
 
@@ -119,6 +192,25 @@ func createVisualizationModes() []VisualizationModeInfo {
 func (v *VisualizationViewModel) SetAnalysisData(data *AnalysisData) {
 	v.analysisData = data
 	v.scrollY = 0
+	v.selectedFunction = 0
+	v.selectedDeadCode = 0
+}
+
+// SetRevisionDiff sets the comparison shown in the revision diff view
+func (v *VisualizationViewModel) SetRevisionDiff(diff *metrics.RevisionDiff) {
+	v.revisionDiff = diff
+	v.scrollY = 0
+}
+
+// SetComparison sets the comparison shown in the compare view
+func (v *VisualizationViewModel) SetComparison(comparison *compare.Comparison) {
+	v.comparison = comparison
+	v.scrollY = 0
+}
+
+// SetTrends sets the snapshots shown in the trends view
+func (v *VisualizationViewModel) SetTrends(snapshots []trends.Snapshot) {
+	v.trendSnapshots = snapshots
 }
 
 // GetCurrentMode returns the current visualization mode
@@ -159,6 +251,22 @@ func (v *VisualizationViewModel) Update(key string) {
 		v.SetMode(TechnicalDebtMode)
 	case "6":
 		v.SetMode(FunctionUsageMode)
+	case "7":
+		v.SetMode(DuplicationMode)
+	case "8":
+		v.SetMode(GitHistoryMode)
+	case "9":
+		v.SetMode(HotspotsMode)
+	case "0":
+		v.SetMode(PackageCouplingMode)
+	case "d":
+		v.SetMode(DeadCodeMode)
+	case "r":
+		v.SetMode(RevisionDiffMode)
+	case "v":
+		v.SetMode(CompareMode)
+	case "t":
+		v.SetMode(TrendsMode)
 	case "up", "k":
 		if v.scrollY > 0 {
 			v.scrollY--
@@ -175,6 +283,13 @@ func (v *VisualizationViewModel) Update(key string) {
 		v.scrollY = 0
 	case "end", "G":
 		v.scrollY = v.maxScroll
+	case "n", "p", "+", "=", "-":
+		switch v.currentMode {
+		case FunctionUsageMode:
+			v.updateCallTree(key)
+		case DeadCodeMode:
+			v.updateDeadCodeSelection(key)
+		}
 	}
 }
 
@@ -234,6 +349,15 @@ func (v *VisualizationViewModel) renderModeTabs() string {
 
 // renderCurrentMode renders the currently selected visualization mode
 func (v *VisualizationViewModel) renderCurrentMode() string {
+	// Comparisons do not depend on an analysis of the working tree
+	switch v.currentMode {
+	case RevisionDiffMode:
+		return v.renderRevisionDiff()
+	case CompareMode:
+		return v.renderComparison()
+	case TrendsMode:
+		return v.renderTrends()
+	}
 	if v.analysisData == nil || v.analysisData.EnhancedProjectAnalysis == nil {
 		return v.renderNoData()
 	}
@@ -251,6 +375,16 @@ func (v *VisualizationViewModel) renderCurrentMode() string {
 		return v.renderTechnicalDebt()
 	case FunctionUsageMode:
 		return v.renderFunctionUsage()
+	case DuplicationMode:
+		return v.renderDuplication()
+	case GitHistoryMode:
+		return v.renderGitHistory()
+	case HotspotsMode:
+		return v.renderHotspots()
+	case PackageCouplingMode:
+		return v.renderPackageCoupling()
+	case DeadCodeMode:
+		return v.renderDeadCode()
 	default:
 		return v.renderNoData()
 	}
@@ -304,25 +438,60 @@ func (v *VisualizationViewModel) renderDependencyTree() string {
 	}
 
 	// Circular dependencies (critical issues)
-	if len(deps.CircularDependencies) > 0 {
+	if len(deps.Cycles) > 0 {
 		b.WriteString("⚠️  Circular Dependencies (Critical Issues):\n")
-		for i, cycle := range deps.CircularDependencies {
+		for i, cycle := range deps.Cycles {
 			if i >= 5 {
-				b.WriteString(fmt.Sprintf("   ... and %d more cycles\n", len(deps.CircularDependencies)-5))
+				b.WriteString(fmt.Sprintf("   ... and %d more cycles\n", len(deps.Cycles)-5))
+				break
+			}
+			names := make([]string, len(cycle.Cycles[0]))
+			for j, pkg := range cycle.Cycles[0] {
+				names[j] = v.relativePath(pkg)
+			}
+			b.WriteString(fmt.Sprintf("🔄 %s (%d packages)\n", strings.Join(names, " → "), len(cycle.Packages)))
+			for j, site := range cycle.Imports {
+				if j >= 3 {
+					b.WriteString(fmt.Sprintf("│   └── ... and %d more imports\n", len(cycle.Imports)-3))
+					break
+				}
+				b.WriteString(fmt.Sprintf("│   ├── %s:%d → %s\n", v.relativePath(site.FilePath), site.Line, site.Import))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Imports that break the project's architecture rules
+	if len(analysis.ArchitectureViolations) > 0 {
+		b.WriteString("🚫 Architecture Violations:\n")
+		for i, violation := range analysis.ArchitectureViolations {
+			if i >= 10 {
+				b.WriteString(fmt.Sprintf("   ... and %d more violations\n", len(analysis.ArchitectureViolations)-10))
 				break
 			}
-			b.WriteString(fmt.Sprintf("🔄 %s\n", strings.Join(cycle, " → ")))
+			b.WriteString(fmt.Sprintf("⛔ %s → %s  %s:%d (%s)\n", violation.From, violation.Target,
+				v.relativePath(violation.FilePath), violation.Line, violation.Rule))
 		}
 		b.WriteString("\n")
 	}
 
+	// Files that change together, with or without an import between them
+	if coupling := v.renderTemporalCoupling(); coupling != "" {
+		b.WriteString(coupling)
+		b.WriteString("\n")
+	}
+
 	// Dependency statistics
 	b.WriteString("📊 Dependency Statistics:\n")
 	b.WriteString(fmt.Sprintf("• Internal Dependencies: %d files\n", len(deps.InternalDependencies)))
 	b.WriteString(fmt.Sprintf("• External Dependencies: %d files\n", len(deps.ExternalDependencies)))
 	b.WriteString(fmt.Sprintf("• Standard Library: %d files\n", len(deps.StandardDependencies)))
-	b.WriteString(fmt.Sprintf("• Circular Dependencies: %d cycles\n", len(deps.CircularDependencies)))
+	b.WriteString(fmt.Sprintf("• Circular Dependencies: %d cycles\n", len(deps.Cycles)))
 	b.WriteString(fmt.Sprintf("• Dependency Depth: %d levels\n", deps.DependencyDepth))
+	b.WriteString(fmt.Sprintf("• Temporally Coupled Pairs: %d\n", len(analysis.TemporalCoupling.Pairs)))
+	b.WriteString(fmt.Sprintf("• Declared but Unused: %d\n", len(deps.UnusedDependencies)))
+	b.WriteString(fmt.Sprintf("• Imported but Undeclared: %d\n", len(deps.UndeclaredDependencies)))
+	b.WriteString(fmt.Sprintf("• Architecture Violations: %d\n", len(analysis.ArchitectureViolations)))
 
 	return v.applyScrolling(b.String())
 }
@@ -342,6 +511,7 @@ func (v *VisualizationViewModel) renderComplexityHeatmap() string {
 	type dirComplexity struct {
 		path       string
 		complexity int
+		cognitive  int
 		files      int
 	}
 
@@ -350,6 +520,7 @@ func (v *VisualizationViewModel) renderComplexityHeatmap() string {
 		dirs = append(dirs, dirComplexity{
 			path:       path,
 			complexity: stats.Complexity,
+			cognitive:  stats.CognitiveComplexity,
 			files:      stats.FileCount,
 		})
 	}
@@ -403,10 +574,12 @@ func (v *VisualizationViewModel) renderComplexityHeatmap() string {
 		bar := strings.Repeat(barChar, barLength)
 		coloredBar := lipgloss.NewStyle().Foreground(barColor).Render(bar)
 
-		b.WriteString(fmt.Sprintf("%-30s %s %d (%d files)\n",
-			dir.path, coloredBar, dir.complexity, dir.files))
+		b.WriteString(fmt.Sprintf("%-30s %s %d (🧠 %d, %d files)\n",
+			dir.path, coloredBar, dir.complexity, dir.cognitive, dir.files))
 	}
 
+	b.WriteString(v.renderCognitiveHotspots())
+
 	b.WriteString("\n📈 Complexity Legend:\n")
 	b.WriteString("🟥 High (>80%):    Requires immediate attention\n")
 	b.WriteString("🟧 Medium-High (60-80%): Consider refactoring\n")
@@ -416,6 +589,63 @@ func (v *VisualizationViewModel) renderComplexityHeatmap() string {
 	return v.applyScrolling(b.String())
 }
 
+// renderCognitiveHotspots lists the functions that are hardest to understand
+func (v *VisualizationViewModel) renderCognitiveHotspots() string {
+	fileResults, ok := v.analysisData.EnhancedProjectAnalysis.FileResults.([]*parser.AnalysisResult)
+	if !ok || len(fileResults) == 0 {
+		return ""
+	}
+
+	type functionComplexity struct {
+		name       string
+		file       string
+		line       int
+		cognitive  int
+		cyclomatic int
+	}
+
+	var functions []functionComplexity
+	for _, result := range fileResults {
+		for _, fn := range result.Functions {
+			functions = append(functions, functionComplexity{fn.Name, result.FilePath, fn.LineStart, fn.CognitiveComplexity, fn.Complexity})
+		}
+		for _, class := range result.Classes {
+			for _, method := range class.Methods {
+				functions = append(functions, functionComplexity{class.Name + "." + method.Name, result.FilePath, method.LineStart, method.CognitiveComplexity, method.Complexity})
+			}
+		}
+	}
+
+	sort.Slice(functions, func(i, j int) bool {
+		return functions[i].cognitive > functions[j].cognitive
+	})
+
+	var b strings.Builder
+	b.WriteString("\n🧠 Highest Cognitive Complexity:\n")
+
+	for i, fn := range functions {
+		if i >= 10 || fn.cognitive == 0 {
+			break
+		}
+
+		// Cognitive complexity above 15 is commonly treated as hard to understand
+		color := lipgloss.Color("#00FF00")
+		if fn.cognitive > 25 {
+			color = lipgloss.Color("#FF0000")
+		} else if fn.cognitive > 15 {
+			color = lipgloss.Color("#FF8800")
+		} else if fn.cognitive > 8 {
+			color = lipgloss.Color("#FFFF00")
+		}
+
+		score := lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%3d", fn.cognitive))
+		b.WriteString(fmt.Sprintf("%s  %-30s %s:%d (cyclomatic %d)\n",
+			score, fn.name, filepath.Base(fn.file), fn.line, fn.cyclomatic))
+	}
+
+	return b.String()
+}
+
 // renderLanguageComposition renders language composition charts
 func (v *VisualizationViewModel) renderLanguageComposition() string {
 	var b strings.Builder
@@ -479,6 +709,21 @@ func (v *VisualizationViewModel) renderLanguageComposition() string {
 		case "java":
 			langIcon = "☕"
 			barColor = lipgloss.Color("#ED8B00")
+		case "sql":
+			langIcon = "🗄️"
+			barColor = lipgloss.Color("#E38C00")
+		case "vue":
+			langIcon = "💚"
+			barColor = lipgloss.Color("#42B883")
+		case "svelte":
+			langIcon = "🔥"
+			barColor = lipgloss.Color("#FF3E00")
+		case "html":
+			langIcon = "🌐"
+			barColor = lipgloss.Color("#E34F26")
+		case "css", "scss", "sass", "less", "stylus":
+			langIcon = "🎨"
+			barColor = lipgloss.Color("#663399")
 		default:
 			langIcon = "📄"
 			barColor = lipgloss.Color("#888888")
@@ -759,21 +1004,10 @@ func (v *VisualizationViewModel) renderFunctionUsage() string {
 		b.WriteString("• Use static analysis tools for function dependency tracking\n")
 	}
 
-	// Mock function usage tree (since we don't have actual call graph data yet)
-	b.WriteString("\n🌳 Sample Function Call Tree:\n")
-	b.WriteString("├── main()\n")
-	b.WriteString("│   ├── initializeApp()\n")
-	b.WriteString("│   ├── processFiles()\n")
-	b.WriteString("│   │   ├── walkDirectory()\n")
-	b.WriteString("│   │   └── analyzeFile()\n")
-	b.WriteString("│   │       ├── parseCode()\n")
-	b.WriteString("│   │       └── calculateMetrics()\n")
-	b.WriteString("│   └── generateReport()\n")
 	b.WriteString("\n")
-	b.WriteString("📝 Note: Detailed function call graphs require additional\n")
-	b.WriteString("    static analysis and will be available in future updates.\n")
+	b.WriteString(v.renderCallGraph())
 
-	return v.applyScrolling(b.String())
+	return b.String()
 }
 
 // Helper methods for visualization rendering
@@ -908,6 +1142,16 @@ func (v *VisualizationViewModel) getLangIcon(lang string) string {
 		return "🐘"
 	case "ruby":
 		return "💎"
+	case "sql":
+		return "🗄️"
+	case "vue":
+		return "💚"
+	case "svelte":
+		return "🔥"
+	case "html":
+		return "🌐"
+	case "css", "scss", "sass", "less", "stylus":
+		return "🎨"
 	default:
 		return "📄"
 	}
@@ -915,7 +1159,13 @@ func (v *VisualizationViewModel) getLangIcon(lang string) string {
 
 // renderFooter renders the visualization footer with navigation hints
 func (v *VisualizationViewModel) renderFooter() string {
-	navigation := "Navigate: ←→/hl (modes) • ↑↓/kj (scroll) • 1-6 (jump) • f (filter)"
+	navigation := "Navigate: ←→/hl (modes) • ↑↓/kj (scroll) • 0-9/d/r/v/t (jump) • f (filter)"
+	switch v.currentMode {
+	case FunctionUsageMode:
+		navigation += " • n/p (call root) • +/- (depth)"
+	case DeadCodeMode:
+		navigation += " • n/p (select) • enter (open source)"
+	}
 	return components.HelpStyle.
 		Align(lipgloss.Center).
 		Render(navigation)
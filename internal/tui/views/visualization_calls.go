@@ -0,0 +1,135 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/callgraph"
+)
+
+// Call graph display limits
+const (
+	maxCallRankingShown  = 10
+	maxCallTreeChildren  = 8
+	defaultCallTreeDepth = 3
+	maxCallTreeDepth     = 8
+)
+
+// renderCallGraph renders call graph statistics, the most called functions and
+// those with the largest fan-out, and the call tree of the selected function
+func (v *VisualizationViewModel) renderCallGraph() string {
+	var b strings.Builder
+
+	graph := v.analysisData.EnhancedProjectAnalysis.CallGraph
+	roots := v.callTreeRoots()
+	if len(roots) == 0 {
+		b.WriteString("🌳 Call Graph:\n")
+		b.WriteString("No calls between project functions were found\n")
+		return b.String()
+	}
+
+	b.WriteString("🔗 Call Graph:\n")
+	b.WriteString(fmt.Sprintf("• Functions: %d\n", len(graph.Nodes)))
+	b.WriteString(fmt.Sprintf("• Resolved Calls: %d\n", graph.Calls))
+	b.WriteString(fmt.Sprintf("• Unresolved Calls (external or ambiguous): %d\n", graph.Unresolved))
+	b.WriteString(fmt.Sprintf("• Never Called: %d\n\n", len(graph.Nodes)-len(graph.MostCalled(len(graph.Nodes)))))
+
+	b.WriteString("📥 Most Called Functions (fan-in):\n")
+	b.WriteString(v.renderCallRanking(graph.MostCalled(maxCallRankingShown), (*callgraph.Node).FanIn, "callers"))
+	b.WriteString("\n")
+
+	b.WriteString("📤 Largest Fan-Out:\n")
+	b.WriteString(v.renderCallRanking(graph.LargestFanOut(maxCallRankingShown), (*callgraph.Node).FanOut, "callees"))
+	b.WriteString("\n")
+
+	root := roots[v.selectedFunction%len(roots)]
+	b.WriteString(fmt.Sprintf("🌳 Call Tree of %s (%d/%d, depth %d):\n",
+		root.Name, v.selectedFunction%len(roots)+1, len(roots), v.callTreeDepth))
+	b.WriteString(fmt.Sprintf("%s  %s:%d\n", root.Name, v.relativePath(root.FilePath), root.Line))
+	v.renderCallTree(&b, graph, root, "", 1, map[string]bool{root.ID: true})
+
+	return b.String()
+}
+
+// renderCallRanking lists functions with a fan-in or fan-out count
+func (v *VisualizationViewModel) renderCallRanking(nodes []*callgraph.Node, count func(*callgraph.Node) int, label string) string {
+	var b strings.Builder
+	countStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#87CEEB")).Bold(true)
+	for i, node := range nodes {
+		b.WriteString(fmt.Sprintf("%2d. %s %-40s %s:%d\n", i+1,
+			countStyle.Render(fmt.Sprintf("%3d %s", count(node), label)),
+			node.Name, v.relativePath(node.FilePath), node.Line))
+	}
+	return b.String()
+}
+
+// renderCallTree writes the callees of node down to the selected depth. Calls
+// back into a function already on the path are marked as recursion.
+func (v *VisualizationViewModel) renderCallTree(b *strings.Builder, graph *callgraph.Graph, node *callgraph.Node, prefix string, depth int, path map[string]bool) {
+	recursionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8C00"))
+	packageStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+
+	for i, id := range node.Callees {
+		if i >= maxCallTreeChildren {
+			b.WriteString(fmt.Sprintf("%s└── ... and %d more\n", prefix, len(node.Callees)-maxCallTreeChildren))
+			break
+		}
+		branch, indent := "├── ", "│   "
+		if i == len(node.Callees)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		callee := graph.Nodes[id]
+		line := callee.Name + "()"
+		if callee.Package != node.Package {
+			line += " " + packageStyle.Render(callee.Package)
+		}
+		switch {
+		case path[id]:
+			b.WriteString(prefix + branch + line + " " + recursionStyle.Render("↻ recursive") + "\n")
+			continue
+		case depth >= v.callTreeDepth && len(callee.Callees) > 0:
+			line += fmt.Sprintf(" (+%d)", len(callee.Callees))
+		}
+		b.WriteString(prefix + branch + line + "\n")
+
+		if depth < v.callTreeDepth {
+			path[id] = true
+			v.renderCallTree(b, graph, callee, prefix+indent, depth+1, path)
+			delete(path, id)
+		}
+	}
+}
+
+// callTreeRoots returns the functions that can be selected as call tree roots:
+// those that call other project functions, largest fan-out first
+func (v *VisualizationViewModel) callTreeRoots() []*callgraph.Node {
+	graph := v.analysisData.EnhancedProjectAnalysis.CallGraph
+	if graph == nil {
+		return nil
+	}
+	return graph.LargestFanOut(len(graph.Nodes))
+}
+
+// updateCallTree handles the keys that select the call tree root and depth
+func (v *VisualizationViewModel) updateCallTree(key string) {
+	if v.analysisData == nil || v.analysisData.EnhancedProjectAnalysis == nil {
+		return
+	}
+	roots := len(v.callTreeRoots())
+	if roots == 0 {
+		return
+	}
+
+	switch key {
+	case "n":
+		v.selectedFunction = (v.selectedFunction + 1) % roots
+	case "p":
+		v.selectedFunction = (v.selectedFunction - 1 + roots) % roots
+	case "+", "=":
+		v.callTreeDepth = min(maxCallTreeDepth, v.callTreeDepth+1)
+	case "-":
+		v.callTreeDepth = max(1, v.callTreeDepth-1)
+	}
+}
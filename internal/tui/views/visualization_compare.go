@@ -0,0 +1,141 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/compare"
+	"github.com/tito-sala/codebasereaderv2/internal/git"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
+)
+
+// renderComparison renders the differences between two saved analyses, with
+// added entries in green, removed ones in red and regressions flagged
+func (v *VisualizationViewModel) renderComparison() string {
+	var b strings.Builder
+
+	b.WriteString("⚖️ Compare Analyses\n\n")
+
+	comparison := v.comparison
+	if comparison == nil {
+		b.WriteString("No comparison loaded. In the Configuration tab, enter\n")
+		b.WriteString("  compare <before.json> <after.json>\n")
+		b.WriteString("with two analyses saved by \"codebasereader analyze -json\".\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Before: %s, %d files, %d lines (%s)\n", comparison.Before.RootPath, comparison.Before.TotalFiles,
+		comparison.Before.TotalLines, comparison.Before.GeneratedAt.Format("2006-01-02 15:04")))
+	b.WriteString(fmt.Sprintf("After:  %s, %d files, %d lines (%s)\n\n", comparison.After.RootPath, comparison.After.TotalFiles,
+		comparison.After.TotalLines, comparison.After.GeneratedAt.Format("2006-01-02 15:04")))
+
+	score := comparison.QualityScore
+	scoreLine := fmt.Sprintf("🏆 Quality Score: %.1f (%s) → %.1f (%s)  %s", score.Before.Overall, score.Before.Grade,
+		score.After.Overall, score.After.Grade, v.renderFloatDelta(score.After.Overall-score.Before.Overall, false))
+	if score.Regressed {
+		scoreLine += "  " + v.renderRegressed()
+	}
+	b.WriteString(scoreLine + "\n\n")
+
+	before, after := comparison.ProjectMetrics.Before, comparison.ProjectMetrics.After
+	b.WriteString("📊 Project Metrics:\n")
+	b.WriteString(fmt.Sprintf("  Complexity       %s  (%d → %d)\n", v.renderIntDelta(after.TotalComplexity-before.TotalComplexity, true), before.TotalComplexity, after.TotalComplexity))
+	b.WriteString(fmt.Sprintf("  Avg complexity   %s  (%.1f → %.1f)\n", v.renderFloatDelta(after.AverageComplexity-before.AverageComplexity, true), before.AverageComplexity, after.AverageComplexity))
+	b.WriteString(fmt.Sprintf("  Maintainability  %s  (%.1f → %.1f)\n", v.renderFloatDelta(after.MaintainabilityIndex-before.MaintainabilityIndex, false), before.MaintainabilityIndex, after.MaintainabilityIndex))
+	b.WriteString(fmt.Sprintf("  Technical debt   %s  (%.1f → %.1f)\n", v.renderFloatDelta(after.TechnicalDebt-before.TechnicalDebt, true), before.TechnicalDebt, after.TechnicalDebt))
+	b.WriteString(fmt.Sprintf("  Documentation    %s  (%.1f%% → %.1f%%)\n", v.renderFloatDelta(after.DocumentationRatio-before.DocumentationRatio, false), before.DocumentationRatio, after.DocumentationRatio))
+	b.WriteString("\n")
+
+	b.WriteString("🎨 Languages:\n")
+	for _, lang := range comparison.Languages {
+		b.WriteString(fmt.Sprintf("  %s %-14s files %s  lines %s  functions %s\n", v.renderCompareStatus(lang.Status, false), lang.Language,
+			v.renderIntDelta(lang.After.FileCount-lang.Before.FileCount, false), v.renderIntDelta(lang.After.LineCount-lang.Before.LineCount, false),
+			v.renderIntDelta(lang.After.FunctionCount-lang.Before.FunctionCount, false)))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("📁 Directories:\n")
+	shown := 0
+	for _, dir := range comparison.Directories {
+		if dir.Status == compare.Unchanged {
+			continue
+		}
+		if shown >= 20 {
+			b.WriteString("  ... and more directories\n")
+			break
+		}
+		shown++
+		b.WriteString(fmt.Sprintf("  %s %-36s complexity %s  maintainability %s\n", v.renderCompareStatus(dir.Status, dir.Regressed), dir.Path,
+			v.renderIntDelta(dir.After.Complexity-dir.Before.Complexity, true),
+			v.renderFloatDelta(dir.After.MaintainabilityIndex-dir.Before.MaintainabilityIndex, false)))
+	}
+	if shown == 0 {
+		b.WriteString("  No directories changed\n")
+	}
+	b.WriteString("\n")
+
+	for _, file := range comparison.Changes.Files {
+		if file.Status == git.StatusRenamed {
+			b.WriteString(fmt.Sprintf("🚚 Moved: %s → %s\n", file.OldPath, file.Path))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("📉 Regressed Functions (%d):\n", len(comparison.Regressions)))
+	if len(comparison.Regressions) == 0 {
+		b.WriteString("  No function got more complex or accrued debt\n")
+	}
+	for i, fn := range comparison.Regressions {
+		if i >= 20 {
+			b.WriteString(fmt.Sprintf("  ... and %d more functions\n", len(comparison.Regressions)-20))
+			break
+		}
+		b.WriteString(fmt.Sprintf("  %-30s %s:%d  complexity %d → %d  debt %.1f → %.1f\n", fn.Name, fn.FilePath, fn.After.Line,
+			fn.Before.Complexity, fn.After.Complexity, fn.Before.TechnicalDebt, fn.After.TechnicalDebt))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("🔧 Added, Removed and Renamed Functions:\n")
+	listed := 0
+	for _, fn := range comparison.Changes.Functions {
+		if fn.Status == metrics.DiffChanged {
+			continue
+		}
+		if listed >= 30 {
+			b.WriteString("  ... and more functions\n")
+			break
+		}
+		listed++
+		name := fn.Name
+		if fn.OldName != "" {
+			name = fn.OldName + " → " + fn.Name
+		}
+		b.WriteString(fmt.Sprintf("  %s %-30s %s\n", v.renderDiffStatus(fn.Status), name, fn.FilePath))
+	}
+	if listed == 0 {
+		b.WriteString("  No functions added, removed or renamed\n")
+	}
+
+	return b.String()
+}
+
+// renderCompareStatus renders the status of a language or directory, flagging
+// changed ones that regressed
+func (v *VisualizationViewModel) renderCompareStatus(status string, regressed bool) string {
+	switch {
+	case status == compare.Added:
+		return lipgloss.NewStyle().Foreground(components.SuccessGreen).Render("+ added    ")
+	case status == compare.Removed:
+		return lipgloss.NewStyle().Foreground(components.ErrorRed).Render("- removed  ")
+	case regressed:
+		return v.renderRegressed() + " "
+	default:
+		return lipgloss.NewStyle().Foreground(components.WarningOrange).Render("~ changed  ")
+	}
+}
+
+// renderRegressed renders the marker for entries that got worse
+func (v *VisualizationViewModel) renderRegressed() string {
+	return lipgloss.NewStyle().Foreground(components.ErrorRed).Bold(true).Render("▼ regressed")
+}
@@ -0,0 +1,67 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxCouplingPairsShown limits how many co-changing file pairs the dependency view lists
+const maxCouplingPairsShown = 15
+
+// renderTemporalCoupling renders files that change together, highlighting pairs
+// that have no import between them
+func (v *VisualizationViewModel) renderTemporalCoupling() string {
+	var b strings.Builder
+
+	coupling := v.analysisData.EnhancedProjectAnalysis.TemporalCoupling
+	if len(coupling.Pairs) == 0 {
+		return ""
+	}
+
+	hidden := 0
+	for _, pair := range coupling.Pairs {
+		if !pair.HasDependency {
+			hidden++
+		}
+	}
+
+	hiddenStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF8C00")).Bold(true)
+
+	b.WriteString(fmt.Sprintf("🔗 Temporal Coupling (%d commits, %d pairs, %d hidden):\n",
+		coupling.CommitsAnalyzed, len(coupling.Pairs), hidden))
+	for i, pair := range coupling.Pairs {
+		if i >= maxCouplingPairsShown {
+			b.WriteString(fmt.Sprintf("   ... and %d more pairs\n", len(coupling.Pairs)-maxCouplingPairsShown))
+			break
+		}
+
+		marker := "   "
+		if !pair.HasDependency {
+			marker = hiddenStyle.Render("⚠️ ")
+		}
+		b.WriteString(fmt.Sprintf("%s %3.0f%% (%d together) %s ⇄ %s\n", marker, pair.Strength, pair.SharedCommits,
+			v.relativePath(pair.FileA), v.relativePath(pair.FileB)))
+	}
+	if hidden > 0 {
+		b.WriteString(hiddenStyle.Render("   ⚠️  hidden coupling: files change together without importing each other") + "\n")
+	}
+
+	if len(coupling.Clusters) > 0 {
+		b.WriteString("\n🧩 Change Clusters:\n")
+		for i, cluster := range coupling.Clusters {
+			if i >= 5 {
+				b.WriteString(fmt.Sprintf("   ... and %d more clusters\n", len(coupling.Clusters)-5))
+				break
+			}
+			files := make([]string, len(cluster))
+			for j, file := range cluster {
+				files[j] = v.relativePath(file)
+			}
+			b.WriteString(fmt.Sprintf("• %s\n", strings.Join(files, ", ")))
+		}
+	}
+
+	return b.String()
+}
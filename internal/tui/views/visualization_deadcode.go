@@ -0,0 +1,93 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// deadCodeHeaderLines is the number of lines renderDeadCode writes before the
+// list, used to keep the selected entry in view
+const deadCodeHeaderLines = 7
+
+// renderDeadCode renders the unreferenced functions, methods and types with the
+// selected one highlighted
+func (v *VisualizationViewModel) renderDeadCode() string {
+	var b strings.Builder
+
+	dead := v.analysisData.EnhancedProjectAnalysis.DeadCode
+
+	b.WriteString("🪦 Dead Code\n\n")
+
+	if len(dead) == 0 {
+		b.WriteString("No unreferenced functions, methods or types found\n")
+		return b.String()
+	}
+
+	kinds := make(map[string]int)
+	for _, d := range dead {
+		kinds[d.Kind]++
+	}
+	b.WriteString(fmt.Sprintf("📊 %d unreferenced: %d functions, %d methods, %d types\n\n",
+		len(dead), kinds[metrics.DeadFunction], kinds[metrics.DeadMethod], kinds[metrics.DeadType]))
+	b.WriteString("Entry points (main, tests, exports and registered handlers) are not listed.\n")
+	b.WriteString("Press enter to open the selected declaration.\n\n")
+
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#87CEEB"))
+	selected := v.selectedDeadCode % len(dead)
+	for i, d := range dead {
+		line := fmt.Sprintf("%-8s %-40s %s:%d", d.Kind, d.Name, v.relativePath(d.FilePath), d.LineStart)
+		if i == selected {
+			b.WriteString(selectedStyle.Render("▶ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// updateDeadCodeSelection moves the selection and scrolls to keep it in view
+func (v *VisualizationViewModel) updateDeadCodeSelection(key string) {
+	if v.analysisData == nil || v.analysisData.EnhancedProjectAnalysis == nil {
+		return
+	}
+	count := len(v.analysisData.EnhancedProjectAnalysis.DeadCode)
+	if count == 0 {
+		return
+	}
+
+	switch key {
+	case "n":
+		v.selectedDeadCode = (v.selectedDeadCode + 1) % count
+	case "p":
+		v.selectedDeadCode = (v.selectedDeadCode - 1 + count) % count
+	default:
+		return
+	}
+
+	line := deadCodeHeaderLines + v.selectedDeadCode
+	visible := v.height - 8 // as in applyScrolling
+	switch {
+	case line < v.scrollY:
+		v.scrollY = line
+	case visible > 0 && line >= v.scrollY+visible:
+		v.scrollY = line - visible + 1
+	}
+}
+
+// SelectedSource returns the file and line of the entry selected in the dead
+// code view, so it can be opened in the content view
+func (v *VisualizationViewModel) SelectedSource() (string, int, bool) {
+	if v.currentMode != DeadCodeMode || v.analysisData == nil || v.analysisData.EnhancedProjectAnalysis == nil {
+		return "", 0, false
+	}
+	dead := v.analysisData.EnhancedProjectAnalysis.DeadCode
+	if len(dead) == 0 {
+		return "", 0, false
+	}
+	selected := dead[v.selectedDeadCode%len(dead)]
+	return selected.FilePath, selected.LineStart, true
+}
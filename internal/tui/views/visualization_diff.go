@@ -0,0 +1,139 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
+)
+
+// renderRevisionDiff renders the net change between two revisions and the
+// functions and classes the change added, removed or changed
+func (v *VisualizationViewModel) renderRevisionDiff() string {
+	var b strings.Builder
+
+	b.WriteString("🔀 Revision Diff\n\n")
+
+	diff := v.revisionDiff
+	if diff == nil {
+		b.WriteString("No revision diff loaded. In the Configuration tab, enter\n")
+		b.WriteString("  diff <base>..<head>\n")
+		b.WriteString("to compare the files changed between two commits, branches or tags.\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Range: %s (%.8s..%.8s), %d files changed\n\n", diff.Range, diff.Base, diff.Head, len(diff.Files)))
+	if len(diff.Files) == 0 {
+		b.WriteString("No supported files changed.\n")
+		return b.String()
+	}
+
+	skipped := 0
+	for _, file := range diff.Files {
+		if file.Skipped != "" {
+			b.WriteString(fmt.Sprintf("⚠️ %s not analyzed: %s\n", file.Path, file.Skipped))
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		b.WriteString("\n")
+	}
+
+	before, after := diff.Before, diff.After
+	b.WriteString("📊 Net Change:\n")
+	b.WriteString(fmt.Sprintf("  Lines            %s  (%d → %d)\n", v.renderIntDelta(after.Lines-before.Lines, false), before.Lines, after.Lines))
+	b.WriteString(fmt.Sprintf("  Functions        %s  (%d → %d)\n", v.renderIntDelta(after.Functions-before.Functions, false), before.Functions, after.Functions))
+	b.WriteString(fmt.Sprintf("  Classes          %s  (%d → %d)\n", v.renderIntDelta(after.Classes-before.Classes, false), before.Classes, after.Classes))
+	b.WriteString(fmt.Sprintf("  Complexity       %s  (%d → %d)\n", v.renderIntDelta(after.Complexity-before.Complexity, true), before.Complexity, after.Complexity))
+	b.WriteString(fmt.Sprintf("  Avg complexity   %s  (%.1f → %.1f)\n", v.renderFloatDelta(after.AverageComplexity-before.AverageComplexity, true), before.AverageComplexity, after.AverageComplexity))
+	b.WriteString(fmt.Sprintf("  Technical debt   %s  (%.1f → %.1f)\n", v.renderFloatDelta(after.TechnicalDebt-before.TechnicalDebt, true), before.TechnicalDebt, after.TechnicalDebt))
+	b.WriteString("\n")
+
+	b.WriteString(fmt.Sprintf("🔧 Functions (%d):\n", len(diff.Functions)))
+	if len(diff.Functions) == 0 {
+		b.WriteString("  No functions added, removed or changed\n")
+	}
+	for i, fn := range diff.Functions {
+		if i >= 30 {
+			b.WriteString(fmt.Sprintf("  ... and %d more functions\n", len(diff.Functions)-30))
+			break
+		}
+		var from, to metrics.FunctionSnapshot
+		line := 0
+		if fn.Before != nil {
+			from, line = *fn.Before, fn.Before.Line
+		}
+		if fn.After != nil {
+			to, line = *fn.After, fn.After.Line
+		}
+		name := fn.Name
+		if fn.OldName != "" {
+			name = fn.OldName + " → " + fn.Name
+		}
+		b.WriteString(fmt.Sprintf("  %s %-30s %s:%d\n", v.renderDiffStatus(fn.Status), name, fn.FilePath, line))
+		b.WriteString(fmt.Sprintf("      complexity %s  lines %s  params %s  debt %s\n",
+			v.renderIntDelta(to.Complexity-from.Complexity, true), v.renderIntDelta(to.LinesOfCode-from.LinesOfCode, false),
+			v.renderIntDelta(to.Parameters-from.Parameters, true), v.renderFloatDelta(to.TechnicalDebt-from.TechnicalDebt, true)))
+	}
+
+	if len(diff.Classes) > 0 {
+		b.WriteString(fmt.Sprintf("\n🏛️ Classes (%d):\n", len(diff.Classes)))
+		for i, class := range diff.Classes {
+			if i >= 15 {
+				b.WriteString(fmt.Sprintf("  ... and %d more classes\n", len(diff.Classes)-15))
+				break
+			}
+			var from, to metrics.ClassSnapshot
+			if class.Before != nil {
+				from = *class.Before
+			}
+			if class.After != nil {
+				to = *class.After
+			}
+			b.WriteString(fmt.Sprintf("  %s %-30s %s  methods %s  complexity %s\n", v.renderDiffStatus(class.Status), class.Name,
+				class.FilePath, v.renderIntDelta(to.Methods-from.Methods, false), v.renderIntDelta(to.Complexity-from.Complexity, true)))
+		}
+	}
+
+	return b.String()
+}
+
+// renderDiffStatus renders an added, removed or changed marker
+func (v *VisualizationViewModel) renderDiffStatus(status string) string {
+	switch status {
+	case metrics.DiffAdded:
+		return lipgloss.NewStyle().Foreground(components.SuccessGreen).Render("+ added  ")
+	case metrics.DiffRemoved:
+		return lipgloss.NewStyle().Foreground(components.ErrorRed).Render("- removed")
+	case metrics.DiffRenamed:
+		return lipgloss.NewStyle().Foreground(components.InfoBlue).Render("→ renamed")
+	default:
+		return lipgloss.NewStyle().Foreground(components.WarningOrange).Render("~ changed")
+	}
+}
+
+// renderIntDelta renders a signed change, colored when an increase is worse
+func (v *VisualizationViewModel) renderIntDelta(delta int, higherIsWorse bool) string {
+	return v.renderDelta(fmt.Sprintf("%+d", delta), float64(delta), higherIsWorse)
+}
+
+// renderFloatDelta renders a signed change with one decimal
+func (v *VisualizationViewModel) renderFloatDelta(delta float64, higherIsWorse bool) string {
+	return v.renderDelta(fmt.Sprintf("%+.1f", delta), delta, higherIsWorse)
+}
+
+// renderDelta colors increases red and decreases green for metrics where
+// higher is worse, and leaves other changes uncolored
+func (v *VisualizationViewModel) renderDelta(text string, delta float64, higherIsWorse bool) string {
+	text = fmt.Sprintf("%6s", text)
+	switch {
+	case !higherIsWorse || delta == 0:
+		return text
+	case delta > 0:
+		return lipgloss.NewStyle().Foreground(components.ErrorRed).Render(text)
+	default:
+		return lipgloss.NewStyle().Foreground(components.SuccessGreen).Render(text)
+	}
+}
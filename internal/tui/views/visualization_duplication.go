@@ -0,0 +1,75 @@
+package views
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxCloneGroupsShown limits how many clone groups the duplication view lists
+const maxCloneGroupsShown = 15
+
+// renderDuplication renders the code duplication visualization
+func (v *VisualizationViewModel) renderDuplication() string {
+	var b strings.Builder
+
+	analysis := v.analysisData.EnhancedProjectAnalysis
+	duplication := analysis.Duplication
+
+	b.WriteString("📋 Code Duplication Analysis\n\n")
+
+	// Project summary
+	color := lipgloss.Color("#00FF00")
+	if duplication.Percentage > 15 {
+		color = lipgloss.Color("#FF0000")
+	} else if duplication.Percentage > 5 {
+		color = lipgloss.Color("#FFFF00")
+	}
+	percentage := lipgloss.NewStyle().Foreground(color).Bold(true).
+		Render(fmt.Sprintf("%.1f%%", duplication.Percentage))
+
+	b.WriteString("📊 Duplication Summary:\n")
+	b.WriteString(fmt.Sprintf("• Duplicated Code: %s %s\n", v.createMiniGauge(100-duplication.Percentage, 20), percentage))
+	b.WriteString(fmt.Sprintf("• Duplicated Lines: %d of %d\n", duplication.DuplicatedLines, duplication.TotalLines))
+	b.WriteString(fmt.Sprintf("• Clone Groups: %d\n\n", len(duplication.Groups)))
+
+	if len(duplication.Groups) == 0 {
+		b.WriteString("✅ No duplicated blocks found\n")
+		return b.String()
+	}
+
+	// Largest clone groups with their locations
+	b.WriteString("🔁 Largest Clone Groups:\n")
+	for i, group := range duplication.Groups {
+		if i >= maxCloneGroupsShown {
+			b.WriteString(fmt.Sprintf("   ... and %d more groups\n", len(duplication.Groups)-maxCloneGroupsShown))
+			break
+		}
+
+		b.WriteString(fmt.Sprintf("%2d. %d lines × %d copies (%d tokens)\n",
+			i+1, group.Lines, len(group.Instances), group.Tokens))
+		for j, instance := range group.Instances {
+			branch := "├──"
+			if j == len(group.Instances)-1 {
+				branch = "└──"
+			}
+			b.WriteString(fmt.Sprintf("    %s %s:%d-%d\n", branch, v.relativePath(instance.FilePath), instance.LineStart, instance.LineEnd))
+		}
+	}
+
+	return b.String()
+}
+
+// relativePath shortens a file path relative to the analyzed project root
+func (v *VisualizationViewModel) relativePath(path string) string {
+	root := v.analysisData.EnhancedProjectAnalysis.RootPath
+	if root == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,166 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+	"github.com/tito-sala/codebasereaderv2/internal/parser"
+)
+
+// renderGitHistory renders churn, authorship and age from the git history stage
+func (v *VisualizationViewModel) renderGitHistory() string {
+	var b strings.Builder
+
+	analysis := v.analysisData.EnhancedProjectAnalysis
+
+	b.WriteString("🕰️  Git History Analysis\n\n")
+
+	fileResults, _ := analysis.FileResults.([]*parser.AnalysisResult)
+	var files []*parser.AnalysisResult
+	authors := make(map[string]bool)
+	commits, added, removed := 0, 0, 0
+	for _, result := range fileResults {
+		if result.History == nil {
+			continue
+		}
+		files = append(files, result)
+		commits += result.History.CommitCount
+		added += result.History.LinesAdded
+		removed += result.History.LinesRemoved
+		for _, author := range result.History.Authors {
+			authors[author.Email] = true
+		}
+	}
+
+	if len(files) == 0 {
+		b.WriteString("No git history available. The analyzed directory is not a git repository,\n")
+		b.WriteString("git is not installed, or history mining is disabled.\n")
+		return b.String()
+	}
+
+	b.WriteString("📊 History Summary:\n")
+	b.WriteString(fmt.Sprintf("• Files with history: %d of %d\n", len(files), len(fileResults)))
+	b.WriteString(fmt.Sprintf("• File changes in window: %d (+%d / -%d lines)\n", commits, added, removed))
+	b.WriteString(fmt.Sprintf("• Distinct authors: %d\n\n", len(authors)))
+
+	// Directories by churn
+	type dirHistory struct {
+		path  string
+		stats metrics.DirectoryStats
+	}
+	var dirs []dirHistory
+	for path, stats := range analysis.DirectoryStats {
+		if stats.AuthorCount > 0 {
+			dirs = append(dirs, dirHistory{path, stats})
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		churnI := dirs[i].stats.LinesAdded + dirs[i].stats.LinesRemoved
+		churnJ := dirs[j].stats.LinesAdded + dirs[j].stats.LinesRemoved
+		if churnI != churnJ {
+			return churnI > churnJ
+		}
+		return dirs[i].path < dirs[j].path
+	})
+
+	b.WriteString("📁 Directories by Churn:\n")
+	for i, dir := range dirs {
+		if i >= 10 {
+			b.WriteString(fmt.Sprintf("   ... and %d more directories\n", len(dirs)-10))
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %-30s %4d changes  +%-6d -%-6d 👥 %d  🚌 %s  🕒 %s\n",
+			v.relativePath(dir.path), dir.stats.CommitCount, dir.stats.LinesAdded, dir.stats.LinesRemoved,
+			dir.stats.AuthorCount, v.renderBusFactor(dir.stats.BusFactor), formatAge(dir.stats.LastModified)))
+	}
+	b.WriteString("\n")
+
+	// Most churned files
+	sort.Slice(files, func(i, j int) bool {
+		churnI := files[i].History.LinesAdded + files[i].History.LinesRemoved
+		churnJ := files[j].History.LinesAdded + files[j].History.LinesRemoved
+		if churnI != churnJ {
+			return churnI > churnJ
+		}
+		return files[i].FilePath < files[j].FilePath
+	})
+
+	b.WriteString("🔥 Most Changed Files:\n")
+	for i, file := range files {
+		if i >= 10 || file.History.CommitCount == 0 {
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %-40s %3d commits  +%-6d -%-6d\n",
+			v.relativePath(file.FilePath), file.History.CommitCount, file.History.LinesAdded, file.History.LinesRemoved))
+	}
+	b.WriteString("\n")
+
+	// Knowledge silos: files a single author owns
+	b.WriteString("🚌 Knowledge Silos (bus factor 1):\n")
+	silos := 0
+	for _, file := range files {
+		if file.History.BusFactor != 1 || len(file.History.Authors) == 0 {
+			continue
+		}
+		if silos >= 10 {
+			b.WriteString("   ...\n")
+			break
+		}
+		owner := file.History.Authors[0]
+		b.WriteString(fmt.Sprintf("• %-40s mostly %s (%d author(s) in total)\n",
+			v.relativePath(file.FilePath), owner.Name, file.History.AuthorCount))
+		silos++
+	}
+	if silos == 0 {
+		b.WriteString("✅ No single-owner files\n")
+	}
+	b.WriteString("\n")
+
+	// Stale files: longest since last modification
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].History.LastModified.Before(files[j].History.LastModified)
+	})
+	b.WriteString("🕸️  Least Recently Modified:\n")
+	for i, file := range files {
+		if i >= 5 {
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %-40s last changed %s, first added %s\n",
+			v.relativePath(file.FilePath), formatAge(file.History.LastModified), file.History.FirstModified.Format("2006-01-02")))
+	}
+
+	return b.String()
+}
+
+// renderBusFactor colors a bus factor: 1 is a risk, 2 a warning
+func (v *VisualizationViewModel) renderBusFactor(busFactor int) string {
+	color := lipgloss.Color("#00FF00")
+	if busFactor <= 1 {
+		color = lipgloss.Color("#FF0000")
+	} else if busFactor == 2 {
+		color = lipgloss.Color("#FFFF00")
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%d", busFactor))
+}
+
+// formatAge describes how long ago a time was in days, months or years
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	days := int(time.Since(t).Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 60:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 730:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dy ago", days/365)
+	}
+}
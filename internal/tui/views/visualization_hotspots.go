@@ -0,0 +1,141 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/engine"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// Scatter plot dimensions in terminal cells
+const (
+	hotspotPlotWidth  = 50
+	hotspotPlotHeight = 14
+)
+
+// renderHotspots renders the ranked hotspot lists and a complexity vs churn scatter plot
+func (v *VisualizationViewModel) renderHotspots() string {
+	var b strings.Builder
+
+	analysis := v.analysisData.EnhancedProjectAnalysis
+
+	b.WriteString("🎯 Hotspot Analysis\n\n")
+
+	if len(analysis.FileHotspots) == 0 {
+		b.WriteString("No hotspots available. Hotspots combine complexity with git change\n")
+		b.WriteString("frequency, so the analyzed directory must be a git repository.\n")
+		return b.String()
+	}
+
+	b.WriteString("📈 Complexity vs Change Frequency (files):\n")
+	b.WriteString(v.renderHotspotScatter(analysis.FileHotspots))
+	b.WriteString("\n")
+
+	b.WriteString("🔥 Riskiest Files:\n")
+	for i, hotspot := range analysis.FileHotspots {
+		if i >= 15 {
+			b.WriteString(fmt.Sprintf("   ... and %d more files\n", len(analysis.FileHotspots)-15))
+			break
+		}
+		b.WriteString(fmt.Sprintf("%2d. %s %-40s complexity %-4d debt %-6.1f %3d commits  %5d lines churn\n",
+			i+1, v.renderHotspotScore(hotspot.Score), v.relativePath(hotspot.FilePath),
+			hotspot.Complexity, hotspot.TechnicalDebt, hotspot.Changes, hotspot.Churn))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("🔧 Riskiest Functions:\n")
+	b.WriteString(fmt.Sprintf("   Commits per function are only counted in the %d most changed files\n", engine.MaxTracedFiles))
+	if len(analysis.FunctionHotspots) == 0 {
+		b.WriteString("No function-level change data\n")
+	}
+	for i, hotspot := range analysis.FunctionHotspots {
+		if i >= 15 {
+			b.WriteString(fmt.Sprintf("   ... and %d more functions\n", len(analysis.FunctionHotspots)-15))
+			break
+		}
+		b.WriteString(fmt.Sprintf("%2d. %s %-30s %s:%d  complexity %-4d %3d commits\n",
+			i+1, v.renderHotspotScore(hotspot.Score), hotspot.Function, v.relativePath(hotspot.FilePath),
+			hotspot.Line, hotspot.Complexity, hotspot.Changes))
+	}
+
+	return b.String()
+}
+
+// renderHotspotScatter plots complexity (y) against number of changes (x). Each
+// cell shows how many files fall in it, colored by the highest score there.
+func (v *VisualizationViewModel) renderHotspotScatter(hotspots []metrics.Hotspot) string {
+	maxComplexity, maxChanges := 1, 1
+	for _, hotspot := range hotspots {
+		maxComplexity = max(maxComplexity, hotspot.Complexity)
+		maxChanges = max(maxChanges, hotspot.Changes)
+	}
+
+	counts := make([][]int, hotspotPlotHeight)
+	scores := make([][]float64, hotspotPlotHeight)
+	for row := range counts {
+		counts[row] = make([]int, hotspotPlotWidth)
+		scores[row] = make([]float64, hotspotPlotWidth)
+	}
+	for _, hotspot := range hotspots {
+		col := hotspot.Changes * (hotspotPlotWidth - 1) / maxChanges
+		row := hotspotPlotHeight - 1 - hotspot.Complexity*(hotspotPlotHeight-1)/maxComplexity
+		counts[row][col]++
+		scores[row][col] = max(scores[row][col], hotspot.Score)
+	}
+
+	var b strings.Builder
+	for row := 0; row < hotspotPlotHeight; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = fmt.Sprintf("%d", maxComplexity)
+		case hotspotPlotHeight - 1:
+			label = "0"
+		case hotspotPlotHeight / 2:
+			label = "cplx"
+		}
+		b.WriteString(fmt.Sprintf("%6s │", label))
+
+		for col := 0; col < hotspotPlotWidth; col++ {
+			switch count := counts[row][col]; {
+			case count == 0:
+				b.WriteString(" ")
+			case count == 1:
+				b.WriteString(hotspotStyle(scores[row][col]).Render("●"))
+			case count < 10:
+				b.WriteString(hotspotStyle(scores[row][col]).Render(fmt.Sprintf("%d", count)))
+			default:
+				b.WriteString(hotspotStyle(scores[row][col]).Render("+"))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("       └" + strings.Repeat("─", hotspotPlotWidth) + "\n")
+	xLabel := fmt.Sprintf("%d commits", maxChanges)
+	b.WriteString("        0" + strings.Repeat(" ", max(1, hotspotPlotWidth-1-len(xLabel))) + xLabel + "\n")
+	b.WriteString("        Top-right files are complex and change often: refactor these first\n")
+
+	return b.String()
+}
+
+// renderHotspotScore renders a hotspot score with its risk color
+func (v *VisualizationViewModel) renderHotspotScore(score float64) string {
+	return hotspotStyle(score).Render(fmt.Sprintf("%5.1f", score))
+}
+
+// hotspotStyle colors a hotspot score from green (low) to red (high)
+func hotspotStyle(score float64) lipgloss.Style {
+	color := lipgloss.Color("#00FF00")
+	switch {
+	case score > 50:
+		color = lipgloss.Color("#FF0000")
+	case score > 25:
+		color = lipgloss.Color("#FF8800")
+	case score > 10:
+		color = lipgloss.Color("#FFFF00")
+	}
+	return lipgloss.NewStyle().Foreground(color)
+}
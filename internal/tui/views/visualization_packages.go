@@ -0,0 +1,143 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/metrics"
+)
+
+// Package coupling display limits and plot size
+const (
+	maxPackagesShown   = 20
+	packagePlotWidth   = 41
+	packagePlotHeight  = 15
+	mainSequenceMarker = "·"
+)
+
+// renderPackageCoupling renders package coupling metrics as a table and an
+// abstractness vs instability plot
+func (v *VisualizationViewModel) renderPackageCoupling() string {
+	var b strings.Builder
+
+	packages := v.analysisData.EnhancedProjectAnalysis.DependencyGraph.Packages
+
+	b.WriteString("📦 Package Coupling\n\n")
+
+	if len(packages) == 0 {
+		b.WriteString("No packages found\n")
+		return b.String()
+	}
+
+	pain, useless, totalDistance := 0, 0, 0.0
+	for _, pkg := range packages {
+		totalDistance += pkg.Distance
+		switch pkg.Zone {
+		case metrics.ZonePain:
+			pain++
+		case metrics.ZoneUselessness:
+			useless++
+		}
+	}
+
+	b.WriteString("📊 Package Summary:\n")
+	b.WriteString(fmt.Sprintf("• Packages: %d\n", len(packages)))
+	b.WriteString(fmt.Sprintf("• Average Distance from Main Sequence: %.2f\n", totalDistance/float64(len(packages))))
+	b.WriteString(fmt.Sprintf("• Zone of Pain (stable, concrete): %d\n", pain))
+	b.WriteString(fmt.Sprintf("• Zone of Uselessness (unstable, abstract): %d\n\n", useless))
+
+	b.WriteString("📋 Packages by Distance (Ca afferent, Ce efferent, I instability, A abstractness, D distance):\n")
+	b.WriteString(fmt.Sprintf("   %-36s %4s %4s %5s %5s %5s  %s\n", "Package", "Ca", "Ce", "I", "A", "D", "Zone"))
+	for i, pkg := range packages {
+		if i >= maxPackagesShown {
+			b.WriteString(fmt.Sprintf("   ... and %d more packages\n", len(packages)-maxPackagesShown))
+			break
+		}
+		name := v.relativePath(pkg.Path)
+		if len(name) > 36 {
+			name = "..." + name[len(name)-33:]
+		}
+		b.WriteString(fmt.Sprintf("   %-36s %4d %4d %5.2f %5.2f %s  %s\n", name, pkg.Afferent, pkg.Efferent,
+			pkg.Instability, pkg.Abstractness, distanceStyle(pkg.Distance).Render(fmt.Sprintf("%5.2f", pkg.Distance)), pkg.Zone))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("📈 Abstractness vs Instability:\n")
+	b.WriteString(v.renderPackageScatter(packages))
+
+	return b.String()
+}
+
+// renderPackageScatter plots abstractness (y) against instability (x) with the
+// main sequence A + I = 1 drawn as a dotted diagonal. Each cell shows how many
+// packages fall in it, colored by the largest distance there.
+func (v *VisualizationViewModel) renderPackageScatter(packages []metrics.PackageMetrics) string {
+	counts := make([][]int, packagePlotHeight)
+	distances := make([][]float64, packagePlotHeight)
+	for row := range counts {
+		counts[row] = make([]int, packagePlotWidth)
+		distances[row] = make([]float64, packagePlotWidth)
+	}
+	for _, pkg := range packages {
+		col := int(math.Round(pkg.Instability * float64(packagePlotWidth-1)))
+		row := packagePlotHeight - 1 - int(math.Round(pkg.Abstractness*float64(packagePlotHeight-1)))
+		counts[row][col]++
+		distances[row][col] = max(distances[row][col], pkg.Distance)
+	}
+
+	var b strings.Builder
+	for row := 0; row < packagePlotHeight; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = "1"
+		case packagePlotHeight - 1:
+			label = "0"
+		case packagePlotHeight / 2:
+			label = "A"
+		}
+		b.WriteString(fmt.Sprintf("%6s │", label))
+
+		// Column of the main sequence on this row
+		abstractness := float64(packagePlotHeight-1-row) / float64(packagePlotHeight-1)
+		sequenceCol := int(math.Round((1 - abstractness) * float64(packagePlotWidth-1)))
+
+		for col := 0; col < packagePlotWidth; col++ {
+			switch count := counts[row][col]; {
+			case count == 0 && col == sequenceCol:
+				b.WriteString(mainSequenceMarker)
+			case count == 0:
+				b.WriteString(" ")
+			case count == 1:
+				b.WriteString(distanceStyle(distances[row][col]).Render("●"))
+			case count < 10:
+				b.WriteString(distanceStyle(distances[row][col]).Render(fmt.Sprintf("%d", count)))
+			default:
+				b.WriteString(distanceStyle(distances[row][col]).Render("+"))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("       └" + strings.Repeat("─", packagePlotWidth) + "\n")
+	b.WriteString("        0" + strings.Repeat(" ", packagePlotWidth/2-1) + "I" + strings.Repeat(" ", packagePlotWidth/2-1) + "1\n")
+	b.WriteString("        Bottom-left: zone of pain • Top-right: zone of uselessness • " + mainSequenceMarker + " main sequence\n")
+
+	return b.String()
+}
+
+// distanceStyle colors a distance from the main sequence from green (near) to red (far)
+func distanceStyle(distance float64) lipgloss.Style {
+	color := lipgloss.Color("#00FF00")
+	switch {
+	case distance > 0.7:
+		color = lipgloss.Color("#FF0000")
+	case distance > 0.5:
+		color = lipgloss.Color("#FF8800")
+	case distance > 0.3:
+		color = lipgloss.Color("#FFFF00")
+	}
+	return lipgloss.NewStyle().Foreground(color)
+}
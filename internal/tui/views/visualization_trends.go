@@ -0,0 +1,89 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tito-sala/codebasereaderv2/internal/trends"
+	"github.com/tito-sala/codebasereaderv2/internal/tui/components"
+)
+
+// trendWidth is the number of most recent snapshots charted per sparkline
+const trendWidth = 40
+
+// renderTrends renders sparklines of the project and directory metrics
+// recorded in the trend store, colored by whether they improved
+func (v *VisualizationViewModel) renderTrends() string {
+	var b strings.Builder
+
+	b.WriteString("📈 Trends\n\n")
+
+	snapshots := v.trendSnapshots
+	if len(snapshots) == 0 {
+		b.WriteString("No snapshots recorded for this project. Record analysis runs with\n")
+		b.WriteString("  codebasereader analyze -record <path>\n")
+		b.WriteString("or enter \"set record_trends true\" in the Configuration tab before analyzing.\n")
+		return b.String()
+	}
+
+	charted := snapshots
+	if len(charted) > trendWidth {
+		charted = charted[len(charted)-trendWidth:]
+	}
+	first, latest := charted[0], charted[len(charted)-1]
+	b.WriteString(fmt.Sprintf("%d snapshots, charting %s to %s\n", len(snapshots),
+		first.Timestamp.Format("2006-01-02 15:04"), latest.Timestamp.Format("2006-01-02 15:04")))
+	if latest.Commit != "" {
+		b.WriteString(fmt.Sprintf("Latest commit: %.12s\n", latest.Commit))
+	}
+	b.WriteString("\n")
+
+	grades := make([]string, 0, len(charted))
+	for _, snapshot := range charted {
+		grades = append(grades, snapshot.QualityScore.Grade)
+	}
+	b.WriteString(fmt.Sprintf("🏆 Grade: %s\n\n", strings.Join(grades, "")))
+
+	b.WriteString("📊 Project:\n")
+	for _, metric := range trends.ProjectMetrics {
+		b.WriteString("  " + v.renderTrendLine(metric, trends.Values(trends.Series(charted, metric))) + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("📁 Directories (most complex first):\n")
+	for i, dir := range trends.Directories(charted) {
+		if i >= 15 {
+			b.WriteString("  ... and more directories\n")
+			break
+		}
+		b.WriteString(fmt.Sprintf("  %s\n", dir))
+		for _, metric := range []string{trends.Complexity, trends.TechnicalDebt} {
+			b.WriteString("    " + v.renderTrendLine(metric, trends.Values(trends.DirectorySeries(charted, dir, metric))) + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// renderTrendLine renders a metric's latest value and sparkline, green when it
+// improved since the first charted snapshot and red when it got worse
+func (v *VisualizationViewModel) renderTrendLine(metric string, values []float64) string {
+	if len(values) == 0 {
+		return fmt.Sprintf("%-20s no data", metric)
+	}
+
+	change := values[len(values)-1] - values[0]
+	if trends.HigherIsBetter(metric) {
+		change = -change
+	}
+	style := lipgloss.NewStyle()
+	switch {
+	case change > 0:
+		style = style.Foreground(components.ErrorRed)
+	case change < 0:
+		style = style.Foreground(components.SuccessGreen)
+	}
+
+	return fmt.Sprintf("%-20s %10.1f  %s", metric, values[len(values)-1], style.Render(trends.Sparkline(values, 0)))
+}